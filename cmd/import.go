@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/importer"
+
+	"github.com/spf13/pflag"
+)
+
+const importUsage = "usage: import <file> [--dry-run] [--format=csv|json|yaml]"
+
+// флаги подкоманды import, регистрируются до pflag.Parse в config.MustLoad
+var (
+	importDryRun = pflag.Bool("dry-run", false, "import: only report what would change")
+	importFormat = pflag.String("format", "", "import: file format (csv, json, yaml), by default taken from the file extension")
+)
+
+// isImportCommand сервис запущен как `import <file>`, а не как сервер
+func isImportCommand() bool {
+	args := pflag.Args()
+	return len(args) > 0 && args[0] == "import"
+}
+
+type importService interface {
+	Import(context.Context, *dto.ImportRequest) (*dto.ImportResponse, error)
+}
+
+// runImport тот же импорт, что и POST /admin/import: файл валидируется целиком, отчет печатается в stdout
+func runImport(ctx context.Context, svc importService, args []string) error {
+	if len(args) != 1 {
+		return errors.New(importUsage)
+	}
+	path := args[0]
+
+	var (
+		format string
+		err    error
+	)
+	if *importFormat != "" {
+		format, err = importer.ParseFormat(*importFormat)
+	} else {
+		format, err = importer.FormatFromPath(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := importer.Parse(f, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	req.DryRun = *importDryRun
+
+	if err := dto.Validate(req); err != nil {
+		return err
+	}
+
+	resp, err := svc.Import(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(resp)
+}
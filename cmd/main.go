@@ -9,16 +9,36 @@ import (
 	"os"
 	"os/signal"
 	"service-order-avito/internal/config"
+	"service-order-avito/internal/events"
+	"service-order-avito/internal/graph"
+	grpcserver "service-order-avito/internal/grpc/server"
+	"service-order-avito/internal/health"
+	"service-order-avito/internal/http/middleware"
 	"service-order-avito/internal/http/server"
+	"service-order-avito/internal/http/server/handlers/admin"
+	availability2 "service-order-avito/internal/http/server/handlers/availability"
+	events2 "service-order-avito/internal/http/server/handlers/events"
+	"service-order-avito/internal/http/server/handlers/export"
+	health2 "service-order-avito/internal/http/server/handlers/health"
 	"service-order-avito/internal/http/server/handlers/pull_request"
 	"service-order-avito/internal/http/server/handlers/team"
 	"service-order-avito/internal/http/server/handlers/user"
+	"service-order-avito/internal/ratelimit"
+	"service-order-avito/internal/repository/memory"
 	"service-order-avito/internal/repository/postgres"
+	"service-order-avito/internal/repository/sqlite"
+	"service-order-avito/internal/service/availability"
 	pull_request2 "service-order-avito/internal/service/pull_request"
 	team2 "service-order-avito/internal/service/team"
 	user2 "service-order-avito/internal/service/user"
+	"service-order-avito/migrations"
 	"service-order-avito/pkg/logger"
 	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
 )
 
 func main() {
@@ -27,80 +47,258 @@ func main() {
 
 	// LOGGER
 	log := logger.MustInit(cfg.Env)
+	// через дефолтный логгер пишутся причины внутренних ошибок в pkg/http/error_wrapper
+	slog.SetDefault(log)
 	log.Info("logger initialized")
 
 	// GS context
 	ctxApp, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// context запросов, отменяется после остановки сервера (или по таймауту остановки),
+	// а не сразу по сигналу: во время drain сервис продолжает нормально обслуживать запросы
+	ctxServer, cancelServer := context.WithCancel(context.Background())
+	defer cancelServer()
+
 	// context для бд, отменяется после завершения сервера
 	ctxDB, cancelDB := context.WithCancel(context.Background())
 	defer cancelDB()
 
-	// Conn to Postgres
-	conn, err := postgres.ConnectPostgres(ctxDB, cfg.Postgres, cfg.Env)
-	if err != nil {
-		log.Error("init repository: " + err.Error())
-		os.Exit(1)
-	}
-	defer func() {
-		conn.Close()
-		log.Info("connection with database closed")
-	}()
-
 	// Repository's Lay
-	userRepo := postgres.NewUserRepositoryPostgres(conn)
-	teamRepo := postgres.NewTeamRepositoryPostgres(conn, userRepo)
-	prRepo := postgres.NewPullRequestRepositoryPostgres(conn, teamRepo, userRepo)
-	log.Info("repository's lay initialized")
+	var (
+		teamRepo  team2.TeamRepository
+		userRepo  user2.UserRepository
+		prRepo    pull_request2.PullRequestRepository
+		idemRepo  idempotencyStore
+		availRepo availability.AvailabilityRepository
+		checkers  []health.Checker
+	)
+	switch cfg.Storage {
+	case config.StorageMemory:
+		// данные живут только в памяти процесса: для локальной разработки и демо без бд
+		if isMigrateCommand() || isImportCommand() {
+			log.Error(pflag.Args()[0] + " requires --storage=" + config.StoragePostgres + " or --storage=" + config.StorageSQLite)
+			os.Exit(1)
+		}
+		storage := memory.NewStorage()
+		userRepo = memory.NewUserRepositoryMemory(storage)
+		teamRepo = memory.NewTeamRepositoryMemory(storage)
+		prRepo = memory.NewPullRequestRepositoryMemory(storage, cfg.Assignment.MaxOpenReviews)
+		idemRepo = memory.NewIdempotencyRepositoryMemory(storage)
+		availRepo = memory.NewAvailabilityRepositoryMemory(storage)
+	case config.StorageSQLite:
+		// один файл бд рядом с бинарником, без отдельного сервера
+		db, err := sqlite.ConnectSQLite(ctxDB, cfg.SQLite)
+		if err != nil {
+			log.Error("init repository: " + err.Error())
+			os.Exit(1)
+		}
+		defer func() {
+			db.Close()
+			log.Info("connection with database closed")
+		}()
+
+		migrator, err := sqlite.NewMigrator(db, migrations.SQLite, log)
+		if err != nil {
+			log.Error("init migrator", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+
+		if prepareSchema(ctxApp, cfg, log, migrator) {
+			return
+		}
+
+		sqliteUserRepo := sqlite.NewUserRepositorySQLite(db)
+		sqliteTeamRepo := sqlite.NewTeamRepositorySQLite(db, sqliteUserRepo)
+		userRepo = sqliteUserRepo
+		teamRepo = sqliteTeamRepo
+		prRepo = sqlite.NewPullRequestRepositorySQLite(db, sqliteTeamRepo, sqliteUserRepo, cfg.Assignment.MaxOpenReviews)
+		idemRepo = sqlite.NewIdempotencyRepositorySQLite(db)
+		availRepo = sqlite.NewAvailabilityRepositorySQLite(db)
+
+		checkers = append(checkers,
+			sqlite.NewPingChecker(db),
+			sqlite.NewSchemaChecker(db, migrator.Latest()),
+		)
+	default:
+		// Conn to Postgres
+		conn, err := postgres.ConnectPostgres(ctxDB, cfg.Postgres, cfg.Env)
+		if err != nil {
+			log.Error("init repository: " + err.Error())
+			os.Exit(1)
+		}
+		defer func() {
+			conn.Close()
+			log.Info("connection with database closed")
+		}()
+
+		// Migrations
+		migrator, err := postgres.NewMigrator(conn, migrations.FS, log)
+		if err != nil {
+			log.Error("init migrator", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer migrator.Close()
+
+		if prepareSchema(ctxApp, cfg, log, migrator) {
+			return
+		}
+
+		pgUserRepo := postgres.NewUserRepositoryPostgres(conn)
+		pgTeamRepo := postgres.NewTeamRepositoryPostgres(conn, pgUserRepo)
+		userRepo = pgUserRepo
+		teamRepo = pgTeamRepo
+		prRepo = postgres.NewPullRequestRepositoryPostgres(conn, pgTeamRepo, pgUserRepo, cfg.Assignment.MaxOpenReviews)
+		idemRepo = postgres.NewIdempotencyRepositoryPostgres(conn)
+		availRepo = postgres.NewAvailabilityRepositoryPostgres(conn)
+
+		checkers = append(checkers,
+			postgres.NewPingChecker(conn),
+			postgres.NewSchemaChecker(conn, migrator.Latest()),
+		)
+	}
+	log.Info("repository's lay initialized", slog.String("storage", cfg.Storage))
 
 	// Service lay
 	teamService := team2.NewTeamService(teamRepo)
-	userService := user2.NewUserService(userRepo)
-	prService := pull_request2.NewPullRequestService(prRepo)
+	userService := user2.NewUserService(userRepo, availRepo, cfg.SLA.Review)
+	broker := events.NewBroker(cfg.Events.HistorySize, cfg.Events.BufferSize)
+	prService := pull_request2.NewPullRequestService(prRepo, broker, ratelimit.New(cfg.RateLimit.ReassignPerPR))
+	availabilityService := availability.NewAvailabilityService(availRepo, teamRepo)
+	absenceScheduler := availability.NewScheduler(log, availRepo, prService)
 	log.Info("service's lay initialized")
 
+	// подкоманда import <file>: выполняется вместо запуска сервера
+	if isImportCommand() {
+		if err := runImport(ctxApp, teamService, pflag.Args()[1:]); err != nil {
+			log.Error("import", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Controller's lay
 	teamHandler := team.NewTeamHandler(teamService)
 	userHandler := user.NewUserHandler(userService)
 	prHandler := pull_request.NewPullRequestHandler(prService)
+	availabilityHandler := availability2.NewAvailabilityHandler(availabilityService)
+	eventsHandler := events2.NewEventsHandler(broker, teamService, cfg.Events.Heartbeat)
+	graphqlHandler := graph.NewHandler(log, teamService, userService, prService)
+	adminHandler := admin.NewAdminHandler(teamService)
+	exportHandler := export.NewExportHandler(prService)
 	log.Info("courier handler initialized")
 
+	// Health
+	probe := health.NewProbe(cfg.Health.CheckTimeout, checkers...)
+	healthHandler := health2.NewHealthHandler(probe)
+
 	// ROUTER & SERVER
-	r := server.InitRouter(log, teamHandler, userHandler, prHandler)
+	go purgeIdempotencyKeys(ctxApp, log, idemRepo, cfg.Idempotency.CleanupInterval)
+	go absenceScheduler.Run(ctxApp, cfg.Availability.SchedulerInterval)
+	idempotency := middleware.WithIdempotency(log, idemRepo, cfg.Idempotency.TTL)
+	routeLimiter := ratelimit.NewRouteLimiter(cfg.RateLimit.Default, cfg.RateLimit.Routes)
+	rateLimit := middleware.WithRateLimit(log, routeLimiter, cfg.RateLimit.PrincipalHeader)
+
+	r := server.InitRouter(log, teamHandler, userHandler, prHandler, availabilityHandler, healthHandler, eventsHandler, graphqlHandler, adminHandler, exportHandler, idempotency, rateLimit)
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.HTTP.Port,
 		Handler: r,
 		BaseContext: func(net.Listener) context.Context {
-			return ctxApp
+			return ctxServer
 		},
 		ReadTimeout:  cfg.HTTP.ReadTimeout,
 		WriteTimeout: cfg.HTTP.WriteTimeout,
 		IdleTimeout:  cfg.HTTP.ShutdownTimeout,
 	}
+	// Shutdown ждет, пока соединения освободятся, а SSE сами не заканчиваются: закрываем подписки в начале остановки
+	srv.RegisterOnShutdown(broker.Close)
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Error("server start up: %s", err.Error())
+			log.Error("server start up", slog.String("error", err.Error()))
 		}
 	}()
 	log.Info("listening on: " + cfg.HTTP.Port)
 
-	gracefulShutdownServer(ctxApp, cfg.HTTP, log, srv, cancelDB)
+	// GRPC
+	grpcSrv, grpcHealth := grpcserver.NewServer(log, teamService, userService, prService)
+	grpcLis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+	if err != nil {
+		log.Error("grpc listen", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	go func() {
+		if err := grpcSrv.Serve(grpcLis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Error("grpc server start up", slog.String("error", err.Error()))
+		}
+	}()
+	log.Info("grpc listening on: " + cfg.GRPC.Port)
+
+	gracefulShutdownServer(ctxApp, cfg, log, srv, grpcSrv, grpcHealth, probe, cancelServer, cancelDB)
 }
 
-func gracefulShutdownServer(ctxApp context.Context, cfg config.HTTPServer, log *slog.Logger, srv *http.Server, cancelDB context.CancelFunc) {
+func gracefulShutdownServer(ctxApp context.Context, cfg config.Config, log *slog.Logger, srv *http.Server, grpcSrv *grpc.Server, grpcHealth *grpchealth.Server, probe *health.Probe, cancelServer, cancelDB context.CancelFunc) {
 	<-ctxApp.Done()
 	log.Info("shutdown signal received. starting graceful shutdown")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	// сначала /readyz и grpc.health.v1 начинают отвечать "не готов", и только потом серверы перестают принимать соединения
+	probe.SetShuttingDown()
+	grpcHealth.Shutdown()
+	log.Info("readiness set to failing, draining", slog.Duration("delay", cfg.Health.DrainDelay))
+	time.Sleep(cfg.Health.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
 
+	// GracefulStop не принимает context, поэтому по таймауту оставшиеся RPC обрываются через Stop
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Error("server shutdown: %s", err.Error())
+		log.Error("server shutdown", slog.String("error", err.Error()))
 	} else {
 		log.Info("server gracefully stopped")
 	}
 
+	select {
+	case <-grpcStopped:
+		log.Info("grpc server gracefully stopped")
+	case <-shutdownCtx.Done():
+		grpcSrv.Stop()
+		log.Error("grpc server shutdown", slog.String("error", shutdownCtx.Err().Error()))
+	}
+
+	// если Shutdown вышел по таймауту, оставшиеся запросы получают отмененный контекст
+	cancelServer()
 	cancelDB()
 }
+
+type idempotencyStore interface {
+	middleware.IdempotencyStore
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// purgeIdempotencyKeys периодически удаляет истекшие Idempotency-Key, пока не придет сигнал остановки
+func purgeIdempotencyKeys(ctx context.Context, log *slog.Logger, store idempotencyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				log.Error("purge idempotency keys", slog.String("error", err.Error()))
+				continue
+			}
+			if deleted > 0 {
+				log.Info("expired idempotency keys purged", slog.Int64("deleted", deleted))
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/pressly/goose/v3"
+	"github.com/spf13/pflag"
+	"log/slog"
+	"os"
+	"service-order-avito/internal/config"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up|down|status|version"
+
+// isMigrateCommand сервис запущен как `migrate ...`, а не как сервер
+func isMigrateCommand() bool {
+	args := pflag.Args()
+	return len(args) > 0 && args[0] == "migrate"
+}
+
+// schemaMigrator общий интерфейс postgres.Migrator и sqlite.Migrator
+type schemaMigrator interface {
+	Latest() int64
+	Up(ctx context.Context) ([]*goose.MigrationResult, error)
+	Down(ctx context.Context) (*goose.MigrationResult, error)
+	Status(ctx context.Context) ([]*goose.MigrationStatus, error)
+	Version(ctx context.Context) (int64, error)
+	CheckVersion(ctx context.Context) error
+}
+
+// prepareSchema выполняет подкоманду migrate либо накатывает (--auto-migrate) и проверяет схему перед стартом.
+// Возвращает true, если была выполнена подкоманда и сервер запускать не нужно
+func prepareSchema(ctx context.Context, cfg config.Config, log *slog.Logger, migrator schemaMigrator) bool {
+	// подкоманда migrate up|down|status|version: выполняется вместо запуска сервера
+	if isMigrateCommand() {
+		if err := runMigrate(ctx, migrator, pflag.Args()[1:]); err != nil {
+			log.Error("migrate", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		return true
+	}
+
+	if cfg.AutoMigrate {
+		if _, err := migrator.Up(ctx); err != nil {
+			log.Error("auto migrate", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		log.Info("migrations applied")
+	}
+
+	// со схемой старее встроенных миграций сервис не стартует
+	if err := migrator.CheckVersion(ctx); err != nil {
+		log.Error("schema check: run `migrate up` or start with --auto-migrate", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	return false
+}
+
+func runMigrate(ctx context.Context, migrator schemaMigrator, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		results, err := migrator.Up(ctx)
+		for _, r := range results {
+			fmt.Printf("OK   %s (%s)\n", r.Source.Path, r.Duration.Round(time.Millisecond))
+		}
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("no migrations to apply")
+		}
+	case "down":
+		r, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("OK   %s (%s)\n", r.Source.Path, r.Duration.Round(time.Millisecond))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "APPLIED AT\tSTATE\tMIGRATION")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", appliedAt, s.State, s.Source.Path)
+		}
+		return w.Flush()
+	case "version":
+		current, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current: %d\nexpected: %d\n", current, migrator.Latest())
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", args[0], migrateUsage)
+	}
+
+	return nil
+}
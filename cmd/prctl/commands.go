@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-order-avito/pkg/client"
+
+	"github.com/spf13/pflag"
+)
+
+var commands = []command{
+	{
+		name:  "users list",
+		args:  "--team <team>",
+		about: "list team members",
+		flags: func(fs *pflag.FlagSet) { fs.String("team", "", "team name") },
+		run:   usersList,
+	},
+	{
+		name:  "users add",
+		args:  "<user_id> --team <team> --name <username> [--inactive] [--dry-run]",
+		about: "add a user to a team or update an existing one (moves the user if the team differs)",
+		flags: func(fs *pflag.FlagSet) {
+			fs.String("team", "", "team name, created if missing")
+			fs.String("name", "", "username")
+			fs.Bool("inactive", false, "add the user as inactive")
+			fs.Bool("dry-run", false, "only show what would change")
+		},
+		run: usersAdd,
+	},
+	{
+		name:  "users deactivate",
+		args:  "<user_id>",
+		about: "stop assigning the user as a reviewer",
+		run:   func(ctx context.Context, e *env, args []string) error { return usersSetActive(ctx, e, args, false) },
+	},
+	{
+		name:  "users activate",
+		args:  "<user_id>",
+		about: "assign the user as a reviewer again",
+		run:   func(ctx context.Context, e *env, args []string) error { return usersSetActive(ctx, e, args, true) },
+	},
+	{
+		name:  "users reviews",
+		args:  "<user_id>",
+		about: "pull requests the user reviews",
+		run:   usersReviews,
+	},
+	{
+		name:  "team stats",
+		args:  "<team>",
+		about: "users and pull requests of a team",
+		run:   teamStats,
+	},
+	{
+		name:  "team workload",
+		args:  "<team>",
+		about: "open reviews per team member",
+		run:   teamWorkload,
+	},
+	{
+		name:  "pr create",
+		args:  "<pull_request_id> --name <name> --author <user_id>",
+		about: "create a pull request, reviewers are assigned by the service",
+		flags: func(fs *pflag.FlagSet) {
+			fs.String("name", "", "pull request name")
+			fs.String("author", "", "author user_id")
+		},
+		run: prCreate,
+	},
+	{
+		name:  "pr get",
+		args:  "<pull_request_id>",
+		about: "show a pull request",
+		run: func(ctx context.Context, e *env, args []string) error {
+			return prShow(ctx, e, args, e.client.GetPullRequest)
+		},
+	},
+	{
+		name:  "pr merge",
+		args:  "<pull_request_id>",
+		about: "merge a pull request (repeating is safe)",
+		run: func(ctx context.Context, e *env, args []string) error {
+			return prShow(ctx, e, args, func(ctx context.Context, id string) (*client.PullRequest, error) {
+				return e.client.MergePullRequest(ctx, id)
+			})
+		},
+	},
+	{
+		name:  "pr close",
+		args:  "<pull_request_id>",
+		about: "close a pull request without merging (repeating is safe)",
+		run: func(ctx context.Context, e *env, args []string) error {
+			return prShow(ctx, e, args, func(ctx context.Context, id string) (*client.PullRequest, error) {
+				return e.client.ClosePullRequest(ctx, id)
+			})
+		},
+	},
+	{
+		name:  "pr reassign",
+		args:  "<pull_request_id> --old <user_id>",
+		about: "replace a reviewer with another active teammate",
+		flags: func(fs *pflag.FlagSet) { fs.String("old", "", "reviewer to replace") },
+		run:   prReassign,
+	},
+}
+
+// requiredString значение обязательного строкового флага команды
+func requiredString(e *env, name string) (string, error) {
+	v, _ := e.fs.GetString(name)
+	if v == "" {
+		return "", fmt.Errorf("%w: --%s is required", errUsage, name)
+	}
+	return v, nil
+}
+
+// oneArg ровно один позиционный аргумент
+func oneArg(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage
+	}
+	return args[0], nil
+}
+
+func usersList(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	teamName, err := requiredString(e, "team")
+	if err != nil {
+		return err
+	}
+
+	team, err := e.client.GetTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	users := make([]client.User, len(team.Members))
+	rows := make([][]string, len(team.Members))
+	for i, m := range team.Members {
+		users[i] = client.User{UserID: m.UserID, Username: m.Username, TeamName: team.TeamName, IsActive: m.IsActive}
+		rows[i] = []string{m.UserID, m.Username, team.TeamName, strconv.FormatBool(m.IsActive)}
+	}
+	return e.out.print(users, []string{"USER_ID", "USERNAME", "TEAM", "ACTIVE"}, rows)
+}
+
+// usersAdd отдельной ручки добавления пользователя нет: /team/add работает только для новой команды,
+// поэтому пользователь добавляется импортом из одной команды с одним участником
+func usersAdd(ctx context.Context, e *env, args []string) error {
+	userID, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+	teamName, err := requiredString(e, "team")
+	if err != nil {
+		return err
+	}
+	username, err := requiredString(e, "name")
+	if err != nil {
+		return err
+	}
+	inactive, _ := e.fs.GetBool("inactive")
+	dryRun, _ := e.fs.GetBool("dry-run")
+
+	report, err := e.client.Import(ctx, []client.Team{{
+		TeamName: teamName,
+		Members:  []client.TeamMember{{UserID: userID, Username: username, IsActive: !inactive}},
+	}}, dryRun)
+	if err != nil {
+		return err
+	}
+
+	var change string
+	switch {
+	case len(report.UsersCreated) > 0:
+		change = "created"
+	case len(report.UsersMoved) > 0:
+		change = "moved from " + report.UsersMoved[0].FromTeam
+	case len(report.UsersUpdated) > 0:
+		fields := make([]string, len(report.UsersUpdated[0].Fields))
+		for i, f := range report.UsersUpdated[0].Fields {
+			fields[i] = string(f)
+		}
+		change = "updated " + strings.Join(fields, ", ")
+	default:
+		change = "unchanged"
+	}
+	if len(report.TeamsCreated) > 0 {
+		change += ", team created"
+	}
+	if dryRun {
+		change += " (dry run)"
+	}
+	return e.out.print(report, []string{"USER_ID", "TEAM", "CHANGE"}, [][]string{{userID, teamName, change}})
+}
+
+func usersSetActive(ctx context.Context, e *env, args []string, isActive bool) error {
+	userID, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	user, err := e.client.SetIsActive(ctx, userID, isActive)
+	if err != nil {
+		return err
+	}
+	return e.out.print(user, []string{"USER_ID", "USERNAME", "TEAM", "ACTIVE"},
+		[][]string{{user.UserID, user.Username, user.TeamName, strconv.FormatBool(user.IsActive)}})
+}
+
+func usersReviews(ctx context.Context, e *env, args []string) error {
+	userID, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	prs, err := e.client.GetUserReviews(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(prs))
+	for i, pr := range prs {
+		rows[i] = []string{pr.PullRequestID, pr.PullRequestName, pr.AuthorID, string(pr.Status)}
+	}
+	return e.out.print(prs, []string{"PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS"}, rows)
+}
+
+func teamStats(ctx context.Context, e *env, args []string) error {
+	teamName, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	stats, err := e.client.GetTeamStats(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	return e.out.print(stats, []string{"TEAM", "ACTIVE_USERS", "INACTIVE_USERS", "OPEN_PRS", "MERGED_PRS"},
+		[][]string{{
+			stats.TeamName,
+			strconv.Itoa(stats.ActiveUsers),
+			strconv.Itoa(stats.InactiveUsers),
+			strconv.Itoa(stats.OpenPRs),
+			strconv.Itoa(stats.MergedPRs),
+		}})
+}
+
+// memberWorkload строка team workload
+type memberWorkload struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	IsActive    bool   `json:"is_active"`
+	OpenReviews int    `json:"open_reviews"`
+}
+
+// teamWorkload открытые ревью по участникам: очередь каждого запрашивается отдельно
+func teamWorkload(ctx context.Context, e *env, args []string) error {
+	teamName, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	team, err := e.client.GetTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	workload := make([]memberWorkload, len(team.Members))
+	rows := make([][]string, len(team.Members))
+	for i, m := range team.Members {
+		prs, err := e.client.GetUserReviews(ctx, m.UserID)
+		if err != nil {
+			return err
+		}
+
+		open := 0
+		for _, pr := range prs {
+			if pr.Status == client.PullRequestStatusOpen {
+				open++
+			}
+		}
+		workload[i] = memberWorkload{UserID: m.UserID, Username: m.Username, IsActive: m.IsActive, OpenReviews: open}
+		rows[i] = []string{m.UserID, m.Username, strconv.FormatBool(m.IsActive), strconv.Itoa(open)}
+	}
+	return e.out.print(workload, []string{"USER_ID", "USERNAME", "ACTIVE", "OPEN_REVIEWS"}, rows)
+}
+
+func prCreate(ctx context.Context, e *env, args []string) error {
+	prID, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+	name, err := requiredString(e, "name")
+	if err != nil {
+		return err
+	}
+	author, err := requiredString(e, "author")
+	if err != nil {
+		return err
+	}
+
+	pr, err := e.client.CreatePullRequest(ctx, client.CreatePullRequestRequest{
+		PullRequestID:   prID,
+		PullRequestName: name,
+		AuthorID:        author,
+	})
+	if err != nil {
+		return err
+	}
+	return printPullRequest(e, pr)
+}
+
+func prShow(ctx context.Context, e *env, args []string, fetch func(context.Context, string) (*client.PullRequest, error)) error {
+	prID, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	pr, err := fetch(ctx, prID)
+	if err != nil {
+		return err
+	}
+	return printPullRequest(e, pr)
+}
+
+func prReassign(ctx context.Context, e *env, args []string) error {
+	prID, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+	oldUserID, err := requiredString(e, "old")
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.ReassignReviewer(ctx, prID, oldUserID)
+	if err != nil {
+		return err
+	}
+	return e.out.print(resp, []string{"PULL_REQUEST_ID", "OLD_USER_ID", "REPLACED_BY"}, [][]string{{prID, oldUserID, resp.ReplacedBy}})
+}
+
+func printPullRequest(e *env, pr *client.PullRequest) error {
+	mergedAt := ""
+	if pr.MergedAt != nil {
+		mergedAt = pr.MergedAt.Format(time.RFC3339)
+	}
+	return e.out.print(pr, []string{"PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS", "MERGED_AT", "VERSION"},
+		[][]string{{
+			pr.PullRequestID,
+			pr.PullRequestName,
+			pr.AuthorID,
+			string(pr.Status),
+			strings.Join(pr.AssignedReviewers, ","),
+			mergedAt,
+			strconv.FormatInt(pr.Version, 10),
+		}})
+}
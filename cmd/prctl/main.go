@@ -0,0 +1,139 @@
+// prctl консольный клиент PR-Manager: ходит в HTTP API через pkg/client
+//
+//	prctl <группа> <действие> [аргументы] [флаги]
+//	prctl users list --team backend -o json
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"service-order-avito/pkg/client"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// errUsage неверные аргументы: печатается справка команды, код выхода 2
+var errUsage = errors.New("usage")
+
+// command одна подкоманда. flags регистрирует ее флаги рядом с общими, run получает позиционные аргументы
+type command struct {
+	name  string
+	args  string
+	about string
+	flags func(fs *pflag.FlagSet)
+	run   func(ctx context.Context, e *env, args []string) error
+}
+
+// env общее для всех команд: клиент, вывод и разобранные флаги
+type env struct {
+	client *client.Client
+	out    *printer
+	fs     *pflag.FlagSet
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) < 2 || strings.HasPrefix(args[0], "-") || strings.HasPrefix(args[1], "-") {
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	cmd, ok := findCommand(args[0] + " " + args[1])
+	if !ok {
+		fmt.Fprintf(stderr, "prctl: unknown command %q\n\n", args[0]+" "+args[1])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	fs := pflag.NewFlagSet("prctl "+cmd.name, pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+	server := fs.String("server", envOr("PRCTL_SERVER", "http://localhost:8080"), "service address (env PRCTL_SERVER)")
+	output := fs.StringP("output", "o", "table", "output format: table or json")
+	timeout := fs.Duration("timeout", 10*time.Second, "request timeout")
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: prctl %s %s\n%s\n\nflags:\n%s", cmd.name, cmd.args, cmd.about, fs.FlagUsages())
+	}
+
+	if err := fs.Parse(args[2:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if *output != formatTable && *output != formatJSON {
+		fmt.Fprintf(stderr, "prctl: unknown output format %q, expected table or json\n", *output)
+		return exitUsage
+	}
+
+	e := &env{
+		client: client.New(*server, client.WithTimeout(*timeout)),
+		out:    &printer{w: stdout, format: *output},
+		fs:     fs,
+	}
+	if err := cmd.run(ctx, e, fs.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			if err != errUsage {
+				fmt.Fprintln(stderr, "prctl:", err)
+			}
+			fs.Usage()
+			return exitUsage
+		}
+		fmt.Fprintln(stderr, "prctl:", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: prctl <group> <action> [args] [flags]")
+	fmt.Fprintln(w, "\ncommands:")
+
+	sorted := append([]command{}, commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range sorted {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", c.name, c.args, c.about)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\ncommon flags: --server (env PRCTL_SERVER), -o/--output table|json, --timeout")
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) string {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/teams/backend", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"team_name": "backend", "members": [
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": false}
+		]}`))
+	})
+	mux.HandleFunc("GET /api/v1/users/{id}/reviews", func(w http.ResponseWriter, r *http.Request) {
+		prs := `[]`
+		if r.PathValue("id") == "u1" {
+			prs = `[{"pull_request_id": "pr1", "status": "OPEN"}, {"pull_request_id": "pr2", "status": "MERGED"}]`
+		}
+		w.Write([]byte(`{"user_id": "` + r.PathValue("id") + `", "pull_requests": ` + prs + `}`))
+	})
+	mux.HandleFunc("POST /api/v1/pull-requests/{id}/merge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"title": "resource not found", "status": 404, "code": "NOT_FOUND"}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func runPrctl(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = run(context.Background(), args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRun_Table(t *testing.T) {
+	server := newServer(t)
+
+	code, stdout, _ := runPrctl(t, "team", "workload", "backend", "--server", server)
+
+	require.Equal(t, exitOK, code)
+	assert.Equal(t,
+		"USER_ID  USERNAME  ACTIVE  OPEN_REVIEWS\n"+
+			"u1       Alice     true    1\n"+
+			"u2       Bob       false   0\n",
+		stdout)
+}
+
+func TestRun_JSON(t *testing.T) {
+	server := newServer(t)
+
+	code, stdout, _ := runPrctl(t, "users", "list", "--team=backend", "-o", "json", "--server", server)
+
+	require.Equal(t, exitOK, code)
+	var users []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout), &users))
+	require.Len(t, users, 2)
+	assert.Equal(t, "backend", users[1]["team_name"])
+	assert.Equal(t, false, users[1]["is_active"])
+}
+
+func TestRun_Errors(t *testing.T) {
+	server := newServer(t)
+
+	t.Run("api error", func(t *testing.T) {
+		code, _, stderr := runPrctl(t, "pr", "merge", "pr9", "--server", server)
+		assert.Equal(t, exitError, code)
+		assert.Equal(t, "prctl: NOT_FOUND: resource not found\n", stderr)
+	})
+
+	t.Run("missing flag", func(t *testing.T) {
+		code, _, stderr := runPrctl(t, "pr", "create", "pr1", "--name", "Fix")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "--author is required")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		code, _, stderr := runPrctl(t, "users", "delete", "u1")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, `unknown command "users delete"`)
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		code, _, _ := runPrctl(t, "team", "stats", "backend", "-o", "yaml")
+		assert.Equal(t, exitUsage, code)
+	})
+}
@@ -7,13 +7,89 @@ import (
 	"github.com/spf13/pflag"
 	"log"
 	"os"
+	"service-order-avito/internal/ratelimit"
+	"strings"
 	"time"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+)
+
 type Config struct {
-	Env      string          `env:"ENVIRONMENT"` //local, dev, prod
-	Postgres PostgresStorage `envPrefix:"POSTGRES_"`
-	HTTP     HTTPServer      `envPrefix:"HTTP_"`
+	Env          string          `env:"ENVIRONMENT"` //local, dev, prod
+	Storage      string          `env:"STORAGE" envDefault:"postgres"`
+	AutoMigrate  bool            `env:"AUTO_MIGRATE" envDefault:"false"`
+	Postgres     PostgresStorage `envPrefix:"POSTGRES_"`
+	SQLite       SQLiteStorage   `envPrefix:"SQLITE_"`
+	HTTP         HTTPServer      `envPrefix:"HTTP_"`
+	GRPC         GRPCServer      `envPrefix:"GRPC_"`
+	Health       Health          `envPrefix:"HEALTH_"`
+	Idempotency  Idempotency     `envPrefix:"IDEMPOTENCY_"`
+	Events       Events          `envPrefix:"EVENTS_"`
+	RateLimit    RateLimit       `envPrefix:"RATE_LIMIT_"`
+	Availability Availability    `envPrefix:"AVAILABILITY_"`
+	SLA          SLA             `envPrefix:"SLA_"`
+	Assignment   Assignment      `envPrefix:"ASSIGNMENT_"`
+}
+
+// Assignment выбор ревьюеров при создании PR и замене
+type Assignment struct {
+	// MaxOpenReviews сколько открытых PR может ревьюить один пользователь, 0 — без ограничения.
+	// Набравший столько не назначается, пока какой-нибудь из его PR не будет смержен
+	MaxOpenReviews int `env:"MAX_OPEN_REVIEWS" envDefault:"0"`
+}
+
+// SLA сроки в рабочем времени пользователя (его расписание или 09:00–18:00 UTC по будням)
+type SLA struct {
+	// Review сколько рабочего времени ревьюера дается на ревью с его назначения на PR
+	Review time.Duration `env:"REVIEW" envDefault:"16h"`
+}
+
+// Availability периоды отсутствия пользователей (отпуск, больничный, неполная занятость)
+type Availability struct {
+	// SchedulerInterval как часто ревьюеры, у которых началось отсутствие, снимаются с открытых PR
+	SchedulerInterval time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1m"`
+}
+
+// RateLimit лимиты вида "10/s", "600/m", "5/h" или "off" (см. internal/ratelimit).
+// Счетчики в памяти процесса: при нескольких репликах лимит действует на каждую отдельно
+type RateLimit struct {
+	// Default лимит клиента на каждый маршрут, у которого нет своего в Routes
+	Default ratelimit.Limit `env:"DEFAULT" envDefault:"20/s"`
+	// Routes свои лимиты маршрутов: "POST /pullRequest/reassign=30/m;GET /export/reviews=10/m"
+	Routes ratelimit.Routes `env:"ROUTES" envDefault:"POST /pullRequest/reassign=30/m;POST /api/v1/pull-requests/{id}/reassign=30/m"`
+	// PrincipalHeader заголовок с идентификатором клиента от шлюза. Без него (или пустой) клиент — IP
+	PrincipalHeader string `env:"PRINCIPAL_HEADER"`
+	// ReassignPerPR сколько раз можно заменить ревьюера одного PR, кто бы ни заменял (HTTP, gRPC, GraphQL)
+	ReassignPerPR ratelimit.Limit `env:"REASSIGN_PER_PR" envDefault:"5/h"`
+}
+
+// Events поток GET /events/stream
+type Events struct {
+	// HistorySize сколько последних событий хранится для дочитывания по Last-Event-ID
+	HistorySize int `env:"HISTORY_SIZE" envDefault:"1000"`
+	// BufferSize очередь одного подписчика: если клиент отстал больше, его соединение закрывается
+	BufferSize int `env:"BUFFER_SIZE" envDefault:"64"`
+	// Heartbeat как часто слать комментарий в пустой поток, чтобы прокси не закрывали соединение
+	Heartbeat time.Duration `env:"HEARTBEAT" envDefault:"15s"`
+}
+
+type Idempotency struct {
+	// TTL сколько хранится ответ на запрос с Idempotency-Key
+	TTL time.Duration `env:"TTL" envDefault:"24h"`
+	// CleanupInterval как часто удаляются истекшие ключи
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+type Health struct {
+	// CheckTimeout ограничивает все проверки /readyz (ping бд, версия схемы)
+	CheckTimeout time.Duration `env:"CHECK_TIMEOUT" envDefault:"2s"`
+	// DrainDelay сколько ждать между переводом /readyz в 503 и остановкой сервера,
+	// чтобы балансировщик успел перестать слать запросы
+	DrainDelay time.Duration `env:"DRAIN_DELAY" envDefault:"5s"`
 }
 
 type HTTPServer struct {
@@ -24,25 +100,45 @@ type HTTPServer struct {
 	IdleTimeout     time.Duration `env:"IDLE_TIMEOUT" envDefault:"60s"`
 }
 
+// GRPCServer gRPC API на отдельном порту, останавливается вместе с HTTP (HTTP_SHUTDOWN_TIMEOUT)
+type GRPCServer struct {
+	Port string `env:"PORT" envDefault:"9090"`
+}
+
+// PostgresStorage обязателен только при STORAGE=postgres, проверяется в validate
 type PostgresStorage struct {
-	User            string        `env:"USER,required"`
-	Password        string        `env:"PASSWORD,required"`
-	Host            string        `env:"HOST,required"`
-	Port            string        `env:"PORT,required"`
-	DbName          string        `env:"DB_NAME,required"`
-	MaxConns        int32         `env:"MAX_CONNS,required"`
-	MinConns        int32         `env:"MIN_CONNS,required"`
-	MaxConnLifeTime time.Duration `env:"MAX_CONN_LIFE_TIME,required"`
+	User            string        `env:"USER"`
+	Password        string        `env:"PASSWORD"`
+	Host            string        `env:"HOST"`
+	Port            string        `env:"PORT"`
+	DbName          string        `env:"DB_NAME"`
+	MaxConns        int32         `env:"MAX_CONNS"`
+	MinConns        int32         `env:"MIN_CONNS"`
+	MaxConnLifeTime time.Duration `env:"MAX_CONN_LIFE_TIME"`
+}
+
+// SQLiteStorage файл бд для STORAGE=sqlite (один узел, без отдельного сервера бд)
+type SQLiteStorage struct {
+	Path string `env:"PATH" envDefault:"pr-manager.db"`
+	// BusyTimeout сколько транзакция ждет блокировку на запись, прежде чем вернуть ошибку
+	BusyTimeout time.Duration `env:"BUSY_TIMEOUT" envDefault:"5s"`
 }
 
 func MustLoad() Config {
 
 	environment := os.Getenv("ENVIRONMENT")
 	var httpPort string
-	if environment == "" {
-		pflag.StringVar(&environment, "env", environment, "environment (local, prod)")
-		pflag.StringVar(&httpPort, "port", httpPort, "server's port")
-		pflag.Parse()
+	var autoMigrate bool
+	var storage string
+	// флаги парсятся всегда, чтобы работали подкоманды (pflag.Args) и --auto-migrate в prod,
+	// но ENVIRONMENT из окружения по-прежнему важнее --env
+	pflag.StringVar(&environment, "env", environment, "environment (local, prod)")
+	pflag.StringVar(&httpPort, "port", httpPort, "server's port")
+	pflag.BoolVar(&autoMigrate, "auto-migrate", false, "apply embedded migrations on startup")
+	pflag.StringVar(&storage, "storage", "", "storage backend (postgres, sqlite, memory)")
+	pflag.Parse()
+	if env := os.Getenv("ENVIRONMENT"); env != "" {
+		environment = env
 	}
 	fmt.Printf(".%s.\n", environment)
 	if environment != "prod" {
@@ -64,5 +160,60 @@ func MustLoad() Config {
 		config.HTTP.Port = httpPort
 	}
 
+	if pflag.CommandLine.Changed("auto-migrate") {
+		config.AutoMigrate = autoMigrate
+	}
+
+	if storage != "" {
+		config.Storage = storage
+	}
+
+	if err := config.validate(); err != nil {
+		log.Fatalf("invalid config: \n%s", err.Error())
+	}
+
 	return config
 }
+
+func (c Config) validate() error {
+	if c.Assignment.MaxOpenReviews < 0 {
+		return fmt.Errorf("ASSIGNMENT_MAX_OPEN_REVIEWS must not be negative, got %d", c.Assignment.MaxOpenReviews)
+	}
+
+	switch c.Storage {
+	case StorageMemory:
+		return nil
+	case StorageSQLite:
+		if c.SQLite.Path == "" {
+			return fmt.Errorf("storage %s requires: SQLITE_PATH", StorageSQLite)
+		}
+		return nil
+	case StoragePostgres:
+	default:
+		return fmt.Errorf("unknown storage %q, expected %s, %s or %s", c.Storage, StoragePostgres, StorageSQLite, StorageMemory)
+	}
+
+	p := c.Postgres
+	required := []struct {
+		name  string
+		empty bool
+	}{
+		{"POSTGRES_USER", p.User == ""},
+		{"POSTGRES_PASSWORD", p.Password == ""},
+		{"POSTGRES_HOST", p.Host == ""},
+		{"POSTGRES_PORT", p.Port == ""},
+		{"POSTGRES_DB_NAME", p.DbName == ""},
+		{"POSTGRES_MAX_CONNS", p.MaxConns == 0},
+		{"POSTGRES_MAX_CONN_LIFE_TIME", p.MaxConnLifeTime == 0},
+	}
+	var missing []string
+	for _, r := range required {
+		if r.empty {
+			missing = append(missing, r.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("storage %s requires: %s", StoragePostgres, strings.Join(missing, ", "))
+	}
+	return nil
+}
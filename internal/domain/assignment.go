@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"math/rand"
+	"time"
+)
+
+// AssignmentKind откуда выбор ревьюеров: создание PR или замена одного ревьюера
+type AssignmentKind string
+
+const (
+	AssignmentCreate   AssignmentKind = "CREATE"
+	AssignmentReassign AssignmentKind = "REASSIGN"
+)
+
+// CandidateReason почему кандидат выбран или нет
+type CandidateReason string
+
+const (
+	// ReasonReviewerRules выбран, чтобы выполнить правила команды к уровням
+	ReasonReviewerRules CandidateReason = "REVIEWER_RULES"
+	// ReasonRequiredTags выбран, чтобы покрыть теги PR
+	ReasonRequiredTags CandidateReason = "REQUIRED_TAGS"
+	// ReasonRank выбран по месту в порядке: рабочее время, вес пар с автором, затем seed
+	ReasonRank CandidateReason = "RANK"
+	// ReasonOutranked не выбран: места заняли кандидаты выше по порядку или нужные правилам и тегам
+	ReasonOutranked CandidateReason = "OUTRANKED"
+)
+
+// CandidateExplanation кандидат в момент выбора: что о нем знал выбор и чем он закончился
+type CandidateExplanation struct {
+	UserID string
+	// Rank место в порядке выбора, с 1
+	Rank    int
+	Working bool
+	// PairingScore вес недавних ревью у автора, 0 при стратегии RANDOM
+	PairingScore float64
+	Level        Level
+	// Tags теги PR, которые кандидат покрывает
+	Tags   []string
+	Chosen bool
+	Reason CandidateReason
+}
+
+// Assignment запись о выборе ревьюеров. По Seed и тем же кандидатам выбор повторяется
+type Assignment struct {
+	ID            int64
+	PullRequestID string
+	Kind          AssignmentKind
+	// ReplacedUserID при замене — кого заменили
+	ReplacedUserID string
+	Seed           int64
+	Strategy       PairingStrategy
+	At             time.Time
+	// Candidates по Rank
+	Candidates []CandidateExplanation
+}
+
+// Reviewers выбранные кандидаты по Rank
+func (a Assignment) Reviewers() []string {
+	reviewers := []string{}
+	for _, c := range a.Candidates {
+		if c.Chosen {
+			reviewers = append(reviewers, c.UserID)
+		}
+	}
+	return reviewers
+}
+
+// NewRand генератор выбора ревьюеров. С тем же seed и тем же пулом Pick выбирает тех же
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
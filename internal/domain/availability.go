@@ -0,0 +1,92 @@
+package domain
+
+import "time"
+
+// Виды отсутствия. На выбор ревьюеров влияют одинаково, различаются для отчетов и планировщика
+const (
+	AbsenceVacation  = "VACATION"
+	AbsenceSickLeave = "SICK_LEAVE"
+	AbsencePartTime  = "PART_TIME"
+)
+
+// Absence период [Starts, Ends), когда пользователь не получает ревью, в отличие от is_active — с датами.
+// Weekdays не 0 — пользователь отсутствует только в эти дни недели периода (неполная занятость).
+// UID задан у периодов из импорта календаря: по нему повторный импорт обновляет период, а не создает новый
+type Absence struct {
+	ID       int64
+	UserID   string
+	Kind     string
+	Starts   time.Time
+	Ends     time.Time
+	Weekdays Weekdays
+	UID      string
+}
+
+// Weekdays битовая маска дней недели: бит 1<<time.Sunday, 1<<time.Monday, ...
+type Weekdays uint8
+
+func NewWeekdays(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, d := range days {
+		w |= 1 << d
+	}
+	return w
+}
+
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w&(1<<d) != 0
+}
+
+// Recurring отсутствие по дням недели: такие ревью планировщик не переназначает, пользователь вернется через день-два
+func (a Absence) Recurring() bool {
+	return a.Weekdays != 0
+}
+
+// Covers отсутствует ли пользователь в момент t. День недели берется в часовом поясе пользователя loc:
+// у PART_TIME по пятницам в Новосибирске пятница начинается в 17:00 UTC четверга
+func (a Absence) Covers(t time.Time, loc *time.Location) bool {
+	if t.Before(a.Starts) || !t.Before(a.Ends) {
+		return false
+	}
+	return !a.Recurring() || a.Weekdays.Has(t.In(loc).Weekday())
+}
+
+// SamePeriod совпадают ли вид, даты и дни недели — импорт календаря не трогает такой период
+func (a Absence) SamePeriod(b Absence) bool {
+	return a.Kind == b.Kind && a.Starts.Equal(b.Starts) && a.Ends.Equal(b.Ends) && a.Weekdays == b.Weekdays
+}
+
+// AbsentAt пользователи, которые отсутствуют в момент t хотя бы по одному из периодов.
+// Часовой пояс пользователя — из его расписания в hours, без расписания — UTC
+func AbsentAt(absences []Absence, hours map[string]WorkingHours, t time.Time) map[string]bool {
+	absent := make(map[string]bool)
+	for _, a := range absences {
+		if a.Covers(t, HoursOf(hours, a.UserID).Location()) {
+			absent[a.UserID] = true
+		}
+	}
+	return absent
+}
+
+// AbsentReview ревьюер открытого PR, у которого началось отсутствие: его планировщик заменяет
+type AbsentReview struct {
+	PullRequestID string
+	ReviewerID    string
+}
+
+// AbsenceImport изменения из календаря, применяются одной транзакцией.
+// Users — все пользователи импорта, должны существовать, даже если событий для них нет.
+// Upsert ищется по (UserID, UID), у Cancel важны только UserID и UID
+type AbsenceImport struct {
+	Users  []string
+	Upsert []Absence
+	Cancel []Absence
+}
+
+// AbsenceImportReport что сделал импорт: Unchanged — период из календаря уже сохранен с теми же датами
+type AbsenceImportReport struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Deleted   int
+}
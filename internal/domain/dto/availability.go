@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"fmt"
+	"service-order-avito/internal/domain"
+	"time"
+)
+
+var weekdayNames = [...]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// Period границы отсутствия [starts, ends). Дата без времени в ends_at включается целиком
+func (r *AddAbsenceRequest) Period() (starts, ends time.Time, err error) {
+	starts, _, err = ParseTimestamp(r.StartsAt)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	ends, dateOnly, err := ParseTimestamp(r.EndsAt)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if dateOnly {
+		ends = ends.AddDate(0, 0, 1)
+	}
+	return starts, ends, nil
+}
+
+// ParseWeekdays MON..SUN в маску. Неизвестные имена отсекает валидация
+func ParseWeekdays(names []string) domain.Weekdays {
+	var w domain.Weekdays
+	for _, name := range names {
+		for d, n := range weekdayNames {
+			if n == name {
+				w |= domain.NewWeekdays(time.Weekday(d))
+			}
+		}
+	}
+	return w
+}
+
+// FormatWeekdays маска в имена дней с понедельника, nil для периода без дней недели
+func FormatWeekdays(w domain.Weekdays) []string {
+	var names []string
+	for _, d := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		if w.Has(d) {
+			names = append(names, weekdayNames[d])
+		}
+	}
+	return names
+}
+
+// ParseClock HH:MM в минуты от полуночи. Формат проверяет тег clock
+func ParseClock(s string) int {
+	var h, m int
+	fmt.Sscanf(s, "%d:%d", &h, &m)
+	return h*60 + m
+}
+
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -1,44 +1,228 @@
 package dto
 
+import (
+	"service-order-avito/internal/domain"
+	"time"
+)
+
+// Ограничения в тегах validate совпадают с колонками в migrations: все идентификаторы и имена VARCHAR(255).
+// id — идентификатор из латиницы, цифр и символов ._:- (см. validation.go)
+
 type TeamAddRequest struct {
-	TeamName string              `json:"team_name"`
-	Members  []TeamMemberRequest `json:"members"`
+	TeamName string              `json:"team_name" validate:"required,max=255,printable"`
+	Members  []TeamMemberRequest `json:"members" validate:"unique=UserID,dive"`
 }
 
 type TeamMemberRequest struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
+	UserID   string `json:"user_id" validate:"required,max=255,id"`
+	Username string `json:"username" validate:"required,max=255,printable"`
 	IsActive bool   `json:"is_active"`
 }
 
 type GetTeamRequest struct {
-	TeamName string `json:"team_name" validate:"required"`
+	TeamName string `json:"team_name" validate:"required,max=255,printable"`
 }
 
 type SetIsActiveRequest struct {
-	UserID   string `json:"user_id"`
+	UserID   string `json:"user_id" validate:"required,max=255,id"`
 	IsActive bool   `json:"is_active"`
 }
 
+// SetSkillsRequest теги пользователя целиком, user_id из пути. Пустой список очищает теги, отсутствующее поле — ошибка
+type SetSkillsRequest struct {
+	UserID string   `json:"user_id" validate:"required,max=255,id"`
+	Skills []string `json:"skills" validate:"required,max=32,unique,dive,tag"`
+}
+
+// UpdateSkillsRequest изменение тегов без гонки чтение-запись на клиенте
+type UpdateSkillsRequest struct {
+	UserID string   `json:"user_id" validate:"required,max=255,id"`
+	Add    []string `json:"add" validate:"max=32,unique,dive,tag"`
+	Remove []string `json:"remove" validate:"max=32,unique,dive,tag"`
+}
+
+// SetLevelRequest уровень пользователя, user_id из пути
+type SetLevelRequest struct {
+	UserID string `json:"user_id" validate:"required,max=255,id"`
+	Level  string `json:"level" validate:"required,oneof=JUNIOR MIDDLE SENIOR LEAD"`
+}
+
+// ReviewerRuleRequest среди ревьюеров PR не меньше count с уровнем min_level или выше.
+// count не больше числа ревьюеров PR (2)
+type ReviewerRuleRequest struct {
+	MinLevel string `json:"min_level" validate:"required,oneof=JUNIOR MIDDLE SENIOR LEAD"`
+	Count    int    `json:"count" validate:"min=1,max=2"`
+}
+
+// SetReviewerRulesRequest правила команды целиком, team_name из пути. Пустой список снимает правила
+type SetReviewerRulesRequest struct {
+	TeamName string                `json:"team_name" validate:"required,max=255,printable"`
+	Rules    []ReviewerRuleRequest `json:"rules" validate:"required,max=4,unique=MinLevel,dive"`
+}
+
+type GetReviewerRulesRequest struct {
+	TeamName string `json:"team_name" validate:"required,max=255,printable"`
+}
+
+// SetPairingSettingsRequest стратегия выбора ревьюеров команды, team_name из пути.
+// decay_days не задан — окно по умолчанию (30 дней)
+type SetPairingSettingsRequest struct {
+	TeamName  string `json:"team_name" validate:"required,max=255,printable"`
+	Strategy  string `json:"strategy" validate:"required,oneof=RANDOM PAIRING_DIVERSITY"`
+	DecayDays int    `json:"decay_days" validate:"omitempty,min=1,max=365"`
+}
+
+type GetPairingSettingsRequest struct {
+	TeamName string `json:"team_name" validate:"required,max=255,printable"`
+}
+
+type GetPairingMatrixRequest struct {
+	TeamName string `json:"team_name" validate:"required,max=255,printable"`
+}
+
 type PullRequestCreateRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string `json:"pull_request_id" validate:"required,max=255,id"`
+	PullRequestName string `json:"pull_request_name" validate:"required,max=255,printable"`
+	AuthorID        string `json:"author_id" validate:"required,max=255,id"`
+	// RequiredTags каждый тег должен быть хотя бы у одного ревьюера, если в команде такой есть
+	RequiredTags []string `json:"required_tags,omitempty" validate:"max=10,unique,dive,tag"`
+}
+
+// PreviewReviewersRequest те же поля, что выбирают ревьюеров при создании PR. Остальные поля тела создания игнорируются
+type PreviewReviewersRequest struct {
+	AuthorID     string   `json:"author_id" validate:"required,max=255,id"`
+	RequiredTags []string `json:"required_tags,omitempty" validate:"max=10,unique,dive,tag"`
 }
 
+// ExpectedVersion в запросах на изменение PR заполняется из заголовка If-Match, 0 — без проверки
+
 type PullRequestMergeRequest struct {
-	PullRequestID string `json:"pull_request_id"`
+	PullRequestID   string `json:"pull_request_id" validate:"required,max=255,id"`
+	ExpectedVersion int64  `json:"-"`
+}
+
+type PullRequestCloseRequest struct {
+	PullRequestID   string `json:"pull_request_id" validate:"required,max=255,id"`
+	ExpectedVersion int64  `json:"-"`
 }
 
 type PullRequestReassignRequest struct {
-	PullRequestID string `json:"pull_request_id"`
-	OldReviewerID string `json:"old_user_id"`
+	PullRequestID   string `json:"pull_request_id" validate:"required,max=255,id"`
+	OldReviewerID   string `json:"old_user_id" validate:"required,max=255,id"`
+	ExpectedVersion int64  `json:"-"`
+}
+
+type GetPullRequestRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required,max=255,id"`
+}
+
+// ExplainAssignmentsRequest reviewer_id задан — только выборы, в которых он назначен
+type ExplainAssignmentsRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required,max=255,id"`
+	ReviewerID    string `json:"reviewer_id" validate:"omitempty,max=255,id"`
+}
+
+// Запросы пачкой для батчевой загрузки в GraphQL. Приходят не от клиента, а из dataloader, поэтому без validate
+
+type GetUsersRequest struct {
+	UserIDs []string
+}
+
+type GetReviewQueuesRequest struct {
+	UserIDs []string
+}
+
+type GetTeamsRequest struct {
+	TeamNames []string
+}
+
+type GetPullRequestsRequest struct {
+	PullRequestIDs []string
 }
 
 type GetReviewPRRequest struct {
-	UserID string `json:"user_id"`
+	UserID string `json:"user_id" validate:"required,max=255,id"`
+}
+
+// ImportRequest команды из файла импорта: CSV, JSON и YAML приводятся к этой структуре.
+// Один пользователь не может быть в нескольких командах (правило unique_member)
+type ImportRequest struct {
+	Teams  []TeamAddRequest `json:"teams" validate:"required,min=1,unique=TeamName,dive"`
+	DryRun bool             `json:"-"`
+}
+
+// EventStreamRequest фильтры GET /events/stream из query, оба необязательны
+type EventStreamRequest struct {
+	UserID   string `json:"user_id" validate:"omitempty,max=255,id"`
+	TeamName string `json:"team" validate:"omitempty,max=255,printable"`
+}
+
+// ExportRequest фильтры выгрузки GET /export/* из query, все необязательны.
+// from и to — RFC 3339 или дата YYYY-MM-DD (для to дата включается целиком)
+type ExportRequest struct {
+	Format   string `json:"format" validate:"omitempty,oneof=csv ndjson"`
+	TeamName string `json:"team" validate:"omitempty,max=255,printable"`
+	From     string `json:"from" validate:"omitempty,timestamp"`
+	To       string `json:"to" validate:"omitempty,timestamp"`
 }
 
 type GetTeamStatsRequest struct {
-	TeamName string `json:"team_name"`
+	TeamName string `json:"team_name" validate:"required,max=255,printable"`
+}
+
+// AddAbsenceRequest период отсутствия пользователя, user_id из пути.
+// starts_at и ends_at — RFC 3339 или дата YYYY-MM-DD (дата в ends_at включается целиком).
+// weekdays ограничивает период днями недели, для PART_TIME обязателен
+type AddAbsenceRequest struct {
+	UserID   string   `json:"user_id" validate:"required,max=255,id"`
+	Kind     string   `json:"kind" validate:"required,oneof=VACATION SICK_LEAVE PART_TIME"`
+	StartsAt string   `json:"starts_at" validate:"required,timestamp"`
+	EndsAt   string   `json:"ends_at" validate:"required,timestamp"`
+	Weekdays []string `json:"weekdays" validate:"unique,dive,oneof=MON TUE WED THU FRI SAT SUN"`
+}
+
+// SetWorkingHoursRequest рабочее время пользователя, user_id из пути. start и end — HH:MM в поясе timezone,
+// end не позже start — смена через полночь
+type SetWorkingHoursRequest struct {
+	UserID   string   `json:"user_id" validate:"required,max=255,id"`
+	Timezone string   `json:"timezone" validate:"required,timezone"`
+	Start    string   `json:"start" validate:"required,clock"`
+	End      string   `json:"end" validate:"required,clock"`
+	Weekdays []string `json:"weekdays" validate:"required,min=1,unique,dive,oneof=MON TUE WED THU FRI SAT SUN"`
+}
+
+type GetWorkingHoursRequest struct {
+	UserID string `json:"user_id" validate:"required,max=255,id"`
+}
+
+type GetAbsencesRequest struct {
+	UserID string `json:"user_id" validate:"required,max=255,id"`
+}
+
+type DeleteAbsenceRequest struct {
+	UserID    string `json:"user_id" validate:"required,max=255,id"`
+	AbsenceID int64  `json:"absence_id" validate:"required,gt=0"`
+}
+
+// ImportCalendarRequest события OOO из календаря (.ics) пользователя или команды — задан один из UserID и TeamName из пути.
+// Для команды событие достается участнику, чей адрес или имя есть в ORGANIZER/ATTENDEE
+type ImportCalendarRequest struct {
+	UserID   string `json:"user_id" validate:"omitempty,max=255,id"`
+	TeamName string `json:"team_name" validate:"omitempty,max=255,printable"`
+	Events   []CalendarEvent
+	// Ignored событий без признака OOO
+	Ignored int
+}
+
+// CalendarEvent событие OOO. UID — UID события, для измененного повторения с "#RECURRENCE-ID".
+// Skip не пуст — событие нельзя перевести в период, причина попадет в отчет
+type CalendarEvent struct {
+	UID       string
+	Kind      string
+	Starts    time.Time
+	Ends      time.Time
+	Weekdays  domain.Weekdays
+	Cancelled bool
+	People    []string
+	Skip      string
 }
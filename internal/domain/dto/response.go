@@ -12,9 +12,11 @@ type AddTeamResponse struct {
 }
 
 type TeamMemberResponse struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	IsActive bool     `json:"is_active"`
+	Skills   []string `json:"skills,omitempty"`
+	Level    string   `json:"level,omitempty"`
 }
 
 type GetTeamResponse struct {
@@ -26,11 +28,55 @@ type SetIsActiveResponse struct {
 	User UserResponse `json:"user"`
 }
 
+type UserSkillsResponse struct {
+	User UserResponse `json:"user"`
+}
+
+type SetLevelResponse struct {
+	User UserResponse `json:"user"`
+}
+
+type ReviewerRuleResponse struct {
+	MinLevel string `json:"min_level"`
+	Count    int    `json:"count"`
+}
+
+// ReviewerRulesResponse правила команды от старшего уровня к младшему
+type ReviewerRulesResponse struct {
+	TeamName string                 `json:"team_name"`
+	Rules    []ReviewerRuleResponse `json:"rules"`
+}
+
+type PairingSettingsResponse struct {
+	TeamName  string `json:"team_name"`
+	Strategy  string `json:"strategy"`
+	DecayDays int    `json:"decay_days"`
+}
+
+// PairingCellResponse сколько PR автора ревьюер ревьюил за окно и их вес с затуханием (свежее ревью — 1)
+type PairingCellResponse struct {
+	AuthorID   string  `json:"author_id"`
+	ReviewerID string  `json:"reviewer_id"`
+	Count      int     `json:"count"`
+	Score      float64 `json:"score"`
+}
+
+// PairingMatrixResponse непустые ячейки матрицы автор-ревьюер за окно команды, сначала самые частые пары
+type PairingMatrixResponse struct {
+	TeamName  string                `json:"team_name"`
+	Strategy  string                `json:"strategy"`
+	DecayDays int                   `json:"decay_days"`
+	Since     time.Time             `json:"since"`
+	Pairs     []PairingCellResponse `json:"pairs"`
+}
+
 type UserResponse struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName string   `json:"team_name"`
+	IsActive bool     `json:"is_active"`
+	Skills   []string `json:"skills,omitempty"`
+	Level    string   `json:"level,omitempty"`
 }
 
 type PullRequestCreateResponse struct {
@@ -43,6 +89,8 @@ type PullRequestResponse struct {
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	RequiredTags      []string `json:"required_tags,omitempty"`
+	Version           int64    `json:"version"`
 }
 
 type PullRequestMergeResponse struct {
@@ -56,10 +104,70 @@ type PullRequestMergedResponse struct {
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	MergedAt          *time.Time `json:"mergedAt"`
+	RequiredTags      []string   `json:"required_tags,omitempty"`
+	Version           int64      `json:"version"`
+}
+
+type PullRequestCloseResponse struct {
+	PullRequest PullRequestMergedResponse `json:"pr"`
 }
 
 type PullRequestReassignResponse struct {
 	ReplacedBy string `json:"replaced_by"`
+	// Version версия PR после замены, отдается только в ETag
+	Version int64 `json:"-"`
+}
+
+// AssignmentCandidateResponse кандидат выбора: место в порядке, что о нем знал выбор и чем он закончился
+type AssignmentCandidateResponse struct {
+	UserID       string   `json:"user_id"`
+	Rank         int      `json:"rank"`
+	Chosen       bool     `json:"chosen"`
+	Reason       string   `json:"reason"`
+	WorkingHours bool     `json:"working_hours"`
+	PairingScore float64  `json:"pairing_score"`
+	Level        string   `json:"level,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+// AssignmentResponse выбор ревьюеров. seed строкой: int64 не помещается в число JSON без потерь
+type AssignmentResponse struct {
+	AssignmentID   int64                         `json:"assignment_id"`
+	Kind           string                        `json:"kind"`
+	ReplacedUserID string                        `json:"replaced_user_id,omitempty"`
+	Reviewers      []string                      `json:"reviewers"`
+	Seed           int64                         `json:"seed,string"`
+	Strategy       string                        `json:"strategy"`
+	AssignedAt     time.Time                     `json:"assigned_at"`
+	Candidates     []AssignmentCandidateResponse `json:"candidates"`
+}
+
+type PullRequestAssignmentsResponse struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Assignments   []AssignmentResponse `json:"assignments"`
+}
+
+// ExcludedMemberResponse участник команды автора, который не кандидат: AUTHOR, INACTIVE, AT_CAPACITY или OUT_OF_OFFICE
+type ExcludedMemberResponse struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// ReviewersPreviewResponse выбор, который сделало бы создание PR сейчас. Кандидаты — как в AssignmentResponse
+type ReviewersPreviewResponse struct {
+	AuthorID     string                        `json:"author_id"`
+	RequiredTags []string                      `json:"required_tags,omitempty"`
+	Reviewers    []string                      `json:"reviewers"`
+	Seed         int64                         `json:"seed,string"`
+	Strategy     string                        `json:"strategy"`
+	Candidates   []AssignmentCandidateResponse `json:"candidates"`
+	Excluded     []ExcludedMemberResponse      `json:"excluded"`
+	// UnmetRules правила команды, которые выбранные не выполняют: создание PR вернуло бы REVIEWER_RULE_UNMET
+	UnmetRules []ReviewerRuleResponse `json:"unmet_rules"`
+}
+
+type GetPullRequestResponse struct {
+	PullRequest PullRequestMergedResponse `json:"pr"`
 }
 
 type PullRequestShortResponse struct {
@@ -67,6 +175,15 @@ type PullRequestShortResponse struct {
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
 	Status          string `json:"status"`
+	// SLA только в очереди ревьюера и только у открытых PR
+	SLA *ReviewSLAResponse `json:"sla,omitempty"`
+}
+
+// ReviewSLAResponse таймер ревью в рабочем времени ревьюера: сколько прошло и когда истечет
+type ReviewSLAResponse struct {
+	ElapsedSeconds int64     `json:"elapsed_seconds"`
+	DueAt          time.Time `json:"due_at"`
+	Breached       bool      `json:"breached"`
 }
 
 type GetReviewPRResponse struct {
@@ -81,3 +198,122 @@ type TeamStatsResponse struct {
 	OpenPRs       int    `json:"open_prs"`
 	MergedPRs     int    `json:"merged_prs"`
 }
+
+// Ответы на запросы пачкой: отсутствующие сущности просто не попадают в ответ
+
+type GetUsersResponse struct {
+	Users []UserResponse `json:"users"`
+}
+
+type GetReviewQueuesResponse struct {
+	// Queues user_id -> id PR на ревью, сначала новые
+	Queues map[string][]string `json:"queues"`
+}
+
+type GetTeamsResponse struct {
+	Teams []GetTeamResponse `json:"teams"`
+}
+
+type GetPullRequestsResponse struct {
+	PullRequests []PullRequestMergedResponse `json:"pull_requests"`
+}
+
+// EventResponse data события в SSE. Для pr.reassigned заполнены только id PR, old_user_id, replaced_by и версия
+type EventResponse struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name,omitempty"`
+	AuthorID          string     `json:"author_id,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	AssignedReviewers []string   `json:"assigned_reviewers,omitempty"`
+	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	OldUserID         string     `json:"old_user_id,omitempty"`
+	ReplacedBy        string     `json:"replaced_by,omitempty"`
+	Version           int64      `json:"version"`
+	OccurredAt        time.Time  `json:"occurred_at"`
+}
+
+// PullRequestExportRow строка выгрузки PR. team_name — команда автора
+type PullRequestExportRow struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+	Version           int64      `json:"version"`
+}
+
+// ReviewExportRow строка выгрузки ревью: одна на каждую пару PR — ревьюер
+type ReviewExportRow struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	TeamName        string     `json:"team_name"`
+	ReviewerID      string     `json:"reviewer_id"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+}
+
+// ImportResponse отчет импорта, при dry_run — что изменится
+type ImportResponse struct {
+	DryRun         bool               `json:"dry_run"`
+	TeamsCreated   []string           `json:"teams_created"`
+	UsersCreated   []string           `json:"users_created"`
+	UsersUpdated   []ImportUserUpdate `json:"users_updated"`
+	UsersMoved     []ImportUserMove   `json:"users_moved"`
+	UsersUnchanged int                `json:"users_unchanged"`
+}
+
+type ImportUserUpdate struct {
+	UserID string   `json:"user_id"`
+	Fields []string `json:"fields"`
+}
+
+type ImportUserMove struct {
+	UserID   string `json:"user_id"`
+	FromTeam string `json:"from_team"`
+	ToTeam   string `json:"to_team"`
+}
+
+type AbsenceResponse struct {
+	AbsenceID int64     `json:"absence_id"`
+	UserID    string    `json:"user_id"`
+	Kind      string    `json:"kind"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Weekdays  []string  `json:"weekdays,omitempty"`
+	UID       string    `json:"uid,omitempty"`
+}
+
+// WorkingHoursResponse is_default — пользователь расписание не задавал, действует общее
+type WorkingHoursResponse struct {
+	UserID    string   `json:"user_id"`
+	Timezone  string   `json:"timezone"`
+	Start     string   `json:"start"`
+	End       string   `json:"end"`
+	Weekdays  []string `json:"weekdays"`
+	IsDefault bool     `json:"is_default"`
+}
+
+type GetAbsencesResponse struct {
+	UserID   string            `json:"user_id"`
+	Absences []AbsenceResponse `json:"absences"`
+}
+
+// ImportCalendarResponse отчет импорта календаря: повторная загрузка того же файла дает только unchanged
+type ImportCalendarResponse struct {
+	Created   int            `json:"created"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Deleted   int            `json:"deleted"`
+	Ignored   int            `json:"ignored"`
+	Skipped   []SkippedEvent `json:"skipped"`
+}
+
+type SkippedEvent struct {
+	UID    string `json:"uid"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,223 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"service-order-avito/internal/domain"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// tagPattern тег навыка: go, c++, c#, node.js. Без заглавных, чтобы один навык не хранился в двух написаниях
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#._-]{0,31}$`)
+
+// clockPattern время суток HH:MM
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// в нарушениях отдаем имена полей из json, а не из go
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	_ = v.RegisterValidation("id", func(fl validator.FieldLevel) bool {
+		return idPattern.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("printable", func(fl validator.FieldLevel) bool {
+		s := fl.Field().String()
+		if strings.TrimSpace(s) == "" {
+			return false
+		}
+		for _, r := range s {
+			if !unicode.IsPrint(r) {
+				return false
+			}
+		}
+		return true
+	})
+
+	_ = v.RegisterValidation("timestamp", func(fl validator.FieldLevel) bool {
+		_, _, err := ParseTimestamp(fl.Field().String())
+		return err == nil
+	})
+
+	// IANA-пояс из базы tzdata. Local зависел бы от машины, на которой запущен сервис
+	_ = v.RegisterValidation("timezone", func(fl validator.FieldLevel) bool {
+		name := fl.Field().String()
+		_, err := time.LoadLocation(name)
+		return err == nil && name != "" && name != "Local"
+	})
+	_ = v.RegisterValidation("clock", func(fl validator.FieldLevel) bool {
+		return clockPattern.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("tag", func(fl validator.FieldLevel) bool {
+		return tagPattern.MatchString(fl.Field().String())
+	})
+
+	v.RegisterStructValidation(validateImportMembers, ImportRequest{})
+	v.RegisterStructValidation(validateAbsence, AddAbsenceRequest{})
+
+	return v
+}
+
+// validateImportMembers пользователь из импорта должен быть ровно в одной команде,
+// иначе итоговая команда зависела бы от порядка строк в файле
+func validateImportMembers(sl validator.StructLevel) {
+	req := sl.Current().Interface().(ImportRequest)
+
+	seen := make(map[string]string)
+	for i, t := range req.Teams {
+		for j, m := range t.Members {
+			path := fmt.Sprintf("teams[%d].members[%d]", i, j)
+			if first, ok := seen[m.UserID]; ok && m.UserID != "" {
+				sl.ReportError(m.UserID, path+".user_id", "UserID", "unique_member", first)
+				continue
+			}
+			seen[m.UserID] = path
+		}
+	}
+}
+
+// validateAbsence период не пустой, а неполная занятость указывает дни недели.
+// Форматы дат проверяет тег timestamp, здесь только порядок границ
+func validateAbsence(sl validator.StructLevel) {
+	req := sl.Current().Interface().(AddAbsenceRequest)
+
+	if starts, ends, err := req.Period(); err == nil && !ends.After(starts) {
+		sl.ReportError(req.EndsAt, "ends_at", "EndsAt", "after_starts_at", "")
+	}
+	if req.Kind == domain.AbsencePartTime && len(req.Weekdays) == 0 {
+		sl.ReportError(req.Weekdays, "weekdays", "Weekdays", "required_for_part_time", "")
+	}
+}
+
+// ParseTimestamp разбирает границу периода из query: RFC 3339 или дата YYYY-MM-DD в UTC.
+// dateOnly нужен, чтобы верхняя граница-дата включала весь день
+func ParseTimestamp(s string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse(time.DateOnly, s); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	return t, false, err
+}
+
+// FieldViolation нарушение правила валидации для конкретного поля запроса
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError список всех нарушений в запросе
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + ": " + v.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Validate проверяет запрос по тегам validate и возвращает *ValidationError со всеми нарушениями сразу
+func Validate(req any) error {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	violations := make([]FieldViolation, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		violations[i] = FieldViolation{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: violationMessage(fe),
+		}
+	}
+
+	return &ValidationError{Violations: violations}
+}
+
+// fieldPath отрезает имя структуры: TeamAddRequest.members[0].user_id -> members[0].user_id
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, path, ok := strings.Cut(ns, "."); ok {
+		return path
+	}
+	return ns
+}
+
+func violationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "max":
+		return boundMessage(fe, "at most")
+	case "id":
+		return "may contain only latin letters, digits and . _ : -"
+	case "printable":
+		return "must not be blank or contain control characters"
+	case "unique":
+		// без параметра unique сравнивает сами элементы, с параметром — поле элемента (unique=UserID)
+		if fe.Param() == "" {
+			return "must not contain duplicates"
+		}
+		return fmt.Sprintf("must not contain duplicate %s", fe.Param())
+	case "min":
+		return boundMessage(fe, "at least")
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "timestamp":
+		return "must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+	case "after_starts_at":
+		return "must be later than starts_at"
+	case "required_for_part_time":
+		return "is required for PART_TIME"
+	case "timezone":
+		return "must be an IANA time zone, e.g. Europe/Moscow"
+	case "clock":
+		return "must be a time of day HH:MM"
+	case "tag":
+		return "must be a lowercase tag of up to 32 letters, digits and + # . _ -"
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "unique_member":
+		return fmt.Sprintf("user is already listed in %s", fe.Param())
+	default:
+		return "is invalid"
+	}
+}
+
+// boundMessage сообщение min/max: у строк граница — длина, у списков — число элементов, у чисел — само значение
+func boundMessage(fe validator.FieldError, bound string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s item(s)", bound, fe.Param())
+	default:
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	}
+}
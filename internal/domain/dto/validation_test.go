@@ -0,0 +1,358 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	long := strings.Repeat("a", 256)
+
+	tests := []struct {
+		name           string
+		req            any
+		expectedFields []string
+	}{
+		{
+			name: "valid team",
+			req: &TeamAddRequest{
+				TeamName: "payments team",
+				Members: []TeamMemberRequest{
+					{UserID: "u1", Username: "Alice", IsActive: true},
+					{UserID: "u2", Username: "Боб", IsActive: false},
+				},
+			},
+		},
+		{
+			name:           "empty team name",
+			req:            &TeamAddRequest{TeamName: ""},
+			expectedFields: []string{"team_name"},
+		},
+		{
+			name:           "blank team name",
+			req:            &GetTeamRequest{TeamName: "   "},
+			expectedFields: []string{"team_name"},
+		},
+		{
+			name:           "team name too long",
+			req:            &GetTeamStatsRequest{TeamName: long},
+			expectedFields: []string{"team_name"},
+		},
+		{
+			name: "member errors",
+			req: &TeamAddRequest{
+				TeamName: "backend",
+				Members: []TeamMemberRequest{
+					{UserID: "u1", Username: "Alice"},
+					{UserID: "", Username: ""},
+					{UserID: "u/3", Username: "Carl"},
+				},
+			},
+			expectedFields: []string{"members[1].user_id", "members[1].username", "members[2].user_id"},
+		},
+		{
+			name: "duplicate members",
+			req: &TeamAddRequest{
+				TeamName: "backend",
+				Members: []TeamMemberRequest{
+					{UserID: "u1", Username: "Alice"},
+					{UserID: "u1", Username: "Alice again"},
+				},
+			},
+			expectedFields: []string{"members"},
+		},
+		{
+			name:           "empty pull request",
+			req:            &PullRequestCreateRequest{},
+			expectedFields: []string{"pull_request_id", "pull_request_name", "author_id"},
+		},
+		{
+			name:           "pull request id too long",
+			req:            &PullRequestMergeRequest{PullRequestID: long},
+			expectedFields: []string{"pull_request_id"},
+		},
+		{
+			name:           "reassign with bad ids",
+			req:            &PullRequestReassignRequest{PullRequestID: "pr-1001", OldReviewerID: "u 2"},
+			expectedFields: []string{"old_user_id"},
+		},
+		{
+			name: "valid pull request",
+			req:  &PullRequestCreateRequest{PullRequestID: "pr-1001", PullRequestName: "Add search", AuthorID: "u1"},
+		},
+		{
+			name:           "set is active without user",
+			req:            &SetIsActiveRequest{IsActive: true},
+			expectedFields: []string{"user_id"},
+		},
+		{
+			name: "valid get review",
+			req:  &GetReviewPRRequest{UserID: "team.lead:1"},
+		},
+		{
+			name:           "empty import",
+			req:            &ImportRequest{},
+			expectedFields: []string{"teams"},
+		},
+		{
+			name: "import with user in two teams",
+			req: &ImportRequest{Teams: []TeamAddRequest{
+				{TeamName: "a", Members: []TeamMemberRequest{{UserID: "u1", Username: "x"}}},
+				{TeamName: "b", Members: []TeamMemberRequest{{UserID: "u2", Username: "y"}, {UserID: "u1", Username: "x"}}},
+			}},
+			expectedFields: []string{"teams[1].members[1].user_id"},
+		},
+		{
+			name: "import with duplicate team",
+			req: &ImportRequest{Teams: []TeamAddRequest{
+				{TeamName: "a"},
+				{TeamName: "a"},
+			}},
+			expectedFields: []string{"teams"},
+		},
+		{
+			name: "import with bad member",
+			req: &ImportRequest{Teams: []TeamAddRequest{
+				{TeamName: "a", Members: []TeamMemberRequest{{UserID: "bad id", Username: "x"}}},
+			}},
+			expectedFields: []string{"teams[0].members[0].user_id"},
+		},
+		{
+			name: "valid export",
+			req:  &ExportRequest{Format: "ndjson", TeamName: "backend", From: "2025-11-01", To: "2025-12-01T00:00:00+03:00"},
+		},
+		{
+			name:           "export with bad format and dates",
+			req:            &ExportRequest{Format: "xlsx", From: "01.11.2025", To: "2025-13-01"},
+			expectedFields: []string{"format", "from", "to"},
+		},
+		{
+			name: "valid vacation",
+			req:  &AddAbsenceRequest{UserID: "u1", Kind: "VACATION", StartsAt: "2025-12-22", EndsAt: "2025-12-22"},
+		},
+		{
+			name: "valid part time",
+			req:  &AddAbsenceRequest{UserID: "u1", Kind: "PART_TIME", StartsAt: "2025-12-01T00:00:00Z", EndsAt: "2026-03-01", Weekdays: []string{"FRI"}},
+		},
+		{
+			name:           "absence ends before start",
+			req:            &AddAbsenceRequest{UserID: "u1", Kind: "SICK_LEAVE", StartsAt: "2025-12-10T12:00:00Z", EndsAt: "2025-12-10T09:00:00Z"},
+			expectedFields: []string{"ends_at"},
+		},
+		{
+			name:           "part time without weekdays",
+			req:            &AddAbsenceRequest{UserID: "u1", Kind: "PART_TIME", StartsAt: "2025-12-01", EndsAt: "2025-12-31"},
+			expectedFields: []string{"weekdays"},
+		},
+		{
+			name:           "absence with bad kind and weekday",
+			req:            &AddAbsenceRequest{UserID: "u1", Kind: "HOLIDAY", StartsAt: "2025-12-01", EndsAt: "2025-12-31", Weekdays: []string{"MON", "FUN"}},
+			expectedFields: []string{"kind", "weekdays[1]"},
+		},
+		{
+			name:           "absence id",
+			req:            &DeleteAbsenceRequest{UserID: "u1"},
+			expectedFields: []string{"absence_id"},
+		},
+		{
+			name: "valid night shift",
+			req:  &SetWorkingHoursRequest{UserID: "u1", Timezone: "Asia/Tokyo", Start: "22:00", End: "06:00", Weekdays: []string{"FRI"}},
+		},
+		{
+			name:           "working hours with bad timezone and clock",
+			req:            &SetWorkingHoursRequest{UserID: "u1", Timezone: "Local", Start: "9:00", End: "24:00", Weekdays: []string{"MON", "MON"}},
+			expectedFields: []string{"timezone", "start", "end", "weekdays"},
+		},
+		{
+			name: "valid skills",
+			req:  &SetSkillsRequest{UserID: "u1", Skills: []string{"go", "c++", "c#", "k8s.io", "ci_cd-2"}},
+		},
+		{
+			name:           "skills with bad tags",
+			req:            &SetSkillsRequest{UserID: "u1", Skills: []string{"Go", "-go", "go lang"}},
+			expectedFields: []string{"skills[0]", "skills[1]", "skills[2]"},
+		},
+		{
+			name:           "update skills with duplicates",
+			req:            &UpdateSkillsRequest{UserID: "u1", Add: []string{"go", "go"}, Remove: []string{""}},
+			expectedFields: []string{"add", "remove[0]"},
+		},
+		{
+			name:           "pull request with bad required tag",
+			req:            &PullRequestCreateRequest{PullRequestID: "pr1", PullRequestName: "n", AuthorID: "u1", RequiredTags: []string{"SQL"}},
+			expectedFields: []string{"required_tags[0]"},
+		},
+		{
+			name: "valid reviewer rules",
+			req:  &SetReviewerRulesRequest{TeamName: "backend", Rules: []ReviewerRuleRequest{{MinLevel: "SENIOR", Count: 1}, {MinLevel: "MIDDLE", Count: 2}}},
+		},
+		{
+			name: "reviewer rules with bad level and count",
+			req: &SetReviewerRulesRequest{TeamName: "backend", Rules: []ReviewerRuleRequest{
+				{MinLevel: "GURU", Count: 1}, {MinLevel: "SENIOR", Count: 0}, {MinLevel: "LEAD", Count: 3},
+			}},
+			expectedFields: []string{"rules[0].min_level", "rules[1].count", "rules[2].count"},
+		},
+		{
+			name:           "reviewer rules with duplicate level",
+			req:            &SetReviewerRulesRequest{TeamName: "backend", Rules: []ReviewerRuleRequest{{MinLevel: "SENIOR", Count: 1}, {MinLevel: "SENIOR", Count: 2}}},
+			expectedFields: []string{"rules"},
+		},
+		{
+			name:           "unknown level",
+			req:            &SetLevelRequest{UserID: "u1", Level: "INTERN"},
+			expectedFields: []string{"level"},
+		},
+		{
+			name: "pairing settings without window",
+			req:  &SetPairingSettingsRequest{TeamName: "backend", Strategy: "PAIRING_DIVERSITY"},
+		},
+		{
+			name:           "pairing settings with unknown strategy and long window",
+			req:            &SetPairingSettingsRequest{TeamName: "backend", Strategy: "ROUND_ROBIN", DecayDays: 366},
+			expectedFields: []string{"strategy", "decay_days"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.req)
+
+			if len(tt.expectedFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var vErr *ValidationError
+			require.True(t, errors.As(err, &vErr))
+
+			fields := make([]string, len(vErr.Violations))
+			for i, v := range vErr.Violations {
+				fields[i] = v.Field
+				assert.NotEmpty(t, v.Rule)
+				assert.NotEmpty(t, v.Message)
+			}
+			assert.ElementsMatch(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+// сообщения min/max зависят от типа поля: длина строки, число элементов или значение числа
+func TestValidate_Messages(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      any
+		field    string
+		expected string
+	}{
+		{
+			name:     "string too long",
+			req:      &GetTeamStatsRequest{TeamName: strings.Repeat("a", 256)},
+			field:    "team_name",
+			expected: "must be at most 255 characters long",
+		},
+		{
+			name:     "count above max",
+			req:      &SetReviewerRulesRequest{TeamName: "backend", Rules: []ReviewerRuleRequest{{MinLevel: "LEAD", Count: 3}}},
+			field:    "rules[0].count",
+			expected: "must be at most 2",
+		},
+		{
+			name:     "count below min",
+			req:      &SetReviewerRulesRequest{TeamName: "backend", Rules: []ReviewerRuleRequest{{MinLevel: "LEAD", Count: 0}}},
+			field:    "rules[0].count",
+			expected: "must be at least 1",
+		},
+		{
+			name: "too many rules",
+			req: &SetReviewerRulesRequest{TeamName: "backend", Rules: []ReviewerRuleRequest{
+				{MinLevel: "JUNIOR", Count: 1}, {MinLevel: "MIDDLE", Count: 1}, {MinLevel: "SENIOR", Count: 1}, {MinLevel: "LEAD", Count: 1}, {MinLevel: "GURU", Count: 1},
+			}},
+			field:    "rules",
+			expected: "must contain at most 4 item(s)",
+		},
+		{
+			name:     "too many required tags",
+			req:      &PullRequestCreateRequest{PullRequestID: "pr1", PullRequestName: "n", AuthorID: "u1", RequiredTags: strings.Split("a,b,c,d,e,f,g,h,i,j,k", ",")},
+			field:    "required_tags",
+			expected: "must contain at most 10 item(s)",
+		},
+		{
+			name:     "duplicate skills",
+			req:      &UpdateSkillsRequest{UserID: "u1", Add: []string{"go", "go"}},
+			field:    "add",
+			expected: "must not contain duplicates",
+		},
+		{
+			name:     "duplicate members",
+			req:      &TeamAddRequest{TeamName: "backend", Members: []TeamMemberRequest{{UserID: "u1", Username: "a"}, {UserID: "u1", Username: "b"}}},
+			field:    "members",
+			expected: "must not contain duplicate UserID",
+		},
+		{
+			name:     "pairing window too long",
+			req:      &SetPairingSettingsRequest{TeamName: "backend", Strategy: "PAIRING_DIVERSITY", DecayDays: 400},
+			field:    "decay_days",
+			expected: "must be at most 365",
+		},
+		{
+			name:     "negative pairing window",
+			req:      &SetPairingSettingsRequest{TeamName: "backend", Strategy: "PAIRING_DIVERSITY", DecayDays: -1},
+			field:    "decay_days",
+			expected: "must be at least 1",
+		},
+		{
+			name:     "no teams",
+			req:      &ImportRequest{Teams: []TeamAddRequest{}},
+			field:    "teams",
+			expected: "must contain at least 1 item(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vErr *ValidationError
+			require.True(t, errors.As(Validate(tt.req), &vErr))
+
+			messages := make(map[string]string, len(vErr.Violations))
+			for _, v := range vErr.Violations {
+				messages[v.Field] = v.Message
+			}
+			assert.Equal(t, tt.expected, messages[tt.field])
+		})
+	}
+}
+
+func TestClock(t *testing.T) {
+	assert.Equal(t, 9*60+30, ParseClock("09:30"))
+	assert.Equal(t, "00:00", FormatClock(0))
+	assert.Equal(t, "23:59", FormatClock(ParseClock("23:59")))
+}
+
+func TestAddAbsenceRequest_Period(t *testing.T) {
+	req := AddAbsenceRequest{StartsAt: "2025-12-22", EndsAt: "2025-12-24"}
+	starts, ends, err := req.Period()
+	require.NoError(t, err)
+	assert.Equal(t, "2025-12-22T00:00:00Z", starts.Format(time.RFC3339))
+	// дата в ends_at включается целиком
+	assert.Equal(t, "2025-12-25T00:00:00Z", ends.Format(time.RFC3339))
+
+	req.EndsAt = "2025-12-24T18:00:00+03:00"
+	_, ends, err = req.Period()
+	require.NoError(t, err)
+	assert.Equal(t, "2025-12-24T15:00:00Z", ends.UTC().Format(time.RFC3339))
+}
+
+func TestWeekdays(t *testing.T) {
+	w := ParseWeekdays([]string{"SUN", "MON", "FRI"})
+	assert.Equal(t, domain.NewWeekdays(time.Sunday, time.Monday, time.Friday), w)
+	assert.Equal(t, []string{"MON", "FRI", "SUN"}, FormatWeekdays(w))
+	assert.Nil(t, FormatWeekdays(0))
+}
@@ -1,6 +1,9 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrTeamAlreadyExists      = errors.New("team already exists")
@@ -10,6 +13,19 @@ var (
 	ErrPullRequestExists      = errors.New("pull request already exists")
 	ErrPullRequestNotFound    = errors.New("pull request not found")
 	ErrPullRequestMerged      = errors.New("pull request already merged")
+	ErrPullRequestClosed      = errors.New("pull request closed")
 	ErrReviewerNotAssigned    = errors.New("reviewer not assigned")
 	ErrNoReplacementCandidate = errors.New("no candidate for reassignment")
+	ErrAbsenceNotFound        = errors.New("absence not found")
+	// ErrReviewerRulesUnmet среди доступных кандидатов не набрать ревьюеров нужного уровня по правилам команды
+	ErrReviewerRulesUnmet = errors.New("team reviewer rules cannot be satisfied")
+	// ErrVersionMismatch версия PR не совпала с ожидаемой (If-Match)
+	ErrVersionMismatch = errors.New("pull request version mismatch")
 )
+
+// Internal оборачивает ошибку драйвера в ErrInternalError.
+// Исходная причина сохраняется в цепочке, поэтому errors.Is(err, ErrInternalError) работает,
+// а в логах видно, что именно сломалось в бд
+func Internal(op string, err error) error {
+	return fmt.Errorf("%s: %w: %w", op, ErrInternalError, err)
+}
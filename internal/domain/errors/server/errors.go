@@ -2,14 +2,26 @@ package server
 
 const (
 	ErrInvalidJSON            = "invalid JSON"
+	ErrInvalidImportFile      = "import file cannot be parsed"
+	ErrValidation             = "request validation failed"
 	ErrTeamAlreadyExists      = "team already exists"
 	ErrTeamNotFound           = "team not found"
 	ErrUserNotFound           = "user not found"
 	ErrPRAlreadyExists        = "PR id already exists"
 	ErrPRNotFound             = "PR not found"
+	ErrAbsenceNotFound        = "absence not found"
 	ErrPullRequestMerged      = "cannot reassign on merged PR"
+	ErrPullRequestClosed      = "PR is closed"
 	ErrReviewerNotAssigned    = "reviewer is not assigned to this PR"
 	ErrNoReplacementCandidate = "no candidate for reassignment"
+	ErrReviewerRulesUnmet     = "not enough available reviewers of the level required by team rules"
+	ErrVersionMismatch        = "PR was modified: version does not match If-Match"
+	ErrRateLimited            = "too many requests, retry after the time in Retry-After"
+	ErrReassignLimitExceeded  = "too many reassignments of this PR, retry after the time in Retry-After"
 	ErrRequestCanceled        = "request canceled"
 	ErrInternalError          = "internal error"
+
+	ErrIdempotencyKeyInvalid    = "Idempotency-Key must be 1-255 printable ASCII characters"
+	ErrIdempotencyKeyReused     = "Idempotency-Key was already used with a different request"
+	ErrIdempotencyKeyInProgress = "request with this Idempotency-Key is still in progress"
 )
@@ -1,6 +1,9 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrInternalError          = errors.New("internal error")
@@ -10,6 +13,35 @@ var (
 	ErrPullRequestExists      = errors.New("pull request already exists")
 	ErrPullRequestNotFound    = errors.New("pull request not found")
 	ErrPullRequestMerged      = errors.New("pull request already merged")
+	ErrPullRequestClosed      = errors.New("pull request closed")
 	ErrReviewerNotAssigned    = errors.New("reviewer not assigned")
 	ErrNoReplacementCandidate = errors.New("no candidate for reassignment")
+	ErrVersionMismatch        = errors.New("pull request version mismatch")
+	ErrReassignLimitExceeded  = errors.New("pull request reassign limit exceeded")
+	ErrAbsenceNotFound        = errors.New("absence not found")
+	ErrReviewerRulesUnmet     = errors.New("team reviewer rules cannot be satisfied")
 )
+
+// Error ошибка уровня сервиса.
+// Kind — одна из ошибок выше, по ней контроллер выбирает код ответа (через errors.Is).
+// Cause — исходная ошибка нижнего уровня, нужна только для логов.
+// RetryAfter — для ограничений по частоте: через сколько можно повторить
+type Error struct {
+	Kind       error
+	Cause      error
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Kind.Error()
+	}
+	return e.Kind.Error() + ": " + e.Cause.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
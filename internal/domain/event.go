@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+const (
+	EventPullRequestCreated    = "pr.created"
+	EventPullRequestMerged     = "pr.merged"
+	EventPullRequestClosed     = "pr.closed"
+	EventPullRequestReassigned = "pr.reassigned"
+)
+
+// Event изменение PR, которое сервис публикует после успешной записи в репозиторий
+type Event struct {
+	// ID проставляет брокер при публикации, уходит клиенту SSE как id (для Last-Event-ID)
+	ID            string
+	Type          string
+	PullRequestID string
+	// AuthorID, Name, Status и MergedAt пустые в pr.reassigned: репозиторий отдает только нового ревьюера
+	AuthorID          string
+	Name              string
+	Status            string
+	MergedAt          *time.Time
+	AssignedReviewers []string
+	// OldReviewerID и NewReviewerID заполнены только в pr.reassigned
+	OldReviewerID string
+	NewReviewerID string
+	Version       int64
+	OccurredAt    time.Time
+}
+
+// Participants автор, ревьюеры и участники замены; пустые поля пропускаются
+func (e Event) Participants() []string {
+	ids := make([]string, 0, len(e.AssignedReviewers)+3)
+	for _, id := range append([]string{e.AuthorID, e.OldReviewerID, e.NewReviewerID}, e.AssignedReviewers...) {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Involves пользователь автор PR, его ревьюер или участник замены
+func (e Event) Involves(userID string) bool {
+	for _, id := range e.Participants() {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
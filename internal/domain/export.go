@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ExportFilter фильтры выгрузки PR. Пустые поля выборку не ограничивают.
+// TeamName — команда автора, From включительно и To не включительно сравниваются с created_at
+type ExportFilter struct {
+	TeamName string
+	From     *time.Time
+	To       *time.Time
+}
+
+// PullRequestExport строка выгрузки: PR с ревьюерами и командой автора.
+// TeamName пустой, если автор больше не состоит в команде
+type PullRequestExport struct {
+	PullRequestWithReviewers
+	TeamName string
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// IdempotencyKey запрос с заголовком Idempotency-Key и, после обработки, ответ на него
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+	// Response nil, пока первый запрос с этим ключом еще обрабатывается
+	Response  *IdempotentResponse
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	// ETag заголовок исходного ответа (версия PR), пустой — если его не было
+	ETag string
+	Body []byte
+}
@@ -0,0 +1,86 @@
+package domain
+
+// ImportReport что меняет (или изменил) импорт команд. Импорт только добавляет: команды и пользователи,
+// которых нет в файле, не трогаются
+type ImportReport struct {
+	TeamsCreated   []string
+	UsersCreated   []string
+	UsersUpdated   []UserUpdate
+	UsersMoved     []UserMove
+	UsersUnchanged int
+}
+
+// UserUpdate поля пользователя, которые поменяются: username, is_active
+type UserUpdate struct {
+	UserID string
+	Fields []string
+}
+
+// UserMove пользователь переходит в другую команду
+type UserMove struct {
+	UserID   string
+	FromTeam string
+	ToTeam   string
+}
+
+// PlanImport сравнивает импорт с текущим состоянием. Общая для всех репозиториев,
+// вызывается внутри транзакции импорта, чтобы отчет совпадал с тем, что будет записано.
+// existingUsers — текущие записи пользователей из импорта, отсутствующих в бд в ней нет
+func PlanImport(teams []TeamWithUsers, existingTeams map[string]bool, existingUsers map[string]User) ImportReport {
+	report := ImportReport{
+		TeamsCreated: []string{},
+		UsersCreated: []string{},
+		UsersUpdated: []UserUpdate{},
+		UsersMoved:   []UserMove{},
+	}
+
+	for _, t := range teams {
+		if !existingTeams[t.TeamName] {
+			report.TeamsCreated = append(report.TeamsCreated, t.TeamName)
+		}
+
+		for _, u := range t.Members {
+			old, ok := existingUsers[u.ID]
+			if !ok {
+				report.UsersCreated = append(report.UsersCreated, u.ID)
+				continue
+			}
+
+			changed := false
+			if old.TeamName != t.TeamName {
+				report.UsersMoved = append(report.UsersMoved, UserMove{UserID: u.ID, FromTeam: old.TeamName, ToTeam: t.TeamName})
+				changed = true
+			}
+
+			var fields []string
+			if old.Username != u.Username {
+				fields = append(fields, "username")
+			}
+			if old.IsActive != u.IsActive {
+				fields = append(fields, "is_active")
+			}
+			if len(fields) > 0 {
+				report.UsersUpdated = append(report.UsersUpdated, UserUpdate{UserID: u.ID, Fields: fields})
+				changed = true
+			}
+
+			if !changed {
+				report.UsersUnchanged++
+			}
+		}
+	}
+
+	return report
+}
+
+// ImportUsers все пользователи импорта с проставленной командой, в порядке файла
+func ImportUsers(teams []TeamWithUsers) []User {
+	var users []User
+	for _, t := range teams {
+		for _, u := range t.Members {
+			u.TeamName = t.TeamName
+			users = append(users, u)
+		}
+	}
+	return users
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// PairingStrategy как выбирать среди кандидатов, равных по правилам, тегам и рабочему времени
+type PairingStrategy string
+
+const (
+	// PairingRandom случайно, как без настроек
+	PairingRandom PairingStrategy = "RANDOM"
+	// PairingDiversity сначала те, кто реже ревьюил автора PR за окно затухания
+	PairingDiversity PairingStrategy = "PAIRING_DIVERSITY"
+)
+
+// DefaultPairingDecayDays окно затухания у команд без настроек
+const DefaultPairingDecayDays = 30
+
+// PairingSettings настройки команды. DecayDays — за сколько дней ревью автора перестает учитываться
+type PairingSettings struct {
+	Strategy  PairingStrategy
+	DecayDays int
+}
+
+// DefaultPairingSettings настройки команды, которая их не задавала
+func DefaultPairingSettings() PairingSettings {
+	return PairingSettings{Strategy: PairingRandom, DecayDays: DefaultPairingDecayDays}
+}
+
+func (s PairingSettings) Window() time.Duration {
+	return time.Duration(s.DecayDays) * 24 * time.Hour
+}
+
+// Pairing ReviewerID назначен ревьюером PR автора AuthorID в At
+type Pairing struct {
+	AuthorID   string
+	ReviewerID string
+	At         time.Time
+}
+
+// Weight вклад ревью в момент now: 1 сразу после назначения, линейно убывает до 0 к концу окна
+func (p Pairing) Weight(now time.Time, window time.Duration) float64 {
+	age := now.Sub(p.At)
+	if age < 0 {
+		age = 0
+	}
+	if window <= 0 || age >= window {
+		return 0
+	}
+	return 1 - float64(age)/float64(window)
+}
+
+// PairingScores вес недавних ревью каждого ревьюера у автора authorID
+func PairingScores(pairings []Pairing, authorID string, now time.Time, window time.Duration) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, p := range pairings {
+		if p.AuthorID != authorID {
+			continue
+		}
+		if w := p.Weight(now, window); w > 0 {
+			scores[p.ReviewerID] += w
+		}
+	}
+	return scores
+}
+
+// PairingCell ячейка матрицы пар: сколько PR автора ревьюил ревьюер за окно и их вес с затуханием
+type PairingCell struct {
+	AuthorID   string
+	ReviewerID string
+	Count      int
+	Score      float64
+}
+
+// PairingMatrix непустые ячейки по парам за окно, сначала самые частые пары
+func PairingMatrix(pairings []Pairing, now time.Time, window time.Duration) []PairingCell {
+	type pair struct{ author, reviewer string }
+	index := make(map[pair]int)
+	var cells []PairingCell
+	for _, p := range pairings {
+		w := p.Weight(now, window)
+		if w <= 0 {
+			continue
+		}
+		key := pair{p.AuthorID, p.ReviewerID}
+		i, ok := index[key]
+		if !ok {
+			i = len(cells)
+			index[key] = i
+			cells = append(cells, PairingCell{AuthorID: p.AuthorID, ReviewerID: p.ReviewerID})
+		}
+		cells[i].Count++
+		cells[i].Score += w
+	}
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Score != cells[j].Score {
+			return cells[i].Score > cells[j].Score
+		}
+		if cells[i].AuthorID != cells[j].AuthorID {
+			return cells[i].AuthorID < cells[j].AuthorID
+		}
+		return cells[i].ReviewerID < cells[j].ReviewerID
+	})
+	return cells
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairing_Weight(t *testing.T) {
+	now := time.Date(2025, 12, 15, 12, 0, 0, 0, time.UTC)
+	window := 10 * 24 * time.Hour
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want float64
+	}{
+		{name: "just now", at: now, want: 1},
+		{name: "half window ago", at: now.Add(-5 * 24 * time.Hour), want: 0.5},
+		{name: "window ago", at: now.Add(-window), want: 0},
+		{name: "older than window", at: now.Add(-30 * 24 * time.Hour), want: 0},
+		{name: "in the future", at: now.Add(time.Hour), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Pairing{At: tt.at}.Weight(now, window), 1e-9)
+		})
+	}
+	assert.Zero(t, Pairing{At: now}.Weight(now, 0))
+}
+
+func TestPairingScoresAndMatrix(t *testing.T) {
+	now := time.Date(2025, 12, 15, 12, 0, 0, 0, time.UTC)
+	window := 10 * 24 * time.Hour
+	day := 24 * time.Hour
+	pairings := []Pairing{
+		{AuthorID: "u1", ReviewerID: "u2", At: now},
+		{AuthorID: "u1", ReviewerID: "u2", At: now.Add(-5 * day)},
+		{AuthorID: "u1", ReviewerID: "u3", At: now.Add(-5 * day)},
+		{AuthorID: "u1", ReviewerID: "u4", At: now.Add(-20 * day)},
+		{AuthorID: "u2", ReviewerID: "u1", At: now.Add(-5 * day)},
+	}
+
+	scores := PairingScores(pairings, "u1", now, window)
+	assert.Len(t, scores, 2)
+	assert.InDelta(t, 1.5, scores["u2"], 1e-9)
+	assert.InDelta(t, 0.5, scores["u3"], 1e-9)
+
+	assert.Equal(t, []PairingCell{
+		{AuthorID: "u1", ReviewerID: "u2", Count: 2, Score: 1.5},
+		{AuthorID: "u1", ReviewerID: "u3", Count: 1, Score: 0.5},
+		{AuthorID: "u2", ReviewerID: "u1", Count: 1, Score: 0.5},
+	}, PairingMatrix(pairings, now, window))
+	assert.Empty(t, PairingMatrix(nil, now, window))
+	assert.Equal(t, PairingSettings{Strategy: PairingRandom, DecayDays: 30}, DefaultPairingSettings())
+	assert.Equal(t, 30*day, DefaultPairingSettings().Window())
+}
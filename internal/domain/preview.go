@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// ExclusionReason почему участник команды автора не кандидат в ревьюеры
+type ExclusionReason string
+
+const (
+	ExcludedAuthor   ExclusionReason = "AUTHOR"
+	ExcludedInactive ExclusionReason = "INACTIVE"
+	// ExcludedAtCapacity у участника уже столько открытых ревью, сколько разрешает лимит
+	ExcludedAtCapacity ExclusionReason = "AT_CAPACITY"
+	// ExcludedOutOfOffice в момент выбора у участника период отсутствия
+	ExcludedOutOfOffice ExclusionReason = "OUT_OF_OFFICE"
+)
+
+type CandidateExclusion struct {
+	UserID string
+	Reason ExclusionReason
+}
+
+// AtCapacity ревьюеры, у которых открытых ревью open не меньше limit. limit <= 0 — лимита нет, nil
+func AtCapacity(open map[string]int, limit int) map[string]bool {
+	if limit <= 0 {
+		return nil
+	}
+	full := make(map[string]bool)
+	for id, n := range open {
+		if n >= limit {
+			full[id] = true
+		}
+	}
+	return full
+}
+
+// SplitCandidates делит участников команды на кандидатов в ревьюеры и исключенных с причиной.
+// Порядок участников сохраняется, у исключенных по нескольким причинам — первая из author, inactive, at capacity, absent
+func SplitCandidates(members []User, authorID string, atCapacity, absent map[string]bool) ([]string, []CandidateExclusion) {
+	var candidates []string
+	var excluded []CandidateExclusion
+	for _, u := range members {
+		switch {
+		case u.ID == authorID:
+			excluded = append(excluded, CandidateExclusion{UserID: u.ID, Reason: ExcludedAuthor})
+		case !u.IsActive:
+			excluded = append(excluded, CandidateExclusion{UserID: u.ID, Reason: ExcludedInactive})
+		case atCapacity[u.ID]:
+			excluded = append(excluded, CandidateExclusion{UserID: u.ID, Reason: ExcludedAtCapacity})
+		case absent[u.ID]:
+			excluded = append(excluded, CandidateExclusion{UserID: u.ID, Reason: ExcludedOutOfOffice})
+		default:
+			candidates = append(candidates, u.ID)
+		}
+	}
+	return candidates, excluded
+}
+
+// ReviewerPreview выбор ревьюеров, который сделало бы создание PR автора AuthorID в момент At, без записи
+type ReviewerPreview struct {
+	AuthorID     string
+	RequiredTags []string
+	Reviewers    []string
+	Seed         int64
+	Strategy     PairingStrategy
+	At           time.Time
+	// Candidates по Rank, как в Assignment
+	Candidates []CandidateExplanation
+	Excluded   []CandidateExclusion
+	// UnmetRules правила команды, которые выбранные не выполняют: создание PR сейчас вернуло бы ошибку
+	UnmetRules []ReviewerRule
+}
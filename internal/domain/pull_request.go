@@ -5,6 +5,8 @@ import "time"
 const (
 	PRStatusOpen   = "OPEN"
 	PRStatusMerged = "MERGED"
+	// PRStatusClosed PR закрыт без merge: ревьюеры остаются в истории, но ревью больше не ждут
+	PRStatusClosed = "CLOSED"
 )
 
 type PullRequest struct {
@@ -14,6 +16,10 @@ type PullRequest struct {
 	Status    string
 	CreatedAt time.Time
 	MergedAt  *time.Time
+	// RequiredTags теги, каждый из которых должен быть у кого-то из ревьюеров, если в команде такие есть
+	RequiredTags []string
+	// Version растет на каждое изменение PR (merge, close, reassign), отдается клиенту как ETag
+	Version int64
 }
 
 type PullRequestWithReviewers struct {
@@ -21,6 +27,15 @@ type PullRequestWithReviewers struct {
 	AssignedReviewers []string
 }
 
+// ReviewPullRequest PR в очереди ревьюера
+type ReviewPullRequest struct {
+	PullRequest
+	// AssignedAt когда ревьюер назначен на PR, при замене — время замены. С него идет SLA ревью
+	AssignedAt time.Time
+}
+
 type Reviewer struct {
 	ID string
+	// PullRequestVersion версия PR после замены ревьюера
+	PullRequestVersion int64
 }
@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"math/bits"
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ReviewerPool кандидаты в ревьюеры, прошедшие фильтры хранилища (активны, не автор, не отсутствуют, не назначены),
+// и то, что влияет на выбор среди них. Выбор один для всех хранилищ, чтобы они назначали одинаково
+type ReviewerPool struct {
+	Candidates []string
+	// Hours расписания кандидатов, у кого они заданы
+	Hours map[string]WorkingHours
+	// Now момент назначения: по нему кандидаты делятся на тех, у кого идет рабочее время, и остальных
+	Now time.Time
+	// Skills теги кандидатов
+	Skills map[string][]string
+	// Required теги, которые должны покрыть выбранные. При замене — только те, что не покрыты оставшимися ревьюерами
+	Required []string
+	// Levels уровни кандидатов, у кого они заданы
+	Levels map[string]Level
+	// Rules правила команды к уровням выбранных. При замене — то, что не выполнено оставшимися ревьюерами
+	Rules []ReviewerRule
+	// Recent вес недавних ревью кандидатов у автора PR (стратегия PAIRING_DIVERSITY), см. PairingScores
+	Recent map[string]float64
+}
+
+// Pick до n ревьюеров случайно, но сначала из тех, у кого сейчас рабочее время:
+// остальные добираются, только если работающих не хватает. Внутри этих групп при заданном Recent
+// вперед идут те, кто реже ревьюил автора.
+// Если заданы Rules или Required, сначала выбираются кандидаты, выполняющие правила и покрывающие
+// как можно больше тегов. Выполнены ли правила в итоге, проверяет Unmet
+func (p ReviewerPool) Pick(n int, rnd *rand.Rand) []string {
+	picked, _ := p.Explain(n, rnd)
+	return picked
+}
+
+// Explain как Pick и с тем же rnd выбирает тех же, но еще объясняет место и исход каждого кандидата
+func (p ReviewerPool) Explain(n int, rnd *rand.Rand) ([]string, []CandidateExplanation) {
+	shuffled := append([]string{}, p.Candidates...)
+	rnd.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	// устойчивая сортировка: при равном весе порядок остается случайным
+	if len(p.Recent) > 0 {
+		sort.SliceStable(shuffled, func(i, j int) bool {
+			return p.Recent[shuffled[i]] < p.Recent[shuffled[j]]
+		})
+	}
+
+	// работающие вперед, внутри групп порядок случайный
+	ordered := make([]string, 0, len(shuffled))
+	var offHours []string
+	for _, id := range shuffled {
+		if HoursOf(p.Hours, id).At(p.Now) {
+			ordered = append(ordered, id)
+		} else {
+			offHours = append(offHours, id)
+		}
+	}
+	ordered = append(ordered, offHours...)
+
+	preferred := p.prefer(ordered, n)
+	picked := append([]string{}, preferred...)
+	for _, id := range ordered {
+		if len(picked) >= n {
+			break
+		}
+		if !slices.Contains(picked, id) {
+			picked = append(picked, id)
+		}
+	}
+
+	required := NormalizeTags(p.Required)
+	explained := make([]CandidateExplanation, len(ordered))
+	for i, id := range ordered {
+		e := CandidateExplanation{
+			UserID:       id,
+			Rank:         i + 1,
+			Working:      HoursOf(p.Hours, id).At(p.Now),
+			PairingScore: p.Recent[id],
+			Level:        p.Levels[id],
+			Chosen:       slices.Contains(picked, id),
+			Reason:       ReasonOutranked,
+		}
+		for _, tag := range required {
+			if slices.Contains(p.Skills[id], tag) {
+				e.Tags = append(e.Tags, tag)
+			}
+		}
+		switch {
+		case slices.Contains(preferred, id) && p.fitsRules(id):
+			e.Reason = ReasonReviewerRules
+		case slices.Contains(preferred, id):
+			e.Reason = ReasonRequiredTags
+		case e.Chosen:
+			e.Reason = ReasonRank
+		}
+		explained[i] = e
+	}
+	return picked, explained
+}
+
+// Strategy стратегия, по которой выбирает пул
+func (p ReviewerPool) Strategy() PairingStrategy {
+	if p.Recent != nil {
+		return PairingDiversity
+	}
+	return PairingRandom
+}
+
+// fitsRules кандидат засчитывается хотя бы в одно правило
+func (p ReviewerPool) fitsRules(id string) bool {
+	for _, r := range p.Rules {
+		if p.Levels[id].AtLeast(r.MinLevel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unmet правила, которые picked не выполняют
+func (p ReviewerPool) Unmet(picked []string) []ReviewerRule {
+	return UnmetRules(p.Rules, p.Levels, picked)
+}
+
+// prefer до n кандидатов: в первую очередь выполняющих Rules, затем покрывающих больше всего тегов Required.
+// Из равных наборов берется первый по порядку ordered, поэтому работающие сейчас предпочтительнее.
+// Перебор полный: n не больше пары ревьюеров, а в наборе только кандидаты с нужными тегами или уровнем
+func (p ReviewerPool) prefer(ordered []string, n int) []string {
+	required := NormalizeTags(p.Required)
+	if len(required) > 64 {
+		required = required[:64]
+	}
+	rules := NormalizeRules(p.Rules)
+	if (len(required) == 0 && len(rules) == 0) || n <= 0 {
+		return nil
+	}
+
+	var (
+		relevant []string
+		masks    []uint64
+		// fits[i][k] — кандидат засчитывается в правило k
+		fits [][]bool
+	)
+	for _, id := range ordered {
+		var mask uint64
+		for i, tag := range required {
+			if slices.Contains(p.Skills[id], tag) {
+				mask |= 1 << i
+			}
+		}
+		fit := make([]bool, len(rules))
+		anyFit := false
+		for k, r := range rules {
+			fit[k] = p.Levels[id].AtLeast(r.MinLevel)
+			anyFit = anyFit || fit[k]
+		}
+		if mask != 0 || anyFit {
+			relevant = append(relevant, id)
+			masks = append(masks, mask)
+			fits = append(fits, fit)
+		}
+	}
+
+	need := make([]int, len(rules))
+	deficit := 0
+	for k, r := range rules {
+		need[k] = r.Count
+		deficit += r.Count
+	}
+
+	var (
+		best        []int
+		bestDeficit = deficit
+		bestCovered int
+		chosen      []int
+	)
+	var walk func(start int, mask uint64, deficit int)
+	walk = func(start int, mask uint64, deficit int) {
+		covered := bits.OnesCount64(mask)
+		if deficit < bestDeficit || (deficit == bestDeficit && covered > bestCovered) {
+			best, bestDeficit, bestCovered = append([]int{}, chosen...), deficit, covered
+		}
+		if len(chosen) == n || (bestDeficit == 0 && bestCovered == len(required)) {
+			return
+		}
+		for i := start; i < len(relevant); i++ {
+			// кандидат без новых тегов и не нужный правилам набор не улучшает
+			reduces := 0
+			for k := range rules {
+				if fits[i][k] && need[k] > 0 {
+					reduces++
+				}
+			}
+			if mask|masks[i] == mask && reduces == 0 {
+				continue
+			}
+			for k := range rules {
+				if fits[i][k] {
+					need[k]--
+				}
+			}
+			chosen = append(chosen, i)
+			walk(i+1, mask|masks[i], deficit-reduces)
+			chosen = chosen[:len(chosen)-1]
+			for k := range rules {
+				if fits[i][k] {
+					need[k]++
+				}
+			}
+		}
+	}
+	walk(0, 0, deficit)
+
+	picked := make([]string, len(best))
+	for i, idx := range best {
+		picked[i] = relevant[idx]
+	}
+	return picked
+}
+
+// SkillsOf теги пользователей по id
+func SkillsOf(users []User) map[string][]string {
+	skills := make(map[string][]string, len(users))
+	for _, u := range users {
+		if len(u.Skills) > 0 {
+			skills[u.ID] = u.Skills
+		}
+	}
+	return skills
+}
@@ -0,0 +1,288 @@
+package domain
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewerPool_Pick(t *testing.T) {
+	// среда 12:00 UTC: у u1 и u2 рабочее время (u2 — по умолчанию), у u3 и u4 ночь
+	now := time.Date(2025, 12, 10, 12, 0, 0, 0, time.UTC)
+	pool := ReviewerPool{
+		Candidates: []string{"u1", "u2", "u3", "u4"},
+		Hours: map[string]WorkingHours{
+			"u1": {UserID: "u1", Timezone: "Europe/Moscow", Start: 9 * 60, End: 18 * 60, Days: DefaultWorkingHours.Days},
+			"u3": {UserID: "u3", Timezone: "Asia/Vladivostok", Start: 9 * 60, End: 18 * 60, Days: DefaultWorkingHours.Days},
+			"u4": {UserID: "u4", Timezone: "America/Los_Angeles", Start: 9 * 60, End: 18 * 60, Days: DefaultWorkingHours.Days},
+		},
+		Now: now,
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		assert.ElementsMatch(t, []string{"u1", "u2"}, pool.Pick(2, rnd))
+
+		// работающих не хватает — добираются остальные
+		picked := pool.Pick(3, rnd)
+		assert.Len(t, picked, 3)
+		assert.ElementsMatch(t, []string{"u1", "u2"}, picked[:2])
+	}
+
+	assert.Len(t, pool.Pick(5, rand.New(rand.NewSource(1))), 4)
+	assert.Empty(t, ReviewerPool{}.Pick(2, rand.New(rand.NewSource(1))))
+}
+
+func TestReviewerPool_PickRequired(t *testing.T) {
+	// воскресенье: рабочего времени по умолчанию нет ни у кого, кроме тех, кому задано
+	sunday := time.Date(2025, 12, 14, 12, 0, 0, 0, time.UTC)
+	everyDay := WorkingHours{Timezone: "UTC", Start: 0, End: 0, Days: NewWeekdays(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday)}
+
+	tests := []struct {
+		name     string
+		pool     ReviewerPool
+		n        int
+		want     []string
+		contains []string
+	}{
+		{
+			name: "one reviewer per tag",
+			pool: ReviewerPool{
+				Candidates: []string{"u2", "u3", "u4", "u5"},
+				Skills:     map[string][]string{"u2": {"go"}, "u3": {"sql"}},
+				Required:   []string{"sql", "go"},
+			},
+			n:    2,
+			want: []string{"u2", "u3"},
+		},
+		{
+			// жадный выбор взял бы x (4 тега) и недобрал бы один из оставшихся
+			name: "best pair over greedy",
+			pool: ReviewerPool{
+				Candidates: []string{"x", "a", "b", "u4"},
+				Skills:     map[string][]string{"x": {"t1", "t2", "t3", "t4"}, "a": {"t1", "t2", "t5"}, "b": {"t3", "t4", "t6"}},
+				Required:   []string{"t1", "t2", "t3", "t4", "t5", "t6"},
+			},
+			n:    2,
+			want: []string{"a", "b"},
+		},
+		{
+			name: "uncoverable tag falls back to normal policy",
+			pool: ReviewerPool{
+				Candidates: []string{"u2", "u3", "u4"},
+				Skills:     map[string][]string{"u2": {"go"}},
+				Required:   []string{"go", "rust"},
+			},
+			n:        2,
+			contains: []string{"u2"},
+		},
+		{
+			name: "one skilled reviewer covers everything",
+			pool: ReviewerPool{
+				Candidates: []string{"u2", "u3", "u4"},
+				Skills:     map[string][]string{"u3": {"frontend", "go"}},
+				Required:   []string{"go", "frontend"},
+			},
+			n:        2,
+			contains: []string{"u3"},
+		},
+		{
+			name: "working hours break ties between skilled",
+			pool: ReviewerPool{
+				Candidates: []string{"u2", "u3", "u4"},
+				Skills:     map[string][]string{"u2": {"go"}, "u3": {"go"}},
+				Hours:      map[string]WorkingHours{"u3": everyDay, "u4": everyDay},
+				Required:   []string{"go"},
+			},
+			n:    1,
+			want: []string{"u3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pool.Now = sunday
+			for seed := int64(0); seed < 20; seed++ {
+				picked := tt.pool.Pick(tt.n, rand.New(rand.NewSource(seed)))
+				assert.Len(t, picked, tt.n)
+				if tt.want != nil {
+					assert.ElementsMatch(t, tt.want, picked)
+				}
+				assert.Subset(t, picked, tt.contains)
+			}
+		})
+	}
+}
+
+func TestUncoveredTags(t *testing.T) {
+	skills := map[string][]string{"u2": {"go", "sql"}, "u3": {"frontend"}}
+
+	assert.Equal(t, []string{"security"}, UncoveredTags([]string{"go", "frontend", "security"}, skills, []string{"u2", "u3"}))
+	assert.Equal(t, []string{"frontend"}, UncoveredTags([]string{"go", "frontend"}, skills, []string{"u2"}))
+	assert.Empty(t, UncoveredTags(nil, skills, []string{"u2"}))
+	assert.Equal(t, []string{"c++", "go"}, NormalizeTags([]string{"go", "c++", "go"}))
+}
+
+func TestReviewerPool_PickRules(t *testing.T) {
+	levels := map[string]Level{"j": LevelJunior, "m": LevelMiddle, "s": LevelSenior, "l": LevelLead}
+
+	t.Run("senior first", func(t *testing.T) {
+		pool := ReviewerPool{
+			Candidates: []string{"j", "m", "s", "x"},
+			Levels:     levels,
+			Rules:      []ReviewerRule{{MinLevel: LevelSenior, Count: 1}},
+		}
+		for seed := int64(0); seed < 20; seed++ {
+			picked := pool.Pick(2, rand.New(rand.NewSource(seed)))
+			assert.Len(t, picked, 2)
+			assert.Contains(t, picked, "s")
+			assert.Empty(t, pool.Unmet(picked))
+		}
+	})
+
+	t.Run("rules before tags", func(t *testing.T) {
+		// пара m+j покрыла бы оба тега, но без senior
+		pool := ReviewerPool{
+			Candidates: []string{"j", "m", "s"},
+			Levels:     levels,
+			Skills:     map[string][]string{"j": {"go"}, "m": {"sql"}, "s": {"go"}},
+			Required:   []string{"go", "sql"},
+			Rules:      []ReviewerRule{{MinLevel: LevelSenior, Count: 1}},
+		}
+		for seed := int64(0); seed < 20; seed++ {
+			assert.ElementsMatch(t, []string{"s", "m"}, pool.Pick(2, rand.New(rand.NewSource(seed))))
+		}
+	})
+
+	t.Run("several rules", func(t *testing.T) {
+		pool := ReviewerPool{
+			Candidates: []string{"j", "m", "s", "l"},
+			Levels:     levels,
+			Rules:      []ReviewerRule{{MinLevel: LevelMiddle, Count: 2}, {MinLevel: LevelLead, Count: 1}},
+		}
+		for seed := int64(0); seed < 20; seed++ {
+			picked := pool.Pick(2, rand.New(rand.NewSource(seed)))
+			assert.Contains(t, picked, "l")
+			assert.NotContains(t, picked, "j")
+		}
+	})
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		pool := ReviewerPool{
+			Candidates: []string{"j", "m"},
+			Levels:     levels,
+			Rules:      []ReviewerRule{{MinLevel: LevelSenior, Count: 1}},
+		}
+		picked := pool.Pick(2, rand.New(rand.NewSource(1)))
+		assert.Len(t, picked, 2)
+		assert.Equal(t, []ReviewerRule{{MinLevel: LevelSenior, Count: 1}}, pool.Unmet(picked))
+	})
+}
+
+func TestUnmetRules(t *testing.T) {
+	levels := map[string]Level{"m": LevelMiddle, "s": LevelSenior, "l": LevelLead}
+	rules := NormalizeRules([]ReviewerRule{
+		{MinLevel: LevelMiddle, Count: 2}, {MinLevel: LevelSenior, Count: 1}, {MinLevel: LevelMiddle, Count: 1},
+	})
+
+	assert.Equal(t, []ReviewerRule{{MinLevel: LevelSenior, Count: 1}, {MinLevel: LevelMiddle, Count: 2}}, rules)
+	assert.Empty(t, UnmetRules(rules, levels, []string{"l", "m"}))
+	assert.Equal(t, []ReviewerRule{{MinLevel: LevelSenior, Count: 1}, {MinLevel: LevelMiddle, Count: 1}}, UnmetRules(rules, levels, []string{"m", "x"}))
+	assert.Nil(t, NormalizeRules(nil))
+	assert.False(t, Level("").AtLeast(LevelJunior))
+}
+
+func TestReviewerPool_PickRecent(t *testing.T) {
+	pool := ReviewerPool{
+		Candidates: []string{"u2", "u3", "u4", "u5"},
+		Recent:     map[string]float64{"u2": 1.5, "u3": 0.2, "u5": 0.9},
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		assert.Equal(t, []string{"u4", "u3"}, pool.Pick(2, rand.New(rand.NewSource(seed))))
+	}
+
+	// рабочее время важнее: u2 единственный работает и берется первым, несмотря на частые ревью
+	everyDay := WorkingHours{Timezone: "UTC", Start: 0, End: 0, Days: NewWeekdays(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday)}
+	pool.Now = time.Date(2025, 12, 14, 12, 0, 0, 0, time.UTC)
+	pool.Hours = map[string]WorkingHours{"u2": everyDay}
+	assert.Equal(t, []string{"u2", "u4"}, pool.Pick(2, rand.New(rand.NewSource(1))))
+
+	// правила важнее: сеньор u5 нужен, хотя ревьюил автора чаще u4 и u3
+	pool.Hours = nil
+	pool.Levels = map[string]Level{"u5": LevelSenior}
+	pool.Rules = []ReviewerRule{{MinLevel: LevelSenior, Count: 1}}
+	assert.Equal(t, []string{"u5", "u4"}, pool.Pick(2, rand.New(rand.NewSource(1))))
+}
+
+func TestReviewerPool_Explain(t *testing.T) {
+	pool := ReviewerPool{
+		Candidates: []string{"j", "m", "s", "x"},
+		Levels:     map[string]Level{"j": LevelJunior, "m": LevelMiddle, "s": LevelSenior},
+		Skills:     map[string][]string{"m": {"sql", "go"}},
+		Required:   []string{"sql"},
+		Rules:      []ReviewerRule{{MinLevel: LevelSenior, Count: 1}},
+		Recent:     map[string]float64{"j": 0.5},
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		picked, explained := pool.Explain(2, NewRand(seed))
+		// тот же seed — тот же выбор
+		again, _ := pool.Explain(2, NewRand(seed))
+		assert.Equal(t, picked, again)
+		assert.Equal(t, pool.Pick(2, NewRand(seed)), picked)
+
+		assert.ElementsMatch(t, []string{"s", "m"}, picked)
+		require.Len(t, explained, 4)
+		reasons := make(map[string]CandidateReason)
+		for i, e := range explained {
+			assert.Equal(t, i+1, e.Rank)
+			assert.Equal(t, slices.Contains(picked, e.UserID), e.Chosen)
+			reasons[e.UserID] = e.Reason
+		}
+		assert.Equal(t, map[string]CandidateReason{
+			"s": ReasonReviewerRules, "m": ReasonRequiredTags, "j": ReasonOutranked, "x": ReasonOutranked,
+		}, reasons)
+		// j недавно ревьюил автора — ниже остальных
+		assert.Equal(t, "j", explained[3].UserID)
+		assert.Equal(t, 0.5, explained[3].PairingScore)
+	}
+	assert.Equal(t, PairingDiversity, pool.Strategy())
+	pool.Recent = nil
+	assert.Equal(t, PairingRandom, pool.Strategy())
+}
+
+func TestSplitCandidates(t *testing.T) {
+	members := []User{
+		{ID: "u1", IsActive: true},
+		{ID: "u2", IsActive: false},
+		{ID: "u3", IsActive: true},
+		{ID: "u4", IsActive: false},
+		{ID: "u5", IsActive: true},
+		{ID: "u6", IsActive: true},
+		{ID: "u7", IsActive: true},
+	}
+
+	atCapacity := map[string]bool{"u6": true, "u7": true}
+	candidates, excluded := SplitCandidates(members, "u1", atCapacity, map[string]bool{"u3": true, "u4": true, "u7": true})
+	assert.Equal(t, []string{"u5"}, candidates)
+	// неактивный и отсутствующий — INACTIVE, отсутствующий с полной загрузкой — AT_CAPACITY
+	assert.Equal(t, []CandidateExclusion{
+		{UserID: "u1", Reason: ExcludedAuthor},
+		{UserID: "u2", Reason: ExcludedInactive},
+		{UserID: "u3", Reason: ExcludedOutOfOffice},
+		{UserID: "u4", Reason: ExcludedInactive},
+		{UserID: "u6", Reason: ExcludedAtCapacity},
+		{UserID: "u7", Reason: ExcludedAtCapacity},
+	}, excluded)
+}
+
+func TestAtCapacity(t *testing.T) {
+	open := map[string]int{"u1": 1, "u2": 2, "u3": 3}
+
+	assert.Nil(t, AtCapacity(open, 0))
+	assert.Equal(t, map[string]bool{"u2": true, "u3": true}, AtCapacity(open, 2))
+	assert.Empty(t, AtCapacity(nil, 2))
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Level уровень пользователя. Пустой — не задан: такой ревьюер не засчитывается ни в одно правило
+type Level string
+
+const (
+	LevelJunior Level = "JUNIOR"
+	LevelMiddle Level = "MIDDLE"
+	LevelSenior Level = "SENIOR"
+	LevelLead   Level = "LEAD"
+)
+
+var levelRanks = map[Level]int{
+	LevelJunior: 1,
+	LevelMiddle: 2,
+	LevelSenior: 3,
+	LevelLead:   4,
+}
+
+// AtLeast уровень задан и не ниже min
+func (l Level) AtLeast(min Level) bool {
+	rank, ok := levelRanks[l]
+	return ok && rank >= levelRanks[min]
+}
+
+// ReviewerRule правило команды: среди ревьюеров PR не меньше Count с уровнем не ниже MinLevel
+type ReviewerRule struct {
+	MinLevel Level
+	Count    int
+}
+
+func (r ReviewerRule) String() string {
+	return fmt.Sprintf("at least %d reviewer(s) of level %s or higher", r.Count, r.MinLevel)
+}
+
+// NormalizeRules одно правило на уровень (с наибольшим Count), от старшего уровня к младшему. nil, если правил нет
+func NormalizeRules(rules []ReviewerRule) []ReviewerRule {
+	byLevel := make(map[Level]int, len(rules))
+	for _, r := range rules {
+		if r.Count > byLevel[r.MinLevel] {
+			byLevel[r.MinLevel] = r.Count
+		}
+	}
+	if len(byLevel) == 0 {
+		return nil
+	}
+
+	normalized := make([]ReviewerRule, 0, len(byLevel))
+	for level, count := range byLevel {
+		normalized = append(normalized, ReviewerRule{MinLevel: level, Count: count})
+	}
+	sort.Slice(normalized, func(i, j int) bool {
+		return levelRanks[normalized[i].MinLevel] > levelRanks[normalized[j].MinLevel]
+	})
+	return normalized
+}
+
+// UnmetRules что осталось от правил после reviewers: Count — сколько еще нужно ревьюеров нужного уровня.
+// Выполненные правила не возвращаются
+func UnmetRules(rules []ReviewerRule, levels map[string]Level, reviewers []string) []ReviewerRule {
+	var unmet []ReviewerRule
+	for _, r := range rules {
+		need := r.Count
+		for _, id := range reviewers {
+			if levels[id].AtLeast(r.MinLevel) {
+				need--
+			}
+		}
+		if need > 0 {
+			unmet = append(unmet, ReviewerRule{MinLevel: r.MinLevel, Count: need})
+		}
+	}
+	return unmet
+}
+
+// LevelsOf уровни пользователей по id, у кого они заданы
+func LevelsOf(users []User) map[string]Level {
+	levels := make(map[string]Level, len(users))
+	for _, u := range users {
+		if u.Level != "" {
+			levels[u.ID] = u.Level
+		}
+	}
+	return levels
+}
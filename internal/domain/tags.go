@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"slices"
+	"sort"
+)
+
+// NormalizeTags теги отсортированы и без повторов: так они хранятся у пользователей и PR
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	normalized := append([]string{}, tags...)
+	sort.Strings(normalized)
+	return slices.Compact(normalized)
+}
+
+// UncoveredTags теги required, которых нет ни у одного из reviewers
+func UncoveredTags(required []string, skills map[string][]string, reviewers []string) []string {
+	var uncovered []string
+	for _, tag := range required {
+		covered := false
+		for _, id := range reviewers {
+			if slices.Contains(skills[id], tag) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, tag)
+		}
+	}
+	return uncovered
+}
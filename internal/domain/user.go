@@ -5,4 +5,8 @@ type User struct {
 	Username string
 	TeamName string
 	IsActive bool
+	// Skills теги экспертизы (go, frontend, sql, ...), отсортированы и без повторов
+	Skills []string
+	// Level уровень, по нему проверяются правила команды к ревьюерам. Пустой — не задан
+	Level Level
 }
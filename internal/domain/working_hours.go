@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// WorkingHours рабочее время пользователя: дни недели и часы [Start, End) в минутах от полуночи его часового пояса.
+// End <= Start — ночная смена, которая заканчивается на следующий день. День недели — день начала смены
+type WorkingHours struct {
+	UserID   string
+	Timezone string
+	Start    int
+	End      int
+	Days     Weekdays
+}
+
+// DefaultWorkingHours расписание пользователей, которые свое не задали
+var DefaultWorkingHours = WorkingHours{
+	Timezone: "UTC",
+	Start:    9 * 60,
+	End:      18 * 60,
+	Days:     NewWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+}
+
+// HoursOf расписание пользователя из hours, для отсутствующих — DefaultWorkingHours
+func HoursOf(hours map[string]WorkingHours, userID string) WorkingHours {
+	if w, ok := hours[userID]; ok {
+		return w
+	}
+	w := DefaultWorkingHours
+	w.UserID = userID
+	return w
+}
+
+// At идет ли рабочее время в момент t
+func (w WorkingHours) At(t time.Time) bool {
+	local := t.In(w.Location())
+	for _, offset := range []int{-1, 0} {
+		start, end, ok := w.shift(local, offset)
+		if ok && !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// BusinessDuration сколько рабочего времени прошло в [from, to): на нем считаются таймеры SLA
+func (w WorkingHours) BusinessDuration(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	loc := w.Location()
+	first, last := from.In(loc), to.In(loc)
+	var total time.Duration
+	// смена, начатая накануне from, тоже может попасть в интервал
+	for offset := -1; ; offset++ {
+		start, end, ok := w.shift(first, offset)
+		if start.After(last) {
+			break
+		}
+		if !ok {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// AddBusiness момент, когда от from пройдет d рабочего времени. Без рабочих дней — нулевое время
+func (w WorkingHours) AddBusiness(from time.Time, d time.Duration) time.Time {
+	if w.Days == 0 {
+		return time.Time{}
+	}
+
+	local := from.In(w.Location())
+	for offset := -1; ; offset++ {
+		start, end, ok := w.shift(local, offset)
+		if !ok || !end.After(from) {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if available := end.Sub(start); d <= available {
+			return start.Add(d)
+		} else {
+			d -= available
+		}
+	}
+}
+
+// shift смена дня day+offset (дата по поясу расписания). ok = false, если этот день нерабочий,
+// start при этом все равно указывает на начало дня — по нему останавливается перебор
+func (w WorkingHours) shift(day time.Time, offset int) (start, end time.Time, ok bool) {
+	loc := w.Location()
+	y, m, d := day.Date()
+	start = time.Date(y, m, d+offset, 0, w.Start, 0, 0, loc)
+	if !w.Days.Has(start.Weekday()) {
+		return time.Date(y, m, d+offset, 0, 0, 0, 0, loc), time.Time{}, false
+	}
+	end = time.Date(y, m, d+offset, 0, w.End, 0, 0, loc)
+	if w.End <= w.Start {
+		end = time.Date(y, m, d+offset+1, 0, w.End, 0, 0, loc)
+	}
+	return start, end, true
+}
+
+// locations часовые пояса уже разобранных расписаний: LoadLocation каждый раз читает базу tzdata
+var locations sync.Map
+
+// Location часовой пояс расписания, неизвестный — UTC
+func (w WorkingHours) Location() *time.Location {
+	if w.Timezone == "" || w.Timezone == "UTC" {
+		return time.UTC
+	}
+	if loc, ok := locations.Load(w.Timezone); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	locations.Store(w.Timezone, loc)
+	return loc
+}
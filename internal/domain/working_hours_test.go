@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkingHours_At(t *testing.T) {
+	moscow := WorkingHours{Timezone: "Europe/Moscow", Start: 9 * 60, End: 18 * 60, Days: DefaultWorkingHours.Days}
+	night := WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 6 * 60, Days: NewWeekdays(time.Friday)}
+
+	tests := []struct {
+		name  string
+		hours WorkingHours
+		at    time.Time
+		want  bool
+	}{
+		{"moscow morning", moscow, time.Date(2025, 12, 10, 6, 0, 0, 0, time.UTC), true},
+		{"moscow before start", moscow, time.Date(2025, 12, 10, 5, 59, 0, 0, time.UTC), false},
+		{"moscow end excluded", moscow, time.Date(2025, 12, 10, 15, 0, 0, 0, time.UTC), false},
+		{"moscow saturday", moscow, time.Date(2025, 12, 13, 8, 0, 0, 0, time.UTC), false},
+		{"night shift friday", night, time.Date(2025, 12, 12, 23, 0, 0, 0, time.UTC), true},
+		// смена пятницы продолжается в субботу утром
+		{"night shift saturday morning", night, time.Date(2025, 12, 13, 5, 0, 0, 0, time.UTC), true},
+		{"night shift friday morning", night, time.Date(2025, 12, 12, 5, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.hours.At(tt.at))
+		})
+	}
+}
+
+func TestWorkingHours_BusinessDuration(t *testing.T) {
+	hours := DefaultWorkingHours
+
+	// с пятницы 17:00 до понедельника 10:00: час в пятницу и час в понедельник
+	from := time.Date(2025, 12, 12, 17, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 15, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2*time.Hour, hours.BusinessDuration(from, to))
+	assert.Equal(t, time.Duration(0), hours.BusinessDuration(to, from))
+
+	// внутри одной смены
+	assert.Equal(t, 30*time.Minute, hours.BusinessDuration(from, from.Add(30*time.Minute)))
+
+	// ночная смена, начатая накануне from
+	night := WorkingHours{Timezone: "UTC", Start: 22 * 60, End: 6 * 60, Days: NewWeekdays(time.Friday)}
+	assert.Equal(t, 3*time.Hour, night.BusinessDuration(time.Date(2025, 12, 13, 3, 0, 0, 0, time.UTC), to))
+
+	// в день перехода на летнее время смена 00:00–06:00 короче на час
+	berlin := WorkingHours{Timezone: "Europe/Berlin", Start: 0, End: 6 * 60, Days: NewWeekdays(time.Sunday)}
+	sunday := time.Date(2026, 3, 29, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 5*time.Hour, berlin.BusinessDuration(sunday.Add(-2*time.Hour), sunday.Add(12*time.Hour)))
+}
+
+func TestWorkingHours_AddBusiness(t *testing.T) {
+	hours := DefaultWorkingHours
+
+	// из выходных отсчет начинается с понедельника
+	saturday := time.Date(2025, 12, 13, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 12, 15, 11, 0, 0, 0, time.UTC), hours.AddBusiness(saturday, 2*time.Hour))
+
+	// 16 часов с четверга 17:00: час в четверг, 9 в пятницу, 6 в понедельник
+	thursday := time.Date(2025, 12, 11, 17, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 12, 15, 15, 0, 0, 0, time.UTC), hours.AddBusiness(thursday, 16*time.Hour))
+
+	// ровно конец смены
+	assert.Equal(t, time.Date(2025, 12, 11, 18, 0, 0, 0, time.UTC), hours.AddBusiness(thursday, time.Hour))
+
+	assert.True(t, WorkingHours{}.AddBusiness(thursday, time.Hour).IsZero())
+}
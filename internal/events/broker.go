@@ -0,0 +1,172 @@
+package events
+
+import (
+	"fmt"
+	"service-order-avito/internal/domain"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Broker pub/sub в памяти процесса. Последние события хранятся в кольцевом буфере,
+// чтобы переподключившийся клиент дочитал пропущенное по Last-Event-ID.
+//
+// id события — "<эпоха>-<номер>", эпоха меняется при перезапуске процесса: id из прошлой жизни
+// не сравнивается с новыми номерами, клиенту отдается весь буфер
+type Broker struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []domain.Event
+	// start индекс самого старого события в history, когда буфер заполнен
+	start      int
+	maxHistory int
+	bufferSize int
+	subs       map[*Subscription]struct{}
+	closed     bool
+}
+
+// Subscription подписка одного клиента. Events закрывается, когда подписчик не успевает читать
+// (буфер переполнен) или брокер остановлен — клиент переподключается с Last-Event-ID
+type Subscription struct {
+	Events <-chan domain.Event
+	// Replay события после Last-Event-ID, которые надо отдать до Events
+	Replay []domain.Event
+
+	events chan domain.Event
+	broker *Broker
+}
+
+func NewBroker(historySize, bufferSize int) *Broker {
+	return &Broker{
+		epoch:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		maxHistory: historySize,
+		bufferSize: bufferSize,
+		subs:       make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish проставляет id и раздает событие подписчикам. Никогда не блокируется:
+// медленный подписчик отключается, а не тормозит запрос, который изменил PR
+func (b *Broker) Publish(e domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	e.ID = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+	b.remember(e)
+
+	for sub := range b.subs {
+		select {
+		case sub.events <- e:
+		default:
+			b.drop(sub)
+		}
+	}
+}
+
+// Subscribe регистрирует подписчика. Replay и регистрация выполняются под одной блокировкой,
+// поэтому между дочитанными и новыми событиями нет ни пропусков, ни повторов
+func (b *Broker) Subscribe(lastEventID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan domain.Event, b.bufferSize)
+	sub := &Subscription{
+		Events: events,
+		Replay: b.since(lastEventID),
+		events: events,
+		broker: b,
+	}
+
+	if b.closed {
+		close(events)
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close отписывает клиента, повторный вызов ничего не делает
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if _, ok := s.broker.subs[s]; ok {
+		s.broker.drop(s)
+	}
+}
+
+// Close закрывает все подписки, новые получают сразу закрытый канал. Вызывается при остановке сервера,
+// иначе открытые SSE-соединения не дадут http.Server.Shutdown завершиться
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
+
+func (b *Broker) drop(sub *Subscription) {
+	delete(b.subs, sub)
+	close(sub.events)
+}
+
+func (b *Broker) remember(e domain.Event) {
+	if b.maxHistory <= 0 {
+		return
+	}
+	if len(b.history) < b.maxHistory {
+		b.history = append(b.history, e)
+		return
+	}
+	b.history[b.start] = e
+	b.start = (b.start + 1) % b.maxHistory
+}
+
+// since события после lastEventID по порядку. Пустой id — новый клиент, ему история не нужна.
+// Чужая эпоха или id старше буфера — отдается весь буфер, лучше повтор, чем пропуск
+func (b *Broker) since(lastEventID string) []domain.Event {
+	if lastEventID == "" || len(b.history) == 0 {
+		return nil
+	}
+
+	ordered := make([]domain.Event, 0, len(b.history))
+	ordered = append(ordered, b.history[b.start:]...)
+	ordered = append(ordered, b.history[:b.start]...)
+
+	epoch, seq, err := parseID(lastEventID)
+	if err != nil || epoch != b.epoch {
+		return ordered
+	}
+
+	_, oldest, _ := parseID(ordered[0].ID)
+	if seq < oldest {
+		return ordered
+	}
+	// номера в буфере идут подряд, поэтому позиция вычисляется без поиска
+	skip := seq - oldest + 1
+	if skip >= uint64(len(ordered)) {
+		return nil
+	}
+	return ordered[skip:]
+}
+
+func parseID(id string) (epoch string, seq uint64, err error) {
+	epoch, rawSeq, ok := strings.Cut(id, "-")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid event id %q", id)
+	}
+	seq, err = strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid event id %q: %w", id, err)
+	}
+	return epoch, seq, nil
+}
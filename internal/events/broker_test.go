@@ -0,0 +1,110 @@
+package events
+
+import (
+	"service-order-avito/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishN(b *Broker, n int) {
+	for i := 0; i < n; i++ {
+		b.Publish(domain.Event{Type: domain.EventPullRequestCreated})
+	}
+}
+
+func ids(events []domain.Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.ID
+	}
+	return out
+}
+
+func TestBroker_Publish(t *testing.T) {
+	b := NewBroker(10, 10)
+	sub := b.Subscribe("")
+	defer sub.Close()
+	require.Empty(t, sub.Replay)
+
+	b.Publish(domain.Event{Type: domain.EventPullRequestCreated, PullRequestID: "pr-1"})
+
+	e := <-sub.Events
+	assert.Equal(t, "pr-1", e.PullRequestID)
+	assert.Equal(t, b.epoch+"-1", e.ID)
+}
+
+func TestBroker_Replay(t *testing.T) {
+	b := NewBroker(3, 10)
+	publishN(b, 5) // в буфере остались 3, 4, 5
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{"new client", "", nil},
+		{"inside buffer", b.epoch + "-3", []string{b.epoch + "-4", b.epoch + "-5"}},
+		{"just before buffer", b.epoch + "-2", []string{b.epoch + "-3", b.epoch + "-4", b.epoch + "-5"}},
+		{"older than buffer", b.epoch + "-1", []string{b.epoch + "-3", b.epoch + "-4", b.epoch + "-5"}},
+		{"up to date", b.epoch + "-5", nil},
+		{"previous process", "other-4", []string{b.epoch + "-3", b.epoch + "-4", b.epoch + "-5"}},
+		{"garbage", "garbage", []string{b.epoch + "-3", b.epoch + "-4", b.epoch + "-5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := b.Subscribe(tt.lastEventID)
+			defer sub.Close()
+
+			if tt.want == nil {
+				assert.Empty(t, sub.Replay)
+				return
+			}
+			assert.Equal(t, tt.want, ids(sub.Replay))
+		})
+	}
+}
+
+func TestBroker_SlowSubscriberDropped(t *testing.T) {
+	b := NewBroker(10, 2)
+	slow := b.Subscribe("")
+	fast := b.Subscribe("")
+	defer fast.Close()
+
+	publishN(b, 2)
+	<-fast.Events
+	<-fast.Events
+	// третье событие не влезает в буфер медленного подписчика
+	publishN(b, 1)
+
+	<-slow.Events
+	<-slow.Events
+	_, ok := <-slow.Events
+	assert.False(t, ok, "slow subscriber must be closed")
+
+	e, ok := <-fast.Events
+	require.True(t, ok)
+	assert.Equal(t, b.epoch+"-3", e.ID)
+
+	// повторный Close отключенного подписчика безопасен
+	slow.Close()
+}
+
+func TestBroker_Close(t *testing.T) {
+	b := NewBroker(10, 10)
+	sub := b.Subscribe("")
+
+	b.Close()
+	_, ok := <-sub.Events
+	assert.False(t, ok)
+	sub.Close()
+
+	late := b.Subscribe("")
+	_, ok = <-late.Events
+	assert.False(t, ok)
+
+	// публикация после остановки не паникует на закрытых каналах
+	publishN(b, 1)
+}
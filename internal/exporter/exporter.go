@@ -0,0 +1,179 @@
+// Package exporter пишет строки выгрузки PR в CSV или NDJSON по мере их чтения из репозитория.
+// Общий для GET /export/pullRequests и GET /export/reviews
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"service-order-avito/internal/domain/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format, expected csv or ndjson")
+
+// Writer пишет строки выгрузки. Flush дописывает буфер и заголовок CSV, если строк не было
+type Writer interface {
+	Write(dto.PullRequestExportRow) error
+	Flush() error
+}
+
+// ContentType для ответа в выбранном формате
+func ContentType(format string) string {
+	if format == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+var (
+	pullRequestColumns = []string{"pull_request_id", "pull_request_name", "author_id", "team_name", "status", "assigned_reviewers", "created_at", "merged_at", "version"}
+	reviewColumns      = []string{"pull_request_id", "pull_request_name", "author_id", "team_name", "reviewer_id", "status", "created_at", "merged_at"}
+)
+
+// NewPullRequestWriter одна строка на PR, ревьюеры в CSV через ";"
+func NewPullRequestWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, pullRequestColumns, func(row dto.PullRequestExportRow) [][]string {
+			return [][]string{{
+				row.PullRequestID,
+				row.PullRequestName,
+				row.AuthorID,
+				row.TeamName,
+				row.Status,
+				strings.Join(row.AssignedReviewers, ";"),
+				formatTime(&row.CreatedAt),
+				formatTime(row.MergedAt),
+				strconv.FormatInt(row.Version, 10),
+			}}
+		}), nil
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		return &ndjsonWriter{write: func(row dto.PullRequestExportRow) error {
+			return enc.Encode(row)
+		}}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// NewReviewWriter одна строка на каждого назначенного ревьюера, PR без ревьюеров не попадают
+func NewReviewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, reviewColumns, func(row dto.PullRequestExportRow) [][]string {
+			records := make([][]string, 0, len(row.AssignedReviewers))
+			for _, reviewerID := range row.AssignedReviewers {
+				records = append(records, []string{
+					row.PullRequestID,
+					row.PullRequestName,
+					row.AuthorID,
+					row.TeamName,
+					reviewerID,
+					row.Status,
+					formatTime(&row.CreatedAt),
+					formatTime(row.MergedAt),
+				})
+			}
+			return records
+		}), nil
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		return &ndjsonWriter{write: func(row dto.PullRequestExportRow) error {
+			for _, review := range reviews(row) {
+				if err := enc.Encode(review); err != nil {
+					return err
+				}
+			}
+			return nil
+		}}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func reviews(row dto.PullRequestExportRow) []dto.ReviewExportRow {
+	out := make([]dto.ReviewExportRow, len(row.AssignedReviewers))
+	for i, reviewerID := range row.AssignedReviewers {
+		out[i] = dto.ReviewExportRow{
+			PullRequestID:   row.PullRequestID,
+			PullRequestName: row.PullRequestName,
+			AuthorID:        row.AuthorID,
+			TeamName:        row.TeamName,
+			ReviewerID:      reviewerID,
+			Status:          row.Status,
+			CreatedAt:       row.CreatedAt,
+			MergedAt:        row.MergedAt,
+		}
+	}
+	return out
+}
+
+// formatTime время в CSV — RFC 3339 в UTC, пустая строка для nil
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	header  []string
+	started bool
+	records func(dto.PullRequestExportRow) [][]string
+}
+
+func newCSVWriter(w io.Writer, header []string, records func(dto.PullRequestExportRow) [][]string) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), header: header, records: records}
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.w.Write(c.header)
+}
+
+func (c *csvWriter) Write(row dto.PullRequestExportRow) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	for _, record := range c.records(row) {
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush заголовок пишется и для пустой выгрузки, чтобы файл открывался в таблицах
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	write func(dto.PullRequestExportRow) error
+}
+
+func (n *ndjsonWriter) Write(row dto.PullRequestExportRow) error {
+	return n.write(row)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
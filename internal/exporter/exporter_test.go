@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"service-order-avito/internal/domain/dto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	createdAt = time.Date(2025, 11, 20, 10, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	mergedAt  = time.Date(2025, 11, 21, 12, 30, 0, 0, time.UTC)
+	rows      = []dto.PullRequestExportRow{
+		{PullRequestID: "pr1", PullRequestName: "Fix, bug", AuthorID: "u1", TeamName: "backend", Status: "MERGED",
+			AssignedReviewers: []string{"u2", "u3"}, CreatedAt: createdAt, MergedAt: &mergedAt, Version: 2},
+		{PullRequestID: "pr2", PullRequestName: "Solo", AuthorID: "u1", TeamName: "backend", Status: "OPEN",
+			AssignedReviewers: []string{}, CreatedAt: createdAt, Version: 1},
+	}
+)
+
+func write(t *testing.T, w Writer) {
+	t.Helper()
+	for _, row := range rows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Flush())
+}
+
+func TestPullRequestWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewPullRequestWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+	write(t, w)
+
+	assert.Equal(t,
+		"pull_request_id,pull_request_name,author_id,team_name,status,assigned_reviewers,created_at,merged_at,version\n"+
+			`pr1,"Fix, bug",u1,backend,MERGED,u2;u3,2025-11-20T07:00:00Z,2025-11-21T12:30:00Z,2`+"\n"+
+			"pr2,Solo,u1,backend,OPEN,,2025-11-20T07:00:00Z,,1\n",
+		buf.String())
+}
+
+func TestReviewWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewReviewWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+	write(t, w)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "pull_request_id,pull_request_name,author_id,team_name,reviewer_id,status,created_at,merged_at", lines[0])
+	assert.Equal(t, `pr1,"Fix, bug",u1,backend,u2,MERGED,2025-11-20T07:00:00Z,2025-11-21T12:30:00Z`, lines[1])
+	assert.Equal(t, `pr1,"Fix, bug",u1,backend,u3,MERGED,2025-11-20T07:00:00Z,2025-11-21T12:30:00Z`, lines[2])
+}
+
+func TestWriter_CSVEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewPullRequestWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+
+	assert.Equal(t, strings.Join(pullRequestColumns, ",")+"\n", buf.String())
+}
+
+func TestWriter_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewPullRequestWriter(&buf, FormatNDJSON)
+	require.NoError(t, err)
+	write(t, w)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var got dto.PullRequestExportRow
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, "pr2", got.PullRequestID)
+	assert.Nil(t, got.MergedAt)
+
+	buf.Reset()
+	w, err = NewReviewWriter(&buf, FormatNDJSON)
+	require.NoError(t, err)
+	write(t, w)
+
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var review dto.ReviewExportRow
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &review))
+	assert.Equal(t, "u3", review.ReviewerID)
+	assert.Equal(t, "pr1", review.PullRequestID)
+}
+
+func TestWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewPullRequestWriter(&bytes.Buffer{}, "xlsx")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	_, err = NewReviewWriter(&bytes.Buffer{}, "xlsx")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"errors"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/pkg/http/error_wrapper"
+)
+
+// Error ошибка резолвера. Код из HTTP API уходит в errors[].extensions.code, нарушения по полям — в extensions.errors
+type Error struct {
+	Code       string
+	Message    string
+	Violations []dto.FieldViolation
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code}
+	if len(e.Violations) > 0 {
+		ext["errors"] = e.Violations
+	}
+	return ext
+}
+
+// serviceError переводит ошибку уровня service в ошибку GraphQL с тем же кодом и сообщением, что и в HTTP
+func serviceError(err error) error {
+	code, message := error_wrapper.ServiceErrorCode(err)
+	return &Error{Code: code, Message: message}
+}
+
+func validationError(err error) error {
+	var vErr *dto.ValidationError
+	if !errors.As(err, &vErr) {
+		return &Error{Code: codes.VALIDATION_ERROR, Message: server.ErrValidation}
+	}
+	return &Error{Code: codes.VALIDATION_ERROR, Message: vErr.Error(), Violations: vErr.Violations}
+}
+
+// notFoundError для обязательных вложенных полей, которые не нашлись (например, автор PR)
+func notFoundError(message string) error {
+	return &Error{Code: codes.NOT_FOUND, Message: message}
+}
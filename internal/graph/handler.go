@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth ограничивает вложенность запроса: user.reviewQueue.reviewers.reviewQueue... иначе раскручивается без конца
+const maxDepth = 10
+
+// NewHandler POST /graphql в формате relay: {"query", "operationName", "variables"}
+func NewHandler(log *slog.Logger, teamService TeamService, userService UserService, prService PullRequestService) http.Handler {
+	root := &resolver{
+		teamService: teamService,
+		userService: userService,
+		prService:   prService,
+	}
+
+	s := graphql.MustParseSchema(schema, root,
+		graphql.MaxDepth(maxDepth),
+		graphql.Logger(&panicLogger{log: log.With(slog.String("component", "graphql"))}),
+	)
+
+	return loadersMiddleware(teamService, userService, prService)(&relay.Handler{Schema: s})
+}
+
+// panicLogger пишет паники резолверов в slog вместо стандартного log
+type panicLogger struct {
+	log *slog.Logger
+}
+
+func (l *panicLogger) LogPanic(ctx context.Context, value any) {
+	l.log.ErrorContext(ctx, "graphql resolver panic", slog.String("panic", fmt.Sprint(value)))
+}
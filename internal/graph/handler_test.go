@@ -0,0 +1,312 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/graph/mocks"
+	"service-order-avito/internal/http/codes"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEnv struct {
+	team    *mocks.MockTeamService
+	user    *mocks.MockUserService
+	pr      *mocks.MockPullRequestService
+	handler http.Handler
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	env := &testEnv{
+		team: mocks.NewMockTeamService(ctrl),
+		user: mocks.NewMockUserService(ctrl),
+		pr:   mocks.NewMockPullRequestService(ctrl),
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	env.handler = NewHandler(log, env.team, env.user, env.pr)
+	return env
+}
+
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (env *testEnv) do(t *testing.T, query string, variables map[string]any) gqlResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	env.handler.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp gqlResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+// TestQuery_Batching вложенные поля всех элементов уровня грузятся одним вызовом сервиса
+func TestQuery_Batching(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.team.EXPECT().
+		GetTeams(gomock.Any(), &dto.GetTeamsRequest{TeamNames: []string{"backend"}}).
+		Return(&dto.GetTeamsResponse{Teams: []dto.GetTeamResponse{{
+			TeamName: "backend",
+			Members: []dto.TeamMemberResponse{
+				{UserID: "u1", Username: "Alice", IsActive: true},
+				{UserID: "u2", Username: "Bob", IsActive: true},
+				{UserID: "u3", Username: "Carol", IsActive: false},
+			},
+		}}}, nil).
+		Times(1)
+
+	env.user.EXPECT().
+		GetReviewQueues(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, req *dto.GetReviewQueuesRequest) (*dto.GetReviewQueuesResponse, error) {
+			assert.ElementsMatch(t, []string{"u1", "u2", "u3"}, req.UserIDs)
+			return &dto.GetReviewQueuesResponse{Queues: map[string][]string{
+				"u1": {"pr-2", "pr-1"},
+				"u2": {"pr-1"},
+			}}, nil
+		}).
+		Times(1)
+
+	env.pr.EXPECT().
+		GetMany(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, req *dto.GetPullRequestsRequest) (*dto.GetPullRequestsResponse, error) {
+			assert.ElementsMatch(t, []string{"pr-1", "pr-2"}, req.PullRequestIDs)
+			return &dto.GetPullRequestsResponse{PullRequests: []dto.PullRequestMergedResponse{
+				{PullRequestID: "pr-1", PullRequestName: "one", AuthorID: "u3", Status: "OPEN", AssignedReviewers: []string{"u1", "u2"}, Version: 1},
+				{PullRequestID: "pr-2", PullRequestName: "two", AuthorID: "u4", Status: "OPEN", AssignedReviewers: []string{"u1"}, Version: 2},
+			}}, nil
+		}).
+		Times(1)
+
+	env.user.EXPECT().
+		GetUsers(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, req *dto.GetUsersRequest) (*dto.GetUsersResponse, error) {
+			assert.ElementsMatch(t, []string{"u1", "u2", "u3", "u4"}, req.UserIDs)
+			return &dto.GetUsersResponse{Users: []dto.UserResponse{
+				{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+				{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+				{UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: false},
+				{UserID: "u4", Username: "Dave", TeamName: "frontend", IsActive: true},
+			}}, nil
+		}).
+		Times(1)
+
+	resp := env.do(t, `{
+		team(name: "backend") {
+			name
+			members {
+				id
+				reviewQueue { id author { username } reviewers { id } }
+			}
+		}
+	}`, nil)
+	require.Empty(t, resp.Errors)
+
+	assert.JSONEq(t, `{"team": {"name": "backend", "members": [
+		{"id": "u1", "reviewQueue": [
+			{"id": "pr-2", "author": {"username": "Dave"}, "reviewers": [{"id": "u1"}]},
+			{"id": "pr-1", "author": {"username": "Carol"}, "reviewers": [{"id": "u1"}, {"id": "u2"}]}
+		]},
+		{"id": "u2", "reviewQueue": [
+			{"id": "pr-1", "author": {"username": "Carol"}, "reviewers": [{"id": "u1"}, {"id": "u2"}]}
+		]},
+		{"id": "u3", "reviewQueue": []}
+	]}}`, string(resp.Data))
+}
+
+func TestQuery_NotFound(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.pr.EXPECT().
+		GetMany(gomock.Any(), &dto.GetPullRequestsRequest{PullRequestIDs: []string{"missing"}}).
+		Return(&dto.GetPullRequestsResponse{}, nil)
+
+	resp := env.do(t, `{ pullRequest(id: "missing") { id } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"pullRequest": null}`, string(resp.Data))
+}
+
+func TestQuery_ReviewQueueStatus(t *testing.T) {
+	env := newTestEnv(t)
+	mergedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	env.user.EXPECT().
+		GetUsers(gomock.Any(), gomock.Any()).
+		Return(&dto.GetUsersResponse{Users: []dto.UserResponse{{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}}}, nil)
+	env.user.EXPECT().
+		GetReviewQueues(gomock.Any(), gomock.Any()).
+		Return(&dto.GetReviewQueuesResponse{Queues: map[string][]string{"u1": {"pr-2", "pr-1"}}}, nil)
+	env.pr.EXPECT().
+		GetMany(gomock.Any(), gomock.Any()).
+		Return(&dto.GetPullRequestsResponse{PullRequests: []dto.PullRequestMergedResponse{
+			{PullRequestID: "pr-1", Status: "MERGED", MergedAt: &mergedAt, Version: 2},
+			{PullRequestID: "pr-2", Status: "OPEN", Version: 1},
+		}}, nil)
+
+	resp := env.do(t, `{ user(id: "u1") { reviewQueue(status: MERGED) { id status mergedAt version } } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"user": {"reviewQueue": [
+		{"id": "pr-1", "status": "MERGED", "mergedAt": "2025-01-02T03:04:05Z", "version": 2}
+	]}}`, string(resp.Data))
+}
+
+func TestQuery_ServiceError(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.team.EXPECT().
+		GetTeams(gomock.Any(), gomock.Any()).
+		Return(nil, &service.Error{Kind: service.ErrInternalError, Cause: errors.New("connection refused")})
+
+	resp := env.do(t, `{ team(name: "backend") { name } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codes.INTERNAL_ERROR, resp.Errors[0].Extensions["code"])
+	assert.NotContains(t, resp.Errors[0].Message, "connection refused")
+}
+
+func TestMutation_MergePullRequest(t *testing.T) {
+	env := newTestEnv(t)
+
+	t.Run("expected version", func(t *testing.T) {
+		mergedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		env.pr.EXPECT().
+			Merge(gomock.Any(), &dto.PullRequestMergeRequest{PullRequestID: "pr-1", ExpectedVersion: 3}).
+			Return(&dto.PullRequestMergeResponse{PullRequest: dto.PullRequestMergedResponse{
+				PullRequestID: "pr-1", Status: "MERGED", AuthorID: "u1", MergedAt: &mergedAt, Version: 4,
+			}}, nil)
+
+		resp := env.do(t, `mutation($id: ID!, $v: Int) { mergePullRequest(id: $id, expectedVersion: $v) { id status version } }`,
+			map[string]any{"id": "pr-1", "v": 3})
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"mergePullRequest": {"id": "pr-1", "status": "MERGED", "version": 4}}`, string(resp.Data))
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		env.pr.EXPECT().
+			Merge(gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrVersionMismatch)
+
+		resp := env.do(t, `mutation { mergePullRequest(id: "pr-1", expectedVersion: 1) { id } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codes.VERSION_MISMATCH, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		resp := env.do(t, `mutation { mergePullRequest(id: "bad id") { id } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codes.VALIDATION_ERROR, resp.Errors[0].Extensions["code"])
+		assert.NotEmpty(t, resp.Errors[0].Extensions["errors"])
+	})
+}
+
+func TestMutation_ClosePullRequest(t *testing.T) {
+	env := newTestEnv(t)
+
+	t.Run("closes", func(t *testing.T) {
+		env.pr.EXPECT().
+			Close(gomock.Any(), &dto.PullRequestCloseRequest{PullRequestID: "pr-1", ExpectedVersion: 3}).
+			Return(&dto.PullRequestCloseResponse{PullRequest: dto.PullRequestMergedResponse{
+				PullRequestID: "pr-1", Status: "CLOSED", AuthorID: "u1", Version: 4,
+			}}, nil)
+
+		resp := env.do(t, `mutation($id: ID!, $v: Int) { closePullRequest(id: $id, expectedVersion: $v) { id status mergedAt version } }`,
+			map[string]any{"id": "pr-1", "v": 3})
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"closePullRequest": {"id": "pr-1", "status": "CLOSED", "mergedAt": null, "version": 4}}`, string(resp.Data))
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		env.pr.EXPECT().
+			Close(gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrPullRequestMerged)
+
+		resp := env.do(t, `mutation { closePullRequest(id: "pr-1") { id } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codes.PR_MERGED, resp.Errors[0].Extensions["code"])
+	})
+}
+
+func TestMutation_ReassignReviewer(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.pr.EXPECT().
+		ReassignReviewer(gomock.Any(), &dto.PullRequestReassignRequest{PullRequestID: "pr-1", OldReviewerID: "u2"}).
+		Return(&dto.PullRequestReassignResponse{ReplacedBy: "u3", Version: 2}, nil)
+	env.pr.EXPECT().
+		GetMany(gomock.Any(), &dto.GetPullRequestsRequest{PullRequestIDs: []string{"pr-1"}}).
+		Return(&dto.GetPullRequestsResponse{PullRequests: []dto.PullRequestMergedResponse{
+			{PullRequestID: "pr-1", Status: "OPEN", AuthorID: "u1", AssignedReviewers: []string{"u3"}, Version: 2},
+		}}, nil)
+	env.user.EXPECT().
+		GetUsers(gomock.Any(), &dto.GetUsersRequest{UserIDs: []string{"u3"}}).
+		Return(&dto.GetUsersResponse{Users: []dto.UserResponse{{UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: true}}}, nil)
+
+	resp := env.do(t, `mutation {
+		reassignReviewer(id: "pr-1", oldUserId: "u2") {
+			pullRequest { version reviewers { id } }
+			replacedBy { id username }
+		}
+	}`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"reassignReviewer": {
+		"pullRequest": {"version": 2, "reviewers": [{"id": "u3"}]},
+		"replacedBy": {"id": "u3", "username": "Carol"}
+	}}`, string(resp.Data))
+}
+
+func TestMutation_AddTeam(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.team.EXPECT().
+		AddTeam(gomock.Any(), &dto.TeamAddRequest{
+			TeamName: "backend",
+			Members:  []dto.TeamMemberRequest{{UserID: "u1", Username: "Alice", IsActive: true}},
+		}).
+		Return(&dto.AddTeamResponse{
+			TeamName: "backend",
+			Members:  []dto.TeamMemberResponse{{UserID: "u1", Username: "Alice", IsActive: true}},
+		}, nil)
+
+	// команда из ответа мутации кладется в loader, за ней повторно не ходим
+	resp := env.do(t, `mutation {
+		addTeam(input: {name: "backend", members: [{id: "u1", username: "Alice", isActive: true}]}) {
+			name
+			members { id team { name } }
+		}
+	}`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"addTeam": {"name": "backend", "members": [{"id": "u1", "team": {"name": "backend"}}]}}`, string(resp.Data))
+}
+
+func TestMaxDepth(t *testing.T) {
+	env := newTestEnv(t)
+
+	resp := env.do(t, `{ user(id: "u1") { team { members { team { members { team { members { team { members { team { name } } } } } } } } } } }`, nil)
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "exceeds max depth")
+}
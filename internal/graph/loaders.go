@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"service-order-avito/internal/domain/dto"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait сколько loader копит ключи перед походом в сервис. Резолверы соседних полей
+// выполняются параллельно и успевают попасть в один батч
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// loaders живут один запрос: кэш не переживает запрос и не отдает устаревшие данные другим клиентам
+type loaders struct {
+	users        *dataloader.Loader[string, *dto.UserResponse]
+	teams        *dataloader.Loader[string, *dto.GetTeamResponse]
+	pullRequests *dataloader.Loader[string, *dto.PullRequestMergedResponse]
+	reviewQueues *dataloader.Loader[string, []string]
+}
+
+func newLoaders(teamService TeamService, userService UserService, prService PullRequestService) *loaders {
+	return &loaders{
+		users: dataloader.NewBatchedLoader(func(ctx context.Context, ids []string) []*dataloader.Result[*dto.UserResponse] {
+			resp, err := userService.GetUsers(ctx, &dto.GetUsersRequest{UserIDs: ids})
+			if err != nil {
+				return failed[*dto.UserResponse](len(ids), err)
+			}
+			byID := make(map[string]*dto.UserResponse, len(resp.Users))
+			for i := range resp.Users {
+				byID[resp.Users[i].UserID] = &resp.Users[i]
+			}
+			return ordered(ids, byID)
+		}, dataloader.WithWait[string, *dto.UserResponse](loaderWait)),
+
+		teams: dataloader.NewBatchedLoader(func(ctx context.Context, names []string) []*dataloader.Result[*dto.GetTeamResponse] {
+			resp, err := teamService.GetTeams(ctx, &dto.GetTeamsRequest{TeamNames: names})
+			if err != nil {
+				return failed[*dto.GetTeamResponse](len(names), err)
+			}
+			byName := make(map[string]*dto.GetTeamResponse, len(resp.Teams))
+			for i := range resp.Teams {
+				byName[resp.Teams[i].TeamName] = &resp.Teams[i]
+			}
+			return ordered(names, byName)
+		}, dataloader.WithWait[string, *dto.GetTeamResponse](loaderWait)),
+
+		pullRequests: dataloader.NewBatchedLoader(func(ctx context.Context, ids []string) []*dataloader.Result[*dto.PullRequestMergedResponse] {
+			resp, err := prService.GetMany(ctx, &dto.GetPullRequestsRequest{PullRequestIDs: ids})
+			if err != nil {
+				return failed[*dto.PullRequestMergedResponse](len(ids), err)
+			}
+			byID := make(map[string]*dto.PullRequestMergedResponse, len(resp.PullRequests))
+			for i := range resp.PullRequests {
+				byID[resp.PullRequests[i].PullRequestID] = &resp.PullRequests[i]
+			}
+			return ordered(ids, byID)
+		}, dataloader.WithWait[string, *dto.PullRequestMergedResponse](loaderWait)),
+
+		reviewQueues: dataloader.NewBatchedLoader(func(ctx context.Context, ids []string) []*dataloader.Result[[]string] {
+			resp, err := userService.GetReviewQueues(ctx, &dto.GetReviewQueuesRequest{UserIDs: ids})
+			if err != nil {
+				return failed[[]string](len(ids), err)
+			}
+			// у пользователя без ревью просто пустая очередь
+			return ordered(ids, resp.Queues)
+		}, dataloader.WithWait[string, []string](loaderWait)),
+	}
+}
+
+// ordered раскладывает ответ сервиса по порядку ключей, как того требует dataloader.
+// Не найденные ключи получают нулевое значение без ошибки
+func ordered[V any](keys []string, byKey map[string]V) []*dataloader.Result[V] {
+	results := make([]*dataloader.Result[V], len(keys))
+	for i, key := range keys {
+		results[i] = &dataloader.Result[V]{Data: byKey[key]}
+	}
+	return results
+}
+
+func failed[V any](n int, err error) []*dataloader.Result[V] {
+	results := make([]*dataloader.Result[V], n)
+	for i := range results {
+		results[i] = &dataloader.Result[V]{Error: serviceError(err)}
+	}
+	return results
+}
+
+// loadAll грузит ключи одним батчем и сохраняет их порядок, не найденные пропускает
+func loadAll[V comparable](ctx context.Context, loader *dataloader.Loader[string, V], keys []string) ([]V, error) {
+	thunks := make([]dataloader.Thunk[V], len(keys))
+	for i, key := range keys {
+		thunks[i] = loader.Load(ctx, key)
+	}
+
+	var zero V
+	values := make([]V, 0, len(keys))
+	for _, thunk := range thunks {
+		v, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		if v != zero {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// loadersMiddleware создает свежий набор loader'ов на каждый запрос
+func loadersMiddleware(teamService TeamService, userService UserService, prService PullRequestService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := withLoaders(r.Context(), newLoaders(teamService, userService, prService))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/graph/resolver.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dto "service-order-avito/internal/domain/dto"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTeamService is a mock of TeamService interface.
+type MockTeamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamServiceMockRecorder
+}
+
+// MockTeamServiceMockRecorder is the mock recorder for MockTeamService.
+type MockTeamServiceMockRecorder struct {
+	mock *MockTeamService
+}
+
+// NewMockTeamService creates a new mock instance.
+func NewMockTeamService(ctrl *gomock.Controller) *MockTeamService {
+	mock := &MockTeamService{ctrl: ctrl}
+	mock.recorder = &MockTeamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamService) EXPECT() *MockTeamServiceMockRecorder {
+	return m.recorder
+}
+
+// AddTeam mocks base method.
+func (m *MockTeamService) AddTeam(arg0 context.Context, arg1 *dto.TeamAddRequest) (*dto.AddTeamResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTeam", arg0, arg1)
+	ret0, _ := ret[0].(*dto.AddTeamResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTeam indicates an expected call of AddTeam.
+func (mr *MockTeamServiceMockRecorder) AddTeam(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeam", reflect.TypeOf((*MockTeamService)(nil).AddTeam), arg0, arg1)
+}
+
+// GetTeams mocks base method.
+func (m *MockTeamService) GetTeams(arg0 context.Context, arg1 *dto.GetTeamsRequest) (*dto.GetTeamsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeams", arg0, arg1)
+	ret0, _ := ret[0].(*dto.GetTeamsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeams indicates an expected call of GetTeams.
+func (mr *MockTeamServiceMockRecorder) GetTeams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeams", reflect.TypeOf((*MockTeamService)(nil).GetTeams), arg0, arg1)
+}
+
+// MockUserService is a mock of UserService interface.
+type MockUserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserServiceMockRecorder
+}
+
+// MockUserServiceMockRecorder is the mock recorder for MockUserService.
+type MockUserServiceMockRecorder struct {
+	mock *MockUserService
+}
+
+// NewMockUserService creates a new mock instance.
+func NewMockUserService(ctrl *gomock.Controller) *MockUserService {
+	mock := &MockUserService{ctrl: ctrl}
+	mock.recorder = &MockUserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReviewQueues mocks base method.
+func (m *MockUserService) GetReviewQueues(arg0 context.Context, arg1 *dto.GetReviewQueuesRequest) (*dto.GetReviewQueuesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewQueues", arg0, arg1)
+	ret0, _ := ret[0].(*dto.GetReviewQueuesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewQueues indicates an expected call of GetReviewQueues.
+func (mr *MockUserServiceMockRecorder) GetReviewQueues(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewQueues", reflect.TypeOf((*MockUserService)(nil).GetReviewQueues), arg0, arg1)
+}
+
+// GetUsers mocks base method.
+func (m *MockUserService) GetUsers(arg0 context.Context, arg1 *dto.GetUsersRequest) (*dto.GetUsersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", arg0, arg1)
+	ret0, _ := ret[0].(*dto.GetUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserServiceMockRecorder) GetUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserService)(nil).GetUsers), arg0, arg1)
+}
+
+// SetIsActive mocks base method.
+func (m *MockUserService) SetIsActive(arg0 context.Context, arg1 *dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIsActive", arg0, arg1)
+	ret0, _ := ret[0].(*dto.SetIsActiveResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetIsActive indicates an expected call of SetIsActive.
+func (mr *MockUserServiceMockRecorder) SetIsActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsActive", reflect.TypeOf((*MockUserService)(nil).SetIsActive), arg0, arg1)
+}
+
+// MockPullRequestService is a mock of PullRequestService interface.
+type MockPullRequestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPullRequestServiceMockRecorder
+}
+
+// MockPullRequestServiceMockRecorder is the mock recorder for MockPullRequestService.
+type MockPullRequestServiceMockRecorder struct {
+	mock *MockPullRequestService
+}
+
+// NewMockPullRequestService creates a new mock instance.
+func NewMockPullRequestService(ctrl *gomock.Controller) *MockPullRequestService {
+	mock := &MockPullRequestService{ctrl: ctrl}
+	mock.recorder = &MockPullRequestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPullRequestService) EXPECT() *MockPullRequestServiceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockPullRequestService) Close(arg0 context.Context, arg1 *dto.PullRequestCloseRequest) (*dto.PullRequestCloseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestCloseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockPullRequestServiceMockRecorder) Close(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPullRequestService)(nil).Close), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockPullRequestService) Create(arg0 context.Context, arg1 *dto.PullRequestCreateRequest) (*dto.PullRequestCreateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestCreateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPullRequestServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPullRequestService)(nil).Create), arg0, arg1)
+}
+
+// GetMany mocks base method.
+func (m *MockPullRequestService) GetMany(arg0 context.Context, arg1 *dto.GetPullRequestsRequest) (*dto.GetPullRequestsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", arg0, arg1)
+	ret0, _ := ret[0].(*dto.GetPullRequestsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockPullRequestServiceMockRecorder) GetMany(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockPullRequestService)(nil).GetMany), arg0, arg1)
+}
+
+// Merge mocks base method.
+func (m *MockPullRequestService) Merge(arg0 context.Context, arg1 *dto.PullRequestMergeRequest) (*dto.PullRequestMergeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestMergeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockPullRequestServiceMockRecorder) Merge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequestService)(nil).Merge), arg0, arg1)
+}
+
+// ReassignReviewer mocks base method.
+func (m *MockPullRequestService) ReassignReviewer(arg0 context.Context, arg1 *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignReviewer", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestReassignResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignReviewer indicates an expected call of ReassignReviewer.
+func (mr *MockPullRequestServiceMockRecorder) ReassignReviewer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignReviewer", reflect.TypeOf((*MockPullRequestService)(nil).ReassignReviewer), arg0, arg1)
+}
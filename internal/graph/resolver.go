@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"context"
+	"service-order-avito/internal/domain/dto"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// mockgen -source="internal/graph/resolver.go" -destination="internal/graph/mocks/mock_services.go" -package=mocks
+type TeamService interface {
+	AddTeam(context.Context, *dto.TeamAddRequest) (*dto.AddTeamResponse, error)
+	GetTeams(context.Context, *dto.GetTeamsRequest) (*dto.GetTeamsResponse, error)
+}
+
+type UserService interface {
+	SetIsActive(context.Context, *dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error)
+	GetUsers(context.Context, *dto.GetUsersRequest) (*dto.GetUsersResponse, error)
+	GetReviewQueues(context.Context, *dto.GetReviewQueuesRequest) (*dto.GetReviewQueuesResponse, error)
+}
+
+type PullRequestService interface {
+	Create(context.Context, *dto.PullRequestCreateRequest) (*dto.PullRequestCreateResponse, error)
+	GetMany(context.Context, *dto.GetPullRequestsRequest) (*dto.GetPullRequestsResponse, error)
+	Merge(context.Context, *dto.PullRequestMergeRequest) (*dto.PullRequestMergeResponse, error)
+	Close(context.Context, *dto.PullRequestCloseRequest) (*dto.PullRequestCloseResponse, error)
+	ReassignReviewer(context.Context, *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error)
+}
+
+// resolver корневой резолвер Query и Mutation. Чтение идет только через loader'ы запроса,
+// мутации вызывают те же методы сервисов, что и HTTP, и сбрасывают затронутые ключи в loader'ах
+type resolver struct {
+	teamService TeamService
+	userService UserService
+	prService   PullRequestService
+}
+
+func (r *resolver) Team(ctx context.Context, args struct{ Name string }) (*teamResolver, error) {
+	team, err := loadersFrom(ctx).teams.Load(ctx, args.Name)()
+	if err != nil || team == nil {
+		return nil, err
+	}
+	return &teamResolver{team: team}, nil
+}
+
+func (r *resolver) Teams(ctx context.Context, args struct{ Names []string }) ([]*teamResolver, error) {
+	teams, err := loadAll(ctx, loadersFrom(ctx).teams, args.Names)
+	if err != nil {
+		return nil, err
+	}
+	return toTeamResolvers(teams), nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, string(args.ID))()
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) Users(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*userResolver, error) {
+	users, err := loadAll(ctx, loadersFrom(ctx).users, toStrings(args.IDs))
+	if err != nil {
+		return nil, err
+	}
+	return toUserResolvers(users), nil
+}
+
+func (r *resolver) PullRequest(ctx context.Context, args struct{ ID graphql.ID }) (*pullRequestResolver, error) {
+	pr, err := loadersFrom(ctx).pullRequests.Load(ctx, string(args.ID))()
+	if err != nil || pr == nil {
+		return nil, err
+	}
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+func (r *resolver) PullRequests(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*pullRequestResolver, error) {
+	prs, err := loadAll(ctx, loadersFrom(ctx).pullRequests, toStrings(args.IDs))
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequestResolvers(prs), nil
+}
+
+type addTeamInput struct {
+	Name    string
+	Members []teamMemberInput
+}
+
+type teamMemberInput struct {
+	ID       graphql.ID
+	Username string
+	IsActive bool
+}
+
+func (r *resolver) AddTeam(ctx context.Context, args struct{ Input addTeamInput }) (*teamResolver, error) {
+	req := dto.TeamAddRequest{
+		TeamName: args.Input.Name,
+		Members:  make([]dto.TeamMemberRequest, len(args.Input.Members)),
+	}
+	for i, m := range args.Input.Members {
+		req.Members[i] = dto.TeamMemberRequest{UserID: string(m.ID), Username: m.Username, IsActive: m.IsActive}
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := r.teamService.AddTeam(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	// участники могли перейти из другой команды
+	l := loadersFrom(ctx)
+	for _, m := range resp.Members {
+		l.users.Clear(ctx, m.UserID)
+	}
+	team := &dto.GetTeamResponse{TeamName: resp.TeamName, Members: resp.Members}
+	l.teams.Clear(ctx, team.TeamName).Prime(ctx, team.TeamName, team)
+
+	return &teamResolver{team: team}, nil
+}
+
+func (r *resolver) SetIsActive(ctx context.Context, args struct {
+	UserID   graphql.ID
+	IsActive bool
+}) (*userResolver, error) {
+	req := dto.SetIsActiveRequest{UserID: string(args.UserID), IsActive: args.IsActive}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := r.userService.SetIsActive(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	l := loadersFrom(ctx)
+	l.users.Clear(ctx, resp.User.UserID).Prime(ctx, resp.User.UserID, &resp.User)
+	l.teams.Clear(ctx, resp.User.TeamName)
+
+	return &userResolver{user: &resp.User}, nil
+}
+
+type createPullRequestInput struct {
+	ID       graphql.ID
+	Name     string
+	AuthorID graphql.ID
+}
+
+func (r *resolver) CreatePullRequest(ctx context.Context, args struct{ Input createPullRequestInput }) (*pullRequestResolver, error) {
+	req := dto.PullRequestCreateRequest{
+		PullRequestID:   string(args.Input.ID),
+		PullRequestName: args.Input.Name,
+		AuthorID:        string(args.Input.AuthorID),
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := r.prService.Create(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	created := resp.PullRequest
+	pr := &dto.PullRequestMergedResponse{
+		PullRequestID:     created.PullRequestID,
+		PullRequestName:   created.PullRequestName,
+		AuthorID:          created.AuthorID,
+		Status:            created.Status,
+		AssignedReviewers: created.AssignedReviewers,
+		Version:           created.Version,
+	}
+	l := loadersFrom(ctx)
+	l.pullRequests.Clear(ctx, pr.PullRequestID).Prime(ctx, pr.PullRequestID, pr)
+	for _, id := range pr.AssignedReviewers {
+		l.reviewQueues.Clear(ctx, id)
+	}
+
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+func (r *resolver) MergePullRequest(ctx context.Context, args struct {
+	ID              graphql.ID
+	ExpectedVersion *int32
+}) (*pullRequestResolver, error) {
+	req := dto.PullRequestMergeRequest{PullRequestID: string(args.ID), ExpectedVersion: expectedVersion(args.ExpectedVersion)}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := r.prService.Merge(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	pr := &resp.PullRequest
+	loadersFrom(ctx).pullRequests.Clear(ctx, pr.PullRequestID).Prime(ctx, pr.PullRequestID, pr)
+
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+func (r *resolver) ClosePullRequest(ctx context.Context, args struct {
+	ID              graphql.ID
+	ExpectedVersion *int32
+}) (*pullRequestResolver, error) {
+	req := dto.PullRequestCloseRequest{PullRequestID: string(args.ID), ExpectedVersion: expectedVersion(args.ExpectedVersion)}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := r.prService.Close(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	pr := &resp.PullRequest
+	loadersFrom(ctx).pullRequests.Clear(ctx, pr.PullRequestID).Prime(ctx, pr.PullRequestID, pr)
+
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+func (r *resolver) ReassignReviewer(ctx context.Context, args struct {
+	ID              graphql.ID
+	OldUserID       graphql.ID
+	ExpectedVersion *int32
+}) (*reassignResultResolver, error) {
+	req := dto.PullRequestReassignRequest{
+		PullRequestID:   string(args.ID),
+		OldReviewerID:   string(args.OldUserID),
+		ExpectedVersion: expectedVersion(args.ExpectedVersion),
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := r.prService.ReassignReviewer(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	l := loadersFrom(ctx)
+	l.pullRequests.Clear(ctx, req.PullRequestID)
+	l.reviewQueues.Clear(ctx, req.OldReviewerID)
+	l.reviewQueues.Clear(ctx, resp.ReplacedBy)
+
+	return &reassignResultResolver{pullRequestID: req.PullRequestID, replacedBy: resp.ReplacedBy}, nil
+}
+
+// expectedVersion null в запросе — без проверки версии, как отсутствие If-Match
+func expectedVersion(v *int32) int64 {
+	if v == nil {
+		return 0
+	}
+	return int64(*v)
+}
+
+func toStrings(ids []graphql.ID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = string(id)
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"context"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/server"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type teamResolver struct {
+	team *dto.GetTeamResponse
+}
+
+func (r *teamResolver) Name() string {
+	return r.team.TeamName
+}
+
+// Members участники приходят вместе с командой, отдельного похода за ними нет
+func (r *teamResolver) Members() []*userResolver {
+	members := make([]*userResolver, len(r.team.Members))
+	for i, m := range r.team.Members {
+		members[i] = &userResolver{user: &dto.UserResponse{
+			UserID:   m.UserID,
+			Username: m.Username,
+			TeamName: r.team.TeamName,
+			IsActive: m.IsActive,
+			Skills:   m.Skills,
+			Level:    m.Level,
+		}}
+	}
+	return members
+}
+
+type userResolver struct {
+	user *dto.UserResponse
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return graphql.ID(r.user.UserID)
+}
+
+func (r *userResolver) Username() string {
+	return r.user.Username
+}
+
+func (r *userResolver) IsActive() bool {
+	return r.user.IsActive
+}
+
+func (r *userResolver) Skills() []string {
+	if r.user.Skills == nil {
+		return []string{}
+	}
+	return r.user.Skills
+}
+
+// Level null, если уровень не задан
+func (r *userResolver) Level() *string {
+	if r.user.Level == "" {
+		return nil
+	}
+	return &r.user.Level
+}
+
+func (r *userResolver) Team(ctx context.Context) (*teamResolver, error) {
+	team, err := loadersFrom(ctx).teams.Load(ctx, r.user.TeamName)()
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, notFoundError(server.ErrTeamNotFound)
+	}
+	return &teamResolver{team: team}, nil
+}
+
+// ReviewQueue очереди всех пользователей уровня грузятся одним батчем, PR из них — следующим
+func (r *userResolver) ReviewQueue(ctx context.Context, args struct{ Status *string }) ([]*pullRequestResolver, error) {
+	l := loadersFrom(ctx)
+	ids, err := l.reviewQueues.Load(ctx, r.user.UserID)()
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := loadAll(ctx, l.pullRequests, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Status != nil {
+		filtered := prs[:0]
+		for _, pr := range prs {
+			if pr.Status == *args.Status {
+				filtered = append(filtered, pr)
+			}
+		}
+		prs = filtered
+	}
+	return toPullRequestResolvers(prs), nil
+}
+
+type pullRequestResolver struct {
+	pr *dto.PullRequestMergedResponse
+}
+
+func (r *pullRequestResolver) ID() graphql.ID {
+	return graphql.ID(r.pr.PullRequestID)
+}
+
+func (r *pullRequestResolver) Name() string {
+	return r.pr.PullRequestName
+}
+
+func (r *pullRequestResolver) Status() string {
+	return r.pr.Status
+}
+
+func (r *pullRequestResolver) Author(ctx context.Context) (*userResolver, error) {
+	author, err := loadersFrom(ctx).users.Load(ctx, r.pr.AuthorID)()
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, notFoundError(server.ErrUserNotFound)
+	}
+	return &userResolver{user: author}, nil
+}
+
+func (r *pullRequestResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	reviewers, err := loadAll(ctx, loadersFrom(ctx).users, r.pr.AssignedReviewers)
+	if err != nil {
+		return nil, err
+	}
+	return toUserResolvers(reviewers), nil
+}
+
+func (r *pullRequestResolver) MergedAt() *graphql.Time {
+	if r.pr.MergedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.pr.MergedAt}
+}
+
+// Version Int в GraphQL 32-битный, до переполнения версии PR не дорастают
+func (r *pullRequestResolver) Version() int32 {
+	return int32(r.pr.Version)
+}
+
+// reassignResultResolver PR перечитывается после замены, чтобы отдать новый список ревьюеров и версию
+type reassignResultResolver struct {
+	pullRequestID string
+	replacedBy    string
+}
+
+func (r *reassignResultResolver) PullRequest(ctx context.Context) (*pullRequestResolver, error) {
+	pr, err := loadersFrom(ctx).pullRequests.Load(ctx, r.pullRequestID)()
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, notFoundError(server.ErrPRNotFound)
+	}
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+func (r *reassignResultResolver) ReplacedBy(ctx context.Context) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, r.replacedBy)()
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, notFoundError(server.ErrUserNotFound)
+	}
+	return &userResolver{user: user}, nil
+}
+
+func toTeamResolvers(teams []*dto.GetTeamResponse) []*teamResolver {
+	out := make([]*teamResolver, len(teams))
+	for i, t := range teams {
+		out[i] = &teamResolver{team: t}
+	}
+	return out
+}
+
+func toUserResolvers(users []*dto.UserResponse) []*userResolver {
+	out := make([]*userResolver, len(users))
+	for i, u := range users {
+		out[i] = &userResolver{user: u}
+	}
+	return out
+}
+
+func toPullRequestResolvers(prs []*dto.PullRequestMergedResponse) []*pullRequestResolver {
+	out := make([]*pullRequestResolver, len(prs))
+	for i, pr := range prs {
+		out[i] = &pullRequestResolver{pr: pr}
+	}
+	return out
+}
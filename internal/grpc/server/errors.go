@@ -0,0 +1,112 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/http/codes"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain google.rpc.ErrorInfo.domain во всех ошибках сервиса
+const ErrorDomain = "pr-manager"
+
+type errorMeta struct {
+	Code    string
+	Message string
+	Status  grpccodes.Code
+}
+
+var internalErrorMeta = errorMeta{codes.INTERNAL_ERROR, server.ErrInternalError, grpccodes.Internal}
+
+// те же виды ошибок и коды, что и в pkg/http/error_wrapper, порядок так же важен
+var serviceErrors = []struct {
+	kind error
+	meta errorMeta
+}{
+	{service.ErrTeamAlreadyExists, errorMeta{codes.TEAM_EXISTS, server.ErrTeamAlreadyExists, grpccodes.AlreadyExists}},
+	{service.ErrTeamNotFound, errorMeta{codes.NOT_FOUND, server.ErrTeamNotFound, grpccodes.NotFound}},
+	{service.ErrUserNotFound, errorMeta{codes.NOT_FOUND, server.ErrUserNotFound, grpccodes.NotFound}},
+	{service.ErrAbsenceNotFound, errorMeta{codes.NOT_FOUND, server.ErrAbsenceNotFound, grpccodes.NotFound}},
+	{service.ErrPullRequestExists, errorMeta{codes.PR_EXISTS, server.ErrPRAlreadyExists, grpccodes.AlreadyExists}},
+	{service.ErrPullRequestNotFound, errorMeta{codes.NOT_FOUND, server.ErrPRNotFound, grpccodes.NotFound}},
+	{service.ErrPullRequestMerged, errorMeta{codes.PR_MERGED, server.ErrPullRequestMerged, grpccodes.FailedPrecondition}},
+	{service.ErrPullRequestClosed, errorMeta{codes.PR_CLOSED, server.ErrPullRequestClosed, grpccodes.FailedPrecondition}},
+	{service.ErrReviewerNotAssigned, errorMeta{codes.NOT_ASSIGNED, server.ErrReviewerNotAssigned, grpccodes.FailedPrecondition}},
+	{service.ErrNoReplacementCandidate, errorMeta{codes.NO_CANDIDATE, server.ErrNoReplacementCandidate, grpccodes.FailedPrecondition}},
+	{service.ErrReviewerRulesUnmet, errorMeta{codes.REVIEWER_RULE_UNMET, server.ErrReviewerRulesUnmet, grpccodes.FailedPrecondition}},
+	// ABORTED, а не FAILED_PRECONDITION: клиенту нужно перечитать PR и повторить, как на 412 в HTTP
+	{service.ErrVersionMismatch, errorMeta{codes.VERSION_MISMATCH, server.ErrVersionMismatch, grpccodes.Aborted}},
+	{service.ErrReassignLimitExceeded, errorMeta{codes.RATE_LIMITED, server.ErrReassignLimitExceeded, grpccodes.ResourceExhausted}},
+	{service.ErrInternalError, internalErrorMeta},
+}
+
+func lookupServiceError(err error) errorMeta {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.kind) {
+			return e.meta
+		}
+	}
+	return internalErrorMeta
+}
+
+// serviceError переводит ошибку уровня service в статус gRPC. Полная цепочка пишется в лог только для Internal
+func serviceError(err error) error {
+	meta := lookupServiceError(err)
+
+	if meta.Status == grpccodes.Internal {
+		slog.Error("service error", slog.String("code", meta.Code), slog.String("error", err.Error()))
+	}
+
+	st := status.New(meta.Status, meta.Message)
+	var svcErr *service.Error
+	if errors.As(err, &svcErr) && svcErr.RetryAfter > 0 {
+		// google.rpc.RetryInfo — аналог Retry-After в HTTP
+		detailed, dErr := st.WithDetails(
+			&errdetails.ErrorInfo{Reason: meta.Code, Domain: ErrorDomain},
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(svcErr.RetryAfter)},
+		)
+		if dErr == nil {
+			return detailed.Err()
+		}
+	}
+	return withInfo(st, meta.Code)
+}
+
+// validationError INVALID_ARGUMENT с нарушениями по полям в google.rpc.BadRequest
+func validationError(err error) error {
+	var vErr *dto.ValidationError
+	if !errors.As(err, &vErr) {
+		return withInfo(status.New(grpccodes.InvalidArgument, server.ErrValidation), codes.VALIDATION_ERROR)
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range vErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Message,
+			Reason:      v.Rule,
+		})
+	}
+
+	st := status.New(grpccodes.InvalidArgument, vErr.Error())
+	detailed, dErr := st.WithDetails(&errdetails.ErrorInfo{Reason: codes.VALIDATION_ERROR, Domain: ErrorDomain}, badRequest)
+	if dErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func withInfo(st *status.Status, code string) error {
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: ErrorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
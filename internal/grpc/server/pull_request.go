@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	prmanagerv1 "service-order-avito/pkg/api/prmanager/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mockgen -source="internal/grpc/server/pull_request.go" -destination="internal/grpc/server/mocks/mock_pull_request_service.go" -package=mocks PullRequestService
+type PullRequestService interface {
+	Create(context.Context, *dto.PullRequestCreateRequest) (*dto.PullRequestCreateResponse, error)
+	Get(context.Context, *dto.GetPullRequestRequest) (*dto.GetPullRequestResponse, error)
+	Merge(context.Context, *dto.PullRequestMergeRequest) (*dto.PullRequestMergeResponse, error)
+	Close(context.Context, *dto.PullRequestCloseRequest) (*dto.PullRequestCloseResponse, error)
+	ReassignReviewer(context.Context, *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error)
+}
+
+type pullRequestServer struct {
+	prmanagerv1.UnimplementedPullRequestServiceServer
+	prService PullRequestService
+}
+
+func NewPullRequestServer(prService PullRequestService) *pullRequestServer {
+	return &pullRequestServer{prService: prService}
+}
+
+func (s *pullRequestServer) CreatePullRequest(ctx context.Context, in *prmanagerv1.CreatePullRequestRequest) (*prmanagerv1.PullRequest, error) {
+	req := dto.PullRequestCreateRequest{
+		PullRequestID:   in.GetPullRequestId(),
+		PullRequestName: in.GetPullRequestName(),
+		AuthorID:        in.GetAuthorId(),
+		RequiredTags:    in.GetRequiredTags(),
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.prService.Create(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	pr := resp.PullRequest
+	return &prmanagerv1.PullRequest{
+		PullRequestId:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorID,
+		Status:            toStatus(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		Version:           pr.Version,
+		RequiredTags:      pr.RequiredTags,
+	}, nil
+}
+
+func (s *pullRequestServer) GetPullRequest(ctx context.Context, in *prmanagerv1.GetPullRequestRequest) (*prmanagerv1.PullRequest, error) {
+	req := dto.GetPullRequestRequest{PullRequestID: in.GetPullRequestId()}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.prService.Get(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toPullRequest(resp.PullRequest), nil
+}
+
+func (s *pullRequestServer) MergePullRequest(ctx context.Context, in *prmanagerv1.MergePullRequestRequest) (*prmanagerv1.PullRequest, error) {
+	req := dto.PullRequestMergeRequest{
+		PullRequestID:   in.GetPullRequestId(),
+		ExpectedVersion: in.GetExpectedVersion(),
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.prService.Merge(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toPullRequest(resp.PullRequest), nil
+}
+
+func (s *pullRequestServer) ClosePullRequest(ctx context.Context, in *prmanagerv1.ClosePullRequestRequest) (*prmanagerv1.PullRequest, error) {
+	req := dto.PullRequestCloseRequest{
+		PullRequestID:   in.GetPullRequestId(),
+		ExpectedVersion: in.GetExpectedVersion(),
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.prService.Close(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toPullRequest(resp.PullRequest), nil
+}
+
+func (s *pullRequestServer) ReassignReviewer(ctx context.Context, in *prmanagerv1.ReassignReviewerRequest) (*prmanagerv1.ReassignReviewerResponse, error) {
+	req := dto.PullRequestReassignRequest{
+		PullRequestID:   in.GetPullRequestId(),
+		OldReviewerID:   in.GetOldUserId(),
+		ExpectedVersion: in.GetExpectedVersion(),
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.prService.ReassignReviewer(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &prmanagerv1.ReassignReviewerResponse{
+		ReplacedBy: resp.ReplacedBy,
+		Version:    resp.Version,
+	}, nil
+}
+
+func toPullRequest(pr dto.PullRequestMergedResponse) *prmanagerv1.PullRequest {
+	out := &prmanagerv1.PullRequest{
+		PullRequestId:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorID,
+		Status:            toStatus(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		Version:           pr.Version,
+		RequiredTags:      pr.RequiredTags,
+	}
+	if pr.MergedAt != nil {
+		out.MergedAt = timestamppb.New(*pr.MergedAt)
+	}
+	return out
+}
+
+func toStatus(status string) prmanagerv1.PullRequestStatus {
+	switch status {
+	case domain.PRStatusOpen:
+		return prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case domain.PRStatusMerged:
+		return prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	case domain.PRStatusClosed:
+		return prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_CLOSED
+	default:
+		return prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+// toLevel пустой или неизвестный уровень — LEVEL_UNSPECIFIED
+func toLevel(level string) prmanagerv1.Level {
+	switch domain.Level(level) {
+	case domain.LevelJunior:
+		return prmanagerv1.Level_LEVEL_JUNIOR
+	case domain.LevelMiddle:
+		return prmanagerv1.Level_LEVEL_MIDDLE
+	case domain.LevelSenior:
+		return prmanagerv1.Level_LEVEL_SENIOR
+	case domain.LevelLead:
+		return prmanagerv1.Level_LEVEL_LEAD
+	default:
+		return prmanagerv1.Level_LEVEL_UNSPECIFIED
+	}
+}
@@ -0,0 +1,74 @@
+// Package server gRPC API поверх тех же сервисов, что и HTTP (см. api/proto)
+package server
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/http/codes"
+	prmanagerv1 "service-order-avito/pkg/api/prmanager/v1"
+	"time"
+
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer регистрирует сервисы PR-Manager, стандартный grpc.health.v1 и reflection (для grpcurl).
+// Возвращенный health.Server переводится в NOT_SERVING при остановке, так же как /readyz
+func NewServer(log *slog.Logger, teamService TeamService, userService UserService, prService PullRequestService) (*grpc.Server, *health.Server) {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			withLogger(log),
+			withRecovery(log),
+		),
+	)
+
+	prmanagerv1.RegisterTeamServiceServer(srv, NewTeamServer(teamService))
+	prmanagerv1.RegisterUserServiceServer(srv, NewUserServer(userService))
+	prmanagerv1.RegisterPullRequestServiceServer(srv, NewPullRequestServer(prService))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	reflection.Register(srv)
+
+	return srv, healthServer
+}
+
+// withLogger аналог middleware.WithLogger для gRPC
+func withLogger(log *slog.Logger) grpc.UnaryServerInterceptor {
+	log = log.With(slog.String("component", "grpc/logger"))
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		log.Info("request completed",
+			slog.String("method", info.FullMethod),
+			slog.String("code", status.Code(err).String()),
+			slog.Duration("time", time.Since(start)),
+		)
+		return resp, err
+	}
+}
+
+// withRecovery паника в обработчике превращается в INTERNAL, а не роняет процесс
+func withRecovery(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Error("panic in grpc handler",
+					slog.String("method", info.FullMethod),
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+				err = withInfo(status.New(grpccodes.Internal, server.ErrInternalError), codes.INTERNAL_ERROR)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
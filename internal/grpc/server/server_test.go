@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/grpc/server/mocks"
+	"service-order-avito/internal/http/codes"
+	prmanagerv1 "service-order-avito/pkg/api/prmanager/v1"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testEnv struct {
+	team   *mocks.MockTeamService
+	user   *mocks.MockUserService
+	pr     *mocks.MockPullRequestService
+	conn   *grpc.ClientConn
+	health interface{ Shutdown() }
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	env := &testEnv{
+		team: mocks.NewMockTeamService(ctrl),
+		user: mocks.NewMockUserService(ctrl),
+		pr:   mocks.NewMockPullRequestService(ctrl),
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, healthServer := NewServer(log, env.team, env.user, env.pr)
+	env.health = healthServer
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	env.conn = conn
+
+	return env
+}
+
+func errorReason(t *testing.T, err error) string {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			assert.Equal(t, ErrorDomain, info.Domain)
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestPullRequestServer(t *testing.T) {
+	env := newTestEnv(t)
+	client := prmanagerv1.NewPullRequestServiceClient(env.conn)
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		env.pr.EXPECT().
+			Create(gomock.Any(), &dto.PullRequestCreateRequest{PullRequestID: "pr1", PullRequestName: "name", AuthorID: "u1", RequiredTags: []string{"go"}}).
+			Return(&dto.PullRequestCreateResponse{PullRequest: dto.PullRequestResponse{
+				PullRequestID: "pr1", PullRequestName: "name", AuthorID: "u1", Status: "OPEN", AssignedReviewers: []string{"u2"},
+				RequiredTags: []string{"go"}, Version: 1,
+			}}, nil)
+
+		pr, err := client.CreatePullRequest(ctx, &prmanagerv1.CreatePullRequestRequest{
+			PullRequestId: "pr1", PullRequestName: "name", AuthorId: "u1", RequiredTags: []string{"go"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, pr.Status)
+		assert.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+		assert.Equal(t, []string{"go"}, pr.RequiredTags)
+		assert.Equal(t, int64(1), pr.Version)
+	})
+
+	t.Run("merge passes expected version", func(t *testing.T) {
+		mergedAt := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+		env.pr.EXPECT().
+			Merge(gomock.Any(), &dto.PullRequestMergeRequest{PullRequestID: "pr1", ExpectedVersion: 2}).
+			Return(&dto.PullRequestMergeResponse{PullRequest: dto.PullRequestMergedResponse{
+				PullRequestID: "pr1", Status: "MERGED", MergedAt: &mergedAt, Version: 3,
+			}}, nil)
+
+		pr, err := client.MergePullRequest(ctx, &prmanagerv1.MergePullRequestRequest{PullRequestId: "pr1", ExpectedVersion: 2})
+		require.NoError(t, err)
+		assert.Equal(t, prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED, pr.Status)
+		assert.True(t, mergedAt.Equal(pr.MergedAt.AsTime()))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		env.pr.EXPECT().
+			Close(gomock.Any(), &dto.PullRequestCloseRequest{PullRequestID: "pr1", ExpectedVersion: 3}).
+			Return(&dto.PullRequestCloseResponse{PullRequest: dto.PullRequestMergedResponse{
+				PullRequestID: "pr1", Status: "CLOSED", Version: 4,
+			}}, nil)
+
+		pr, err := client.ClosePullRequest(ctx, &prmanagerv1.ClosePullRequestRequest{PullRequestId: "pr1", ExpectedVersion: 3})
+		require.NoError(t, err)
+		assert.Equal(t, prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_CLOSED, pr.Status)
+		assert.Nil(t, pr.MergedAt)
+		assert.Equal(t, int64(4), pr.Version)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		_, err := client.ReassignReviewer(ctx, &prmanagerv1.ReassignReviewerRequest{PullRequestId: "pr1"})
+		assert.Equal(t, grpccodes.InvalidArgument, status.Code(err))
+		assert.Equal(t, codes.VALIDATION_ERROR, errorReason(t, err))
+
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, d := range status.Convert(err).Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				violations = br.FieldViolations
+			}
+		}
+		require.Len(t, violations, 1)
+		assert.Equal(t, "old_user_id", violations[0].Field)
+	})
+}
+
+func TestServiceErrorCodes(t *testing.T) {
+	env := newTestEnv(t)
+	client := prmanagerv1.NewPullRequestServiceClient(env.conn)
+	ctx := context.Background()
+
+	tests := []struct {
+		err    error
+		code   grpccodes.Code
+		reason string
+	}{
+		{service.ErrPullRequestNotFound, grpccodes.NotFound, codes.NOT_FOUND},
+		{service.ErrUserNotFound, grpccodes.NotFound, codes.NOT_FOUND},
+		{service.ErrAbsenceNotFound, grpccodes.NotFound, codes.NOT_FOUND},
+		{service.ErrPullRequestMerged, grpccodes.FailedPrecondition, codes.PR_MERGED},
+		{service.ErrPullRequestClosed, grpccodes.FailedPrecondition, codes.PR_CLOSED},
+		{service.ErrReviewerNotAssigned, grpccodes.FailedPrecondition, codes.NOT_ASSIGNED},
+		{service.ErrNoReplacementCandidate, grpccodes.FailedPrecondition, codes.NO_CANDIDATE},
+		{service.ErrReviewerRulesUnmet, grpccodes.FailedPrecondition, codes.REVIEWER_RULE_UNMET},
+		{service.ErrVersionMismatch, grpccodes.Aborted, codes.VERSION_MISMATCH},
+		{&service.Error{Kind: service.ErrReassignLimitExceeded, RetryAfter: time.Minute}, grpccodes.ResourceExhausted, codes.RATE_LIMITED},
+		{&service.Error{Kind: service.ErrInternalError, Cause: errors.New("connection reset")}, grpccodes.Internal, codes.INTERNAL_ERROR},
+		{errors.New("unexpected"), grpccodes.Internal, codes.INTERNAL_ERROR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			env.pr.EXPECT().ReassignReviewer(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			_, err := client.ReassignReviewer(ctx, &prmanagerv1.ReassignReviewerRequest{PullRequestId: "pr1", OldUserId: "u2"})
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.reason, errorReason(t, err))
+			// причина внутренней ошибки не уходит клиенту
+			assert.NotContains(t, status.Convert(err).Message(), "connection reset")
+		})
+	}
+
+	t.Run("retry info", func(t *testing.T) {
+		env.pr.EXPECT().ReassignReviewer(gomock.Any(), gomock.Any()).
+			Return(nil, &service.Error{Kind: service.ErrReassignLimitExceeded, RetryAfter: time.Minute})
+
+		_, err := client.ReassignReviewer(ctx, &prmanagerv1.ReassignReviewerRequest{PullRequestId: "pr1", OldUserId: "u2"})
+		var retryInfo *errdetails.RetryInfo
+		for _, d := range status.Convert(err).Details() {
+			if info, ok := d.(*errdetails.RetryInfo); ok {
+				retryInfo = info
+			}
+		}
+		require.NotNil(t, retryInfo)
+		assert.Equal(t, time.Minute, retryInfo.RetryDelay.AsDuration())
+	})
+}
+
+func TestTeamAndUserServers(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	env.team.EXPECT().
+		AddTeam(gomock.Any(), &dto.TeamAddRequest{TeamName: "backend", Members: []dto.TeamMemberRequest{{UserID: "u1", Username: "Alice", IsActive: true}}}).
+		Return(&dto.AddTeamResponse{TeamName: "backend", Members: []dto.TeamMemberResponse{
+			{UserID: "u1", Username: "Alice", IsActive: true, Skills: []string{"go", "sql"}, Level: "SENIOR"},
+		}}, nil)
+
+	team, err := prmanagerv1.NewTeamServiceClient(env.conn).AddTeam(ctx, &prmanagerv1.AddTeamRequest{
+		TeamName: "backend",
+		Members:  []*prmanagerv1.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+	})
+	require.NoError(t, err)
+	require.Len(t, team.Members, 1)
+	assert.Equal(t, "u1", team.Members[0].UserId)
+	assert.Equal(t, []string{"go", "sql"}, team.Members[0].Skills)
+	assert.Equal(t, prmanagerv1.Level_LEVEL_SENIOR, team.Members[0].Level)
+
+	env.team.EXPECT().AddTeam(gomock.Any(), gomock.Any()).Return(nil, service.ErrTeamAlreadyExists)
+	_, err = prmanagerv1.NewTeamServiceClient(env.conn).AddTeam(ctx, &prmanagerv1.AddTeamRequest{TeamName: "backend"})
+	assert.Equal(t, grpccodes.AlreadyExists, status.Code(err))
+	assert.Equal(t, codes.TEAM_EXISTS, errorReason(t, err))
+
+	env.user.EXPECT().
+		SetIsActive(gomock.Any(), &dto.SetIsActiveRequest{UserID: "u1", IsActive: false}).
+		Return(&dto.SetIsActiveResponse{User: dto.UserResponse{UserID: "u1", Username: "Alice", TeamName: "backend", Skills: []string{"go"}}}, nil)
+
+	user, err := prmanagerv1.NewUserServiceClient(env.conn).SetIsActive(ctx, &prmanagerv1.SetIsActiveRequest{UserId: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, user.Skills)
+	// уровень не задан
+	assert.Equal(t, prmanagerv1.Level_LEVEL_UNSPECIFIED, user.Level)
+
+	env.user.EXPECT().
+		GetReviewPullRequests(gomock.Any(), &dto.GetReviewPRRequest{UserID: "u2"}).
+		Return(&dto.GetReviewPRResponse{UserID: "u2", PullRequests: []dto.PullRequestShortResponse{{PullRequestID: "pr1", Status: "OPEN"}}}, nil)
+
+	reviews, err := prmanagerv1.NewUserServiceClient(env.conn).GetReviewPullRequests(ctx, &prmanagerv1.GetReviewPullRequestsRequest{UserId: "u2"})
+	require.NoError(t, err)
+	require.Len(t, reviews.PullRequests, 1)
+	assert.Equal(t, prmanagerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, reviews.PullRequests[0].Status)
+}
+
+func TestHealth(t *testing.T) {
+	env := newTestEnv(t)
+	client := healthpb.NewHealthClient(env.conn)
+	ctx := context.Background()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	env.health.Shutdown()
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"service-order-avito/internal/domain/dto"
+	prmanagerv1 "service-order-avito/pkg/api/prmanager/v1"
+)
+
+// mockgen -source="internal/grpc/server/team.go" -destination="internal/grpc/server/mocks/mock_team_service.go" -package=mocks TeamService
+type TeamService interface {
+	AddTeam(context.Context, *dto.TeamAddRequest) (*dto.AddTeamResponse, error)
+	GetTeam(context.Context, *dto.GetTeamRequest) (*dto.GetTeamResponse, error)
+	GetTeamStats(context.Context, *dto.GetTeamStatsRequest) (*dto.TeamStatsResponse, error)
+}
+
+type teamServer struct {
+	prmanagerv1.UnimplementedTeamServiceServer
+	teamService TeamService
+}
+
+func NewTeamServer(teamService TeamService) *teamServer {
+	return &teamServer{teamService: teamService}
+}
+
+func (s *teamServer) AddTeam(ctx context.Context, in *prmanagerv1.AddTeamRequest) (*prmanagerv1.Team, error) {
+	req := dto.TeamAddRequest{
+		TeamName: in.GetTeamName(),
+		Members:  make([]dto.TeamMemberRequest, 0, len(in.GetMembers())),
+	}
+	for _, m := range in.GetMembers() {
+		req.Members = append(req.Members, dto.TeamMemberRequest{
+			UserID:   m.GetUserId(),
+			Username: m.GetUsername(),
+			IsActive: m.GetIsActive(),
+		})
+	}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.teamService.AddTeam(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toTeam(resp.TeamName, resp.Members), nil
+}
+
+func (s *teamServer) GetTeam(ctx context.Context, in *prmanagerv1.GetTeamRequest) (*prmanagerv1.Team, error) {
+	req := dto.GetTeamRequest{TeamName: in.GetTeamName()}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.teamService.GetTeam(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toTeam(resp.TeamName, resp.Members), nil
+}
+
+func (s *teamServer) GetTeamStats(ctx context.Context, in *prmanagerv1.GetTeamStatsRequest) (*prmanagerv1.TeamStats, error) {
+	req := dto.GetTeamStatsRequest{TeamName: in.GetTeamName()}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.teamService.GetTeamStats(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &prmanagerv1.TeamStats{
+		TeamName:      resp.TeamName,
+		ActiveUsers:   int32(resp.ActiveUsers),
+		InactiveUsers: int32(resp.InactiveUsers),
+		OpenPrs:       int32(resp.OpenPRs),
+		MergedPrs:     int32(resp.MergedPRs),
+	}, nil
+}
+
+func toTeam(name string, members []dto.TeamMemberResponse) *prmanagerv1.Team {
+	team := &prmanagerv1.Team{
+		TeamName: name,
+		Members:  make([]*prmanagerv1.TeamMember, 0, len(members)),
+	}
+	for _, m := range members {
+		team.Members = append(team.Members, &prmanagerv1.TeamMember{
+			UserId:   m.UserID,
+			Username: m.Username,
+			IsActive: m.IsActive,
+			Skills:   m.Skills,
+			Level:    toLevel(m.Level),
+		})
+	}
+	return team
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"service-order-avito/internal/domain/dto"
+	prmanagerv1 "service-order-avito/pkg/api/prmanager/v1"
+)
+
+// mockgen -source="internal/grpc/server/user.go" -destination="internal/grpc/server/mocks/mock_user_service.go" -package=mocks UserService
+type UserService interface {
+	SetIsActive(ctx context.Context, req *dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error)
+	GetReviewPullRequests(context.Context, *dto.GetReviewPRRequest) (*dto.GetReviewPRResponse, error)
+}
+
+type userServer struct {
+	prmanagerv1.UnimplementedUserServiceServer
+	userService UserService
+}
+
+func NewUserServer(userService UserService) *userServer {
+	return &userServer{userService: userService}
+}
+
+func (s *userServer) SetIsActive(ctx context.Context, in *prmanagerv1.SetIsActiveRequest) (*prmanagerv1.User, error) {
+	req := dto.SetIsActiveRequest{UserID: in.GetUserId(), IsActive: in.GetIsActive()}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.userService.SetIsActive(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &prmanagerv1.User{
+		UserId:   resp.User.UserID,
+		Username: resp.User.Username,
+		TeamName: resp.User.TeamName,
+		IsActive: resp.User.IsActive,
+		Skills:   resp.User.Skills,
+		Level:    toLevel(resp.User.Level),
+	}, nil
+}
+
+func (s *userServer) GetReviewPullRequests(ctx context.Context, in *prmanagerv1.GetReviewPullRequestsRequest) (*prmanagerv1.GetReviewPullRequestsResponse, error) {
+	req := dto.GetReviewPRRequest{UserID: in.GetUserId()}
+	if err := dto.Validate(&req); err != nil {
+		return nil, validationError(err)
+	}
+
+	resp, err := s.userService.GetReviewPullRequests(ctx, &req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	out := &prmanagerv1.GetReviewPullRequestsResponse{
+		UserId:       resp.UserID,
+		PullRequests: make([]*prmanagerv1.PullRequestShort, 0, len(resp.PullRequests)),
+	}
+	for _, pr := range resp.PullRequests {
+		out.PullRequests = append(out.PullRequests, &prmanagerv1.PullRequestShort{
+			PullRequestId:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorID,
+			Status:          toStatus(pr.Status),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusFail         = "fail"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Checker проверка одной зависимости сервиса (бд, версия схемы, ...)
+type Checker interface {
+	Name() string
+	// Check возвращает ошибку, если зависимость недоступна. details попадают в ответ /readyz как есть
+	Check(ctx context.Context) (details map[string]any, err error)
+}
+
+// DependencyReport результат проверки одной зависимости
+type DependencyReport struct {
+	Status     string         `json:"status"`
+	DurationMS int64          `json:"duration_ms"`
+	Error      string         `json:"error,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// Report результат проверки готовности сервиса
+type Report struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyReport `json:"checks,omitempty"`
+}
+
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Probe собирает проверки зависимостей для /readyz.
+// После SetShuttingDown сервис сразу перестает быть готовым, чтобы балансировщик успел снять с него трафик
+type Probe struct {
+	checkers     []Checker
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewProbe(timeout time.Duration, checkers ...Checker) *Probe {
+	return &Probe{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+func (p *Probe) SetShuttingDown() {
+	p.shuttingDown.Store(true)
+}
+
+// Ready запускает все проверки параллельно, каждая ограничена таймаутом пробы
+func (p *Probe) Ready(ctx context.Context) Report {
+	if p.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]DependencyReport, len(p.checkers)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range p.checkers {
+		wg.Add(1)
+		go func(c Checker) {
+			defer wg.Done()
+
+			start := time.Now()
+			details, err := c.Check(ctx)
+			dep := DependencyReport{
+				Status:     StatusOK,
+				DurationMS: time.Since(start).Milliseconds(),
+				Details:    details,
+			}
+			if err != nil {
+				dep.Status = StatusFail
+				dep.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.Name()] = dep
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return report
+}
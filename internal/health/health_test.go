@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChecker struct {
+	name    string
+	details map[string]any
+	err     error
+	delay   time.Duration
+}
+
+func (c fakeChecker) Name() string {
+	return c.name
+}
+
+func (c fakeChecker) Check(ctx context.Context) (map[string]any, error) {
+	if c.delay > 0 {
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			return c.details, ctx.Err()
+		}
+	}
+	return c.details, c.err
+}
+
+func TestProbe_Ready(t *testing.T) {
+	tests := []struct {
+		name           string
+		checkers       []Checker
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "no checkers",
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{},
+		},
+		{
+			name: "all ok",
+			checkers: []Checker{
+				fakeChecker{name: "postgres"},
+				fakeChecker{name: "schema", details: map[string]any{"current": 2, "expected": 2}},
+			},
+			expectedStatus: StatusOK,
+			expectedChecks: map[string]string{"postgres": StatusOK, "schema": StatusOK},
+		},
+		{
+			name: "one failing",
+			checkers: []Checker{
+				fakeChecker{name: "postgres", err: errors.New("connection refused")},
+				fakeChecker{name: "schema"},
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"postgres": StatusFail, "schema": StatusOK},
+		},
+		{
+			name: "timeout",
+			checkers: []Checker{
+				fakeChecker{name: "postgres", delay: time.Second},
+			},
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]string{"postgres": StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := NewProbe(50*time.Millisecond, tt.checkers...)
+
+			report := probe.Ready(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			require.Len(t, report.Checks, len(tt.expectedChecks))
+			for name, status := range tt.expectedChecks {
+				assert.Equal(t, status, report.Checks[name].Status, name)
+				if status == StatusFail {
+					assert.NotEmpty(t, report.Checks[name].Error)
+				}
+			}
+		})
+	}
+}
+
+func TestProbe_ShuttingDown(t *testing.T) {
+	probe := NewProbe(time.Second, fakeChecker{name: "postgres"})
+	require.True(t, probe.Ready(context.Background()).Ready())
+
+	probe.SetShuttingDown()
+
+	report := probe.Ready(context.Background())
+	assert.False(t, report.Ready())
+	assert.Equal(t, StatusShuttingDown, report.Status)
+}
@@ -1,12 +1,23 @@
 package codes
 
 const (
-	TEAM_EXISTS    = "TEAM_EXISTS"
-	PR_EXISTS      = "PR_EXISTS"
-	PR_MERGED      = "PR_MERGED"
-	NOT_ASSIGNED   = "NOT_ASSIGNED"
-	NO_CANDIDATE   = "NO_CANDIDATE"
-	NOT_FOUND      = "NOT_FOUND"
-	INTERNAL_ERROR = "INTERNAL_ERROR"
-	INVALID_JSON   = "INVALID_JSON"
+	TEAM_EXISTS      = "TEAM_EXISTS"
+	PR_EXISTS        = "PR_EXISTS"
+	PR_MERGED        = "PR_MERGED"
+	PR_CLOSED        = "PR_CLOSED"
+	NOT_ASSIGNED     = "NOT_ASSIGNED"
+	NO_CANDIDATE     = "NO_CANDIDATE"
+	NOT_FOUND        = "NOT_FOUND"
+	INTERNAL_ERROR   = "INTERNAL_ERROR"
+	INVALID_JSON     = "INVALID_JSON"
+	INVALID_FILE     = "INVALID_FILE"
+	VALIDATION_ERROR = "VALIDATION_ERROR"
+	VERSION_MISMATCH = "VERSION_MISMATCH"
+	RATE_LIMITED     = "RATE_LIMITED"
+
+	// REVIEWER_RULE_UNMET правила команды к уровням ревьюеров не выполнить среди доступных кандидатов
+	REVIEWER_RULE_UNMET = "REVIEWER_RULE_UNMET"
+
+	IDEMPOTENCY_KEY_REUSED      = "IDEMPOTENCY_KEY_REUSED"
+	IDEMPOTENCY_KEY_IN_PROGRESS = "IDEMPOTENCY_KEY_IN_PROGRESS"
 )
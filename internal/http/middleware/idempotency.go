@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/go-chi/chi/v5/middleware"
+	"io"
+	"log/slog"
+	"net/http"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/pkg/http/error_wrapper"
+	"service-order-avito/pkg/http/etag"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
+
+	maxIdempotencyKeyLen = 255
+)
+
+// IdempotencyStore хранилище ключей, реализации в internal/repository/{postgres,sqlite,memory}
+type IdempotencyStore interface {
+	// Reserve занимает ключ и возвращает nil, либо возвращает уже существующий живой ключ
+	Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, resp domain.IdempotentResponse) error
+	Release(ctx context.Context, key string) error
+}
+
+// WithIdempotency повторяет ответ на POST с тем же Idempotency-Key вместо повторного выполнения.
+// Отпечаток запроса — метод, путь и тело: тот же ключ с другим запросом отклоняется (422),
+// пока первый запрос не завершился — 409. Ответы 5xx не сохраняются, чтобы повтор мог выполниться заново.
+// Повтор отдает тело, Content-Type и ETag исходного ответа.
+// Ключи общие для всех клиентов: аутентификации в сервисе нет, поэтому клиенты должны генерировать
+// уникальные ключи (UUID), а не последовательные номера, иначе чужой ключ вернет чужой ответ
+func WithIdempotency(log *slog.Logger, store IdempotencyStore, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/idempotency"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderIdempotencyKey)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !validIdempotencyKey(key) {
+				error_wrapper.WriteError(w, r, codes.VALIDATION_ERROR, server.ErrIdempotencyKeyInvalid, http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			fingerprint := requestFingerprint(r, body)
+			existing, err := store.Reserve(r.Context(), domain.IdempotencyKey{
+				Key:         key,
+				Fingerprint: fingerprint,
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			})
+			if err != nil {
+				log.Error("reserve idempotency key", slog.String("error", err.Error()))
+				error_wrapper.WriteError(w, r, codes.INTERNAL_ERROR, server.ErrInternalError, http.StatusInternalServerError)
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.Fingerprint != fingerprint:
+					error_wrapper.WriteError(w, r, codes.IDEMPOTENCY_KEY_REUSED, server.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity)
+				case existing.Response == nil:
+					error_wrapper.WriteError(w, r, codes.IDEMPOTENCY_KEY_IN_PROGRESS, server.ErrIdempotencyKeyInProgress, http.StatusConflict)
+				default:
+					replay(w, existing.Response)
+				}
+				return
+			}
+
+			// ответ сохраняется даже если клиент уже отключился: повтор получит результат
+			storeCtx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if !completed {
+					if err := store.Release(storeCtx, key); err != nil {
+						log.Error("release idempotency key", slog.String("error", err.Error()))
+					}
+				}
+			}()
+
+			var buf bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			err = store.Complete(storeCtx, key, domain.IdempotentResponse{
+				StatusCode:  status,
+				ContentType: ww.Header().Get("Content-Type"),
+				ETag:        ww.Header().Get(etag.HeaderETag),
+				Body:        buf.Bytes(),
+			})
+			if err != nil {
+				log.Error("complete idempotency key", slog.String("error", err.Error()))
+				return
+			}
+			completed = true
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func replay(w http.ResponseWriter, resp *domain.IdempotentResponse) {
+	if resp.ContentType != "" {
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+	// по ETag клиент, повторивший запрос после таймаута, может дальше менять PR с If-Match
+	if resp.ETag != "" {
+		w.Header().Set(etag.HeaderETag, resp.ETag)
+	}
+	w.Header().Set(HeaderIdempotencyReplayed, "true")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
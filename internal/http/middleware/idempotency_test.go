@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler отвечает status и номером вызова, чтобы было видно, выполнялся ли обработчик повторно
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"call": h.calls, "body": string(body)})
+}
+
+func newIdempotencyHandler(store IdempotencyStore, next http.Handler) http.Handler {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return WithIdempotency(log, store, time.Hour)(next)
+}
+
+func do(t *testing.T, h http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, "/pullRequest/create", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(HeaderIdempotencyKey, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp dto.ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp.Error.Code
+}
+
+func TestWithIdempotency(t *testing.T) {
+	t.Run("replay returns original response", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusCreated}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		first := do(t, h, http.MethodPost, "key-1", `{"pull_request_id":"pr-1"}`)
+		second := do(t, h, http.MethodPost, "key-1", `{"pull_request_id":"pr-1"}`)
+
+		assert.Equal(t, 1, next.calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Empty(t, first.Header().Get(HeaderIdempotencyReplayed))
+		assert.Equal(t, "true", second.Header().Get(HeaderIdempotencyReplayed))
+	})
+
+	t.Run("replay keeps etag", func(t *testing.T) {
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"2"`)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		do(t, h, http.MethodPost, "key-1", `{}`)
+		second := do(t, h, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, "true", second.Header().Get(HeaderIdempotencyReplayed))
+		assert.Equal(t, `"2"`, second.Header().Get("ETag"))
+	})
+
+	t.Run("4xx is replayed too", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusConflict}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		do(t, h, http.MethodPost, "key-1", `{}`)
+		second := do(t, h, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, 1, next.calls)
+		assert.Equal(t, http.StatusConflict, second.Code)
+	})
+
+	t.Run("different body is rejected", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusOK}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		do(t, h, http.MethodPost, "key-1", `{"pull_request_id":"pr-1"}`)
+		second := do(t, h, http.MethodPost, "key-1", `{"pull_request_id":"pr-2"}`)
+
+		assert.Equal(t, 1, next.calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+		assert.Equal(t, codes.IDEMPOTENCY_KEY_REUSED, errorCode(t, second))
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		store := memory.NewIdempotencyRepositoryMemory(memory.NewStorage())
+		next := &countingHandler{status: http.StatusOK}
+		h := newIdempotencyHandler(store, next)
+
+		// первый запрос с тем же отпечатком занял ключ, но еще не ответил
+		_, err := store.Reserve(context.Background(), domain.IdempotencyKey{
+			Key:         "key-1",
+			Fingerprint: requestFingerprint(httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil), []byte(`{}`)),
+			CreatedAt:   time.Now(),
+			ExpiresAt:   time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		w := do(t, h, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, 0, next.calls)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, codes.IDEMPOTENCY_KEY_IN_PROGRESS, errorCode(t, w))
+	})
+
+	t.Run("5xx is not stored", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusInternalServerError}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		do(t, h, http.MethodPost, "key-1", `{}`)
+		next.status = http.StatusCreated
+		second := do(t, h, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, 2, next.calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Empty(t, second.Header().Get(HeaderIdempotencyReplayed))
+	})
+
+	t.Run("panic releases key", func(t *testing.T) {
+		calls := 0
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				panic("boom")
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		assert.Panics(t, func() { do(t, h, http.MethodPost, "key-1", `{}`) })
+		w := do(t, h, http.MethodPost, "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusOK}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		do(t, h, http.MethodPost, "", `{}`)
+		do(t, h, http.MethodPost, "", `{}`)
+
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("not a POST", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusOK}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		do(t, h, http.MethodGet, "key-1", "")
+		do(t, h, http.MethodGet, "key-1", "")
+
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		next := &countingHandler{status: http.StatusOK}
+		h := newIdempotencyHandler(memory.NewIdempotencyRepositoryMemory(memory.NewStorage()), next)
+
+		w := do(t, h, http.MethodPost, strings.Repeat("k", 256), `{}`)
+
+		assert.Equal(t, 0, next.calls)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, codes.VALIDATION_ERROR, errorCode(t, w))
+	})
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net"
+	"net/http"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/pkg/http/error_wrapper"
+	"time"
+)
+
+// RouteLimiter реализация в internal/ratelimit
+type RouteLimiter interface {
+	// Allow route — "METHOD /шаблон chi", client — ключ клиента. false — лимит исчерпан, повторить через duration
+	Allow(route, client string) (bool, time.Duration)
+}
+
+// WithRateLimit отвечает 429 RATE_LIMITED с Retry-After, когда клиент исчерпал лимит маршрута.
+// Клиент — значение principalHeader (его ставит шлюз после аутентификации), без него — IP из RemoteAddr.
+// Маршрут ищется по шаблону chi, поэтому /pull-requests/{id}/reassign для всех PR — один маршрут
+func WithRateLimit(log *slog.Logger, limiter RouteLimiter, principalHeader string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/ratelimit"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			route := routePattern(r)
+			if route == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := clientKey(r, principalHeader)
+			ok, wait := limiter.Allow(r.Method+" "+route, client)
+			if ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			log.Warn("rate limit exceeded",
+				slog.String("route", r.Method+" "+route),
+				slog.String("client", client),
+				slog.Duration("retry_after", wait),
+			)
+			error_wrapper.SetRetryAfter(w, wait)
+			error_wrapper.WriteError(w, r, codes.RATE_LIMITED, server.ErrRateLimited, http.StatusTooManyRequests)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// routePattern полный шаблон маршрута. Middleware может стоять до того, как chi дошел до вложенного роутера,
+// поэтому шаблон ищется заново от корневого роутера
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	return rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+}
+
+func clientKey(r *http.Request, principalHeader string) string {
+	if principalHeader != "" {
+		if principal := r.Header.Get(principalHeader); principal != "" {
+			return "principal:" + principal
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
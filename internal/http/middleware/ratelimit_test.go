@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/http/codes"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRouteLimiter пропускает limit запросов на пару маршрут-клиент и запоминает, что у него спрашивали
+type fakeRouteLimiter struct {
+	limit int
+	calls map[string]int
+}
+
+func (l *fakeRouteLimiter) Allow(route, client string) (bool, time.Duration) {
+	l.calls[route+" "+client]++
+	return l.calls[route+" "+client] <= l.limit, 1500 * time.Millisecond
+}
+
+func newRateLimitRouter(limiter RouteLimiter, principalHeader string) chi.Router {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	r := chi.NewRouter()
+	r.Get("/livez", ok)
+	r.Group(func(r chi.Router) {
+		r.Use(WithRateLimit(log, limiter, principalHeader))
+		r.Route("/api/v1/pull-requests", func(r chi.Router) {
+			r.Post("/{id}/reassign", ok)
+		})
+		r.Get("/team/get", ok)
+	})
+	return r
+}
+
+func send(h http.Handler, method, target, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, nil)
+	r.RemoteAddr = remoteAddr
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestWithRateLimit(t *testing.T) {
+	t.Run("429 with Retry-After and RATE_LIMITED", func(t *testing.T) {
+		limiter := &fakeRouteLimiter{limit: 1, calls: map[string]int{}}
+		h := newRateLimitRouter(limiter, "")
+
+		first := send(h, http.MethodGet, "/team/get?team_name=backend", "10.0.0.1:5000", nil)
+		second := send(h, http.MethodGet, "/team/get?team_name=backend", "10.0.0.1:5001", nil)
+
+		assert.Equal(t, http.StatusOK, first.Code)
+		require.Equal(t, http.StatusTooManyRequests, second.Code)
+		assert.Equal(t, "2", second.Header().Get("Retry-After"))
+		assert.Equal(t, codes.RATE_LIMITED, errorCode(t, second))
+		// порт не важен: клиент — IP
+		assert.Equal(t, 2, limiter.calls["GET /team/get ip:10.0.0.1"])
+	})
+
+	t.Run("route is the chi pattern", func(t *testing.T) {
+		limiter := &fakeRouteLimiter{limit: 1, calls: map[string]int{}}
+		h := newRateLimitRouter(limiter, "")
+
+		send(h, http.MethodPost, "/api/v1/pull-requests/pr1/reassign", "10.0.0.1:5000", nil)
+		w := send(h, http.MethodPost, "/api/v1/pull-requests/pr2/reassign", "10.0.0.1:5000", nil)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, 2, limiter.calls["POST /api/v1/pull-requests/{id}/reassign ip:10.0.0.1"])
+	})
+
+	t.Run("principal header", func(t *testing.T) {
+		limiter := &fakeRouteLimiter{limit: 1, calls: map[string]int{}}
+		h := newRateLimitRouter(limiter, "X-Client-Id")
+
+		first := send(h, http.MethodGet, "/team/get", "10.0.0.1:5000", http.Header{"X-Client-Id": {"bot"}})
+		other := send(h, http.MethodGet, "/team/get", "10.0.0.1:5000", http.Header{"X-Client-Id": {"ci"}})
+		noHeader := send(h, http.MethodGet, "/team/get", "10.0.0.1:5000", nil)
+
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, http.StatusOK, other.Code)
+		assert.Equal(t, http.StatusOK, noHeader.Code)
+		assert.Equal(t, 1, limiter.calls["GET /team/get principal:bot"])
+		assert.Equal(t, 1, limiter.calls["GET /team/get ip:10.0.0.1"])
+	})
+
+	t.Run("routes outside the group are not limited", func(t *testing.T) {
+		limiter := &fakeRouteLimiter{limit: 0, calls: map[string]int{}}
+		h := newRateLimitRouter(limiter, "")
+
+		assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/livez", "10.0.0.1:5000", nil).Code)
+		assert.Equal(t, http.StatusNotFound, send(h, http.MethodGet, "/missing", "10.0.0.1:5000", nil).Code)
+		assert.Empty(t, limiter.calls)
+	})
+}
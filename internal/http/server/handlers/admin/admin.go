@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/importer"
+	"service-order-avito/pkg/http/error_wrapper"
+	"strconv"
+)
+
+// maxImportSize ограничение тела импорта, файл читается в память целиком
+const maxImportSize = 10 << 20
+
+// mockgen -source="internal/http/server/handlers/admin/admin.go" -destination="internal/http/server/handlers/admin/mocks/mock_import_service.go" -package=mocks ImportService
+type ImportService interface {
+	Import(context.Context, *dto.ImportRequest) (*dto.ImportResponse, error)
+}
+
+type adminHandler struct {
+	importService ImportService
+}
+
+func NewAdminHandler(importService ImportService) *adminHandler {
+	return &adminHandler{importService: importService}
+}
+
+// Import POST /admin/import?dry_run=true&format=csv — команды и участники из CSV, JSON или YAML.
+// Формат берется из ?format, иначе из Content-Type. Весь файл валидируется до записи и применяется одной транзакцией
+func (h *adminHandler) Import(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var (
+		format string
+		err    error
+	)
+	if f := query.Get("format"); f != "" {
+		format, err = importer.ParseFormat(f)
+	} else {
+		format, err = importer.FormatFromContentType(r.Header.Get("Content-Type"))
+	}
+	if err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_FILE, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	dryRun := false
+	if raw := query.Get("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			error_wrapper.WriteValidationError(w, r, &dto.ValidationError{Violations: []dto.FieldViolation{
+				{Field: "dry_run", Rule: "boolean", Message: "must be true or false"},
+			}})
+			return
+		}
+	}
+
+	req, err := importer.Parse(http.MaxBytesReader(w, r.Body, maxImportSize), format)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			error_wrapper.WriteError(w, r, codes.INVALID_FILE, server.ErrInvalidImportFile+": file is larger than 10 MiB", http.StatusRequestEntityTooLarge)
+			return
+		}
+		error_wrapper.WriteError(w, r, codes.INVALID_FILE, server.ErrInvalidImportFile+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.DryRun = dryRun
+
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.importService.Import(r.Context(), req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/server/handlers/admin/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const csvFile = "team_name,user_id,username,is_active\nbackend,u1,Alice,true\nbackend,u2,Bob,false\n"
+
+func TestAdminHandler_Import(t *testing.T) {
+	report := &dto.ImportResponse{
+		TeamsCreated: []string{"backend"},
+		UsersCreated: []string{"u1", "u2"},
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		contentType  string
+		body         string
+		wantDryRun   *bool
+		mockErr      error
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "csv by content type",
+			url:          "/admin/import",
+			contentType:  "text/csv",
+			body:         csvFile,
+			wantDryRun:   boolPtr(false),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "dry run, format from query",
+			url:          "/admin/import?dry_run=true&format=csv",
+			contentType:  "application/octet-stream",
+			body:         csvFile,
+			wantDryRun:   boolPtr(true),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unsupported format",
+			url:          "/admin/import",
+			contentType:  "application/xml",
+			body:         "<teams/>",
+			expectedCode: http.StatusUnsupportedMediaType,
+			expectedErr:  codes.INVALID_FILE,
+		},
+		{
+			name:         "bad dry_run",
+			url:          "/admin/import?dry_run=maybe",
+			contentType:  "text/csv",
+			body:         csvFile,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  codes.VALIDATION_ERROR,
+		},
+		{
+			name:         "unparsable file",
+			url:          "/admin/import",
+			contentType:  "text/csv",
+			body:         "team_name,user_id,username,is_active\nbackend,u1,Alice,yes\n",
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  codes.INVALID_FILE,
+		},
+		{
+			name:         "duplicate member across teams",
+			url:          "/admin/import",
+			contentType:  "text/csv",
+			body:         csvFile + "frontend,u1,Alice,true\n",
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  codes.VALIDATION_ERROR,
+		},
+		{
+			name:         "service error",
+			url:          "/admin/import",
+			contentType:  "application/json",
+			body:         `{"teams": [{"team_name": "backend", "members": []}]}`,
+			wantDryRun:   boolPtr(false),
+			mockErr:      service.ErrInternalError,
+			expectedCode: http.StatusInternalServerError,
+			expectedErr:  codes.INTERNAL_ERROR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockImportService(ctrl)
+			handler := NewAdminHandler(mockService)
+
+			if tt.wantDryRun != nil {
+				mockService.EXPECT().
+					Import(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, req *dto.ImportRequest) (*dto.ImportResponse, error) {
+						assert.Equal(t, *tt.wantDryRun, req.DryRun)
+						if tt.mockErr != nil {
+							return nil, tt.mockErr
+						}
+						return report, nil
+					})
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+
+			handler.Import(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedErr != "" {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErr, resp.Error.Code)
+				return
+			}
+
+			var resp dto.ImportResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, report.UsersCreated, resp.UsersCreated)
+		})
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
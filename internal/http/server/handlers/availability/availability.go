@@ -0,0 +1,181 @@
+package availability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/server/handlers"
+	"service-order-avito/internal/ical"
+	"service-order-avito/pkg/http/error_wrapper"
+	"strconv"
+)
+
+// mockgen -source="internal/http/server/handlers/availability/availability.go" -destination="internal/http/server/handlers/availability/mocks/mock_availability_service.go" -package=mocks AvailabilityService
+type AvailabilityService interface {
+	AddAbsence(context.Context, *dto.AddAbsenceRequest) (*dto.AbsenceResponse, error)
+	ListAbsences(context.Context, *dto.GetAbsencesRequest) (*dto.GetAbsencesResponse, error)
+	DeleteAbsence(context.Context, *dto.DeleteAbsenceRequest) error
+	ImportCalendar(context.Context, *dto.ImportCalendarRequest) (*dto.ImportCalendarResponse, error)
+	SetWorkingHours(context.Context, *dto.SetWorkingHoursRequest) (*dto.WorkingHoursResponse, error)
+	GetWorkingHours(context.Context, *dto.GetWorkingHoursRequest) (*dto.WorkingHoursResponse, error)
+}
+
+// maxCalendarSize ограничение тела импорта календаря, как у импорта команд
+const maxCalendarSize = 10 << 20
+
+type availabilityHandler struct {
+	availabilityService AvailabilityService
+}
+
+func NewAvailabilityHandler(availabilityService AvailabilityService) *availabilityHandler {
+	return &availabilityHandler{availabilityService: availabilityService}
+}
+
+// AddAbsence POST /api/v1/users/{id}/absences. user_id из тела игнорируется
+func (h *availabilityHandler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.UserID = handlers.PathParam(r, "id")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.availabilityService.AddAbsence(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListAbsences GET /api/v1/users/{id}/absences
+func (h *availabilityHandler) ListAbsences(w http.ResponseWriter, r *http.Request) {
+	req := dto.GetAbsencesRequest{UserID: handlers.PathParam(r, "id")}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.availabilityService.ListAbsences(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteAbsence DELETE /api/v1/users/{id}/absences/{absenceId}. Нечисловой absenceId отсекает валидация (absence_id = 0)
+func (h *availabilityHandler) DeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	absenceID, _ := strconv.ParseInt(handlers.PathParam(r, "absenceId"), 10, 64)
+	req := dto.DeleteAbsenceRequest{UserID: handlers.PathParam(r, "id"), AbsenceID: absenceID}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	if err := h.availabilityService.DeleteAbsence(r.Context(), &req); err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetWorkingHours PUT /api/v1/users/{id}/working-hours. Расписание заменяется целиком, user_id из тела игнорируется
+func (h *availabilityHandler) SetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetWorkingHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.UserID = handlers.PathParam(r, "id")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.availabilityService.SetWorkingHours(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetWorkingHours GET /api/v1/users/{id}/working-hours
+func (h *availabilityHandler) GetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	req := dto.GetWorkingHoursRequest{UserID: handlers.PathParam(r, "id")}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.availabilityService.GetWorkingHours(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ImportUserCalendar POST /api/v1/users/{id}/absences/import — тело .ics (файл или выгрузка фида),
+// события OOO становятся периодами отсутствия пользователя
+func (h *availabilityHandler) ImportUserCalendar(w http.ResponseWriter, r *http.Request) {
+	h.importCalendar(w, r, dto.ImportCalendarRequest{UserID: handlers.PathParam(r, "id")})
+}
+
+// ImportTeamCalendar POST /api/v1/teams/{name}/absences/import — общий календарь команды,
+// событие достается участникам из ORGANIZER и ATTENDEE
+func (h *availabilityHandler) ImportTeamCalendar(w http.ResponseWriter, r *http.Request) {
+	h.importCalendar(w, r, dto.ImportCalendarRequest{TeamName: handlers.PathParam(r, "name")})
+}
+
+// importCalendar owner — запрос с user_id или team_name из пути, события берутся из тела
+func (h *availabilityHandler) importCalendar(w http.ResponseWriter, r *http.Request, owner dto.ImportCalendarRequest) {
+	if err := dto.Validate(&owner); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	req, err := ical.Parse(http.MaxBytesReader(w, r.Body, maxCalendarSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			error_wrapper.WriteError(w, r, codes.INVALID_FILE, server.ErrInvalidImportFile+": file is larger than 10 MiB", http.StatusRequestEntityTooLarge)
+			return
+		}
+		error_wrapper.WriteError(w, r, codes.INVALID_FILE, server.ErrInvalidImportFile+": "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.UserID, req.TeamName = owner.UserID, owner.TeamName
+
+	resp, err := h.availabilityService.ImportCalendar(r.Context(), req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,272 @@
+package availability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/http/server/handlers/availability/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityHandler_AddAbsence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAvailabilityService(ctrl)
+	handler := NewAvailabilityHandler(mockService)
+
+	t.Run("created, user_id from path", func(t *testing.T) {
+		starts := time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().
+			AddAbsence(gomock.Any(), &dto.AddAbsenceRequest{
+				UserID: "u1", Kind: "VACATION", StartsAt: "2025-12-22", EndsAt: "2025-12-31",
+			}).
+			Return(&dto.AbsenceResponse{AbsenceID: 1, UserID: "u1", Kind: "VACATION", StartsAt: starts, EndsAt: starts.AddDate(0, 0, 10)}, nil)
+
+		body := []byte(`{"user_id":"other","kind":"VACATION","starts_at":"2025-12-22","ends_at":"2025-12-31"}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u1/absences", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.AddAbsence(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+		var resp dto.AbsenceResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, int64(1), resp.AbsenceID)
+		assert.Nil(t, resp.Weekdays)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		body := []byte(`{"kind":"PART_TIME","starts_at":"2025-12-22","ends_at":"2025-12-01"}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u1/absences", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.AddAbsence(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u1/absences", bytes.NewReader([]byte("{"))), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.AddAbsence(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockService.EXPECT().AddAbsence(gomock.Any(), gomock.Any()).Return(nil, service.ErrUserNotFound)
+
+		body := []byte(`{"kind":"SICK_LEAVE","starts_at":"2025-12-22","ends_at":"2025-12-23"}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u9/absences", bytes.NewReader(body)), "id", "u9")
+		w := httptest.NewRecorder()
+
+		handler.AddAbsence(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestAvailabilityHandler_ListAbsences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAvailabilityService(ctrl)
+	handler := NewAvailabilityHandler(mockService)
+
+	mockService.EXPECT().
+		ListAbsences(gomock.Any(), &dto.GetAbsencesRequest{UserID: "u1"}).
+		Return(&dto.GetAbsencesResponse{UserID: "u1", Absences: []dto.AbsenceResponse{}}, nil)
+
+	req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/users/u1/absences", nil), "id", "u1")
+	w := httptest.NewRecorder()
+
+	handler.ListAbsences(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.JSONEq(t, `{"user_id":"u1","absences":[]}`, w.Body.String())
+}
+
+func TestAvailabilityHandler_DeleteAbsence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAvailabilityService(ctrl)
+	handler := NewAvailabilityHandler(mockService)
+
+	t.Run("no content", func(t *testing.T) {
+		mockService.EXPECT().
+			DeleteAbsence(gomock.Any(), &dto.DeleteAbsenceRequest{UserID: "u1", AbsenceID: 3}).
+			Return(nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodDelete, "/api/v1/users/u1/absences/3", nil), "id", "u1", "absenceId", "3")
+		w := httptest.NewRecorder()
+
+		handler.DeleteAbsence(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.EXPECT().
+			DeleteAbsence(gomock.Any(), &dto.DeleteAbsenceRequest{UserID: "u1", AbsenceID: 4}).
+			Return(service.ErrAbsenceNotFound)
+
+		req := withURLParams(httptest.NewRequest(http.MethodDelete, "/api/v1/users/u1/absences/4", nil), "id", "u1", "absenceId", "4")
+		w := httptest.NewRecorder()
+
+		handler.DeleteAbsence(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("invalid absence id", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodDelete, "/api/v1/users/u1/absences/x", nil), "id", "u1", "absenceId", "x")
+		w := httptest.NewRecorder()
+
+		handler.DeleteAbsence(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestAvailabilityHandler_WorkingHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAvailabilityService(ctrl)
+	handler := NewAvailabilityHandler(mockService)
+
+	t.Run("set", func(t *testing.T) {
+		want := &dto.SetWorkingHoursRequest{
+			UserID: "u1", Timezone: "Europe/Moscow", Start: "10:00", End: "19:00", Weekdays: []string{"MON", "FRI"},
+		}
+		mockService.EXPECT().SetWorkingHours(gomock.Any(), want).
+			Return(&dto.WorkingHoursResponse{UserID: "u1", Timezone: "Europe/Moscow", Start: "10:00", End: "19:00", Weekdays: []string{"MON", "FRI"}}, nil)
+
+		body := []byte(`{"user_id":"other","timezone":"Europe/Moscow","start":"10:00","end":"19:00","weekdays":["MON","FRI"]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/users/u1/working-hours", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.SetWorkingHours(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	for name, body := range map[string]string{
+		"unknown timezone": `{"timezone":"Mars/Olympus","start":"10:00","end":"19:00","weekdays":["MON"]}`,
+		"local timezone":   `{"timezone":"Local","start":"10:00","end":"19:00","weekdays":["MON"]}`,
+		"bad clock":        `{"timezone":"UTC","start":"24:00","end":"19:00","weekdays":["MON"]}`,
+		"no weekdays":      `{"timezone":"UTC","start":"10:00","end":"19:00","weekdays":[]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/users/u1/working-hours", strings.NewReader(body)), "id", "u1")
+			w := httptest.NewRecorder()
+
+			handler.SetWorkingHours(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		})
+	}
+
+	t.Run("get unknown user", func(t *testing.T) {
+		mockService.EXPECT().GetWorkingHours(gomock.Any(), &dto.GetWorkingHoursRequest{UserID: "u9"}).Return(nil, service.ErrUserNotFound)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/users/u9/working-hours", nil), "id", "u9")
+		w := httptest.NewRecorder()
+
+		handler.GetWorkingHours(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestAvailabilityHandler_ImportCalendar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAvailabilityService(ctrl)
+	handler := NewAvailabilityHandler(mockService)
+
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nSUMMARY:OOO\r\nDTSTART;VALUE=DATE:20251222\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	t.Run("user calendar", func(t *testing.T) {
+		mockService.EXPECT().
+			ImportCalendar(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *dto.ImportCalendarRequest) (*dto.ImportCalendarResponse, error) {
+				assert.Equal(t, "u1", req.UserID)
+				assert.Empty(t, req.TeamName)
+				require.Len(t, req.Events, 1)
+				assert.Equal(t, "1", req.Events[0].UID)
+				return &dto.ImportCalendarResponse{Created: 1, Skipped: []dto.SkippedEvent{}}, nil
+			})
+
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u1/absences/import", strings.NewReader(ics)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.ImportUserCalendar(w, req)
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var resp dto.ImportCalendarResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, 1, resp.Created)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		mockService.EXPECT().
+			ImportCalendar(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *dto.ImportCalendarRequest) (*dto.ImportCalendarResponse, error) {
+				assert.Equal(t, "ghost", req.TeamName)
+				return nil, service.ErrTeamNotFound
+			})
+
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/teams/ghost/absences/import", strings.NewReader(ics)), "name", "ghost")
+		w := httptest.NewRecorder()
+
+		handler.ImportTeamCalendar(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("not a calendar", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u1/absences/import", strings.NewReader("user_id\nu1\n")), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.ImportUserCalendar(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "INVALID_FILE")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		body := "BEGIN:VCALENDAR\r\n" + strings.Repeat("X-PAD:"+strings.Repeat("a", 1000)+"\r\n", 11<<10)
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/users/u1/absences/import", strings.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.ImportUserCalendar(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Result().StatusCode)
+	})
+}
+
+// withURLParams кладет параметры пути так же, как это делает chi при маршрутизации
+func withURLParams(r *http.Request, kv ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(kv); i += 2 {
+		rctx.URLParams.Add(kv[i], kv[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
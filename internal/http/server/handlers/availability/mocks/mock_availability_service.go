@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/http/server/handlers/availability/availability.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dto "service-order-avito/internal/domain/dto"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAvailabilityService is a mock of AvailabilityService interface.
+type MockAvailabilityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAvailabilityServiceMockRecorder
+}
+
+// MockAvailabilityServiceMockRecorder is the mock recorder for MockAvailabilityService.
+type MockAvailabilityServiceMockRecorder struct {
+	mock *MockAvailabilityService
+}
+
+// NewMockAvailabilityService creates a new mock instance.
+func NewMockAvailabilityService(ctrl *gomock.Controller) *MockAvailabilityService {
+	mock := &MockAvailabilityService{ctrl: ctrl}
+	mock.recorder = &MockAvailabilityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAvailabilityService) EXPECT() *MockAvailabilityServiceMockRecorder {
+	return m.recorder
+}
+
+// AddAbsence mocks base method.
+func (m *MockAvailabilityService) AddAbsence(arg0 context.Context, arg1 *dto.AddAbsenceRequest) (*dto.AbsenceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAbsence", arg0, arg1)
+	ret0, _ := ret[0].(*dto.AbsenceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAbsence indicates an expected call of AddAbsence.
+func (mr *MockAvailabilityServiceMockRecorder) AddAbsence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAbsence", reflect.TypeOf((*MockAvailabilityService)(nil).AddAbsence), arg0, arg1)
+}
+
+// DeleteAbsence mocks base method.
+func (m *MockAvailabilityService) DeleteAbsence(arg0 context.Context, arg1 *dto.DeleteAbsenceRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAbsence", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAbsence indicates an expected call of DeleteAbsence.
+func (mr *MockAvailabilityServiceMockRecorder) DeleteAbsence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAbsence", reflect.TypeOf((*MockAvailabilityService)(nil).DeleteAbsence), arg0, arg1)
+}
+
+// GetWorkingHours mocks base method.
+func (m *MockAvailabilityService) GetWorkingHours(arg0 context.Context, arg1 *dto.GetWorkingHoursRequest) (*dto.WorkingHoursResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkingHours", arg0, arg1)
+	ret0, _ := ret[0].(*dto.WorkingHoursResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkingHours indicates an expected call of GetWorkingHours.
+func (mr *MockAvailabilityServiceMockRecorder) GetWorkingHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkingHours", reflect.TypeOf((*MockAvailabilityService)(nil).GetWorkingHours), arg0, arg1)
+}
+
+// ImportCalendar mocks base method.
+func (m *MockAvailabilityService) ImportCalendar(arg0 context.Context, arg1 *dto.ImportCalendarRequest) (*dto.ImportCalendarResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCalendar", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ImportCalendarResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCalendar indicates an expected call of ImportCalendar.
+func (mr *MockAvailabilityServiceMockRecorder) ImportCalendar(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCalendar", reflect.TypeOf((*MockAvailabilityService)(nil).ImportCalendar), arg0, arg1)
+}
+
+// ListAbsences mocks base method.
+func (m *MockAvailabilityService) ListAbsences(arg0 context.Context, arg1 *dto.GetAbsencesRequest) (*dto.GetAbsencesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAbsences", arg0, arg1)
+	ret0, _ := ret[0].(*dto.GetAbsencesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAbsences indicates an expected call of ListAbsences.
+func (mr *MockAvailabilityServiceMockRecorder) ListAbsences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAbsences", reflect.TypeOf((*MockAvailabilityService)(nil).ListAbsences), arg0, arg1)
+}
+
+// SetWorkingHours mocks base method.
+func (m *MockAvailabilityService) SetWorkingHours(arg0 context.Context, arg1 *dto.SetWorkingHoursRequest) (*dto.WorkingHoursResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWorkingHours", arg0, arg1)
+	ret0, _ := ret[0].(*dto.WorkingHoursResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWorkingHours indicates an expected call of SetWorkingHours.
+func (mr *MockAvailabilityServiceMockRecorder) SetWorkingHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkingHours", reflect.TypeOf((*MockAvailabilityService)(nil).SetWorkingHours), arg0, arg1)
+}
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/events"
+	"service-order-avito/pkg/http/error_wrapper"
+	"time"
+)
+
+// mockgen -source="internal/http/server/handlers/events/events.go" -destination="internal/http/server/handlers/events/mocks/mock_services.go" -package=mocks TeamService,Subscriber
+type TeamService interface {
+	GetTeam(context.Context, *dto.GetTeamRequest) (*dto.GetTeamResponse, error)
+}
+
+type Subscriber interface {
+	Subscribe(lastEventID string) *events.Subscription
+}
+
+// retryMillis подсказка EventSource, через сколько переподключаться после обрыва
+const retryMillis = 3000
+
+type eventsHandler struct {
+	broker      Subscriber
+	teamService TeamService
+	heartbeat   time.Duration
+}
+
+func NewEventsHandler(broker Subscriber, teamService TeamService, heartbeat time.Duration) *eventsHandler {
+	return &eventsHandler{broker: broker, teamService: teamService, heartbeat: heartbeat}
+}
+
+// Stream GET /events/stream?user_id=...&team=... — события PR в формате Server-Sent Events.
+// Поток заканчивается, когда клиент отключился, сервер останавливается или клиент не успевает читать
+func (h *eventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	req := dto.EventStreamRequest{
+		UserID:   r.URL.Query().Get("user_id"),
+		TeamName: r.URL.Query().Get("team"),
+	}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	match, err := h.filter(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	// WriteTimeout сервера рассчитан на обычные запросы, поток живет дольше
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Error("events stream: reset write deadline", slog.String("error", err.Error()))
+	}
+
+	// EventSource сам присылает Last-Event-ID при переподключении, query — для первого подключения
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	sub := h.broker.Subscribe(lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", retryMillis); err != nil {
+		return
+	}
+	for _, e := range sub.Replay {
+		if match(e) {
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if !match(e) {
+				continue
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// filter состав команды берется на момент подключения: событие подходит, если в нем участвует кто-то из команды
+func (h *eventsHandler) filter(ctx context.Context, req *dto.EventStreamRequest) (func(domain.Event) bool, error) {
+	var members map[string]struct{}
+	if req.TeamName != "" {
+		team, err := h.teamService.GetTeam(ctx, &dto.GetTeamRequest{TeamName: req.TeamName})
+		if err != nil {
+			return nil, err
+		}
+		members = make(map[string]struct{}, len(team.Members))
+		for _, m := range team.Members {
+			members[m.UserID] = struct{}{}
+		}
+	}
+
+	return func(e domain.Event) bool {
+		if req.UserID != "" && !e.Involves(req.UserID) {
+			return false
+		}
+		if members != nil {
+			for _, id := range e.Participants() {
+				if _, ok := members[id]; ok {
+					return true
+				}
+			}
+			return false
+		}
+		return true
+	}, nil
+}
+
+func writeEvent(w http.ResponseWriter, e domain.Event) error {
+	data, err := json.Marshal(dto.EventResponse{
+		PullRequestID:     e.PullRequestID,
+		PullRequestName:   e.Name,
+		AuthorID:          e.AuthorID,
+		Status:            e.Status,
+		AssignedReviewers: e.AssignedReviewers,
+		MergedAt:          e.MergedAt,
+		OldUserID:         e.OldReviewerID,
+		ReplacedBy:        e.NewReviewerID,
+		Version:           e.Version,
+		OccurredAt:        e.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
@@ -0,0 +1,194 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/events"
+	"service-order-avito/internal/http/server/handlers/events/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type frame struct {
+	id    string
+	event string
+	data  string
+}
+
+type stream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// open подключается к потоку и ждет, пока обработчик подпишется (первым приходит retry)
+func open(t *testing.T, srv *httptest.Server, query string, header http.Header) *stream {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/events/stream"+query, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	s := &stream{body: resp.Body, reader: bufio.NewReader(resp.Body)}
+	t.Cleanup(func() { s.body.Close() })
+
+	line, err := s.reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "retry:"))
+	_, _ = s.reader.ReadString('\n')
+	return s
+}
+
+// next следующее событие, комментарии (heartbeat) пропускаются
+func (s *stream) next(t *testing.T) frame {
+	t.Helper()
+
+	var f frame
+	for {
+		line, err := s.reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && f.event != "":
+			return f
+		case strings.HasPrefix(line, "id: "):
+			f.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			f.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			f.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func newServer(t *testing.T, broker *events.Broker, teamService TeamService) *httptest.Server {
+	t.Helper()
+
+	h := NewEventsHandler(broker, teamService, 20*time.Millisecond)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events/stream", h.Stream)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEventsHandler_UserFilter(t *testing.T) {
+	broker := events.NewBroker(10, 10)
+	srv := newServer(t, broker, nil)
+
+	s := open(t, srv, "?user_id=u2", nil)
+
+	broker.Publish(domain.Event{Type: domain.EventPullRequestCreated, PullRequestID: "pr-1", AuthorID: "u1", AssignedReviewers: []string{"u3"}})
+	broker.Publish(domain.Event{Type: domain.EventPullRequestReassigned, PullRequestID: "pr-1", OldReviewerID: "u3", NewReviewerID: "u2", Version: 2})
+
+	f := s.next(t)
+	assert.Equal(t, domain.EventPullRequestReassigned, f.event)
+
+	var data dto.EventResponse
+	require.NoError(t, json.Unmarshal([]byte(f.data), &data))
+	assert.Equal(t, "pr-1", data.PullRequestID)
+	assert.Equal(t, "u3", data.OldUserID)
+	assert.Equal(t, "u2", data.ReplacedBy)
+	assert.Equal(t, int64(2), data.Version)
+}
+
+func TestEventsHandler_TeamFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	teamService := mocks.NewMockTeamService(ctrl)
+	broker := events.NewBroker(10, 10)
+	srv := newServer(t, broker, teamService)
+
+	t.Run("members only", func(t *testing.T) {
+		teamService.EXPECT().
+			GetTeam(gomock.Any(), &dto.GetTeamRequest{TeamName: "backend"}).
+			Return(&dto.GetTeamResponse{TeamName: "backend", Members: []dto.TeamMemberResponse{{UserID: "u1"}, {UserID: "u2"}}}, nil)
+
+		s := open(t, srv, "?team=backend", nil)
+
+		broker.Publish(domain.Event{Type: domain.EventPullRequestCreated, PullRequestID: "other", AuthorID: "u9"})
+		broker.Publish(domain.Event{Type: domain.EventPullRequestMerged, PullRequestID: "mine", AuthorID: "u1"})
+
+		f := s.next(t)
+		assert.Equal(t, domain.EventPullRequestMerged, f.event)
+		assert.Contains(t, f.data, `"pull_request_id":"mine"`)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		teamService.EXPECT().
+			GetTeam(gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrTeamNotFound)
+
+		resp, err := srv.Client().Get(srv.URL + "/events/stream?team=missing")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestEventsHandler_Validation(t *testing.T) {
+	srv := newServer(t, events.NewBroker(10, 10), nil)
+
+	resp, err := srv.Client().Get(srv.URL + "/events/stream?user_id=bad%20id")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEventsHandler_LastEventID(t *testing.T) {
+	broker := events.NewBroker(10, 10)
+	srv := newServer(t, broker, nil)
+
+	first := open(t, srv, "", nil)
+	broker.Publish(domain.Event{Type: domain.EventPullRequestCreated, PullRequestID: "pr-1"})
+	seen := first.next(t)
+	first.body.Close()
+
+	// пока клиент был отключен
+	broker.Publish(domain.Event{Type: domain.EventPullRequestMerged, PullRequestID: "pr-1"})
+
+	s := open(t, srv, "", http.Header{"Last-Event-Id": {seen.id}})
+	f := s.next(t)
+	assert.Equal(t, domain.EventPullRequestMerged, f.event)
+	assert.NotEqual(t, seen.id, f.id)
+}
+
+func TestEventsHandler_BrokerClosed(t *testing.T) {
+	broker := events.NewBroker(10, 10)
+	srv := newServer(t, broker, nil)
+
+	s := open(t, srv, "", nil)
+	broker.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(s.body)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("stream must end when broker is closed")
+	}
+}
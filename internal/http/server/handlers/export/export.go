@@ -0,0 +1,111 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/exporter"
+	"service-order-avito/pkg/http/error_wrapper"
+	"time"
+)
+
+// mockgen -source="internal/http/server/handlers/export/export.go" -destination="internal/http/server/handlers/export/mocks/mock_export_service.go" -package=mocks ExportService
+type ExportService interface {
+	Export(context.Context, *dto.ExportRequest, func(dto.PullRequestExportRow) error) error
+}
+
+type exportHandler struct {
+	exportService ExportService
+}
+
+func NewExportHandler(exportService ExportService) *exportHandler {
+	return &exportHandler{exportService: exportService}
+}
+
+// PullRequests GET /export/pullRequests?format=csv|ndjson&team=...&from=...&to=... — одна строка на PR
+func (h *exportHandler) PullRequests(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, "pull_requests", exporter.NewPullRequestWriter)
+}
+
+// Reviews GET /export/reviews с теми же параметрами — одна строка на пару PR — ревьюер
+func (h *exportHandler) Reviews(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, "reviews", exporter.NewReviewWriter)
+}
+
+// export строки пишутся в ответ по мере чтения из репозитория. Пока в ответ ничего не ушло
+// (CSV буферизуется), ошибка отдается как обычно. Если выгрузка оборвалась на середине,
+// соединение разрывается, чтобы клиент не принял обрезанный файл за целый
+func (h *exportHandler) export(w http.ResponseWriter, r *http.Request, name string, newWriter func(io.Writer, string) (exporter.Writer, error)) {
+	query := r.URL.Query()
+	req := dto.ExportRequest{
+		Format:   query.Get("format"),
+		TeamName: query.Get("team"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+	}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+	if req.Format == "" {
+		req.Format = exporter.FormatCSV
+	}
+
+	body := &lazyResponse{w: w, contentType: exporter.ContentType(req.Format), filename: name + "." + req.Format}
+	out, err := newWriter(body, req.Format)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	// WriteTimeout сервера рассчитан на обычные запросы, большая выгрузка пишется дольше
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Error("export: reset write deadline", slog.String("error", err.Error()))
+	}
+
+	err = h.exportService.Export(r.Context(), &req, out.Write)
+	if err == nil {
+		err = out.Flush()
+	}
+	if err == nil {
+		// пустая NDJSON-выгрузка: тела нет, но заголовки все равно нужны
+		body.start()
+		return
+	}
+
+	if !body.started {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+	if r.Context().Err() == nil {
+		slog.Error("export aborted", slog.String("path", r.URL.Path), slog.String("error", err.Error()))
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// lazyResponse заголовки ответа пишутся при первой записи тела: до нее еще можно ответить ошибкой
+type lazyResponse struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (l *lazyResponse) start() {
+	if l.started {
+		return
+	}
+	l.started = true
+	l.w.Header().Set("Content-Type", l.contentType)
+	l.w.Header().Set("Content-Disposition", `attachment; filename="`+l.filename+`"`)
+	l.w.WriteHeader(http.StatusOK)
+}
+
+func (l *lazyResponse) Write(p []byte) (int, error) {
+	l.start()
+	return l.w.Write(p)
+}
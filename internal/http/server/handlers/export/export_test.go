@@ -0,0 +1,150 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/server/handlers/export/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var row = dto.PullRequestExportRow{
+	PullRequestID:     "pr1",
+	PullRequestName:   "Fix bug",
+	AuthorID:          "u1",
+	TeamName:          "backend",
+	Status:            "OPEN",
+	AssignedReviewers: []string{"u2", "u3"},
+	CreatedAt:         time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC),
+	Version:           1,
+}
+
+// rowsOf сервис, который отдает строки и в конце возвращает err
+func rowsOf(err error, rows ...dto.PullRequestExportRow) func(context.Context, *dto.ExportRequest, func(dto.PullRequestExportRow) error) error {
+	return func(_ context.Context, _ *dto.ExportRequest, fn func(dto.PullRequestExportRow) error) error {
+		for _, r := range rows {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func TestExportHandler_PullRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockExportService(ctrl)
+	handler := NewExportHandler(mockService)
+
+	t.Run("csv by default", func(t *testing.T) {
+		mockService.EXPECT().
+			Export(gomock.Any(), &dto.ExportRequest{Format: "csv", TeamName: "backend", From: "2025-11-01"}, gomock.Any()).
+			DoAndReturn(rowsOf(nil, row))
+
+		rr := httptest.NewRecorder()
+		handler.PullRequests(rr, httptest.NewRequest(http.MethodGet, "/export/pullRequests?team=backend&from=2025-11-01", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="pull_requests.csv"`, rr.Header().Get("Content-Disposition"))
+		lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, "pr1,Fix bug,u1,backend,OPEN,u2;u3,2025-11-20T10:00:00Z,,1", lines[1])
+	})
+
+	t.Run("empty ndjson", func(t *testing.T) {
+		mockService.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rowsOf(nil))
+
+		rr := httptest.NewRecorder()
+		handler.PullRequests(rr, httptest.NewRequest(http.MethodGet, "/export/pullRequests?format=ndjson", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+		assert.Empty(t, rr.Body.String())
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/export/pullRequests?format=xlsx&to=yesterday", nil)
+		req.Header.Set("Accept", "application/problem+json")
+		handler.PullRequests(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		var problem dto.ProblemDetails
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+		assert.Equal(t, codes.VALIDATION_ERROR, problem.Code)
+		assert.Len(t, problem.Errors, 2)
+	})
+
+	t.Run("error before anything was written", func(t *testing.T) {
+		// CSV буферизуется, поэтому даже после строк можно ответить ошибкой
+		mockService.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rowsOf(service.ErrTeamNotFound, row))
+
+		rr := httptest.NewRecorder()
+		handler.PullRequests(rr, httptest.NewRequest(http.MethodGet, "/export/pullRequests?team=missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		var resp dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, codes.NOT_FOUND, resp.Error.Code)
+	})
+}
+
+func TestExportHandler_Reviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockExportService(ctrl)
+	handler := NewExportHandler(mockService)
+
+	mockService.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(rowsOf(nil, row))
+
+	rr := httptest.NewRecorder()
+	handler.Reviews(rr, httptest.NewRequest(http.MethodGet, "/export/reviews?format=ndjson", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `attachment; filename="reviews.ndjson"`, rr.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	var review dto.ReviewExportRow
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &review))
+	assert.Equal(t, "u3", review.ReviewerID)
+}
+
+func TestExportHandler_AbortMidStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockExportService(ctrl)
+	handler := NewExportHandler(mockService)
+
+	mockService.EXPECT().
+		Export(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *dto.ExportRequest, fn func(dto.PullRequestExportRow) error) error {
+			// больше буфера ответа, чтобы заголовки и часть тела точно ушли клиенту
+			for range 1000 {
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			return service.ErrInternalError
+		})
+
+	srv := httptest.NewServer(http.HandlerFunc(handler.PullRequests))
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL + "?format=ndjson")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = io.ReadAll(resp.Body)
+	assert.Error(t, err, "truncated export must not look complete")
+}
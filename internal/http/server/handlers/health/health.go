@@ -0,0 +1,44 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"service-order-avito/internal/health"
+)
+
+// mockgen -source="internal/http/server/handlers/health/health.go" -destination="internal/http/server/handlers/health/mocks/mock_readiness_probe.go" -package=mocks ReadinessProbe
+type ReadinessProbe interface {
+	Ready(context.Context) health.Report
+}
+
+type healthHandler struct {
+	probe ReadinessProbe
+}
+
+func NewHealthHandler(probe ReadinessProbe) *healthHandler {
+	return &healthHandler{probe: probe}
+}
+
+// Livez отвечает, пока процесс жив и обрабатывает запросы. Зависимости не проверяются,
+// иначе недоступная бд приводила бы к перезапуску пода
+func (h *healthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(health.Report{Status: health.StatusOK})
+}
+
+// Readyz проверяет зависимости и отвечает 503, если хотя бы одна недоступна или идет остановка сервера
+func (h *healthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.probe.Ready(r.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"service-order-avito/internal/health"
+	"service-order-avito/internal/http/server/handlers/health/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler_Livez(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// liveness не должна трогать зависимости
+	handler := NewHealthHandler(mocks.NewMockReadinessProbe(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	w := httptest.NewRecorder()
+
+	handler.Livez(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	tests := []struct {
+		name         string
+		report       health.Report
+		expectedCode int
+	}{
+		{
+			name: "ready",
+			report: health.Report{
+				Status: health.StatusOK,
+				Checks: map[string]health.DependencyReport{
+					"postgres": {Status: health.StatusOK},
+				},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "dependency failing",
+			report: health.Report{
+				Status: health.StatusFail,
+				Checks: map[string]health.DependencyReport{
+					"postgres": {Status: health.StatusFail, Error: "connection refused"},
+				},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:         "shutting down",
+			report:       health.Report{Status: health.StatusShuttingDown},
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProbe := mocks.NewMockReadinessProbe(ctrl)
+			mockProbe.EXPECT().Ready(gomock.Any()).Return(tt.report)
+			handler := NewHealthHandler(mockProbe)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+
+			handler.Readyz(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Result().StatusCode)
+
+			var resp health.Report
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tt.report.Status, resp.Status)
+			assert.Len(t, resp.Checks, len(tt.report.Checks))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/http/server/handlers/health/health.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	health "service-order-avito/internal/health"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReadinessProbe is a mock of ReadinessProbe interface.
+type MockReadinessProbe struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadinessProbeMockRecorder
+}
+
+// MockReadinessProbeMockRecorder is the mock recorder for MockReadinessProbe.
+type MockReadinessProbeMockRecorder struct {
+	mock *MockReadinessProbe
+}
+
+// NewMockReadinessProbe creates a new mock instance.
+func NewMockReadinessProbe(ctrl *gomock.Controller) *MockReadinessProbe {
+	mock := &MockReadinessProbe{ctrl: ctrl}
+	mock.recorder = &MockReadinessProbeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReadinessProbe) EXPECT() *MockReadinessProbeMockRecorder {
+	return m.recorder
+}
+
+// Ready mocks base method.
+func (m *MockReadinessProbe) Ready(arg0 context.Context) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", arg0)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockReadinessProbeMockRecorder) Ready(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockReadinessProbe)(nil).Ready), arg0)
+}
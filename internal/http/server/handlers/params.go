@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"net/url"
+)
+
+// PathParam значение параметра пути из роутера /api/v1.
+// chi отдает его как есть из RawPath, поэтому имя команды с пробелом приходит как %20 и раскодируется здесь
+func PathParam(r *http.Request, name string) string {
+	raw := chi.URLParam(r, name)
+	value, err := url.PathUnescape(raw)
+	if err != nil {
+		// невалидное экранирование отсекается валидацией dto
+		return raw
+	}
+	return value
+}
@@ -35,6 +35,21 @@ func (m *MockPullRequestService) EXPECT() *MockPullRequestServiceMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockPullRequestService) Close(arg0 context.Context, arg1 *dto.PullRequestCloseRequest) (*dto.PullRequestCloseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestCloseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockPullRequestServiceMockRecorder) Close(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPullRequestService)(nil).Close), arg0, arg1)
+}
+
 // Create mocks base method.
 func (m *MockPullRequestService) Create(arg0 context.Context, arg1 *dto.PullRequestCreateRequest) (*dto.PullRequestCreateResponse, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,36 @@ func (mr *MockPullRequestServiceMockRecorder) Create(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPullRequestService)(nil).Create), arg0, arg1)
 }
 
+// Explain mocks base method.
+func (m *MockPullRequestService) Explain(arg0 context.Context, arg1 *dto.ExplainAssignmentsRequest) (*dto.PullRequestAssignmentsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestAssignmentsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockPullRequestServiceMockRecorder) Explain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockPullRequestService)(nil).Explain), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockPullRequestService) Get(arg0 context.Context, arg1 *dto.GetPullRequestRequest) (*dto.GetPullRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*dto.GetPullRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPullRequestServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPullRequestService)(nil).Get), arg0, arg1)
+}
+
 // Merge mocks base method.
 func (m *MockPullRequestService) Merge(arg0 context.Context, arg1 *dto.PullRequestMergeRequest) (*dto.PullRequestMergeResponse, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +110,21 @@ func (mr *MockPullRequestServiceMockRecorder) Merge(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequestService)(nil).Merge), arg0, arg1)
 }
 
+// PreviewReviewers mocks base method.
+func (m *MockPullRequestService) PreviewReviewers(arg0 context.Context, arg1 *dto.PreviewReviewersRequest) (*dto.ReviewersPreviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewReviewers", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ReviewersPreviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewReviewers indicates an expected call of PreviewReviewers.
+func (mr *MockPullRequestServiceMockRecorder) PreviewReviewers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewReviewers", reflect.TypeOf((*MockPullRequestService)(nil).PreviewReviewers), arg0, arg1)
+}
+
 // ReassignReviewer mocks base method.
 func (m *MockPullRequestService) ReassignReviewer(arg0 context.Context, arg1 *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error) {
 	m.ctrl.T.Helper()
@@ -7,14 +7,20 @@ import (
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/domain/errors/server"
 	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/server/handlers"
 	"service-order-avito/pkg/http/error_wrapper"
+	"service-order-avito/pkg/http/etag"
 )
 
 // mockgen -source="internal/http/server/handlers/pull_request/pull_request.go" -destination="internal/http/server/handlers/pull_request/mocks/mock_pull_request_service.go" -package=mocks PullRequestService
 type PullRequestService interface {
 	Create(context.Context, *dto.PullRequestCreateRequest) (*dto.PullRequestCreateResponse, error)
+	Get(context.Context, *dto.GetPullRequestRequest) (*dto.GetPullRequestResponse, error)
 	Merge(context.Context, *dto.PullRequestMergeRequest) (*dto.PullRequestMergeResponse, error)
+	Close(context.Context, *dto.PullRequestCloseRequest) (*dto.PullRequestCloseResponse, error)
 	ReassignReviewer(context.Context, *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error)
+	Explain(context.Context, *dto.ExplainAssignmentsRequest) (*dto.PullRequestAssignmentsResponse, error)
+	PreviewReviewers(context.Context, *dto.PreviewReviewersRequest) (*dto.ReviewersPreviewResponse, error)
 }
 
 type pullRequestHandler struct {
@@ -28,56 +34,215 @@ func NewPullRequestHandler(pullRequestService PullRequestService) *pullRequestHa
 func (h *pullRequestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req dto.PullRequestCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
 		return
 	}
 
 	resp, err := h.prService.Create(r.Context(), &req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
+	w.Header().Set(etag.HeaderETag, etag.Format(resp.PullRequest.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(resp)
 	return
 }
 
+// Get отдает PR с ETag. На совпавший If-None-Match отвечает 304 без тела
+func (h *pullRequestHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.get(w, r, &dto.GetPullRequestRequest{PullRequestID: r.URL.Query().Get("pull_request_id")})
+}
+
+// GetByID GET /api/v1/pull-requests/{id}
+func (h *pullRequestHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	h.get(w, r, &dto.GetPullRequestRequest{PullRequestID: handlers.PathParam(r, "id")})
+}
+
+func (h *pullRequestHandler) get(w http.ResponseWriter, r *http.Request, req *dto.GetPullRequestRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.prService.Get(r.Context(), req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set(etag.HeaderETag, etag.Format(resp.PullRequest.Version))
+	if etag.NoneMatch(r, resp.PullRequest.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
 func (h *pullRequestHandler) Merge(w http.ResponseWriter, r *http.Request) {
 	var req dto.PullRequestMergeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
 		return
 	}
+	h.merge(w, r, &req)
+}
 
-	resp, err := h.prService.Merge(r.Context(), &req)
+// MergeByID POST /api/v1/pull-requests/{id}/merge, тело не нужно
+func (h *pullRequestHandler) MergeByID(w http.ResponseWriter, r *http.Request) {
+	h.merge(w, r, &dto.PullRequestMergeRequest{PullRequestID: handlers.PathParam(r, "id")})
+}
+
+func (h *pullRequestHandler) merge(w http.ResponseWriter, r *http.Request, req *dto.PullRequestMergeRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	version, err := etag.ParseIfMatch(r)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteError(w, r, codes.VERSION_MISMATCH, server.ErrVersionMismatch, http.StatusPreconditionFailed)
 		return
 	}
+	req.ExpectedVersion = version
 
+	resp, err := h.prService.Merge(r.Context(), req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set(etag.HeaderETag, etag.Format(resp.PullRequest.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 	return
 }
 
+// CloseByID POST /api/v1/pull-requests/{id}/close, тело не нужно
+func (h *pullRequestHandler) CloseByID(w http.ResponseWriter, r *http.Request) {
+	req := dto.PullRequestCloseRequest{PullRequestID: handlers.PathParam(r, "id")}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	version, err := etag.ParseIfMatch(r)
+	if err != nil {
+		error_wrapper.WriteError(w, r, codes.VERSION_MISMATCH, server.ErrVersionMismatch, http.StatusPreconditionFailed)
+		return
+	}
+	req.ExpectedVersion = version
+
+	resp, err := h.prService.Close(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set(etag.HeaderETag, etag.Format(resp.PullRequest.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
 func (h *pullRequestHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req dto.PullRequestReassignRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	h.reassignReviewer(w, r, &req)
+}
+
+// ReassignByID POST /api/v1/pull-requests/{id}/reassign, в теле только old_user_id
+func (h *pullRequestHandler) ReassignByID(w http.ResponseWriter, r *http.Request) {
+	var req dto.PullRequestReassignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.PullRequestID = handlers.PathParam(r, "id")
+	h.reassignReviewer(w, r, &req)
+}
+
+func (h *pullRequestHandler) reassignReviewer(w http.ResponseWriter, r *http.Request, req *dto.PullRequestReassignRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	version, err := etag.ParseIfMatch(r)
+	if err != nil {
+		error_wrapper.WriteError(w, r, codes.VERSION_MISMATCH, server.ErrVersionMismatch, http.StatusPreconditionFailed)
 		return
 	}
+	req.ExpectedVersion = version
 
-	resp, err := h.prService.ReassignReviewer(r.Context(), &req)
+	resp, err := h.prService.ReassignReviewer(r.Context(), req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
+	w.Header().Set(etag.HeaderETag, etag.Format(resp.Version))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 	return
 }
+
+// Explain GET /api/v1/pull-requests/{id}/explain: записанные выборы ревьюеров с seed и всеми кандидатами.
+// ?reviewer_id= оставляет только выборы, в которых он назначен
+func (h *pullRequestHandler) Explain(w http.ResponseWriter, r *http.Request) {
+	req := dto.ExplainAssignmentsRequest{
+		PullRequestID: handlers.PathParam(r, "id"),
+		ReviewerID:    r.URL.Query().Get("reviewer_id"),
+	}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.prService.Explain(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// PreviewReviewers POST /pullRequest/previewReviewers: кого назначило бы создание PR, ничего не записывая
+func (h *pullRequestHandler) PreviewReviewers(w http.ResponseWriter, r *http.Request) {
+	var req dto.PreviewReviewersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.prService.PreviewReviewers(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -2,13 +2,16 @@ package pull_request
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/http/codes"
 	"service-order-avito/internal/http/server/handlers/pull_request/mocks"
 	"testing"
 )
@@ -61,6 +64,17 @@ func TestPullRequestHandler_Create(t *testing.T) {
 			mockReturnErr:  nil,
 			expectedCode:   http.StatusBadRequest,
 		},
+		{
+			name: "validation error",
+			reqBody: &dto.PullRequestCreateRequest{
+				PullRequestID:   "",
+				PullRequestName: "Fix bug",
+				AuthorID:        "u1",
+			},
+			mockReturnResp: nil,
+			mockReturnErr:  nil,
+			expectedCode:   http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -75,7 +89,7 @@ func TestPullRequestHandler_Create(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewReader(bodyBytes))
 			w := httptest.NewRecorder()
 
-			if tt.name != "invalid json" {
+			if tt.name != "invalid json" && tt.name != "validation error" {
 				mockService.EXPECT().
 					Create(gomock.Any(), gomock.Any()).
 					Return(tt.mockReturnResp, tt.mockReturnErr)
@@ -137,3 +151,349 @@ func TestPullRequestHandler_ReassignReviewer(t *testing.T) {
 	handler.ReassignReviewer(w, req)
 	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
 }
+
+func TestPullRequestHandler_IfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPullRequestService(ctrl)
+	handler := NewPullRequestHandler(mockService)
+
+	t.Run("merge passes version and returns new etag", func(t *testing.T) {
+		mockService.EXPECT().
+			Merge(gomock.Any(), &dto.PullRequestMergeRequest{PullRequestID: "pr1", ExpectedVersion: 2}).
+			Return(&dto.PullRequestMergeResponse{PullRequest: dto.PullRequestMergedResponse{PullRequestID: "pr1", Version: 3}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/merge", bytes.NewReader([]byte(`{"pull_request_id":"pr1"}`)))
+		req.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+
+		handler.Merge(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, `"3"`, w.Result().Header.Get("ETag"))
+	})
+
+	t.Run("reassign with stale version", func(t *testing.T) {
+		mockService.EXPECT().
+			ReassignReviewer(gomock.Any(), &dto.PullRequestReassignRequest{PullRequestID: "pr1", OldReviewerID: "u2", ExpectedVersion: 1}).
+			Return(nil, service.ErrVersionMismatch)
+
+		req := httptest.NewRequest(http.MethodPost, "/reassign", bytes.NewReader([]byte(`{"pull_request_id":"pr1","old_user_id":"u2"}`)))
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		handler.ReassignReviewer(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Result().StatusCode)
+		var resp dto.ErrorResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, codes.VERSION_MISMATCH, resp.Error.Code)
+	})
+
+	t.Run("reassign returns etag", func(t *testing.T) {
+		mockService.EXPECT().
+			ReassignReviewer(gomock.Any(), gomock.Any()).
+			Return(&dto.PullRequestReassignResponse{ReplacedBy: "u3", Version: 5}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/reassign", bytes.NewReader([]byte(`{"pull_request_id":"pr1","old_user_id":"u2"}`)))
+		w := httptest.NewRecorder()
+
+		handler.ReassignReviewer(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, `"5"`, w.Result().Header.Get("ETag"))
+	})
+
+	t.Run("malformed if-match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/merge", bytes.NewReader([]byte(`{"pull_request_id":"pr1"}`)))
+		req.Header.Set("If-Match", `W/"2"`)
+		w := httptest.NewRecorder()
+
+		handler.Merge(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Result().StatusCode)
+	})
+}
+
+func TestPullRequestHandler_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPullRequestService(ctrl)
+	handler := NewPullRequestHandler(mockService)
+
+	resp := &dto.GetPullRequestResponse{PullRequest: dto.PullRequestMergedResponse{PullRequestID: "pr1", Status: "OPEN", Version: 4}}
+
+	t.Run("ok", func(t *testing.T) {
+		mockService.EXPECT().Get(gomock.Any(), &dto.GetPullRequestRequest{PullRequestID: "pr1"}).Return(resp, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/get?pull_request_id=pr1", nil)
+		w := httptest.NewRecorder()
+
+		handler.Get(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, `"4"`, w.Result().Header.Get("ETag"))
+		var got dto.GetPullRequestResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		assert.Equal(t, *resp, got)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		mockService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(resp, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/get?pull_request_id=pr1", nil)
+		req.Header.Set("If-None-Match", `"4"`)
+		w := httptest.NewRecorder()
+
+		handler.Get(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Result().StatusCode)
+		assert.Equal(t, 0, w.Body.Len())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, service.ErrPullRequestNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/get?pull_request_id=missing", nil)
+		w := httptest.NewRecorder()
+
+		handler.Get(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/get", nil)
+		w := httptest.NewRecorder()
+
+		handler.Get(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestPullRequestHandler_ReassignReviewer_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPullRequestService(ctrl)
+	handler := NewPullRequestHandler(mockService)
+
+	bodyBytes, _ := json.Marshal(&dto.PullRequestReassignRequest{PullRequestID: "pr1"})
+	req := httptest.NewRequest(http.MethodPost, "/reassign", bytes.NewReader(bodyBytes))
+	req.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+
+	handler.ReassignReviewer(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	assert.Equal(t, "application/problem+json", w.Result().Header.Get("Content-Type"))
+
+	var problem dto.ProblemDetails
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, codes.VALIDATION_ERROR, problem.Code)
+	if assert.Len(t, problem.Errors, 1) {
+		assert.Equal(t, "old_user_id", problem.Errors[0].Field)
+	}
+}
+
+func TestPullRequestHandler_V1(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPullRequestService(ctrl)
+	handler := NewPullRequestHandler(mockService)
+
+	t.Run("get by id", func(t *testing.T) {
+		mockService.EXPECT().
+			Get(gomock.Any(), &dto.GetPullRequestRequest{PullRequestID: "pr1"}).
+			Return(&dto.GetPullRequestResponse{PullRequest: dto.PullRequestMergedResponse{PullRequestID: "pr1", Version: 1}}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/pull-requests/pr1", nil), "id", "pr1")
+		w := httptest.NewRecorder()
+
+		handler.GetByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, `"1"`, w.Result().Header.Get("ETag"))
+	})
+
+	t.Run("merge without body", func(t *testing.T) {
+		mockService.EXPECT().
+			Merge(gomock.Any(), &dto.PullRequestMergeRequest{PullRequestID: "pr1", ExpectedVersion: 1}).
+			Return(&dto.PullRequestMergeResponse{PullRequest: dto.PullRequestMergedResponse{PullRequestID: "pr1", Version: 2}}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/pull-requests/pr1/merge", nil), "id", "pr1")
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		handler.MergeByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, `"2"`, w.Result().Header.Get("ETag"))
+	})
+
+	t.Run("close without body", func(t *testing.T) {
+		mockService.EXPECT().
+			Close(gomock.Any(), &dto.PullRequestCloseRequest{PullRequestID: "pr1", ExpectedVersion: 1}).
+			Return(&dto.PullRequestCloseResponse{PullRequest: dto.PullRequestMergedResponse{PullRequestID: "pr1", Status: "CLOSED", Version: 2}}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/pull-requests/pr1/close", nil), "id", "pr1")
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		handler.CloseByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, `"2"`, w.Result().Header.Get("ETag"))
+	})
+
+	t.Run("close with stale version", func(t *testing.T) {
+		mockService.EXPECT().
+			Close(gomock.Any(), &dto.PullRequestCloseRequest{PullRequestID: "pr1", ExpectedVersion: 1}).
+			Return(nil, service.ErrVersionMismatch)
+
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/pull-requests/pr1/close", nil), "id", "pr1")
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		handler.CloseByID(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Result().StatusCode)
+	})
+
+	t.Run("merge of closed pr", func(t *testing.T) {
+		mockService.EXPECT().
+			Merge(gomock.Any(), &dto.PullRequestMergeRequest{PullRequestID: "pr1"}).
+			Return(nil, service.ErrPullRequestClosed)
+
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/pull-requests/pr1/merge", nil), "id", "pr1")
+		w := httptest.NewRecorder()
+
+		handler.MergeByID(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		var resp dto.ErrorResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, codes.PR_CLOSED, resp.Error.Code)
+	})
+
+	t.Run("reassign id from path", func(t *testing.T) {
+		mockService.EXPECT().
+			ReassignReviewer(gomock.Any(), &dto.PullRequestReassignRequest{PullRequestID: "pr1", OldReviewerID: "u2"}).
+			Return(&dto.PullRequestReassignResponse{ReplacedBy: "u3", Version: 2}, nil)
+
+		body := []byte(`{"old_user_id":"u2"}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/pull-requests/pr1/reassign", bytes.NewReader(body)), "id", "pr1")
+		w := httptest.NewRecorder()
+
+		handler.ReassignByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("reassign validation error", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodPost, "/api/v1/pull-requests/pr1/reassign", bytes.NewReader([]byte(`{}`))), "id", "pr1")
+		w := httptest.NewRecorder()
+
+		handler.ReassignByID(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestPullRequestHandler_Explain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPullRequestService(ctrl)
+	handler := NewPullRequestHandler(mockService)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.EXPECT().
+			Explain(gomock.Any(), &dto.ExplainAssignmentsRequest{PullRequestID: "pr1", ReviewerID: "u2"}).
+			Return(&dto.PullRequestAssignmentsResponse{
+				PullRequestID: "pr1",
+				Assignments: []dto.AssignmentResponse{{
+					AssignmentID: 1, Kind: "CREATE", Reviewers: []string{"u2"}, Seed: 9007199254740993, Strategy: "RANDOM",
+					Candidates: []dto.AssignmentCandidateResponse{{UserID: "u2", Rank: 1, Chosen: true, Reason: "RANK"}},
+				}},
+			}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/pull-requests/pr1/explain?reviewer_id=u2", nil), "id", "pr1")
+		w := httptest.NewRecorder()
+
+		handler.Explain(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		// seed строкой: больше 2^53 и в число JSON без потерь не помещается
+		assert.Contains(t, w.Body.String(), `"seed":"9007199254740993"`)
+	})
+
+	t.Run("invalid reviewer id", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/pull-requests/pr1/explain?reviewer_id=u%202", nil), "id", "pr1")
+		w := httptest.NewRecorder()
+
+		handler.Explain(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestPullRequestHandler_PreviewReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPullRequestService(ctrl)
+	handler := NewPullRequestHandler(mockService)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.EXPECT().
+			PreviewReviewers(gomock.Any(), &dto.PreviewReviewersRequest{AuthorID: "u1", RequiredTags: []string{"go"}}).
+			Return(&dto.ReviewersPreviewResponse{
+				AuthorID:  "u1",
+				Reviewers: []string{"u2"},
+				Strategy:  "RANDOM",
+				Excluded:  []dto.ExcludedMemberResponse{{UserID: "u3", Reason: "INACTIVE"}},
+			}, nil)
+
+		// тело создания PR тоже подходит: лишние поля игнорируются
+		body := []byte(`{"pull_request_id":"pr1","pull_request_name":"Fix","author_id":"u1","required_tags":["go"]}`)
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/previewReviewers", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.PreviewReviewers(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), `"excluded":[{"user_id":"u3","reason":"INACTIVE"}]`)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/previewReviewers", bytes.NewReader([]byte(`{}`)))
+		w := httptest.NewRecorder()
+
+		handler.PreviewReviewers(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/previewReviewers", bytes.NewReader([]byte(`{`)))
+		w := httptest.NewRecorder()
+
+		handler.PreviewReviewers(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+// withURLParams кладет параметры пути так же, как это делает chi при маршрутизации
+func withURLParams(r *http.Request, kv ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(kv); i += 2 {
+		rctx.URLParams.Add(kv[i], kv[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -50,6 +50,51 @@ func (mr *MockTeamServiceMockRecorder) AddTeam(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeam", reflect.TypeOf((*MockTeamService)(nil).AddTeam), arg0, arg1)
 }
 
+// GetPairingMatrix mocks base method.
+func (m *MockTeamService) GetPairingMatrix(arg0 context.Context, arg1 *dto.GetPairingMatrixRequest) (*dto.PairingMatrixResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPairingMatrix", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PairingMatrixResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPairingMatrix indicates an expected call of GetPairingMatrix.
+func (mr *MockTeamServiceMockRecorder) GetPairingMatrix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPairingMatrix", reflect.TypeOf((*MockTeamService)(nil).GetPairingMatrix), arg0, arg1)
+}
+
+// GetPairingSettings mocks base method.
+func (m *MockTeamService) GetPairingSettings(arg0 context.Context, arg1 *dto.GetPairingSettingsRequest) (*dto.PairingSettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPairingSettings", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PairingSettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPairingSettings indicates an expected call of GetPairingSettings.
+func (mr *MockTeamServiceMockRecorder) GetPairingSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPairingSettings", reflect.TypeOf((*MockTeamService)(nil).GetPairingSettings), arg0, arg1)
+}
+
+// GetReviewerRules mocks base method.
+func (m *MockTeamService) GetReviewerRules(arg0 context.Context, arg1 *dto.GetReviewerRulesRequest) (*dto.ReviewerRulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewerRules", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ReviewerRulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewerRules indicates an expected call of GetReviewerRules.
+func (mr *MockTeamServiceMockRecorder) GetReviewerRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewerRules", reflect.TypeOf((*MockTeamService)(nil).GetReviewerRules), arg0, arg1)
+}
+
 // GetTeam mocks base method.
 func (m *MockTeamService) GetTeam(arg0 context.Context, arg1 *dto.GetTeamRequest) (*dto.GetTeamResponse, error) {
 	m.ctrl.T.Helper()
@@ -79,3 +124,33 @@ func (mr *MockTeamServiceMockRecorder) GetTeamStats(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamStats", reflect.TypeOf((*MockTeamService)(nil).GetTeamStats), arg0, arg1)
 }
+
+// SetPairingSettings mocks base method.
+func (m *MockTeamService) SetPairingSettings(arg0 context.Context, arg1 *dto.SetPairingSettingsRequest) (*dto.PairingSettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPairingSettings", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PairingSettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPairingSettings indicates an expected call of SetPairingSettings.
+func (mr *MockTeamServiceMockRecorder) SetPairingSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPairingSettings", reflect.TypeOf((*MockTeamService)(nil).SetPairingSettings), arg0, arg1)
+}
+
+// SetReviewerRules mocks base method.
+func (m *MockTeamService) SetReviewerRules(arg0 context.Context, arg1 *dto.SetReviewerRulesRequest) (*dto.ReviewerRulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewerRules", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ReviewerRulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReviewerRules indicates an expected call of SetReviewerRules.
+func (mr *MockTeamServiceMockRecorder) SetReviewerRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewerRules", reflect.TypeOf((*MockTeamService)(nil).SetReviewerRules), arg0, arg1)
+}
@@ -7,6 +7,7 @@ import (
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/domain/errors/server"
 	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/server/handlers"
 	"service-order-avito/pkg/http/error_wrapper"
 )
 
@@ -15,6 +16,11 @@ type TeamService interface {
 	AddTeam(context.Context, *dto.TeamAddRequest) (*dto.AddTeamResponse, error)
 	GetTeam(context.Context, *dto.GetTeamRequest) (*dto.GetTeamResponse, error)
 	GetTeamStats(context.Context, *dto.GetTeamStatsRequest) (*dto.TeamStatsResponse, error)
+	SetReviewerRules(context.Context, *dto.SetReviewerRulesRequest) (*dto.ReviewerRulesResponse, error)
+	GetReviewerRules(context.Context, *dto.GetReviewerRulesRequest) (*dto.ReviewerRulesResponse, error)
+	SetPairingSettings(context.Context, *dto.SetPairingSettingsRequest) (*dto.PairingSettingsResponse, error)
+	GetPairingSettings(context.Context, *dto.GetPairingSettingsRequest) (*dto.PairingSettingsResponse, error)
+	GetPairingMatrix(context.Context, *dto.GetPairingMatrixRequest) (*dto.PairingMatrixResponse, error)
 }
 
 type teamHandler struct {
@@ -28,13 +34,17 @@ func NewTeamHandler(teamService TeamService) *teamHandler {
 func (h *teamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 	var req dto.TeamAddRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
 		return
 	}
 
 	resp, err := h.teamService.AddTeam(r.Context(), &req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
@@ -47,13 +57,26 @@ func (h *teamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 func (h *teamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	var req dto.GetTeamRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	h.getTeam(w, r, &req)
+}
+
+// GetTeamByName GET /api/v1/teams/{name}
+func (h *teamHandler) GetTeamByName(w http.ResponseWriter, r *http.Request) {
+	h.getTeam(w, r, &dto.GetTeamRequest{TeamName: handlers.PathParam(r, "name")})
+}
+
+func (h *teamHandler) getTeam(w http.ResponseWriter, r *http.Request, req *dto.GetTeamRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
 		return
 	}
 
-	resp, err := h.teamService.GetTeam(r.Context(), &req)
+	resp, err := h.teamService.GetTeam(r.Context(), req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
@@ -66,13 +89,26 @@ func (h *teamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 func (h *teamHandler) GetTeamStats(w http.ResponseWriter, r *http.Request) {
 	var req dto.GetTeamStatsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	h.getTeamStats(w, r, &req)
+}
+
+// GetTeamStatsByName GET /api/v1/teams/{name}/stats
+func (h *teamHandler) GetTeamStatsByName(w http.ResponseWriter, r *http.Request) {
+	h.getTeamStats(w, r, &dto.GetTeamStatsRequest{TeamName: handlers.PathParam(r, "name")})
+}
+
+func (h *teamHandler) getTeamStats(w http.ResponseWriter, r *http.Request, req *dto.GetTeamStatsRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
 		return
 	}
 
-	resp, err := h.teamService.GetTeamStats(r.Context(), &req)
+	resp, err := h.teamService.GetTeamStats(r.Context(), req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
@@ -81,3 +117,108 @@ func (h *teamHandler) GetTeamStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 	return
 }
+
+// SetReviewerRules PUT /api/v1/teams/{name}/reviewer-rules — правила целиком. team_name из тела игнорируется
+func (h *teamHandler) SetReviewerRules(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetReviewerRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.TeamName = handlers.PathParam(r, "name")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.teamService.SetReviewerRules(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetReviewerRules GET /api/v1/teams/{name}/reviewer-rules
+func (h *teamHandler) GetReviewerRules(w http.ResponseWriter, r *http.Request) {
+	req := dto.GetReviewerRulesRequest{TeamName: handlers.PathParam(r, "name")}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.teamService.GetReviewerRules(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetPairingSettings PUT /api/v1/teams/{name}/pairing-settings. team_name из тела игнорируется
+func (h *teamHandler) SetPairingSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetPairingSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.TeamName = handlers.PathParam(r, "name")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.teamService.SetPairingSettings(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetPairingSettings GET /api/v1/teams/{name}/pairing-settings
+func (h *teamHandler) GetPairingSettings(w http.ResponseWriter, r *http.Request) {
+	req := dto.GetPairingSettingsRequest{TeamName: handlers.PathParam(r, "name")}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.teamService.GetPairingSettings(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetPairingMatrix GET /api/v1/teams/{name}/pairings — матрица автор-ревьюер за окно команды
+func (h *teamHandler) GetPairingMatrix(w http.ResponseWriter, r *http.Request) {
+	req := dto.GetPairingMatrixRequest{TeamName: handlers.PathParam(r, "name")}
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.teamService.GetPairingMatrix(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
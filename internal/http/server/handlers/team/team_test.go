@@ -2,7 +2,9 @@ package team
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -48,6 +50,15 @@ func TestTeamHandler_AddTeam(t *testing.T) {
 			mockReturnErr:  nil,
 			expectedCode:   http.StatusBadRequest,
 		},
+		{
+			name: "validation error",
+			reqBody: &dto.TeamAddRequest{
+				TeamName: "",
+			},
+			mockReturnResp: nil,
+			mockReturnErr:  nil,
+			expectedCode:   http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -65,7 +76,7 @@ func TestTeamHandler_AddTeam(t *testing.T) {
 				bodyBytes, _ = json.Marshal(tt.reqBody)
 			}
 
-			if tt.name != "invalid json" {
+			if tt.name != "invalid json" && tt.name != "validation error" {
 				mockService.EXPECT().
 					AddTeam(gomock.Any(), gomock.Any()).
 					Return(tt.mockReturnResp, tt.mockReturnErr)
@@ -151,3 +162,162 @@ func TestTeamHandler_GetTeam(t *testing.T) {
 		})
 	}
 }
+
+func TestTeamHandler_GetTeamByName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockTeamService(ctrl)
+	handler := NewTeamHandler(mockService)
+
+	t.Run("name from path", func(t *testing.T) {
+		mockService.EXPECT().
+			GetTeam(gomock.Any(), &dto.GetTeamRequest{TeamName: "team one"}).
+			Return(&dto.GetTeamResponse{TeamName: "team one"}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/teams/team%20one", nil), "name", "team%20one")
+		w := httptest.NewRecorder()
+
+		handler.GetTeamByName(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		mockService.EXPECT().
+			GetTeamStats(gomock.Any(), &dto.GetTeamStatsRequest{TeamName: "team1"}).
+			Return(&dto.TeamStatsResponse{TeamName: "team1"}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/teams/team1/stats", nil), "name", "team1")
+		w := httptest.NewRecorder()
+
+		handler.GetTeamStatsByName(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Return(nil, service.ErrTeamNotFound)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/teams/missing", nil), "name", "missing")
+		w := httptest.NewRecorder()
+
+		handler.GetTeamByName(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestTeamHandler_ReviewerRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockTeamService(ctrl)
+	handler := NewTeamHandler(mockService)
+
+	t.Run("set", func(t *testing.T) {
+		mockService.EXPECT().
+			SetReviewerRules(gomock.Any(), &dto.SetReviewerRulesRequest{
+				TeamName: "backend",
+				Rules:    []dto.ReviewerRuleRequest{{MinLevel: "SENIOR", Count: 1}},
+			}).
+			Return(&dto.ReviewerRulesResponse{TeamName: "backend"}, nil)
+
+		body := []byte(`{"team_name":"other","rules":[{"min_level":"SENIOR","count":1}]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/teams/backend/reviewer-rules", bytes.NewReader(body)), "name", "backend")
+		w := httptest.NewRecorder()
+
+		handler.SetReviewerRules(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("set invalid rules", func(t *testing.T) {
+		body := []byte(`{"rules":[{"min_level":"GURU","count":1},{"min_level":"SENIOR","count":3}]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/teams/backend/reviewer-rules", bytes.NewReader(body)), "name", "backend")
+		w := httptest.NewRecorder()
+
+		handler.SetReviewerRules(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		mockService.EXPECT().GetReviewerRules(gomock.Any(), &dto.GetReviewerRulesRequest{TeamName: "missing"}).Return(nil, service.ErrTeamNotFound)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/teams/missing/reviewer-rules", nil), "name", "missing")
+		w := httptest.NewRecorder()
+
+		handler.GetReviewerRules(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestTeamHandler_Pairing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockTeamService(ctrl)
+	handler := NewTeamHandler(mockService)
+
+	t.Run("set settings", func(t *testing.T) {
+		mockService.EXPECT().
+			SetPairingSettings(gomock.Any(), &dto.SetPairingSettingsRequest{TeamName: "backend", Strategy: "PAIRING_DIVERSITY", DecayDays: 14}).
+			Return(&dto.PairingSettingsResponse{TeamName: "backend", Strategy: "PAIRING_DIVERSITY", DecayDays: 14}, nil)
+
+		body := []byte(`{"strategy":"PAIRING_DIVERSITY","decay_days":14}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/teams/backend/pairing-settings", bytes.NewReader(body)), "name", "backend")
+		w := httptest.NewRecorder()
+
+		handler.SetPairingSettings(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("set invalid settings", func(t *testing.T) {
+		body := []byte(`{"strategy":"ROUND_ROBIN","decay_days":400}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/teams/backend/pairing-settings", bytes.NewReader(body)), "name", "backend")
+		w := httptest.NewRecorder()
+
+		handler.SetPairingSettings(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("get settings not found", func(t *testing.T) {
+		mockService.EXPECT().GetPairingSettings(gomock.Any(), &dto.GetPairingSettingsRequest{TeamName: "missing"}).Return(nil, service.ErrTeamNotFound)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/teams/missing/pairing-settings", nil), "name", "missing")
+		w := httptest.NewRecorder()
+
+		handler.GetPairingSettings(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("matrix", func(t *testing.T) {
+		mockService.EXPECT().GetPairingMatrix(gomock.Any(), &dto.GetPairingMatrixRequest{TeamName: "backend"}).
+			Return(&dto.PairingMatrixResponse{
+				TeamName: "backend",
+				Pairs:    []dto.PairingCellResponse{{AuthorID: "u1", ReviewerID: "u2", Count: 3, Score: 2.5}},
+			}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/teams/backend/pairings", nil), "name", "backend")
+		w := httptest.NewRecorder()
+
+		handler.GetPairingMatrix(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), `"pairs":[{"author_id":"u1","reviewer_id":"u2","count":3,"score":2.5}]`)
+	})
+}
+
+// withURLParams кладет параметры пути так же, как это делает chi при маршрутизации
+func withURLParams(r *http.Request, kv ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(kv); i += 2 {
+		rctx.URLParams.Add(kv[i], kv[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -64,3 +64,48 @@ func (mr *MockUserServiceMockRecorder) SetIsActive(ctx, req interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsActive", reflect.TypeOf((*MockUserService)(nil).SetIsActive), ctx, req)
 }
+
+// SetLevel mocks base method.
+func (m *MockUserService) SetLevel(arg0 context.Context, arg1 *dto.SetLevelRequest) (*dto.SetLevelResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLevel", arg0, arg1)
+	ret0, _ := ret[0].(*dto.SetLevelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLevel indicates an expected call of SetLevel.
+func (mr *MockUserServiceMockRecorder) SetLevel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLevel", reflect.TypeOf((*MockUserService)(nil).SetLevel), arg0, arg1)
+}
+
+// SetSkills mocks base method.
+func (m *MockUserService) SetSkills(arg0 context.Context, arg1 *dto.SetSkillsRequest) (*dto.UserSkillsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSkills", arg0, arg1)
+	ret0, _ := ret[0].(*dto.UserSkillsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSkills indicates an expected call of SetSkills.
+func (mr *MockUserServiceMockRecorder) SetSkills(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSkills", reflect.TypeOf((*MockUserService)(nil).SetSkills), arg0, arg1)
+}
+
+// UpdateSkills mocks base method.
+func (m *MockUserService) UpdateSkills(arg0 context.Context, arg1 *dto.UpdateSkillsRequest) (*dto.UserSkillsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSkills", arg0, arg1)
+	ret0, _ := ret[0].(*dto.UserSkillsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSkills indicates an expected call of UpdateSkills.
+func (mr *MockUserServiceMockRecorder) UpdateSkills(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSkills", reflect.TypeOf((*MockUserService)(nil).UpdateSkills), arg0, arg1)
+}
@@ -7,6 +7,7 @@ import (
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/domain/errors/server"
 	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/server/handlers"
 	"service-order-avito/pkg/http/error_wrapper"
 )
 
@@ -14,6 +15,9 @@ import (
 type UserService interface {
 	SetIsActive(ctx context.Context, req *dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error)
 	GetReviewPullRequests(context.Context, *dto.GetReviewPRRequest) (*dto.GetReviewPRResponse, error)
+	SetSkills(context.Context, *dto.SetSkillsRequest) (*dto.UserSkillsResponse, error)
+	UpdateSkills(context.Context, *dto.UpdateSkillsRequest) (*dto.UserSkillsResponse, error)
+	SetLevel(context.Context, *dto.SetLevelRequest) (*dto.SetLevelResponse, error)
 }
 
 type userHandler struct {
@@ -27,13 +31,32 @@ func NewUserHandler(userService UserService) *userHandler {
 func (h *userHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	var req dto.SetIsActiveRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	h.setIsActive(w, r, &req)
+}
+
+// UpdateUser PATCH /api/v1/users/{id}, в теле только is_active. user_id из тела игнорируется
+func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetIsActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.UserID = handlers.PathParam(r, "id")
+	h.setIsActive(w, r, &req)
+}
+
+func (h *userHandler) setIsActive(w http.ResponseWriter, r *http.Request, req *dto.SetIsActiveRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
 		return
 	}
 
-	resp, err := h.userService.SetIsActive(r.Context(), &req)
+	resp, err := h.userService.SetIsActive(r.Context(), req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
@@ -43,16 +66,101 @@ func (h *userHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// SetSkills PUT /api/v1/users/{id}/skills — теги целиком. user_id из тела игнорируется
+func (h *userHandler) SetSkills(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetSkillsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.UserID = handlers.PathParam(r, "id")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.userService.SetSkills(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// UpdateSkills PATCH /api/v1/users/{id}/skills — добавить add и убрать remove
+func (h *userHandler) UpdateSkills(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateSkillsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.UserID = handlers.PathParam(r, "id")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.userService.UpdateSkills(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetLevel PUT /api/v1/users/{id}/level
+func (h *userHandler) SetLevel(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	req.UserID = handlers.PathParam(r, "id")
+	if err := dto.Validate(&req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
+		return
+	}
+
+	resp, err := h.userService.SetLevel(r.Context(), &req)
+	if err != nil {
+		error_wrapper.WriteServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
 func (h *userHandler) GetReviewPullRequests(w http.ResponseWriter, r *http.Request) {
 	var req dto.GetReviewPRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		error_wrapper.WriteError(w, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		error_wrapper.WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+	h.getReviewPullRequests(w, r, &req)
+}
+
+// GetUserReviews GET /api/v1/users/{id}/reviews
+func (h *userHandler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
+	h.getReviewPullRequests(w, r, &dto.GetReviewPRRequest{UserID: handlers.PathParam(r, "id")})
+}
+
+func (h *userHandler) getReviewPullRequests(w http.ResponseWriter, r *http.Request, req *dto.GetReviewPRRequest) {
+	if err := dto.Validate(req); err != nil {
+		error_wrapper.WriteValidationError(w, r, err)
 		return
 	}
 
-	resp, err := h.userService.GetReviewPullRequests(r.Context(), &req)
+	resp, err := h.userService.GetReviewPullRequests(r.Context(), req)
 	if err != nil {
-		error_wrapper.WriteServiceError(w, err)
+		error_wrapper.WriteServiceError(w, r, err)
 		return
 	}
 
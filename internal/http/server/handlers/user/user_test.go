@@ -2,6 +2,7 @@ package user
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"service-order-avito/internal/domain/errors/service"
 	"service-order-avito/internal/http/server/handlers/user/mocks"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -84,6 +86,21 @@ func TestUserHandler_SetIsActive(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 	})
 
+	t.Run("validation error", func(t *testing.T) {
+		bodyBytes, _ := json.Marshal(&dto.SetIsActiveRequest{UserID: "", IsActive: true})
+		req := httptest.NewRequest(http.MethodPost, "/set-active", bytes.NewReader(bodyBytes))
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.SetIsActive(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+		var resp dto.ErrorResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "VALIDATION_ERROR", resp.Error.Code)
+	})
+
 	t.Run("internal error", func(t *testing.T) {
 		reqBody := &dto.SetIsActiveRequest{
 			UserID:   "u1",
@@ -103,3 +120,160 @@ func TestUserHandler_SetIsActive(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
 	})
 }
+
+func TestUserHandler_V1(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockUserService(ctrl)
+	handler := NewUserHandler(mockService)
+
+	t.Run("update takes id from path", func(t *testing.T) {
+		mockService.EXPECT().
+			SetIsActive(gomock.Any(), &dto.SetIsActiveRequest{UserID: "u1", IsActive: true}).
+			Return(&dto.SetIsActiveResponse{}, nil)
+
+		body := []byte(`{"user_id":"other","is_active":true}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPatch, "/api/v1/users/u1", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.UpdateUser(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("update invalid json", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodPatch, "/api/v1/users/u1", bytes.NewReader([]byte("{"))), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.UpdateUser(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("reviews", func(t *testing.T) {
+		mockService.EXPECT().
+			GetReviewPullRequests(gomock.Any(), &dto.GetReviewPRRequest{UserID: "u1"}).
+			Return(&dto.GetReviewPRResponse{UserID: "u1"}, nil)
+
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/users/u1/reviews", nil), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.GetUserReviews(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("reviews invalid id", func(t *testing.T) {
+		req := withURLParams(httptest.NewRequest(http.MethodGet, "/api/v1/users/u%201/reviews", nil), "id", "u%201")
+		w := httptest.NewRecorder()
+
+		handler.GetUserReviews(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestUserHandler_Skills(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockUserService(ctrl)
+	handler := NewUserHandler(mockService)
+
+	t.Run("set", func(t *testing.T) {
+		mockService.EXPECT().
+			SetSkills(gomock.Any(), &dto.SetSkillsRequest{UserID: "u1", Skills: []string{"go", "sql"}}).
+			Return(&dto.UserSkillsResponse{User: dto.UserResponse{UserID: "u1", Skills: []string{"go", "sql"}}}, nil)
+
+		body := []byte(`{"user_id":"other","skills":["go","sql"]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/users/u1/skills", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.SetSkills(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var resp dto.UserSkillsResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, []string{"go", "sql"}, resp.User.Skills)
+	})
+
+	t.Run("set invalid tag", func(t *testing.T) {
+		body := []byte(`{"skills":["Go Lang"]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/users/u1/skills", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.SetSkills(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockService.EXPECT().
+			UpdateSkills(gomock.Any(), &dto.UpdateSkillsRequest{UserID: "u1", Add: []string{"go"}, Remove: []string{"java"}}).
+			Return(&dto.UserSkillsResponse{User: dto.UserResponse{UserID: "u1", Skills: []string{"go"}}}, nil)
+
+		body := []byte(`{"add":["go"],"remove":["java"]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPatch, "/api/v1/users/u1/skills", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.UpdateSkills(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("update user not found", func(t *testing.T) {
+		mockService.EXPECT().
+			UpdateSkills(gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrUserNotFound)
+
+		body := []byte(`{"add":["go"]}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPatch, "/api/v1/users/nope/skills", bytes.NewReader(body)), "id", "nope")
+		w := httptest.NewRecorder()
+
+		handler.UpdateSkills(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestUserHandler_SetLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockUserService(ctrl)
+	handler := NewUserHandler(mockService)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.EXPECT().
+			SetLevel(gomock.Any(), &dto.SetLevelRequest{UserID: "u1", Level: "SENIOR"}).
+			Return(&dto.SetLevelResponse{User: dto.UserResponse{UserID: "u1", Level: "SENIOR"}}, nil)
+
+		body := []byte(`{"level":"SENIOR"}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/users/u1/level", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.SetLevel(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		body := []byte(`{"level":"senior"}`)
+		req := withURLParams(httptest.NewRequest(http.MethodPut, "/api/v1/users/u1/level", bytes.NewReader(body)), "id", "u1")
+		w := httptest.NewRecorder()
+
+		handler.SetLevel(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+// withURLParams кладет параметры пути так же, как это делает chi при маршрутизации
+func withURLParams(r *http.Request, kv ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(kv); i += 2 {
+		rctx.URLParams.Add(kv[i], kv[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
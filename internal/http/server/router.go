@@ -12,23 +12,78 @@ type TeamHandler interface {
 	AddTeam(http.ResponseWriter, *http.Request)
 	GetTeam(http.ResponseWriter, *http.Request)
 	GetTeamStats(http.ResponseWriter, *http.Request)
+	GetTeamByName(http.ResponseWriter, *http.Request)
+	GetTeamStatsByName(http.ResponseWriter, *http.Request)
+	SetReviewerRules(http.ResponseWriter, *http.Request)
+	GetReviewerRules(http.ResponseWriter, *http.Request)
+	SetPairingSettings(http.ResponseWriter, *http.Request)
+	GetPairingSettings(http.ResponseWriter, *http.Request)
+	GetPairingMatrix(http.ResponseWriter, *http.Request)
 }
 
 type UserHandler interface {
 	SetIsActive(http.ResponseWriter, *http.Request)
 	GetReviewPullRequests(http.ResponseWriter, *http.Request)
+	UpdateUser(http.ResponseWriter, *http.Request)
+	GetUserReviews(http.ResponseWriter, *http.Request)
+	SetSkills(http.ResponseWriter, *http.Request)
+	UpdateSkills(http.ResponseWriter, *http.Request)
+	SetLevel(http.ResponseWriter, *http.Request)
+}
+
+type AvailabilityHandler interface {
+	AddAbsence(http.ResponseWriter, *http.Request)
+	ListAbsences(http.ResponseWriter, *http.Request)
+	DeleteAbsence(http.ResponseWriter, *http.Request)
+	ImportUserCalendar(http.ResponseWriter, *http.Request)
+	ImportTeamCalendar(http.ResponseWriter, *http.Request)
+	SetWorkingHours(http.ResponseWriter, *http.Request)
+	GetWorkingHours(http.ResponseWriter, *http.Request)
+}
+
+type HealthHandler interface {
+	Livez(http.ResponseWriter, *http.Request)
+	Readyz(http.ResponseWriter, *http.Request)
+}
+
+type EventsHandler interface {
+	Stream(http.ResponseWriter, *http.Request)
+}
+
+type AdminHandler interface {
+	Import(http.ResponseWriter, *http.Request)
+}
+
+type ExportHandler interface {
+	PullRequests(http.ResponseWriter, *http.Request)
+	Reviews(http.ResponseWriter, *http.Request)
 }
 
 type PullRequestHandler interface {
 	Create(http.ResponseWriter, *http.Request)
 	Merge(http.ResponseWriter, *http.Request)
 	ReassignReviewer(http.ResponseWriter, *http.Request)
+	Get(http.ResponseWriter, *http.Request)
+	GetByID(http.ResponseWriter, *http.Request)
+	MergeByID(http.ResponseWriter, *http.Request)
+	CloseByID(http.ResponseWriter, *http.Request)
+	ReassignByID(http.ResponseWriter, *http.Request)
+	Explain(http.ResponseWriter, *http.Request)
+	PreviewReviewers(http.ResponseWriter, *http.Request)
 }
 
 func InitRouter(log *slog.Logger,
 	teamHandler TeamHandler,
 	userHandler UserHandler,
 	prHandler PullRequestHandler,
+	availabilityHandler AvailabilityHandler,
+	healthHandler HealthHandler,
+	eventsHandler EventsHandler,
+	graphqlHandler http.Handler,
+	adminHandler AdminHandler,
+	exportHandler ExportHandler,
+	idempotency func(http.Handler) http.Handler,
+	rateLimit func(http.Handler) http.Handler,
 ) chi.Router {
 	router := chi.NewRouter()
 
@@ -38,22 +93,118 @@ func InitRouter(log *slog.Logger,
 
 	router.Get("/ping", handlers.PingGetHandler)
 	router.Head("/healthcheck", handlers.HealthcheckHeadHandler)
+	router.Get("/livez", healthHandler.Livez)
+	router.Get("/readyz", healthHandler.Readyz)
+
+	// пробы и /ping выше не ограничиваются, чтобы балансировщик не снял под из-за лимита
+	router.Group(func(router chi.Router) {
+		router.Use(rateLimit)
+		initLimitedRoutes(router, teamHandler, userHandler, prHandler, availabilityHandler, eventsHandler, graphqlHandler, adminHandler, exportHandler, idempotency)
+	})
+	return router
+}
+
+func initLimitedRoutes(router chi.Router,
+	teamHandler TeamHandler,
+	userHandler UserHandler,
+	prHandler PullRequestHandler,
+	availabilityHandler AvailabilityHandler,
+	eventsHandler EventsHandler,
+	graphqlHandler http.Handler,
+	adminHandler AdminHandler,
+	exportHandler ExportHandler,
+	idempotency func(http.Handler) http.Handler,
+) {
+	// SSE: соединение долгое, WriteTimeout снимает сам обработчик
+	router.Get("/events/stream", eventsHandler.Stream)
 
+	// /graphql ходит в те же сервисы, вложенные поля грузятся батчами (internal/graph)
+	router.With(idempotency).Post("/graphql", graphqlHandler.ServeHTTP)
+
+	// массовый импорт команд, тот же что и подкоманда import
+	router.With(idempotency).Post("/admin/import", adminHandler.Import)
+
+	// выгрузки CSV/NDJSON пишутся потоком, обработчики сами снимают WriteTimeout, как и SSE
+	router.Route("/export", func(r chi.Router) {
+		r.Get("/pullRequests", exportHandler.PullRequests)
+		r.Get("/reviews", exportHandler.Reviews)
+	})
+
+	router.Route("/api/v1", func(r chi.Router) {
+		initV1Routes(r, teamHandler, userHandler, prHandler, availabilityHandler, idempotency)
+	})
+
+	// старые RPC-маршруты остаются для существующих клиентов и ведут на те же обработчики.
+	// idempotency срабатывает только на POST с заголовком Idempotency-Key
 	router.Route("/team", func(r chi.Router) {
-		r.Post("/add", teamHandler.AddTeam)
+		r.With(idempotency).Post("/add", teamHandler.AddTeam)
 		r.Get("/get", teamHandler.GetTeam)
 		r.Get("/stats", teamHandler.GetTeamStats)
 	})
 
 	router.Route("/users", func(r chi.Router) {
-		r.Post("/setIsActive", userHandler.SetIsActive)
+		r.With(idempotency).Post("/setIsActive", userHandler.SetIsActive)
 		r.Get("/getReview", userHandler.GetReviewPullRequests)
 	})
 
 	router.Route("/pullRequest", func(r chi.Router) {
-		r.Post("/create", prHandler.Create)
-		r.Post("/merge", prHandler.Merge)
-		r.Post("/reassign", prHandler.ReassignReviewer)
+		r.Get("/get", prHandler.Get)
+		r.With(idempotency).Post("/create", prHandler.Create)
+		r.With(idempotency).Post("/merge", prHandler.Merge)
+		r.With(idempotency).Post("/reassign", prHandler.ReassignReviewer)
+		// выбор ревьюеров без создания PR: ничего не пишет, поэтому без idempotency
+		r.Post("/previewReviewers", prHandler.PreviewReviewers)
+	})
+}
+
+// initV1Routes ресурсные маршруты: идентификаторы в пути, GET без тела
+func initV1Routes(r chi.Router,
+	teamHandler TeamHandler,
+	userHandler UserHandler,
+	prHandler PullRequestHandler,
+	availabilityHandler AvailabilityHandler,
+	idempotency func(http.Handler) http.Handler,
+) {
+	r.Route("/teams", func(r chi.Router) {
+		r.With(idempotency).Post("/", teamHandler.AddTeam)
+		r.Get("/{name}", teamHandler.GetTeamByName)
+		r.Get("/{name}/stats", teamHandler.GetTeamStatsByName)
+		// правила к уровням ревьюеров, например «хотя бы один SENIOR»
+		r.Put("/{name}/reviewer-rules", teamHandler.SetReviewerRules)
+		r.Get("/{name}/reviewer-rules", teamHandler.GetReviewerRules)
+		// стратегия выбора ревьюеров и матрица пар автор-ревьюер, по которой видно перекос
+		r.Put("/{name}/pairing-settings", teamHandler.SetPairingSettings)
+		r.Get("/{name}/pairing-settings", teamHandler.GetPairingSettings)
+		r.Get("/{name}/pairings", teamHandler.GetPairingMatrix)
+		r.With(idempotency).Post("/{name}/absences/import", availabilityHandler.ImportTeamCalendar)
+	})
+
+	r.Route("/users/{id}", func(r chi.Router) {
+		r.Patch("/", userHandler.UpdateUser)
+		r.Get("/reviews", userHandler.GetUserReviews)
+		// теги экспертизы: по ним выбираются ревьюеры PR с required_tags
+		r.Put("/skills", userHandler.SetSkills)
+		r.Patch("/skills", userHandler.UpdateSkills)
+		// уровень: по нему проверяются правила команды к ревьюерам
+		r.Put("/level", userHandler.SetLevel)
+		// периоды отсутствия: в отличие от is_active, с датами, по ним же снимаются назначения
+		r.With(idempotency).Post("/absences", availabilityHandler.AddAbsence)
+		r.Get("/absences", availabilityHandler.ListAbsences)
+		r.Delete("/absences/{absenceId}", availabilityHandler.DeleteAbsence)
+		r.With(idempotency).Post("/absences/import", availabilityHandler.ImportUserCalendar)
+		// рабочее время: по нему выбираются ревьюеры и считается SLA ревью
+		r.Put("/working-hours", availabilityHandler.SetWorkingHours)
+		r.Get("/working-hours", availabilityHandler.GetWorkingHours)
+	})
+
+	r.Route("/pull-requests", func(r chi.Router) {
+		r.With(idempotency).Post("/", prHandler.Create)
+		r.Get("/{id}", prHandler.GetByID)
+		r.With(idempotency).Post("/{id}/merge", prHandler.MergeByID)
+		// закрытие без merge есть только в v1
+		r.With(idempotency).Post("/{id}/close", prHandler.CloseByID)
+		r.With(idempotency).Post("/{id}/reassign", prHandler.ReassignByID)
+		// отладка: почему выбраны эти ревьюеры, с seed для воспроизведения
+		r.Get("/{id}/explain", prHandler.Explain)
 	})
-	return router
 }
@@ -0,0 +1,467 @@
+// Package ical разбирает календарь iCalendar (RFC 5545) в события OOO для импорта периодов отсутствия.
+// Поддерживается то, что выгружают Google Calendar, Outlook и Thunderbird: VEVENT с DTSTART/DTEND или DURATION,
+// TZID, события на весь день и еженедельное повторение с UNTIL
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"strconv"
+	"strings"
+	"time"
+
+	// TZID календаря должен разбираться и в образе без системной базы часовых поясов
+	_ "time/tzdata"
+)
+
+// ErrNotCalendar в теле нет BEGIN:VCALENDAR
+var ErrNotCalendar = errors.New("not an iCalendar file, expected BEGIN:VCALENDAR")
+
+// property строка содержимого NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+// event свойства одного VEVENT, вложенные VALARM пропускаются
+type event map[string][]property
+
+func (e event) first(name string) (property, bool) {
+	props := e[name]
+	if len(props) == 0 {
+		return property{}, false
+	}
+	return props[0], true
+}
+
+func (e event) text(name string) string {
+	p, _ := e.first(name)
+	return unescape(p.value)
+}
+
+// Parse читает календарь целиком. События без признака OOO считаются в Ignored,
+// события, которые нельзя перевести в период (повторение без UNTIL, пустой период), попадают в Events с Skip.
+// Ошибка возвращается только на синтаксис файла, с номером строки
+func Parse(r io.Reader) (*dto.ImportCalendarRequest, error) {
+	events, err := parseEvents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &dto.ImportCalendarRequest{Events: []dto.CalendarEvent{}}
+	// UID (+ RECURRENCE-ID) -> индекс в req.Events: повтор события в файле заменяет предыдущее
+	seen := make(map[string]int)
+	for _, e := range events {
+		if !outOfOffice(e) {
+			req.Ignored++
+			continue
+		}
+
+		ev := convert(e)
+		if i, ok := seen[ev.UID]; ok {
+			req.Events[i] = ev
+			continue
+		}
+		seen[ev.UID] = len(req.Events)
+		req.Events = append(req.Events, ev)
+	}
+	return req, nil
+}
+
+func parseEvents(r io.Reader) ([]event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events   []event
+		current  event
+		stack    []string
+		calendar bool
+	)
+	for _, l := range lines {
+		p, err := parseLine(l.text)
+		if err != nil && !calendar {
+			return nil, ErrNotCalendar
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.number, err)
+		}
+		p.line = l.number
+
+		switch p.name {
+		case "BEGIN":
+			component := strings.ToUpper(p.value)
+			if len(stack) == 0 && component != "VCALENDAR" {
+				return nil, ErrNotCalendar
+			}
+			calendar = true
+			stack = append(stack, component)
+			if component == "VEVENT" && len(stack) == 2 {
+				current = event{}
+			}
+			continue
+		case "END":
+			component := strings.ToUpper(p.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("line %d: END:%s without matching BEGIN", l.number, p.value)
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" && len(stack) == 1 {
+				events = append(events, current)
+				current = nil
+			}
+			continue
+		}
+
+		if len(stack) == 0 {
+			return nil, ErrNotCalendar
+		}
+		// свойства события, но не вложенных в него VALARM
+		if current != nil && len(stack) == 2 {
+			current[p.name] = append(current[p.name], p)
+		}
+	}
+
+	if !calendar {
+		return nil, ErrNotCalendar
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unexpected end of file, missing END:%s", stack[len(stack)-1])
+	}
+	return events, nil
+}
+
+type line struct {
+	number int
+	text   string
+}
+
+// unfold склеивает перенесенные строки: продолжение начинается с пробела или табуляции
+func unfold(r io.Reader) ([]line, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	var lines []line
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, line{number: number, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine разбирает NAME;PARAM=VALUE;PARAM="a:b":value. Двоеточие внутри кавычек значение не начинает
+func parseLine(text string) (property, error) {
+	p := property{params: map[string]string{}}
+
+	inQuotes := false
+	colon := -1
+	for i, r := range text {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ':' && !inQuotes:
+			colon = i
+		}
+		if colon >= 0 {
+			break
+		}
+	}
+	if colon < 0 {
+		return p, errors.New("expected NAME:value")
+	}
+
+	head, value := text[:colon], text[colon+1:]
+	parts := splitParams(head)
+	p.name = strings.ToUpper(parts[0])
+	if p.name == "" {
+		return p, errors.New("empty property name")
+	}
+	for _, param := range parts[1:] {
+		k, v, ok := strings.Cut(param, "=")
+		if !ok {
+			return p, fmt.Errorf("parameter %q: expected NAME=value", param)
+		}
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	p.value = value
+	return p, nil
+}
+
+func splitParams(head string) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i, r := range head {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ';' && !inQuotes:
+			parts = append(parts, head[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, head[start:])
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// splitList значения через запятую с учетом экранирования (CATEGORIES)
+func splitList(s string) []string {
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteByte(s[i])
+			b.WriteByte(s[i+1])
+			i++
+		case s[i] == ',':
+			items = append(items, unescape(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return append(items, unescape(b.String()))
+}
+
+var oooSummary = regexp.MustCompile(`(?i)^\W*(ooo|out of office)\b`)
+
+// outOfOffice признаки OOO: категория OOO / Out of office, статус OOF из Outlook
+// или заголовок, начинающийся с "OOO" или "Out of office"
+func outOfOffice(e event) bool {
+	for _, p := range e["CATEGORIES"] {
+		for _, c := range splitList(p.value) {
+			switch normalizeTag(c) {
+			case "OOO", "OUT OF OFFICE":
+				return true
+			}
+		}
+	}
+	if strings.EqualFold(e.text("X-MICROSOFT-CDO-BUSYSTATUS"), "OOF") {
+		return true
+	}
+	return oooSummary.MatchString(e.text("SUMMARY"))
+}
+
+// sick категория больничного, остальные события OOO — отпуск
+func sick(e event) bool {
+	for _, p := range e["CATEGORIES"] {
+		for _, c := range splitList(p.value) {
+			switch normalizeTag(c) {
+			case "SICK", "SICK LEAVE", "ILLNESS":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeTag(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), " ")
+}
+
+// convert событие OOO в период отсутствия. Ошибки в значениях не роняют импорт, а уходят в Skip
+func convert(e event) dto.CalendarEvent {
+	ev := dto.CalendarEvent{
+		UID:       e.text("UID"),
+		Kind:      domain.AbsenceVacation,
+		Cancelled: strings.EqualFold(e.text("STATUS"), "CANCELLED"),
+		People:    people(e),
+	}
+	if sick(e) {
+		ev.Kind = domain.AbsenceSickLeave
+	}
+	if id, ok := e.first("RECURRENCE-ID"); ok {
+		ev.UID += "#" + id.value
+	}
+	if ev.UID == "" {
+		ev.Skip = "event has no UID"
+		return ev
+	}
+	if ev.Cancelled {
+		return ev
+	}
+
+	start, ok := e.first("DTSTART")
+	if !ok {
+		ev.Skip = "event has no DTSTART"
+		return ev
+	}
+	starts, allDay, err := parseTime(start)
+	if err != nil {
+		ev.Skip = fmt.Sprintf("DTSTART (line %d): %v", start.line, err)
+		return ev
+	}
+
+	var ends time.Time
+	switch end, hasEnd := e.first("DTEND"); {
+	case hasEnd:
+		if ends, _, err = parseTime(end); err != nil {
+			ev.Skip = fmt.Sprintf("DTEND (line %d): %v", end.line, err)
+			return ev
+		}
+	case len(e["DURATION"]) > 0:
+		d, _ := e.first("DURATION")
+		duration, err := parseDuration(d.value)
+		if err != nil {
+			ev.Skip = fmt.Sprintf("DURATION (line %d): %v", d.line, err)
+			return ev
+		}
+		ends = starts.Add(duration)
+	case allDay:
+		ends = starts.AddDate(0, 0, 1)
+	default:
+		ends = starts
+	}
+	if !ends.After(starts) {
+		ev.Skip = "event ends before it starts"
+		return ev
+	}
+
+	ev.Starts, ev.Ends = starts, ends
+	if rule, ok := e.first("RRULE"); ok {
+		if err := applyWeekly(&ev, rule.value); err != nil {
+			ev.Skip = fmt.Sprintf("RRULE (line %d): %v", rule.line, err)
+		}
+	}
+	return ev
+}
+
+// people ORGANIZER и ATTENDEE: адрес без mailto: и имя из CN, организатор первым
+func people(e event) []string {
+	var result []string
+	for _, name := range []string{"ORGANIZER", "ATTENDEE"} {
+		for _, p := range e[name] {
+			if addr := strings.TrimSpace(p.value); addr != "" {
+				if len(addr) > 7 && strings.EqualFold(addr[:7], "mailto:") {
+					addr = addr[7:]
+				}
+				result = append(result, addr)
+			}
+			if cn := strings.TrimSpace(p.params["CN"]); cn != "" {
+				result = append(result, cn)
+			}
+		}
+	}
+	return result
+}
+
+// parseTime DATE (весь день), DATE-TIME в UTC (Z), с TZID или "плавающее" время, которое считается UTC
+func parseTime(p property) (t time.Time, allDay bool, err error) {
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(p.value) == len("20060102") {
+		t, err = time.Parse("20060102", p.value)
+		return t, true, err
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		t, err = time.Parse("20060102T150405Z", p.value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", p.value, loc)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration P1W, P2D, PT4H30M, P1DT12H. Отрицательная длительность для события не имеет смысла
+func parseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+1])
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// applyWeekly еженедельное повторение (например, выходной по пятницам) становится периодом PART_TIME
+// от DTSTART до UNTIL по дням BYDAY. Время внутри дня теряется: пользователь отсутствует весь день
+func applyWeekly(ev *dto.CalendarEvent, rule string) error {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		parts[strings.ToUpper(k)] = strings.ToUpper(v)
+	}
+
+	if parts["FREQ"] != "WEEKLY" {
+		return fmt.Errorf("only FREQ=WEEKLY is supported, got %q", parts["FREQ"])
+	}
+	if interval := parts["INTERVAL"]; interval != "" && interval != "1" {
+		return errors.New("INTERVAL other than 1 is not supported")
+	}
+	if parts["UNTIL"] == "" {
+		return errors.New("recurrence without UNTIL is not supported")
+	}
+
+	until, _, err := parseTime(property{value: parts["UNTIL"], params: map[string]string{}})
+	if err != nil {
+		return fmt.Errorf("UNTIL: %w", err)
+	}
+
+	var days domain.Weekdays
+	if byDay := parts["BYDAY"]; byDay != "" {
+		for _, code := range strings.Split(byDay, ",") {
+			d, ok := weekdayCodes[code]
+			if !ok {
+				return fmt.Errorf("BYDAY %q is not supported", code)
+			}
+			days |= domain.NewWeekdays(d)
+		}
+	} else {
+		// по RFC 5545 день недели и даты повторений — в часовом поясе DTSTART, а не в UTC
+		days = domain.NewWeekdays(ev.Starts.Weekday())
+	}
+
+	// UNTIL включает последнее повторение, поэтому период заканчивается на следующий день
+	y, m, d := until.In(ev.Starts.Location()).Date()
+	ev.Ends = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	y, m, d = ev.Starts.Date()
+	ev.Starts = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	ev.Kind = domain.AbsencePartTime
+	ev.Weekdays = days
+	if !ev.Ends.After(ev.Starts) {
+		return errors.New("UNTIL is before DTSTART")
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package ical
+
+import (
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func calendar(lines ...string) string {
+	return strings.Join(append(append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//test//EN"}, lines...), "END:VCALENDAR"), "\r\n") + "\r\n"
+}
+
+func TestParse(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	input := calendar(
+		// весь день, DTEND не включается
+		"BEGIN:VEVENT",
+		"UID:vacation@example.com",
+		"SUMMARY:OOO - отпуск",
+		"ORGANIZER;CN=\"Smith, Alice\":mailto:alice@example.com",
+		"DTSTART;VALUE=DATE:20251222",
+		"DTEND;VALUE=DATE:20260101",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:OOO reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		// категория, TZID и перенос строки
+		"BEGIN:VEVENT",
+		"UID:sick@exa",
+		" mple.com",
+		"SUMMARY:Doctor",
+		"CATEGORIES:Out-of-office,Sick",
+		"ATTENDEE;CN=Bob:MAILTO:bob@example.com",
+		"DTSTART;TZID=Europe/Moscow:20251210T090000",
+		"DURATION:PT4H",
+		"END:VEVENT",
+		// статус Outlook, событие на день без DTEND
+		"BEGIN:VEVENT",
+		"UID:outlook",
+		"SUMMARY:Day off",
+		"X-MICROSOFT-CDO-BUSYSTATUS:OOF",
+		"DTSTART;VALUE=DATE:20251215",
+		"END:VEVENT",
+		// не OOO
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"SUMMARY:Standup\\, daily",
+		"DTSTART:20251210T070000Z",
+		"DTEND:20251210T071500Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:book",
+		"SUMMARY:Oooh a book club",
+		"DTSTART:20251210T070000Z",
+		"DTEND:20251210T071500Z",
+		"END:VEVENT",
+		// выходной по пятницам
+		"BEGIN:VEVENT",
+		"UID:fridays",
+		"SUMMARY:Out of office: part time",
+		"DTSTART:20251205T000000Z",
+		"DTEND:20251206T000000Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=FR;UNTIL=20260227T000000Z",
+		"END:VEVENT",
+		// без BYDAY день недели берется из DTSTART в его поясе: полночь пятницы в Москве — еще четверг в UTC
+		"BEGIN:VEVENT",
+		"UID:moscow-fridays",
+		"SUMMARY:OOO",
+		"DTSTART;TZID=Europe/Moscow:20251205T000000",
+		"DTEND;TZID=Europe/Moscow:20251206T000000",
+		"RRULE:FREQ=WEEKLY;UNTIL=20251225T210000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:forever",
+		"SUMMARY:OOO",
+		"DTSTART:20251205T000000Z",
+		"DTEND:20251206T000000Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled",
+		"SUMMARY:OOO",
+		"STATUS:CANCELLED",
+		"DTSTART:20251205T000000Z",
+		"END:VEVENT",
+		// повтор UID — побеждает последний
+		"BEGIN:VEVENT",
+		"UID:vacation@example.com",
+		"SEQUENCE:1",
+		"SUMMARY:OOO",
+		"DTSTART;VALUE=DATE:20251222",
+		"DTEND;VALUE=DATE:20260109",
+		"END:VEVENT",
+	)
+
+	req, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, 2, req.Ignored)
+
+	assert.Equal(t, []dto.CalendarEvent{
+		{
+			UID:    "vacation@example.com",
+			Kind:   domain.AbsenceVacation,
+			Starts: time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC),
+			Ends:   time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			UID:    "sick@example.com",
+			Kind:   domain.AbsenceSickLeave,
+			Starts: time.Date(2025, 12, 10, 9, 0, 0, 0, moscow),
+			Ends:   time.Date(2025, 12, 10, 13, 0, 0, 0, moscow),
+			People: []string{"bob@example.com", "Bob"},
+		},
+		{
+			UID:    "outlook",
+			Kind:   domain.AbsenceVacation,
+			Starts: time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC),
+			Ends:   time.Date(2025, 12, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			UID:      "fridays",
+			Kind:     domain.AbsencePartTime,
+			Starts:   time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC),
+			Ends:     time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+			Weekdays: domain.NewWeekdays(time.Friday),
+		},
+		{
+			UID:      "moscow-fridays",
+			Kind:     domain.AbsencePartTime,
+			Starts:   time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC),
+			Ends:     time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC),
+			Weekdays: domain.NewWeekdays(time.Friday),
+		},
+		{
+			UID:    "forever",
+			Kind:   domain.AbsenceVacation,
+			Starts: time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC),
+			Ends:   time.Date(2025, 12, 6, 0, 0, 0, 0, time.UTC),
+			Skip:   "RRULE (line 61): recurrence without UNTIL is not supported",
+		},
+		{
+			UID:       "cancelled",
+			Kind:      domain.AbsenceVacation,
+			Cancelled: true,
+		},
+	}, req.Events)
+}
+
+func TestParse_People(t *testing.T) {
+	req, err := Parse(strings.NewReader(calendar(
+		"BEGIN:VEVENT",
+		"UID:1",
+		"SUMMARY:OOO",
+		"ORGANIZER;CN=\"Smith, Alice\":mailto:alice@example.com",
+		"DTSTART:20251210T070000Z",
+		"DTEND:20251211T070000Z",
+		"END:VEVENT",
+	)))
+	require.NoError(t, err)
+	require.Len(t, req.Events, 1)
+	assert.Equal(t, []string{"alice@example.com", "Smith, Alice"}, req.Events[0].People)
+}
+
+func TestParse_Skip(t *testing.T) {
+	tests := []struct {
+		name  string
+		event []string
+		want  string
+	}{
+		{
+			name:  "no uid",
+			event: []string{"DTSTART:20251210T070000Z"},
+			want:  "event has no UID",
+		},
+		{
+			name:  "no dtstart",
+			event: []string{"UID:1"},
+			want:  "event has no DTSTART",
+		},
+		{
+			name:  "zero length",
+			event: []string{"UID:1", "DTSTART:20251210T070000Z"},
+			want:  "event ends before it starts",
+		},
+		{
+			name:  "unknown tzid",
+			event: []string{"UID:1", "DTSTART;TZID=Mars/Olympus:20251210T070000"},
+			want:  `DTSTART (line 7): unknown TZID "Mars/Olympus"`,
+		},
+		{
+			name:  "daily rule",
+			event: []string{"UID:1", "DTSTART;VALUE=DATE:20251210", "RRULE:FREQ=DAILY;UNTIL=20251220"},
+			want:  `RRULE (line 8): only FREQ=WEEKLY is supported, got "DAILY"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VEVENT", "SUMMARY:OOO"}, tt.event...)
+			req, err := Parse(strings.NewReader(calendar(append(lines, "END:VEVENT")...)))
+			require.NoError(t, err)
+			require.Len(t, req.Events, 1)
+			assert.Equal(t, tt.want, req.Events[0].Skip)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "not a calendar",
+			input: "user_id,team_name\nu1,backend\n",
+			want:  "not an iCalendar file",
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  "not an iCalendar file",
+		},
+		{
+			name:  "unterminated",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:1\n",
+			want:  "missing END:VEVENT",
+		},
+		{
+			name:  "mismatched end",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+			want:  "line 3: END:VCALENDAR without matching BEGIN",
+		},
+		{
+			name:  "no colon",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY OOO\nEND:VEVENT\nEND:VCALENDAR\n",
+			want:  "line 3: expected NAME:value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
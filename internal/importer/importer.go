@@ -0,0 +1,184 @@
+// Package importer разбирает файл импорта команд (CSV, JSON, YAML) в dto.ImportRequest.
+// Общий для POST /admin/import и подкоманды import
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"service-order-avito/internal/domain/dto"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported import format, expected csv, json or yaml")
+
+// csvColumns обязательные колонки CSV, порядок в файле любой
+var csvColumns = []string{"team_name", "user_id", "username", "is_active"}
+
+// FormatFromContentType формат по Content-Type запроса
+func FormatFromContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", ErrUnsupportedFormat
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/json":
+		return FormatJSON, nil
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// FormatFromPath формат по расширению файла
+func FormatFromPath(path string) (string, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// ParseFormat проверяет явно заданный формат (?format=, --format)
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Parse читает файл целиком. Ошибки содержат номер строки (CSV) или описание декодера,
+// правила полей проверяются отдельно через dto.Validate
+func Parse(r io.Reader, format string) (*dto.ImportRequest, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		return decodeJSON(r)
+	case FormatYAML:
+		return parseYAML(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// decodeJSON неизвестные поля — ошибка: опечатка в is_active при массовом импорте иначе молча даст false
+func decodeJSON(r io.Reader) (*dto.ImportRequest, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var req dto.ImportRequest
+	if err := dec.Decode(&req); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the top-level object")
+	}
+	return &req, nil
+}
+
+// parseYAML та же структура, что и в JSON: YAML приводится к JSON, чтобы работали json-теги dto
+func parseYAML(r io.Reader) (*dto.ImportRequest, error) {
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty document")
+		}
+		return nil, err
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(bytes.NewReader(raw))
+}
+
+// parseCSV одна строка — один участник, команды собираются в порядке первого упоминания.
+// Строка только с team_name создает команду без участников
+func parseCSV(r io.Reader) (*dto.ImportRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty file, expected header: " + strings.Join(csvColumns, ","))
+		}
+		return nil, err
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("header: missing column %q", name)
+		}
+	}
+
+	req := &dto.ImportRequest{Teams: []dto.TeamAddRequest{}}
+	teams := make(map[string]int)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			return strings.TrimSpace(record[index[column]])
+		}
+
+		teamName := get("team_name")
+		i, ok := teams[teamName]
+		if !ok {
+			i = len(req.Teams)
+			teams[teamName] = i
+			req.Teams = append(req.Teams, dto.TeamAddRequest{TeamName: teamName, Members: []dto.TeamMemberRequest{}})
+		}
+
+		userID, username, rawActive := get("user_id"), get("username"), get("is_active")
+		if userID == "" && username == "" && rawActive == "" {
+			continue
+		}
+
+		isActive, err := strconv.ParseBool(rawActive)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: is_active must be true or false, got %q", line, rawActive)
+		}
+
+		req.Teams[i].Members = append(req.Teams[i].Members, dto.TeamMemberRequest{
+			UserID:   userID,
+			Username: username,
+			IsActive: isActive,
+		})
+	}
+
+	return req, nil
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"service-order-avito/internal/domain/dto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var want = &dto.ImportRequest{Teams: []dto.TeamAddRequest{
+	{TeamName: "backend", Members: []dto.TeamMemberRequest{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: false},
+	}},
+	{TeamName: "frontend", Members: []dto.TeamMemberRequest{}},
+}}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			input: "user_id,team_name,username,is_active\n" +
+				"u1,backend,Alice,true\n" +
+				"u2, backend ,Bob,0\n" +
+				",frontend,,\n",
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			input: `{"teams": [
+				{"team_name": "backend", "members": [
+					{"user_id": "u1", "username": "Alice", "is_active": true},
+					{"user_id": "u2", "username": "Bob", "is_active": false}
+				]},
+				{"team_name": "frontend", "members": []}
+			]}`,
+		},
+		{
+			name:   "yaml",
+			format: FormatYAML,
+			input: `
+teams:
+  - team_name: backend
+    members:
+      - {user_id: u1, username: Alice, is_active: true}
+      - user_id: u2
+        username: Bob
+        is_active: false
+  - team_name: frontend
+    members: []
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := Parse(strings.NewReader(tt.input), tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, want, req)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		errMsg string
+	}{
+		{"csv missing column", FormatCSV, "team_name,user_id,username\nbackend,u1,Alice\n", `missing column "is_active"`},
+		{"csv bad bool", FormatCSV, "team_name,user_id,username,is_active\nbackend,u1,Alice,true\nbackend,u2,Bob,yes\n", "line 3"},
+		{"csv empty", FormatCSV, "", "empty file"},
+		{"json unknown field", FormatJSON, `{"teams": [{"team_name": "a", "members": [{"user_id": "u1", "is_activ": true}]}]}`, "is_activ"},
+		{"json trailing data", FormatJSON, `{"teams": []} {}`, "unexpected data"},
+		{"yaml unknown field", FormatYAML, "teams:\n  - name: a\n", `"name"`},
+		{"yaml empty", FormatYAML, "", "empty document"},
+		{"unknown format", "xml", "<teams/>", "unsupported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input), tt.format)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	format, err := FormatFromContentType("text/csv; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = FormatFromContentType("application/x-yaml")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, format)
+
+	_, err = FormatFromContentType("application/xml")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	format, err = FormatFromPath("/tmp/teams.YML")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, format)
+
+	_, err = FormatFromPath("teams.txt")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
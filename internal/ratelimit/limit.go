@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit не больше N запросов за Per, всплеск до N подряд. Нулевой Limit ("off") не ограничивает
+type Limit struct {
+	N   int
+	Per time.Duration
+}
+
+var units = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// ParseLimit разбирает "10/s", "600/m", "5/h" или "off". Без ограничения — только "off" и "0":
+// "0/s" похоже на запрет всех запросов, поэтому это ошибка, а не отключение лимита
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+
+	n, unit, ok := strings.Cut(s, "/")
+	per, known := units[unit]
+	if !ok || !known {
+		return Limit{}, fmt.Errorf("rate limit %q: expected <n>/s, <n>/m, <n>/h or off", s)
+	}
+	count, err := strconv.Atoi(n)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: %q is not a positive number, use off to disable the limit", s, n)
+	}
+	return Limit{N: count, Per: per}, nil
+}
+
+func (l Limit) Unlimited() bool {
+	return l.N == 0
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	for unit, per := range units {
+		if per == l.Per {
+			return fmt.Sprintf("%d/%s", l.N, unit)
+		}
+	}
+	return fmt.Sprintf("%d/%s", l.N, l.Per)
+}
+
+// UnmarshalText для переменных окружения (caarlos0/env)
+func (l *Limit) UnmarshalText(text []byte) error {
+	parsed, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// Routes лимиты по маршрутам: "POST /pullRequest/reassign=30/m;GET /export/reviews=10/h".
+// Маршрут — метод и шаблон chi, как в router.go (с {id}, а не конкретным значением)
+type Routes map[string]Limit
+
+func (r *Routes) UnmarshalText(text []byte) error {
+	routes := Routes{}
+	for _, part := range strings.Split(string(text), ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(part, "=")
+		method, pattern, hasPattern := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPattern || method == "" || !strings.HasPrefix(pattern, "/") {
+			return fmt.Errorf("rate limit route %q: expected \"<METHOD> <pattern>=<limit>\"", part)
+		}
+		parsed, err := ParseLimit(limit)
+		if err != nil {
+			return fmt.Errorf("rate limit route %q: %w", route, err)
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(pattern)] = parsed
+	}
+	*r = routes
+	return nil
+}
+
+func (r Routes) String() string {
+	parts := make([]string, 0, len(r))
+	for route, limit := range r {
+		parts = append(parts, route+"="+limit.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
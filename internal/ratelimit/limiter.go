@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter token bucket на каждый ключ (клиент, PR). Состояние в памяти процесса:
+// при нескольких репликах лимит действует на каждую отдельно
+type Limiter struct {
+	limit Limit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow забирает токен ключа. Если токенов нет, возвращает false и через сколько появится следующий
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l.limit.Unlimited() {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b := l.refill(key, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate())
+}
+
+// Refund возвращает токен, если действие после Allow не состоялось (например, ошибка репозитория)
+func (l *Limiter) Refund(key string) {
+	if l.limit.Unlimited() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key, l.now())
+	b.tokens = min(b.tokens+1, float64(l.limit.N))
+}
+
+// rate токенов в наносекунду
+func (l *Limiter) rate() float64 {
+	return float64(l.limit.N) / float64(l.limit.Per)
+}
+
+func (l *Limiter) refill(key string, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.N), updated: now}
+		l.buckets[key] = b
+		return b
+	}
+	b.tokens = min(b.tokens+float64(now.Sub(b.updated))*l.rate(), float64(l.limit.N))
+	b.updated = now
+	return b
+}
+
+// sweep раз в Per удаляет полные корзины: полная корзина не отличается от новой, а ключей (IP, PR) может быть много
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.limit.Per {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+float64(now.Sub(b.updated))*l.rate() >= float64(l.limit.N) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RouteLimiter лимиты HTTP-маршрутов: у каждого маршрута и клиента своя корзина,
+// маршрутам без своего лимита достается лимит по умолчанию
+type RouteLimiter struct {
+	fallback Limit
+	routes   map[string]*Limiter
+
+	mu       sync.Mutex
+	defaults map[string]*Limiter
+}
+
+func NewRouteLimiter(fallback Limit, routes Routes) *RouteLimiter {
+	rl := &RouteLimiter{
+		fallback: fallback,
+		routes:   make(map[string]*Limiter, len(routes)),
+		defaults: make(map[string]*Limiter),
+	}
+	for route, limit := range routes {
+		rl.routes[route] = New(limit)
+	}
+	return rl
+}
+
+// Allow route — "METHOD /шаблон", client — ключ клиента
+func (rl *RouteLimiter) Allow(route, client string) (bool, time.Duration) {
+	if limiter, ok := rl.routes[route]; ok {
+		return limiter.Allow(client)
+	}
+
+	rl.mu.Lock()
+	limiter, ok := rl.defaults[route]
+	if !ok {
+		limiter = New(rl.fallback)
+		rl.defaults[route] = limiter
+	}
+	rl.mu.Unlock()
+
+	return limiter.Allow(client)
+}
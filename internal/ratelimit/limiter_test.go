@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock время, которое двигает тест
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestLimiter(limit Limit) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)}
+	l := New(limit)
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	l, clock := newTestLimiter(Limit{N: 3, Per: time.Minute})
+
+	for range 3 {
+		ok, _ := l.Allow("client")
+		require.True(t, ok)
+	}
+
+	ok, wait := l.Allow("client")
+	assert.False(t, ok)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// у другого ключа своя корзина
+	ok, _ = l.Allow("other")
+	assert.True(t, ok)
+
+	// за 20 секунд набирается один токен
+	clock.now = clock.now.Add(20 * time.Second)
+	ok, _ = l.Allow("client")
+	assert.True(t, ok)
+	ok, _ = l.Allow("client")
+	assert.False(t, ok)
+}
+
+func TestLimiter_Refund(t *testing.T) {
+	l, _ := newTestLimiter(Limit{N: 1, Per: time.Hour})
+
+	ok, _ := l.Allow("pr1")
+	require.True(t, ok)
+	l.Refund("pr1")
+
+	ok, _ = l.Allow("pr1")
+	assert.True(t, ok)
+	ok, wait := l.Allow("pr1")
+	assert.False(t, ok)
+	assert.Equal(t, time.Hour, wait)
+
+	// возврат не поднимает корзину выше N
+	l.Refund("pr2")
+	l.Refund("pr2")
+	ok, _ = l.Allow("pr2")
+	assert.True(t, ok)
+	ok, _ = l.Allow("pr2")
+	assert.False(t, ok)
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l, _ := newTestLimiter(Limit{})
+
+	for range 100 {
+		ok, _ := l.Allow("client")
+		require.True(t, ok)
+	}
+	assert.Empty(t, l.buckets)
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	l, clock := newTestLimiter(Limit{N: 2, Per: time.Minute})
+
+	l.Allow("idle")
+	clock.now = clock.now.Add(30 * time.Second)
+	l.Allow("busy")
+	l.Allow("busy")
+
+	// через минуту "idle" снова полная и удаляется, "busy" наполовину пустая и остается
+	clock.now = clock.now.Add(30 * time.Second)
+	l.Allow("new")
+
+	assert.NotContains(t, l.buckets, "idle")
+	assert.Contains(t, l.buckets, "busy")
+	assert.Contains(t, l.buckets, "new")
+}
+
+func TestRouteLimiter_Allow(t *testing.T) {
+	rl := NewRouteLimiter(Limit{N: 2, Per: time.Minute}, Routes{
+		"POST /pullRequest/reassign": {N: 1, Per: time.Minute},
+		"GET /export/reviews":        {},
+	})
+
+	ok, _ := rl.Allow("POST /pullRequest/reassign", "ip:1.1.1.1")
+	require.True(t, ok)
+	ok, _ = rl.Allow("POST /pullRequest/reassign", "ip:1.1.1.1")
+	assert.False(t, ok, "own route limit")
+	ok, _ = rl.Allow("POST /pullRequest/reassign", "ip:2.2.2.2")
+	assert.True(t, ok, "limit is per client")
+
+	for range 2 {
+		ok, _ = rl.Allow("GET /team/get", "ip:1.1.1.1")
+		require.True(t, ok)
+	}
+	ok, _ = rl.Allow("GET /team/get", "ip:1.1.1.1")
+	assert.False(t, ok, "default limit")
+	ok, _ = rl.Allow("POST /pullRequest/create", "ip:1.1.1.1")
+	assert.True(t, ok, "default limit is per route")
+
+	for range 10 {
+		ok, _ = rl.Allow("GET /export/reviews", "ip:1.1.1.1")
+		require.True(t, ok, "off")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Limit
+		wantErr  bool
+	}{
+		{in: "10/s", expected: Limit{N: 10, Per: time.Second}},
+		{in: "600/m", expected: Limit{N: 600, Per: time.Minute}},
+		{in: " 5/h ", expected: Limit{N: 5, Per: time.Hour}},
+		{in: "off", expected: Limit{}},
+		{in: "0", expected: Limit{}},
+		{in: "10", wantErr: true},
+		{in: "10/d", wantErr: true},
+		{in: "-1/s", wantErr: true},
+		{in: "0/s", wantErr: true},
+		{in: "0/h", wantErr: true},
+		{in: "x/s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRoutes_UnmarshalText(t *testing.T) {
+	var routes Routes
+	require.NoError(t, routes.UnmarshalText([]byte("post /pullRequest/reassign=30/m; GET /api/v1/pull-requests/{id}=off;")))
+	assert.Equal(t, Routes{
+		"POST /pullRequest/reassign":     {N: 30, Per: time.Minute},
+		"GET /api/v1/pull-requests/{id}": {},
+	}, routes)
+	assert.Equal(t, "GET /api/v1/pull-requests/{id}=off;POST /pullRequest/reassign=30/m", routes.String())
+
+	assert.Error(t, routes.UnmarshalText([]byte("/pullRequest/reassign=30/m")))
+	assert.Error(t, routes.UnmarshalText([]byte("POST /pullRequest/reassign")))
+	assert.Error(t, routes.UnmarshalText([]byte("POST /pullRequest/reassign=fast")))
+}
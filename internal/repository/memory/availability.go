@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"sort"
+	"time"
+)
+
+type availabilityRepositoryMemory struct {
+	storage *Storage
+}
+
+func NewAvailabilityRepositoryMemory(storage *Storage) *availabilityRepositoryMemory {
+	return &availabilityRepositoryMemory{storage: storage}
+}
+
+func (r *availabilityRepositoryMemory) AddAbsence(ctx context.Context, absence domain.Absence) (*domain.Absence, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.users[absence.UserID]; !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	r.storage.absenceSeq++
+	absence.ID = r.storage.absenceSeq
+	r.storage.absences[absence.ID] = absence
+
+	return &absence, nil
+}
+
+// ListAbsences периоды пользователя по времени начала
+func (r *availabilityRepositoryMemory) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.users[userID]; !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	absences := []domain.Absence{}
+	for _, a := range r.storage.absences {
+		if a.UserID == userID {
+			absences = append(absences, a)
+		}
+	}
+	sort.Slice(absences, func(i, j int) bool {
+		if !absences[i].Starts.Equal(absences[j].Starts) {
+			return absences[i].Starts.Before(absences[j].Starts)
+		}
+		return absences[i].ID < absences[j].ID
+	})
+	return absences, nil
+}
+
+func (r *availabilityRepositoryMemory) DeleteAbsence(ctx context.Context, userID string, absenceID int64) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.users[userID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	if a, ok := r.storage.absences[absenceID]; !ok || a.UserID != userID {
+		return repository.ErrAbsenceNotFound
+	}
+	delete(r.storage.absences, absenceID)
+	return nil
+}
+
+// ImportAbsences применяет импорт календаря под одной блокировкой: период ищется по (UserID, UID),
+// поэтому повторная загрузка того же календаря обновляет периоды, а не дублирует их
+func (r *availabilityRepositoryMemory) ImportAbsences(ctx context.Context, imp domain.AbsenceImport) (*domain.AbsenceImportReport, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	for _, userID := range imp.Users {
+		if _, ok := r.storage.users[userID]; !ok {
+			return nil, repository.ErrUserNotFound
+		}
+	}
+
+	report := &domain.AbsenceImportReport{}
+	for _, absence := range imp.Upsert {
+		stored, ok := r.storage.absenceByUIDLocked(absence.UserID, absence.UID)
+		switch {
+		case !ok:
+			r.storage.absenceSeq++
+			absence.ID = r.storage.absenceSeq
+			report.Created++
+		case stored.SamePeriod(absence):
+			report.Unchanged++
+			continue
+		default:
+			absence.ID = stored.ID
+			report.Updated++
+		}
+		r.storage.absences[absence.ID] = absence
+	}
+
+	for _, absence := range imp.Cancel {
+		if stored, ok := r.storage.absenceByUIDLocked(absence.UserID, absence.UID); ok {
+			delete(r.storage.absences, stored.ID)
+			report.Deleted++
+		}
+	}
+	return report, nil
+}
+
+// ListAbsentReviews ревьюеры открытых PR, у которых в момент now идет отсутствие без дней недели
+func (r *availabilityRepositoryMemory) ListAbsentReviews(ctx context.Context, now time.Time) ([]domain.AbsentReview, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	absent := make(map[string]bool)
+	for _, a := range r.storage.absences {
+		// у периодов без дней недели часовой пояс ни на что не влияет
+		if !a.Recurring() && a.Covers(now, time.UTC) {
+			absent[a.UserID] = true
+		}
+	}
+
+	reviews := []domain.AbsentReview{}
+	for prID, reviewers := range r.storage.reviewers {
+		if r.storage.prs[prID].Status != domain.PRStatusOpen {
+			continue
+		}
+		for _, uid := range reviewers {
+			if absent[uid] {
+				reviews = append(reviews, domain.AbsentReview{PullRequestID: prID, ReviewerID: uid})
+			}
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if reviews[i].PullRequestID != reviews[j].PullRequestID {
+			return reviews[i].PullRequestID < reviews[j].PullRequestID
+		}
+		return reviews[i].ReviewerID < reviews[j].ReviewerID
+	})
+	return reviews, nil
+}
+
+// SetWorkingHours задает расписание пользователя, заменяя прежнее
+func (r *availabilityRepositoryMemory) SetWorkingHours(ctx context.Context, hours domain.WorkingHours) (*domain.WorkingHours, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.users[hours.UserID]; !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	r.storage.workingHours[hours.UserID] = hours
+	return &hours, nil
+}
+
+// GetWorkingHours расписание пользователя, nil — не задано
+func (r *availabilityRepositoryMemory) GetWorkingHours(ctx context.Context, userID string) (*domain.WorkingHours, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.users[userID]; !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	hours, ok := r.storage.workingHours[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &hours, nil
+}
+
+// ListWorkingHours заданные расписания пользователей, неизвестные и без расписания пропускаются
+func (r *availabilityRepositoryMemory) ListWorkingHours(ctx context.Context, userIDs []string) (map[string]domain.WorkingHours, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	hours := make(map[string]domain.WorkingHours, len(userIDs))
+	for _, id := range userIDs {
+		if w, ok := r.storage.workingHours[id]; ok {
+			hours[id] = w
+		}
+	}
+	return hours, nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"time"
+)
+
+type idempotencyRepositoryMemory struct {
+	s *Storage
+}
+
+func NewIdempotencyRepositoryMemory(s *Storage) *idempotencyRepositoryMemory {
+	return &idempotencyRepositoryMemory{s: s}
+}
+
+// Reserve сохраняет ключ как "в обработке" и возвращает nil.
+// Если живой (не истекший на key.CreatedAt) ключ уже есть, возвращает его копию, ничего не меняя
+func (r *idempotencyRepositoryMemory) Reserve(_ context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.idempotency[key.Key]; ok && existing.ExpiresAt.After(key.CreatedAt) {
+		return copyIdempotencyKey(existing), nil
+	}
+
+	key.Response = nil
+	r.s.idempotency[key.Key] = key
+	return nil, nil
+}
+
+func (r *idempotencyRepositoryMemory) Complete(_ context.Context, key string, resp domain.IdempotentResponse) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.idempotency[key]
+	if !ok {
+		return nil
+	}
+	resp.Body = append([]byte(nil), resp.Body...)
+	existing.Response = &resp
+	r.s.idempotency[key] = existing
+	return nil
+}
+
+// Release удаляет ключ, по которому не удалось получить ответ: повтор выполнится заново
+func (r *idempotencyRepositoryMemory) Release(_ context.Context, key string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.idempotency, key)
+	return nil
+}
+
+func (r *idempotencyRepositoryMemory) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for k, v := range r.s.idempotency {
+		if !v.ExpiresAt.After(now) {
+			delete(r.s.idempotency, k)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyIdempotencyKey(key domain.IdempotencyKey) *domain.IdempotencyKey {
+	if key.Response != nil {
+		resp := *key.Response
+		resp.Body = append([]byte(nil), resp.Body...)
+		key.Response = &resp
+	}
+	return &key
+}
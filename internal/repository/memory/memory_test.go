@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"testing"
+
+	"service-order-avito/internal/repository/repotest"
+	"service-order-avito/internal/service/pull_request"
+)
+
+func TestRepositoriesMemory(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		storage := NewStorage()
+		return repotest.Repositories{
+			Team:        NewTeamRepositoryMemory(storage),
+			User:        NewUserRepositoryMemory(storage),
+			PullRequest: NewPullRequestRepositoryMemory(storage, 0),
+			LimitedPullRequest: func(maxOpenReviews int) pull_request.PullRequestRepository {
+				return NewPullRequestRepositoryMemory(storage, maxOpenReviews)
+			},
+			Idempotency:  NewIdempotencyRepositoryMemory(storage),
+			Availability: NewAvailabilityRepositoryMemory(storage),
+		}
+	})
+}
@@ -0,0 +1,400 @@
+package memory
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"sort"
+	"time"
+)
+
+type pullRequestRepositoryMemory struct {
+	storage *Storage
+	// maxOpenReviews сколько открытых PR может ревьюить один пользователь, 0 — без ограничения
+	maxOpenReviews int
+}
+
+func NewPullRequestRepositoryMemory(storage *Storage, maxOpenReviews int) *pullRequestRepositoryMemory {
+	return &pullRequestRepositoryMemory{storage: storage, maxOpenReviews: maxOpenReviews}
+}
+
+// CreateWithReviewers seed — зерно генератора выбора ревьюеров, сохраняется вместе с объяснением выбора
+func (r *pullRequestRepositoryMemory) CreateWithReviewers(ctx context.Context, pr domain.PullRequest, seed int64) (*domain.PullRequestWithReviewers, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	pool, _, err := r.createPoolLocked(&pr)
+	if err != nil {
+		return nil, err
+	}
+
+	activeMembers, explained := pool.Explain(MAX_REVIEWERS, domain.NewRand(seed))
+
+	if _, ok := r.storage.prs[pr.ID]; ok {
+		return nil, repository.ErrPullRequestExists
+	}
+	if len(pool.Unmet(activeMembers)) > 0 {
+		return nil, repository.ErrReviewerRulesUnmet
+	}
+
+	pr.Status = domain.PRStatusOpen
+	pr.MergedAt = nil
+	pr.Version = 1
+	r.storage.prs[pr.ID] = pr
+	r.storage.reviewers[pr.ID] = activeMembers
+	r.storage.addAssignmentLocked(domain.Assignment{
+		PullRequestID: pr.ID,
+		Kind:          domain.AssignmentCreate,
+		Seed:          seed,
+		Strategy:      pool.Strategy(),
+		At:            pr.CreatedAt,
+		Candidates:    explained,
+	})
+
+	return &domain.PullRequestWithReviewers{
+		PullRequest:       pr,
+		AssignedReviewers: append([]string{}, activeMembers...),
+	}, nil
+}
+
+// PreviewReviewers выбор, который сделал бы CreateWithReviewers с тем же seed, без записи.
+// Невыполнимые правила команды не ошибка, как при создании, а список UnmetRules
+func (r *pullRequestRepositoryMemory) PreviewReviewers(ctx context.Context, pr domain.PullRequest, seed int64) (*domain.ReviewerPreview, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	pool, excluded, err := r.createPoolLocked(&pr)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, explained := pool.Explain(MAX_REVIEWERS, domain.NewRand(seed))
+
+	return &domain.ReviewerPreview{
+		AuthorID:     pr.AuthorID,
+		RequiredTags: pr.RequiredTags,
+		Reviewers:    reviewers,
+		Seed:         seed,
+		Strategy:     pool.Strategy(),
+		At:           pr.CreatedAt,
+		Candidates:   explained,
+		Excluded:     excluded,
+		UnmetRules:   pool.Unmet(reviewers),
+	}, nil
+}
+
+// createPoolLocked кандидаты в ревьюеры нового PR и исключенные участники команды автора.
+// Заполняет у pr момент создания и нормализует теги. Вызывать под мьютексом
+func (r *pullRequestRepositoryMemory) createPoolLocked(pr *domain.PullRequest) (domain.ReviewerPool, []domain.CandidateExclusion, error) {
+	author, ok := r.storage.users[pr.AuthorID]
+	if !ok {
+		return domain.ReviewerPool{}, nil, repository.ErrUserNotFound
+	}
+
+	if _, ok := r.storage.teams[author.TeamName]; !ok {
+		return domain.ReviewerPool{}, nil, repository.ErrTeamNotFound
+	}
+
+	// момент создания задает сервис: по нему считаются отсутствия и рабочее время
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = r.storage.now()
+	}
+
+	// Случайный выбор ревьюеров среди активных и не отсутствующих сейчас, в первую очередь — покрывающих теги PR
+	// и у кого рабочее время
+	members := r.storage.membersLocked(author.TeamName)
+	pr.RequiredTags = domain.NormalizeTags(pr.RequiredTags)
+	pool := domain.ReviewerPool{
+		Hours:    r.storage.workingHours,
+		Now:      pr.CreatedAt,
+		Skills:   domain.SkillsOf(members),
+		Required: pr.RequiredTags,
+		Levels:   domain.LevelsOf(members),
+		Rules:    r.storage.reviewerRules[author.TeamName],
+		Recent:   r.storage.recentPairingsLocked(author.TeamName, pr.AuthorID, pr.CreatedAt),
+	}
+	var excluded []domain.CandidateExclusion
+	atCapacity := r.storage.atCapacityLocked(author.TeamName, r.maxOpenReviews)
+	pool.Candidates, excluded = domain.SplitCandidates(members, pr.AuthorID, atCapacity, r.storage.absentLocked(pr.CreatedAt))
+	return pool, excluded, nil
+}
+
+func (r *pullRequestRepositoryMemory) GetByID(ctx context.Context, prID string) (*domain.PullRequestWithReviewers, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	pr, ok := r.storage.prWithReviewersLocked(prID)
+	if !ok {
+		return nil, repository.ErrPullRequestNotFound
+	}
+	return pr, nil
+}
+
+// GetByIDs PR с ревьюерами по списку id, несуществующие пропускаются. Порядок как в Postgres — по id
+func (r *pullRequestRepositoryMemory) GetByIDs(ctx context.Context, prIDs []string) ([]domain.PullRequestWithReviewers, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	prs := []domain.PullRequestWithReviewers{}
+	seen := make(map[string]struct{}, len(prIDs))
+	for _, id := range prIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		if pr, ok := r.storage.prWithReviewersLocked(id); ok {
+			prs = append(prs, *pr)
+		}
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].ID < prs[j].ID
+	})
+
+	return prs, nil
+}
+
+// Export отдает PR по фильтру в fn, в порядке created_at. Данные и так в памяти: под мьютексом снимается копия,
+// а fn вызывается уже без блокировки, чтобы медленный клиент не держал запись
+func (r *pullRequestRepositoryMemory) Export(ctx context.Context, filter domain.ExportFilter, fn func(domain.PullRequestExport) error) error {
+	prs, err := r.exportSnapshot(filter)
+	if err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		if err := fn(pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *pullRequestRepositoryMemory) exportSnapshot(filter domain.ExportFilter) ([]domain.PullRequestExport, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if filter.TeamName != "" {
+		if _, ok := r.storage.teams[filter.TeamName]; !ok {
+			return nil, repository.ErrTeamNotFound
+		}
+	}
+
+	var prs []domain.PullRequestExport
+	for id, pr := range r.storage.prs {
+		teamName := r.storage.users[pr.AuthorID].TeamName
+		switch {
+		case filter.TeamName != "" && teamName != filter.TeamName:
+			continue
+		case filter.From != nil && pr.CreatedAt.Before(*filter.From):
+			continue
+		case filter.To != nil && !pr.CreatedAt.Before(*filter.To):
+			continue
+		}
+
+		withReviewers, _ := r.storage.prWithReviewersLocked(id)
+		sort.Strings(withReviewers.AssignedReviewers)
+		prs = append(prs, domain.PullRequestExport{PullRequestWithReviewers: *withReviewers, TeamName: teamName})
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		if !prs[i].CreatedAt.Equal(prs[j].CreatedAt) {
+			return prs[i].CreatedAt.Before(prs[j].CreatedAt)
+		}
+		return prs[i].ID < prs[j].ID
+	})
+
+	return prs, nil
+}
+
+// Merge помечает PR как MERGED. expectedVersion — версия из If-Match, 0 — без проверки.
+// merged false, если PR уже был MERGED и ничего не изменилось (повторный merge)
+func (r *pullRequestRepositoryMemory) Merge(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequestWithReviewers, bool, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	pr, ok := r.storage.prs[prID]
+	if !ok {
+		return nil, false, repository.ErrPullRequestNotFound
+	}
+
+	if !versionMatches(pr.Version, expectedVersion) {
+		return nil, false, repository.ErrVersionMismatch
+	}
+
+	if pr.Status == domain.PRStatusClosed {
+		return nil, false, repository.ErrPullRequestClosed
+	}
+
+	// проверка для идемпотентности
+	changed := pr.Status != domain.PRStatusMerged
+	if changed {
+		mergedAt := r.storage.now()
+		pr.Status = domain.PRStatusMerged
+		pr.MergedAt = &mergedAt
+		pr.Version++
+		r.storage.prs[prID] = pr
+	}
+
+	merged, _ := r.storage.prWithReviewersLocked(prID)
+	return merged, changed, nil
+}
+
+// Close помечает PR как CLOSED. expectedVersion — версия из If-Match, 0 — без проверки.
+// closed false, если PR уже был CLOSED и ничего не изменилось (повторный close)
+func (r *pullRequestRepositoryMemory) Close(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequestWithReviewers, bool, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	pr, ok := r.storage.prs[prID]
+	if !ok {
+		return nil, false, repository.ErrPullRequestNotFound
+	}
+
+	if !versionMatches(pr.Version, expectedVersion) {
+		return nil, false, repository.ErrVersionMismatch
+	}
+
+	if pr.Status == domain.PRStatusMerged {
+		return nil, false, repository.ErrPullRequestMerged
+	}
+
+	// проверка для идемпотентности
+	changed := pr.Status != domain.PRStatusClosed
+	if changed {
+		pr.Status = domain.PRStatusClosed
+		pr.Version++
+		r.storage.prs[prID] = pr
+	}
+
+	closed, _ := r.storage.prWithReviewersLocked(prID)
+	return closed, changed, nil
+}
+
+// ReassignReviewer заменяет ревьюера. expectedVersion — версия из If-Match, 0 — без проверки; now — момент замены;
+// seed — зерно генератора выбора, сохраняется вместе с объяснением выбора
+func (r *pullRequestRepositoryMemory) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, expectedVersion int64, now time.Time, seed int64) (*domain.Reviewer, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	pr, ok := r.storage.prWithReviewersLocked(prID)
+	if !ok {
+		return nil, repository.ErrPullRequestNotFound
+	}
+
+	if !versionMatches(pr.Version, expectedVersion) {
+		return nil, repository.ErrVersionMismatch
+	}
+
+	// проверка на MERGED и CLOSED
+	if pr.Status == domain.PRStatusMerged {
+		return nil, repository.ErrPullRequestMerged
+	}
+	if pr.Status == domain.PRStatusClosed {
+		return nil, repository.ErrPullRequestClosed
+	}
+
+	// проверка есть ли вообще такой пользователь
+	oldUser, ok := r.storage.users[oldReviewerID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	// проверка, что oldReviewer действительно был назначен
+	idx := -1
+	assigned := make(map[string]struct{}, len(pr.AssignedReviewers))
+	for i, uid := range pr.AssignedReviewers {
+		assigned[uid] = struct{}{}
+		if uid == oldReviewerID {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return nil, repository.ErrReviewerNotAssigned
+	}
+
+	if _, ok := r.storage.teams[oldUser.TeamName]; !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	// новый ревьюер должен покрыть теги и правила уровней, которые держались только на заменяемом
+	var remaining []string
+	for _, uid := range pr.AssignedReviewers {
+		if uid != oldReviewerID {
+			remaining = append(remaining, uid)
+		}
+	}
+	members := r.storage.membersLocked(oldUser.TeamName)
+	skills := domain.SkillsOf(members)
+	levels := domain.LevelsOf(members)
+	absent := r.storage.absentLocked(now)
+	atCapacity := r.storage.atCapacityLocked(oldUser.TeamName, r.maxOpenReviews)
+	pool := domain.ReviewerPool{
+		Hours:    r.storage.workingHours,
+		Now:      now,
+		Skills:   skills,
+		Required: domain.UncoveredTags(pr.RequiredTags, skills, remaining),
+		Levels:   levels,
+		Rules:    domain.UnmetRules(r.storage.reviewerRules[oldUser.TeamName], levels, remaining),
+		Recent:   r.storage.recentPairingsLocked(oldUser.TeamName, pr.AuthorID, now),
+	}
+	for _, u := range members {
+		if _, ok := assigned[u.ID]; ok {
+			continue
+		}
+		if u.ID != pr.AuthorID && u.IsActive && !absent[u.ID] && !atCapacity[u.ID] {
+			pool.Candidates = append(pool.Candidates, u.ID)
+		}
+	}
+
+	// проверка на наличие кандидата
+	if len(pool.Candidates) == 0 {
+		return nil, repository.ErrNoReplacementCandidate
+	}
+
+	picked, explained := pool.Explain(1, domain.NewRand(seed))
+	newReviewer := picked[0]
+	if len(pool.Unmet([]string{newReviewer})) > 0 {
+		return nil, repository.ErrReviewerRulesUnmet
+	}
+
+	r.storage.reviewers[prID][idx] = newReviewer
+	stored := r.storage.prs[prID]
+	stored.Version++
+	r.storage.prs[prID] = stored
+	r.storage.addAssignmentLocked(domain.Assignment{
+		PullRequestID:  prID,
+		Kind:           domain.AssignmentReassign,
+		ReplacedUserID: oldReviewerID,
+		Seed:           seed,
+		Strategy:       pool.Strategy(),
+		At:             now,
+		Candidates:     explained,
+	})
+
+	return &domain.Reviewer{
+		ID:                 newReviewer,
+		PullRequestVersion: stored.Version,
+	}, nil
+}
+
+// versionMatches expected == 0 означает, что клиент не прислал If-Match
+func versionMatches(current, expected int64) bool {
+	return expected == 0 || current == expected
+}
+
+// GetAssignments выборы ревьюеров PR в порядке записи, кандидаты — по rank
+func (r *pullRequestRepositoryMemory) GetAssignments(ctx context.Context, prID string) ([]domain.Assignment, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.prs[prID]; !ok {
+		return nil, repository.ErrPullRequestNotFound
+	}
+
+	assignments := []domain.Assignment{}
+	for _, a := range r.storage.assignments {
+		if a.PullRequestID == prID {
+			assignments = append(assignments, a)
+		}
+	}
+	return assignments, nil
+}
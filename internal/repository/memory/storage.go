@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"service-order-avito/internal/domain"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const MAX_REVIEWERS = 2
+
+// Storage общее состояние для всех in-memory репозиториев.
+// Один мьютекс на все таблицы дает те же гарантии, что и транзакции в Postgres:
+// create/merge/reassign видят и меняют данные атомарно
+type Storage struct {
+	mu        sync.RWMutex
+	teams     map[string]domain.Team
+	users     map[string]domain.User
+	prs       map[string]domain.PullRequest
+	reviewers map[string][]string // pull_request_id -> user_id в порядке назначения
+	// idempotency ключи Idempotency-Key, истекшие удаляются через DeleteExpired
+	idempotency map[string]domain.IdempotencyKey
+	// absences периоды отсутствия пользователей, absenceSeq — последний выданный id
+	absences   map[int64]domain.Absence
+	absenceSeq int64
+	// workingHours заданные пользователями расписания, у остальных domain.DefaultWorkingHours
+	workingHours map[string]domain.WorkingHours
+	// reviewerRules правила команд к уровням ревьюеров
+	reviewerRules map[string][]domain.ReviewerRule
+	// pairingSettings стратегии команд, у остальных domain.DefaultPairingSettings
+	pairingSettings map[string]domain.PairingSettings
+	// assignments выборы ревьюеров в порядке записи, assignmentSeq — последний выданный id
+	assignments   []domain.Assignment
+	assignmentSeq int64
+	now           func() time.Time
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		teams:           make(map[string]domain.Team),
+		users:           make(map[string]domain.User),
+		prs:             make(map[string]domain.PullRequest),
+		reviewers:       make(map[string][]string),
+		idempotency:     make(map[string]domain.IdempotencyKey),
+		absences:        make(map[int64]domain.Absence),
+		workingHours:    make(map[string]domain.WorkingHours),
+		reviewerRules:   make(map[string][]domain.ReviewerRule),
+		pairingSettings: make(map[string]domain.PairingSettings),
+		now:             time.Now,
+	}
+}
+
+// membersLocked возвращает участников команды, отсортированных по user_id. Вызывать под мьютексом
+func (s *Storage) membersLocked(teamName string) []domain.User {
+	var members []domain.User
+	for _, u := range s.users {
+		if u.TeamName == teamName {
+			members = append(members, u)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+	return members
+}
+
+// upsertUserLocked добавляет или обновляет пользователя. Теги и уровень команды и импорт не задают,
+// у существующего они сохраняются. Вызывать под мьютексом
+func (s *Storage) upsertUserLocked(u domain.User) {
+	u.Skills = s.users[u.ID].Skills
+	u.Level = s.users[u.ID].Level
+	s.users[u.ID] = u
+}
+
+// absentLocked пользователи, отсутствующие в момент t, дни недели — в часовом поясе их расписаний.
+// Вызывать под мьютексом
+func (s *Storage) absentLocked(t time.Time) map[string]bool {
+	absences := make([]domain.Absence, 0, len(s.absences))
+	for _, a := range s.absences {
+		absences = append(absences, a)
+	}
+	return domain.AbsentAt(absences, s.workingHours, t)
+}
+
+// absenceByUIDLocked период, импортированный из события календаря uid. Вызывать под мьютексом
+func (s *Storage) absenceByUIDLocked(userID, uid string) (domain.Absence, bool) {
+	for _, a := range s.absences {
+		if a.UserID == userID && a.UID == uid {
+			return a, true
+		}
+	}
+	return domain.Absence{}, false
+}
+
+// pairingSettingsLocked стратегия команды. Вызывать под мьютексом
+func (s *Storage) pairingSettingsLocked(teamName string) domain.PairingSettings {
+	if settings, ok := s.pairingSettings[teamName]; ok {
+		return settings
+	}
+	return domain.DefaultPairingSettings()
+}
+
+// pairingsLocked пары автор-ревьюер из выборов ревьюеров не раньше since, со временем назначения,
+// авторов, для которых match. Ревьюер, которого потом заменили, остается в истории. Вызывать под мьютексом
+func (s *Storage) pairingsLocked(since time.Time, match func(author domain.User) bool) []domain.Pairing {
+	var pairings []domain.Pairing
+	for _, a := range s.assignments {
+		pr, ok := s.prs[a.PullRequestID]
+		if !ok || a.At.Before(since) || !match(s.users[pr.AuthorID]) {
+			continue
+		}
+		for _, reviewerID := range a.Reviewers() {
+			pairings = append(pairings, domain.Pairing{AuthorID: pr.AuthorID, ReviewerID: reviewerID, At: a.At})
+		}
+	}
+	sort.Slice(pairings, func(i, j int) bool {
+		a, b := pairings[i], pairings[j]
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		if a.AuthorID != b.AuthorID {
+			return a.AuthorID < b.AuthorID
+		}
+		return a.ReviewerID < b.ReviewerID
+	})
+	return pairings
+}
+
+// recentPairingsLocked вес недавних ревью у автора для выбора ревьюеров в команде teamName.
+// nil, если команда выбирает случайно. Вызывать под мьютексом
+func (s *Storage) recentPairingsLocked(teamName, authorID string, now time.Time) map[string]float64 {
+	settings := s.pairingSettingsLocked(teamName)
+	if settings.Strategy != domain.PairingDiversity {
+		return nil
+	}
+	pairings := s.pairingsLocked(now.Add(-settings.Window()), func(author domain.User) bool {
+		return author.ID == authorID
+	})
+	return domain.PairingScores(pairings, authorID, now, settings.Window())
+}
+
+// atCapacityLocked участники команды, у которых открытых ревью не меньше limit. limit <= 0 — nil. Вызывать под мьютексом
+func (s *Storage) atCapacityLocked(teamName string, limit int) map[string]bool {
+	if limit <= 0 {
+		return nil
+	}
+	open := make(map[string]int)
+	for prID, reviewers := range s.reviewers {
+		if s.prs[prID].Status != domain.PRStatusOpen {
+			continue
+		}
+		for _, uid := range reviewers {
+			if s.users[uid].TeamName == teamName {
+				open[uid]++
+			}
+		}
+	}
+	return domain.AtCapacity(open, limit)
+}
+
+// addAssignmentLocked сохраняет выбор ревьюеров. Кандидаты не разделяются с вызывающим. Вызывать под мьютексом
+func (s *Storage) addAssignmentLocked(a domain.Assignment) {
+	s.assignmentSeq++
+	a.ID = s.assignmentSeq
+	a.Candidates = slices.Clone(a.Candidates)
+	s.assignments = append(s.assignments, a)
+}
+
+// assignedAtLocked когда ревьюер назначен на PR: последний выбор, в котором он выбран. Вызывать под мьютексом
+func (s *Storage) assignedAtLocked(pr domain.PullRequest, reviewerID string) time.Time {
+	at := pr.CreatedAt
+	for _, a := range s.assignments {
+		if a.PullRequestID == pr.ID && slices.Contains(a.Reviewers(), reviewerID) {
+			at = a.At
+		}
+	}
+	return at
+}
+
+func (s *Storage) prWithReviewersLocked(prID string) (*domain.PullRequestWithReviewers, bool) {
+	pr, ok := s.prs[prID]
+	if !ok {
+		return nil, false
+	}
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		pr.MergedAt = &mergedAt
+	}
+	return &domain.PullRequestWithReviewers{
+		PullRequest:       pr,
+		AssignedReviewers: append([]string{}, s.reviewers[prID]...),
+	}, true
+}
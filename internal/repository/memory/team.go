@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"sort"
+	"time"
+)
+
+type teamRepositoryMemory struct {
+	storage *Storage
+}
+
+func NewTeamRepositoryMemory(storage *Storage) *teamRepositoryMemory {
+	return &teamRepositoryMemory{storage: storage}
+}
+
+func (r *teamRepositoryMemory) AddTeamWithMembers(ctx context.Context, team domain.Team, members []domain.User) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.teams[team.Name]; ok {
+		return repository.ErrTeamAlreadyExists
+	}
+
+	r.storage.teams[team.Name] = team
+	// как ON CONFLICT DO UPDATE: существующие пользователи переезжают в новую команду, теги остаются
+	for _, u := range members {
+		r.storage.upsertUserLocked(u)
+	}
+
+	return nil
+}
+
+func (r *teamRepositoryMemory) GetTeamWithMembers(ctx context.Context, teamName string) (*domain.TeamWithUsers, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	team, ok := r.storage.teams[teamName]
+	if !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	return &domain.TeamWithUsers{
+		TeamName: team.Name,
+		Members:  r.storage.membersLocked(teamName),
+	}, nil
+}
+
+// GetTeamStats возвращает статистику по команде:
+// количество активных и неактивных пользователей,
+// количество открытых и замерженных PR, авторы которых состоят в команде
+func (r *teamRepositoryMemory) GetTeamStats(ctx context.Context, teamName string) (activeUsers, inactiveUsers, openPRs, mergedPRs int, err error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.teams[teamName]; !ok {
+		return 0, 0, 0, 0, repository.ErrTeamNotFound
+	}
+
+	for _, u := range r.storage.membersLocked(teamName) {
+		if u.IsActive {
+			activeUsers++
+		} else {
+			inactiveUsers++
+		}
+	}
+
+	for _, pr := range r.storage.prs {
+		author, ok := r.storage.users[pr.AuthorID]
+		if !ok || author.TeamName != teamName {
+			continue
+		}
+		switch pr.Status {
+		case domain.PRStatusOpen:
+			openPRs++
+		case domain.PRStatusMerged:
+			mergedPRs++
+		}
+	}
+
+	return activeUsers, inactiveUsers, openPRs, mergedPRs, nil
+}
+
+// GetTeamsWithMembers команды с участниками, несуществующие пропускаются. Порядок как в Postgres — по имени
+func (r *teamRepositoryMemory) GetTeamsWithMembers(ctx context.Context, teamNames []string) ([]domain.TeamWithUsers, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	teams := []domain.TeamWithUsers{}
+	seen := make(map[string]struct{}, len(teamNames))
+	for _, name := range teamNames {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		if _, ok := r.storage.teams[name]; !ok {
+			continue
+		}
+		teams = append(teams, domain.TeamWithUsers{
+			TeamName: name,
+			Members:  append([]domain.User{}, r.storage.membersLocked(name)...),
+		})
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].TeamName < teams[j].TeamName
+	})
+
+	return teams, nil
+}
+
+// ImportTeams создает недостающие команды и upsert'ит участников под одной блокировкой, dryRun только считает отчет
+func (r *teamRepositoryMemory) ImportTeams(ctx context.Context, teams []domain.TeamWithUsers, dryRun bool) (*domain.ImportReport, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	existingTeams := make(map[string]bool, len(teams))
+	for _, t := range teams {
+		_, existingTeams[t.TeamName] = r.storage.teams[t.TeamName]
+	}
+
+	users := domain.ImportUsers(teams)
+	existingUsers := make(map[string]domain.User, len(users))
+	for _, u := range users {
+		if old, ok := r.storage.users[u.ID]; ok {
+			existingUsers[u.ID] = old
+		}
+	}
+
+	report := domain.PlanImport(teams, existingTeams, existingUsers)
+	if dryRun {
+		return &report, nil
+	}
+
+	for _, name := range report.TeamsCreated {
+		r.storage.teams[name] = domain.Team{Name: name}
+	}
+	for _, u := range users {
+		r.storage.upsertUserLocked(u)
+	}
+	return &report, nil
+}
+
+// SetReviewerRules заменяет правила команды к уровням ревьюеров, пустой список их снимает
+func (r *teamRepositoryMemory) SetReviewerRules(ctx context.Context, teamName string, rules []domain.ReviewerRule) ([]domain.ReviewerRule, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.teams[teamName]; !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+
+	rules = domain.NormalizeRules(rules)
+	if rules == nil {
+		delete(r.storage.reviewerRules, teamName)
+	} else {
+		r.storage.reviewerRules[teamName] = rules
+	}
+	return append([]domain.ReviewerRule(nil), rules...), nil
+}
+
+// GetReviewerRules правила команды, от старшего уровня к младшему
+func (r *teamRepositoryMemory) GetReviewerRules(ctx context.Context, teamName string) ([]domain.ReviewerRule, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.teams[teamName]; !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+	return append([]domain.ReviewerRule(nil), r.storage.reviewerRules[teamName]...), nil
+}
+
+// SetPairingSettings задает стратегию выбора ревьюеров команды
+func (r *teamRepositoryMemory) SetPairingSettings(ctx context.Context, teamName string, settings domain.PairingSettings) (*domain.PairingSettings, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.teams[teamName]; !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+	r.storage.pairingSettings[teamName] = settings
+	return &settings, nil
+}
+
+// GetPairingSettings стратегия команды, без настроек — domain.DefaultPairingSettings
+func (r *teamRepositoryMemory) GetPairingSettings(ctx context.Context, teamName string) (*domain.PairingSettings, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.teams[teamName]; !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+	settings := r.storage.pairingSettingsLocked(teamName)
+	return &settings, nil
+}
+
+// GetPairings назначения ревьюеров к PR авторов команды не раньше since
+func (r *teamRepositoryMemory) GetPairings(ctx context.Context, teamName string, since time.Time) ([]domain.Pairing, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	if _, ok := r.storage.teams[teamName]; !ok {
+		return nil, repository.ErrTeamNotFound
+	}
+	return r.storage.pairingsLocked(since, func(author domain.User) bool {
+		return author.TeamName == teamName
+	}), nil
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"slices"
+	"sort"
+)
+
+type userRepositoryMemory struct {
+	storage *Storage
+}
+
+func NewUserRepositoryMemory(storage *Storage) *userRepositoryMemory {
+	return &userRepositoryMemory{storage: storage}
+}
+
+func (r *userRepositoryMemory) GetByTeamName(ctx context.Context, teamName string) ([]domain.User, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	return r.storage.membersLocked(teamName), nil
+}
+
+func (r *userRepositoryMemory) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	u, ok := r.storage.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	u.IsActive = isActive
+	r.storage.users[userID] = u
+
+	return &u, nil
+}
+
+// SetSkills заменяет теги пользователя
+func (r *userRepositoryMemory) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	u, ok := r.storage.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	u.Skills = domain.NormalizeTags(skills)
+	r.storage.users[userID] = u
+
+	return &u, nil
+}
+
+// UpdateSkills добавляет и убирает теги
+func (r *userRepositoryMemory) UpdateSkills(ctx context.Context, userID string, add, remove []string) (*domain.User, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	u, ok := r.storage.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	skills := make([]string, 0, len(u.Skills)+len(add))
+	for _, s := range append(append([]string{}, u.Skills...), add...) {
+		if !slices.Contains(remove, s) {
+			skills = append(skills, s)
+		}
+	}
+	u.Skills = domain.NormalizeTags(skills)
+	r.storage.users[userID] = u
+
+	return &u, nil
+}
+
+// SetLevel задает уровень пользователя
+func (r *userRepositoryMemory) SetLevel(ctx context.Context, userID string, level domain.Level) (*domain.User, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	u, ok := r.storage.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	u.Level = level
+	r.storage.users[userID] = u
+
+	return &u, nil
+}
+
+func (r *userRepositoryMemory) GetByID(ctx context.Context, userID string) (*domain.User, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	u, ok := r.storage.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *userRepositoryMemory) GetReviewPullRequests(ctx context.Context, userID string) ([]domain.ReviewPullRequest, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	// проверка на существование такого пользователя
+	if _, ok := r.storage.users[userID]; !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	var prs []domain.ReviewPullRequest
+	for prID, reviewers := range r.storage.reviewers {
+		for _, uid := range reviewers {
+			if uid == userID {
+				pr := r.storage.prs[prID]
+				pr.MergedAt = nil
+				prs = append(prs, domain.ReviewPullRequest{PullRequest: pr, AssignedAt: r.storage.assignedAtLocked(pr, userID)})
+				break
+			}
+		}
+	}
+
+	// как и в Postgres: сначала новые
+	sort.Slice(prs, func(i, j int) bool {
+		if prs[i].CreatedAt.Equal(prs[j].CreatedAt) {
+			return prs[i].ID > prs[j].ID
+		}
+		return prs[i].CreatedAt.After(prs[j].CreatedAt)
+	})
+
+	return prs, nil
+}
+
+// GetByIDs пользователи по списку id, несуществующие пропускаются. Порядок как в Postgres — по user_id
+func (r *userRepositoryMemory) GetByIDs(ctx context.Context, userIDs []string) ([]domain.User, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	users := []domain.User{}
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		if u, ok := r.storage.users[id]; ok {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users, nil
+}
+
+// GetReviewPullRequestIDs id PR на ревью для каждого пользователя, сначала новые.
+// Пользователи без PR (и несуществующие) в map не попадают
+func (r *userRepositoryMemory) GetReviewPullRequestIDs(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	wanted := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = struct{}{}
+	}
+
+	prs := make(map[string][]domain.PullRequest)
+	for prID, reviewers := range r.storage.reviewers {
+		for _, uid := range reviewers {
+			if _, ok := wanted[uid]; ok {
+				prs[uid] = append(prs[uid], r.storage.prs[prID])
+			}
+		}
+	}
+
+	queues := make(map[string][]string, len(prs))
+	for uid, list := range prs {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+				return list[i].ID > list[j].ID
+			}
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		})
+		ids := make([]string, len(list))
+		for i, pr := range list {
+			ids[i] = pr.ID
+		}
+		queues[uid] = ids
+	}
+
+	return queues, nil
+}
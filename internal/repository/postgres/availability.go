@@ -0,0 +1,327 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"time"
+)
+
+type availabilityRepositoryPostgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewAvailabilityRepositoryPostgres(pool *pgxpool.Pool) *availabilityRepositoryPostgres {
+	return &availabilityRepositoryPostgres{pool: pool}
+}
+
+func (r *availabilityRepositoryPostgres) AddAbsence(ctx context.Context, absence domain.Absence) (*domain.Absence, error) {
+	const op = "repository.postgres.availability.AddAbsence"
+
+	query := `
+        INSERT INTO user_absences (user_id, kind, starts_at, ends_at, weekdays)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING absence_id
+    `
+
+	err := r.pool.QueryRow(ctx, query, absence.UserID, absence.Kind, absence.Starts, absence.Ends, int16(absence.Weekdays)).
+		Scan(&absence.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, repository.Internal(op, err)
+	}
+
+	return &absence, nil
+}
+
+// ListAbsences периоды пользователя по времени начала
+func (r *availabilityRepositoryPostgres) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	const op = "repository.postgres.availability.ListAbsences"
+
+	if err := userExists(ctx, r.pool, userID); err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT absence_id, user_id, kind, starts_at, ends_at, weekdays, COALESCE(source_uid, '')
+        FROM user_absences
+        WHERE user_id = $1
+        ORDER BY starts_at, absence_id
+    `
+	absences, err := queryAbsences(ctx, r.pool, query, userID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return absences, nil
+}
+
+func (r *availabilityRepositoryPostgres) DeleteAbsence(ctx context.Context, userID string, absenceID int64) error {
+	const op = "repository.postgres.availability.DeleteAbsence"
+
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM user_absences WHERE absence_id = $1 AND user_id = $2`,
+		absenceID, userID,
+	)
+	if err != nil {
+		return repository.Internal(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		// различаем "нет пользователя" и "нет такого периода у пользователя"
+		if err := userExists(ctx, r.pool, userID); err != nil {
+			return err
+		}
+		return repository.ErrAbsenceNotFound
+	}
+	return nil
+}
+
+// ImportAbsences применяет импорт календаря одной транзакцией: период ищется по (user_id, source_uid),
+// поэтому повторная загрузка того же календаря обновляет периоды, а не дублирует их
+func (r *availabilityRepositoryPostgres) ImportAbsences(ctx context.Context, imp domain.AbsenceImport) (*domain.AbsenceImportReport, error) {
+	const op = "repository.postgres.availability.ImportAbsences"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, userID := range imp.Users {
+		if err := userExists(ctx, tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &domain.AbsenceImportReport{}
+	for _, absence := range imp.Upsert {
+		stored, err := queryAbsences(ctx, tx, `
+            SELECT absence_id, user_id, kind, starts_at, ends_at, weekdays, COALESCE(source_uid, '')
+            FROM user_absences
+            WHERE user_id = $1 AND source_uid = $2
+            FOR UPDATE
+        `, absence.UserID, absence.UID)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+
+		switch {
+		case len(stored) == 0:
+			_, err = tx.Exec(ctx, `
+                INSERT INTO user_absences (user_id, kind, starts_at, ends_at, weekdays, source_uid)
+                VALUES ($1, $2, $3, $4, $5, $6)
+            `, absence.UserID, absence.Kind, absence.Starts, absence.Ends, int16(absence.Weekdays), absence.UID)
+			report.Created++
+		case stored[0].SamePeriod(absence):
+			report.Unchanged++
+		default:
+			_, err = tx.Exec(ctx, `
+                UPDATE user_absences SET kind = $2, starts_at = $3, ends_at = $4, weekdays = $5
+                WHERE absence_id = $1
+            `, stored[0].ID, absence.Kind, absence.Starts, absence.Ends, int16(absence.Weekdays))
+			report.Updated++
+		}
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	for _, absence := range imp.Cancel {
+		tag, err := tx.Exec(ctx,
+			`DELETE FROM user_absences WHERE user_id = $1 AND source_uid = $2`,
+			absence.UserID, absence.UID,
+		)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		report.Deleted += int(tag.RowsAffected())
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return report, nil
+}
+
+// ListAbsentReviews ревьюеры открытых PR, у которых в момент now идет отсутствие без дней недели
+func (r *availabilityRepositoryPostgres) ListAbsentReviews(ctx context.Context, now time.Time) ([]domain.AbsentReview, error) {
+	const op = "repository.postgres.availability.ListAbsentReviews"
+
+	query := `
+        SELECT DISTINCT r.pull_request_id, r.user_id
+        FROM pr_reviewers r
+        JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+        JOIN user_absences a ON a.user_id = r.user_id
+        WHERE p.status = 'OPEN'
+          AND a.weekdays = 0
+          AND a.starts_at <= $1 AND a.ends_at > $1
+        ORDER BY r.pull_request_id, r.user_id
+    `
+	rows, err := r.pool.Query(ctx, query, now)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	reviews := []domain.AbsentReview{}
+	for rows.Next() {
+		var review domain.AbsentReview
+		if err := rows.Scan(&review.PullRequestID, &review.ReviewerID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return reviews, nil
+}
+
+// SetWorkingHours задает расписание пользователя, заменяя прежнее
+func (r *availabilityRepositoryPostgres) SetWorkingHours(ctx context.Context, hours domain.WorkingHours) (*domain.WorkingHours, error) {
+	const op = "repository.postgres.availability.SetWorkingHours"
+
+	query := `
+        INSERT INTO user_working_hours (user_id, timezone, start_minute, end_minute, weekdays)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id) DO UPDATE
+        SET timezone = EXCLUDED.timezone, start_minute = EXCLUDED.start_minute,
+            end_minute = EXCLUDED.end_minute, weekdays = EXCLUDED.weekdays
+    `
+	_, err := r.pool.Exec(ctx, query, hours.UserID, hours.Timezone, int16(hours.Start), int16(hours.End), int16(hours.Days))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, repository.Internal(op, err)
+	}
+	return &hours, nil
+}
+
+// GetWorkingHours расписание пользователя, nil — не задано
+func (r *availabilityRepositoryPostgres) GetWorkingHours(ctx context.Context, userID string) (*domain.WorkingHours, error) {
+	const op = "repository.postgres.availability.GetWorkingHours"
+
+	if err := userExists(ctx, r.pool, userID); err != nil {
+		return nil, err
+	}
+
+	hours, err := queryWorkingHours(ctx, r.pool, `
+        SELECT user_id, timezone, start_minute, end_minute, weekdays
+        FROM user_working_hours
+        WHERE user_id = $1
+    `, userID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	w, ok := hours[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &w, nil
+}
+
+// ListWorkingHours заданные расписания пользователей, неизвестные и без расписания пропускаются
+func (r *availabilityRepositoryPostgres) ListWorkingHours(ctx context.Context, userIDs []string) (map[string]domain.WorkingHours, error) {
+	const op = "repository.postgres.availability.ListWorkingHours"
+
+	hours, err := queryWorkingHours(ctx, r.pool, `
+        SELECT user_id, timezone, start_minute, end_minute, weekdays
+        FROM user_working_hours
+        WHERE user_id = ANY($1)
+    `, userIDs)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return hours, nil
+}
+
+func userExists(ctx context.Context, q querier, userID string) error {
+	const op = "repository.postgres.availability.userExists"
+
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1)`, userID).Scan(&exists); err != nil {
+		return repository.Internal(op, err)
+	}
+	if !exists {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+// absentInTeam участники команды, отсутствующие в момент now: их не назначают ревьюерами.
+// Дни недели проверяются в Go в часовом поясе из hours, как и в остальных хранилищах (domain.AbsentAt)
+func absentInTeam(ctx context.Context, q querier, teamName string, hours map[string]domain.WorkingHours, now time.Time) (map[string]bool, error) {
+	query := `
+        SELECT a.absence_id, a.user_id, a.kind, a.starts_at, a.ends_at, a.weekdays, COALESCE(a.source_uid, '')
+        FROM user_absences a
+        JOIN users u ON u.user_id = a.user_id
+        WHERE u.team_name = $1 AND a.starts_at <= $2 AND a.ends_at > $2
+    `
+	absences, err := queryAbsences(ctx, q, query, teamName, now)
+	if err != nil {
+		return nil, err
+	}
+	return domain.AbsentAt(absences, hours, now), nil
+}
+
+func queryAbsences(ctx context.Context, q querier, query string, args ...any) ([]domain.Absence, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absences := []domain.Absence{}
+	for rows.Next() {
+		var (
+			a        domain.Absence
+			weekdays int16
+		)
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Kind, &a.Starts, &a.Ends, &weekdays, &a.UID); err != nil {
+			return nil, err
+		}
+		a.Weekdays = domain.Weekdays(weekdays)
+		absences = append(absences, a)
+	}
+	return absences, rows.Err()
+}
+
+// workingHoursInTeam заданные расписания участников команды: по ним выбор ревьюеров предпочитает тех, кто сейчас работает
+func workingHoursInTeam(ctx context.Context, q querier, teamName string) (map[string]domain.WorkingHours, error) {
+	return queryWorkingHours(ctx, q, `
+        SELECT w.user_id, w.timezone, w.start_minute, w.end_minute, w.weekdays
+        FROM user_working_hours w
+        JOIN users u ON u.user_id = w.user_id
+        WHERE u.team_name = $1
+    `, teamName)
+}
+
+func queryWorkingHours(ctx context.Context, q querier, query string, args ...any) (map[string]domain.WorkingHours, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hours := make(map[string]domain.WorkingHours)
+	for rows.Next() {
+		var (
+			w                domain.WorkingHours
+			start, end, days int16
+		)
+		if err := rows.Scan(&w.UserID, &w.Timezone, &start, &end, &days); err != nil {
+			return nil, err
+		}
+		w.Start, w.End, w.Days = int(start), int(end), domain.Weekdays(days)
+		hours[w.UserID] = w
+	}
+	return hours, rows.Err()
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pingChecker struct {
+	pool *pgxpool.Pool
+}
+
+func NewPingChecker(pool *pgxpool.Pool) *pingChecker {
+	return &pingChecker{pool: pool}
+}
+
+func (c *pingChecker) Name() string {
+	return "postgres"
+}
+
+func (c *pingChecker) Check(ctx context.Context) (map[string]any, error) {
+	stat := c.pool.Stat()
+	details := map[string]any{
+		"total_conns":    stat.TotalConns(),
+		"idle_conns":     stat.IdleConns(),
+		"acquired_conns": stat.AcquiredConns(),
+	}
+
+	if err := c.pool.Ping(ctx); err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+type schemaChecker struct {
+	pool     *pgxpool.Pool
+	expected int64
+}
+
+func NewSchemaChecker(pool *pgxpool.Pool, expected int64) *schemaChecker {
+	return &schemaChecker{pool: pool, expected: expected}
+}
+
+func (c *schemaChecker) Name() string {
+	return "schema"
+}
+
+func (c *schemaChecker) Check(ctx context.Context) (map[string]any, error) {
+	details := map[string]any{"expected": c.expected}
+
+	current, err := CurrentSchemaVersion(ctx, c.pool)
+	if err != nil {
+		return details, err
+	}
+	details["current"] = current
+
+	// более новая схема допустима: миграции накатываются раньше выкатки сервиса
+	if current < c.expected {
+		return details, fmt.Errorf("%w: current %d, expected %d", ErrSchemaOutdated, current, c.expected)
+	}
+	return details, nil
+}
+
+// CurrentSchemaVersion возвращает последнюю примененную миграцию из таблицы goose.
+// Для каждой версии берется последняя запись: откат миграции пишет строку с is_applied = false
+func CurrentSchemaVersion(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	const op = "repository.postgres.CurrentSchemaVersion"
+
+	query := `
+        SELECT COALESCE(MAX(version_id), 0)
+        FROM (
+            SELECT DISTINCT ON (version_id) version_id, is_applied
+            FROM goose_db_version
+            ORDER BY version_id, id DESC
+        ) v
+        WHERE is_applied
+    `
+
+	var version int64
+	if err := pool.QueryRow(ctx, query).Scan(&version); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"time"
+)
+
+type idempotencyRepositoryPostgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepositoryPostgres(pool *pgxpool.Pool) *idempotencyRepositoryPostgres {
+	return &idempotencyRepositoryPostgres{pool: pool}
+}
+
+// Reserve сохраняет ключ как "в обработке" и возвращает nil.
+// Если живой (не истекший на key.CreatedAt) ключ уже есть, возвращает его запись, ничего не меняя
+func (r *idempotencyRepositoryPostgres) Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	const op = "repository.postgres.idempotency.Reserve"
+
+	// истекший ключ перезаписывается, как будто его не было
+	queryReserve := `
+        INSERT INTO idempotency_keys (idempotency_key, fingerprint, created_at, expires_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (idempotency_key) DO UPDATE
+        SET fingerprint = EXCLUDED.fingerprint,
+            status_code = NULL,
+            content_type = NULL,
+            etag = NULL,
+            response_body = NULL,
+            created_at = EXCLUDED.created_at,
+            expires_at = EXCLUDED.expires_at
+        WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+        RETURNING idempotency_key
+    `
+
+	queryGet := `
+        SELECT idempotency_key, fingerprint, status_code, content_type, etag, response_body, created_at, expires_at
+        FROM idempotency_keys
+        WHERE idempotency_key = $1
+    `
+
+	// между INSERT и SELECT ключ могут освободить (Release), тогда пробуем занять его еще раз
+	for attempt := 0; attempt < 3; attempt++ {
+		var reserved string
+		err := r.pool.QueryRow(ctx, queryReserve, key.Key, key.Fingerprint, key.CreatedAt, key.ExpiresAt).Scan(&reserved)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.Internal(op, err)
+		}
+
+		var (
+			existing    domain.IdempotencyKey
+			statusCode  *int
+			contentType *string
+			etag        *string
+			body        []byte
+		)
+		err = r.pool.QueryRow(ctx, queryGet, key.Key).Scan(
+			&existing.Key, &existing.Fingerprint, &statusCode, &contentType, &etag, &body, &existing.CreatedAt, &existing.ExpiresAt,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+
+		if statusCode != nil {
+			existing.Response = &domain.IdempotentResponse{StatusCode: *statusCode, Body: body}
+			if contentType != nil {
+				existing.Response.ContentType = *contentType
+			}
+			if etag != nil {
+				existing.Response.ETag = *etag
+			}
+		}
+		return &existing, nil
+	}
+
+	return nil, repository.Internal(op, errors.New("idempotency key is reserved and released concurrently"))
+}
+
+func (r *idempotencyRepositoryPostgres) Complete(ctx context.Context, key string, resp domain.IdempotentResponse) error {
+	const op = "repository.postgres.idempotency.Complete"
+
+	query := `
+        UPDATE idempotency_keys
+        SET status_code = $2, content_type = $3, etag = $4, response_body = $5
+        WHERE idempotency_key = $1
+    `
+	if _, err := r.pool.Exec(ctx, query, key, resp.StatusCode, resp.ContentType, resp.ETag, resp.Body); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+// Release удаляет ключ, по которому не удалось получить ответ: повтор выполнится заново
+func (r *idempotencyRepositoryPostgres) Release(ctx context.Context, key string) error {
+	const op = "repository.postgres.idempotency.Release"
+
+	if _, err := r.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE idempotency_key = $1`, key); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepositoryPostgres) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "repository.postgres.idempotency.DeleteExpired"
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, repository.Internal(op, err)
+	}
+	return tag.RowsAffected(), nil
+}
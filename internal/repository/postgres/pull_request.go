@@ -3,10 +3,10 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"math/rand"
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/errors/repository"
 	"time"
@@ -14,65 +14,63 @@ import (
 
 const MAX_REVIEWERS = 2
 
+// userTxReader и teamTxReader — то, что репозиторию PR нужно от соседних репозиториев внутри своей транзакции
+type userTxReader interface {
+	GetByIDTx(ctx context.Context, tx pgx.Tx, userID string) (*domain.User, error)
+}
+
+type teamTxReader interface {
+	GetTeamWithMembersTx(ctx context.Context, tx pgx.Tx, teamName string) (*domain.TeamWithUsers, error)
+}
+
+// querier общее у pgx.Tx и *pgxpool.Pool: чтение PR и внутри транзакции, и без нее
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 type pullRequestRepositoryPostgres struct {
 	pool     *pgxpool.Pool
-	teamRepo *teamRepositoryPostgres
-	userRepo *userRepositoryPostgres
+	teamRepo teamTxReader
+	userRepo userTxReader
+	// maxOpenReviews сколько открытых PR может ревьюить один пользователь, 0 — без ограничения
+	maxOpenReviews int
 }
 
-func NewPullRequestRepositoryPostgres(pool *pgxpool.Pool, teamRepo *teamRepositoryPostgres, userRepo *userRepositoryPostgres) *pullRequestRepositoryPostgres {
+func NewPullRequestRepositoryPostgres(pool *pgxpool.Pool, teamRepo teamTxReader, userRepo userTxReader, maxOpenReviews int) *pullRequestRepositoryPostgres {
 	return &pullRequestRepositoryPostgres{
-		pool:     pool,
-		teamRepo: teamRepo,
-		userRepo: userRepo,
+		pool:           pool,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		maxOpenReviews: maxOpenReviews,
 	}
 }
 
-func (r *pullRequestRepositoryPostgres) CreateWithReviewers(ctx context.Context, pr domain.PullRequest) (*domain.PullRequestWithReviewers, error) {
+// CreateWithReviewers seed — зерно генератора выбора ревьюеров, сохраняется вместе с объяснением выбора
+func (r *pullRequestRepositoryPostgres) CreateWithReviewers(ctx context.Context, pr domain.PullRequest, seed int64) (*domain.PullRequestWithReviewers, error) {
+	const op = "repository.postgres.pullRequest.CreateWithReviewers"
+
 	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-	}()
+	// после Commit откат ничего не делает, а на ранних return (PR_MERGED, NOT_ASSIGNED, ...) err == nil,
+	// поэтому откатываем безусловно, иначе транзакция и соединение остаются висеть
+	defer tx.Rollback(ctx)
 
-	author, err := r.userRepo.GetByIDTx(ctx, tx, pr.AuthorID)
+	pool, _, err := r.createPool(ctx, tx, &pr)
 	if err != nil {
-		// ошибка не оборачивается, так как ошибка оборачивается на предыдущем уровне
 		return nil, err
 	}
 
-	teamWithMembers, err := r.teamRepo.GetTeamWithMembersTx(ctx, tx, author.TeamName)
-	if err != nil {
-		return nil, err
-	}
-
-	// Случайный выбор ревьюеров
-	activeMembers := make([]string, 0, len(teamWithMembers.Members))
-	for _, u := range teamWithMembers.Members {
-		if u.ID != pr.AuthorID && u.IsActive {
-			activeMembers = append(activeMembers, u.ID)
-		}
-	}
-
-	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
-	rand.Shuffle(len(activeMembers), func(i, j int) {
-		activeMembers[i], activeMembers[j] = activeMembers[j], activeMembers[i]
-	})
-
-	if len(activeMembers) > MAX_REVIEWERS {
-		activeMembers = activeMembers[:MAX_REVIEWERS]
-	}
+	activeMembers, explained := pool.Explain(MAX_REVIEWERS, domain.NewRand(seed))
 
 	queryCreatePR := `
-        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status)
-        VALUES ($1, $2, $3, 'OPEN')
+        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, required_tags)
+        VALUES ($1, $2, $3, 'OPEN', $4, $5)
     `
 
-	_, err = tx.Exec(ctx, queryCreatePR, pr.ID, pr.Name, pr.AuthorID)
+	_, err = tx.Exec(ctx, queryCreatePR, pr.ID, pr.Name, pr.AuthorID, pr.CreatedAt, tagsArg(pr.RequiredTags))
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -81,7 +79,12 @@ func (r *pullRequestRepositoryPostgres) CreateWithReviewers(ctx context.Context,
 				return nil, repository.ErrPullRequestExists
 			}
 		}
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
+	}
+
+	// после вставки: для существующего PR ответ PR_EXISTS, а не ошибка правил. Транзакция откатится
+	if len(pool.Unmet(activeMembers)) > 0 {
+		return nil, repository.ErrReviewerRulesUnmet
 	}
 
 	querySetReviewers := `
@@ -90,91 +93,373 @@ func (r *pullRequestRepositoryPostgres) CreateWithReviewers(ctx context.Context,
         `
 
 	for _, reviewerID := range activeMembers {
-		_, err := tx.Exec(ctx, querySetReviewers, pr.ID, reviewerID)
+		_, err = tx.Exec(ctx, querySetReviewers, pr.ID, reviewerID)
 		if err != nil {
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
 
+	assignment := domain.Assignment{
+		PullRequestID: pr.ID,
+		Kind:          domain.AssignmentCreate,
+		Seed:          seed,
+		Strategy:      pool.Strategy(),
+		At:            pr.CreatedAt,
+		Candidates:    explained,
+	}
+	if err = insertAssignment(ctx, tx, assignment); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
 
+	pr.Status = domain.PRStatusOpen
+	pr.Version = 1
+
 	return &domain.PullRequestWithReviewers{
 		PullRequest:       pr,
 		AssignedReviewers: activeMembers,
 	}, nil
 }
 
-func (r *pullRequestRepositoryPostgres) Merge(ctx context.Context, prID string) (*domain.PullRequestWithReviewers, error) {
-	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+// PreviewReviewers выбор, который сделал бы CreateWithReviewers с тем же seed, без записи.
+// Невыполнимые правила команды не ошибка, как при создании, а список UnmetRules
+func (r *pullRequestRepositoryPostgres) PreviewReviewers(ctx context.Context, pr domain.PullRequest, seed int64) (*domain.ReviewerPreview, error) {
+	const op = "repository.postgres.pullRequest.PreviewReviewers"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-	}()
+	// только чтение: транзакция нужна, чтобы участники, отсутствия и история пар были согласованы
+	defer tx.Rollback(ctx)
 
-	existing, err := r.getPRWithReviewersTx(ctx, tx, prID)
+	pool, excluded, err := r.createPool(ctx, tx, &pr)
 	if err != nil {
 		return nil, err
 	}
 
+	reviewers, explained := pool.Explain(MAX_REVIEWERS, domain.NewRand(seed))
+
+	return &domain.ReviewerPreview{
+		AuthorID:     pr.AuthorID,
+		RequiredTags: pr.RequiredTags,
+		Reviewers:    reviewers,
+		Seed:         seed,
+		Strategy:     pool.Strategy(),
+		At:           pr.CreatedAt,
+		Candidates:   explained,
+		Excluded:     excluded,
+		UnmetRules:   pool.Unmet(reviewers),
+	}, nil
+}
+
+// createPool кандидаты в ревьюеры нового PR и исключенные участники команды автора, в транзакции tx.
+// Заполняет у pr момент создания и нормализует теги
+func (r *pullRequestRepositoryPostgres) createPool(ctx context.Context, tx pgx.Tx, pr *domain.PullRequest) (domain.ReviewerPool, []domain.CandidateExclusion, error) {
+	const op = "repository.postgres.pullRequest.createPool"
+
+	author, err := r.userRepo.GetByIDTx(ctx, tx, pr.AuthorID)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+
+	teamWithMembers, err := r.teamRepo.GetTeamWithMembersTx(ctx, tx, author.TeamName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+
+	// момент создания задает сервис: по нему считаются отсутствия и рабочее время
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now()
+	}
+
+	hours, err := workingHoursInTeam(ctx, tx, author.TeamName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	// дни недели периодов отсутствия считаются в часовом поясе расписания пользователя
+	absent, err := absentInTeam(ctx, tx, author.TeamName, hours, pr.CreatedAt)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	atCapacity, err := atCapacityInTeam(ctx, tx, author.TeamName, r.maxOpenReviews)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	rules, err := reviewerRules(ctx, tx, author.TeamName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	recent, err := recentPairings(ctx, tx, author.TeamName, pr.AuthorID, pr.CreatedAt)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+
+	// Случайный выбор ревьюеров среди активных и не отсутствующих сейчас, в первую очередь — покрывающих теги PR
+	// и у кого рабочее время
+	pr.RequiredTags = domain.NormalizeTags(pr.RequiredTags)
+	pool := domain.ReviewerPool{
+		Hours:    hours,
+		Now:      pr.CreatedAt,
+		Skills:   domain.SkillsOf(teamWithMembers.Members),
+		Required: pr.RequiredTags,
+		Levels:   domain.LevelsOf(teamWithMembers.Members),
+		Rules:    rules,
+		Recent:   recent,
+	}
+	var excluded []domain.CandidateExclusion
+	pool.Candidates, excluded = domain.SplitCandidates(teamWithMembers.Members, pr.AuthorID, atCapacity, absent)
+	return pool, excluded, nil
+}
+
+// atCapacityInTeam участники команды, у которых открытых ревью не меньше limit. limit <= 0 — nil, без запроса
+func atCapacityInTeam(ctx context.Context, q querier, teamName string, limit int) (map[string]bool, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	query := `
+        SELECT r.user_id, COUNT(*)
+        FROM pr_reviewers r
+        JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+        JOIN users u ON u.user_id = r.user_id
+        WHERE u.team_name = $1 AND p.status = 'OPEN'
+        GROUP BY r.user_id
+    `
+	rows, err := q.Query(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	open := make(map[string]int)
+	for rows.Next() {
+		var (
+			userID string
+			n      int
+		)
+		if err := rows.Scan(&userID, &n); err != nil {
+			return nil, err
+		}
+		open[userID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.AtCapacity(open, limit), nil
+}
+
+func (r *pullRequestRepositoryPostgres) GetByID(ctx context.Context, prID string) (*domain.PullRequestWithReviewers, error) {
+	return r.getPRWithReviewers(ctx, r.pool, prID, false)
+}
+
+// GetByIDs PR с ревьюерами по списку id двумя запросами (для батчевой загрузки в GraphQL).
+// Несуществующие id пропускаются
+func (r *pullRequestRepositoryPostgres) GetByIDs(ctx context.Context, prIDs []string) ([]domain.PullRequestWithReviewers, error) {
+	const op = "repository.postgres.pullRequest.GetByIDs"
+
+	queryGetPRs := `
+        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags
+        FROM pull_requests
+        WHERE pull_request_id = ANY($1)
+        ORDER BY pull_request_id
+    `
+
+	rows, err := r.pool.Query(ctx, queryGetPRs, prIDs)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	prs := []domain.PullRequestWithReviewers{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.Version, &pr.RequiredTags); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		pr.RequiredTags = scannedTags(pr.RequiredTags)
+		index[pr.ID] = len(prs)
+		prs = append(prs, domain.PullRequestWithReviewers{PullRequest: pr, AssignedReviewers: []string{}})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rows.Close()
+
+	queryReviewers := `
+        SELECT pull_request_id, user_id
+        FROM pr_reviewers
+        WHERE pull_request_id = ANY($1)
+    `
+
+	reviewerRows, err := r.pool.Query(ctx, queryReviewers, prIDs)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer reviewerRows.Close()
+
+	for reviewerRows.Next() {
+		var prID, userID string
+		if err := reviewerRows.Scan(&prID, &userID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		if i, ok := index[prID]; ok {
+			prs[i].AssignedReviewers = append(prs[i].AssignedReviewers, userID)
+		}
+	}
+	if err := reviewerRows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return prs, nil
+}
+
+// Merge помечает PR как MERGED. expectedVersion — версия из If-Match, 0 — без проверки.
+// merged false, если PR уже был MERGED и ничего не изменилось (повторный merge)
+func (r *pullRequestRepositoryPostgres) Merge(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequestWithReviewers, bool, error) {
+	const op = "repository.postgres.pullRequest.Merge"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	existing, err := r.getPRWithReviewers(ctx, tx, prID, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !versionMatches(existing.Version, expectedVersion) {
+		return nil, false, repository.ErrVersionMismatch
+	}
+
+	if existing.Status == domain.PRStatusClosed {
+		return nil, false, repository.ErrPullRequestClosed
+	}
+
 	// проверка для идемпотентности
 	if existing.Status == domain.PRStatusMerged {
 		if err = tx.Commit(ctx); err != nil {
-			return nil, repository.ErrInternalError
+			return nil, false, repository.Internal(op, err)
 		}
-		return existing, nil
+		return existing, false, nil
 	}
 
 	queryMerge := `
         UPDATE pull_requests
-        SET status = 'MERGED', merged_at = NOW()
+        SET status = 'MERGED', merged_at = NOW(), version = version + 1
         WHERE pull_request_id = $1
     `
 	_, err = tx.Exec(ctx, queryMerge, prID)
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, false, repository.Internal(op, err)
 	}
 
-	updated, err := r.getPRWithReviewersTx(ctx, tx, prID)
+	updated, err := r.getPRWithReviewers(ctx, tx, prID, false)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return nil, repository.ErrInternalError
+		return nil, false, repository.Internal(op, err)
 	}
 
-	return updated, nil
+	return updated, true, nil
 }
 
-func (r *pullRequestRepositoryPostgres) getPRWithReviewersTx(ctx context.Context, tx pgx.Tx, prID string) (*domain.PullRequestWithReviewers, error) {
+// Close помечает PR как CLOSED. expectedVersion — версия из If-Match, 0 — без проверки.
+// closed false, если PR уже был CLOSED и ничего не изменилось (повторный close)
+func (r *pullRequestRepositoryPostgres) Close(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequestWithReviewers, bool, error) {
+	const op = "repository.postgres.pullRequest.Close"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	existing, err := r.getPRWithReviewers(ctx, tx, prID, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !versionMatches(existing.Version, expectedVersion) {
+		return nil, false, repository.ErrVersionMismatch
+	}
+
+	if existing.Status == domain.PRStatusMerged {
+		return nil, false, repository.ErrPullRequestMerged
+	}
+
+	// проверка для идемпотентности
+	if existing.Status == domain.PRStatusClosed {
+		if err = tx.Commit(ctx); err != nil {
+			return nil, false, repository.Internal(op, err)
+		}
+		return existing, false, nil
+	}
+
+	queryClose := `
+        UPDATE pull_requests
+        SET status = 'CLOSED', version = version + 1
+        WHERE pull_request_id = $1
+    `
+	_, err = tx.Exec(ctx, queryClose, prID)
+	if err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+
+	updated, err := r.getPRWithReviewers(ctx, tx, prID, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+
+	return updated, true, nil
+}
+
+// getPRWithReviewers читает PR с ревьюерами. forUpdate блокирует строку PR до конца транзакции:
+// конкурирующие merge/close/reassign того же PR ждут, а не читают устаревшее состояние
+func (r *pullRequestRepositoryPostgres) getPRWithReviewers(ctx context.Context, q querier, prID string, forUpdate bool) (*domain.PullRequestWithReviewers, error) {
+	const op = "repository.postgres.pullRequest.getPRWithReviewers"
+
 	queryGetPR := `
-        SELECT pull_request_id, pull_request_name, author_id, status, merged_at
+        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags
         FROM pull_requests
         WHERE pull_request_id = $1
     `
+	if forUpdate {
+		queryGetPR += ` FOR UPDATE`
+	}
 	var pr domain.PullRequest
 
-	err := tx.QueryRow(ctx, queryGetPR, prID).Scan(
+	err := q.QueryRow(ctx, queryGetPR, prID).Scan(
 		&pr.ID,
 		&pr.Name,
 		&pr.AuthorID,
 		&pr.Status,
+		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.Version,
+		&pr.RequiredTags,
 	)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrPullRequestNotFound
 		default:
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
+	pr.RequiredTags = scannedTags(pr.RequiredTags)
 
 	queryReviewers := `
         SELECT user_id
@@ -182,9 +467,9 @@ func (r *pullRequestRepositoryPostgres) getPRWithReviewersTx(ctx context.Context
         WHERE pull_request_id = $1
     `
 
-	rows, err := tx.Query(ctx, queryReviewers, prID)
+	rows, err := q.Query(ctx, queryReviewers, prID)
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
 	defer rows.Close()
 
@@ -192,7 +477,7 @@ func (r *pullRequestRepositoryPostgres) getPRWithReviewersTx(ctx context.Context
 	for rows.Next() {
 		var uid string
 		if err := rows.Scan(&uid); err != nil {
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 		reviewers = append(reviewers, uid)
 	}
@@ -203,26 +488,33 @@ func (r *pullRequestRepositoryPostgres) getPRWithReviewersTx(ctx context.Context
 	}, nil
 }
 
-func (r *pullRequestRepositoryPostgres) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*domain.Reviewer, error) {
+// ReassignReviewer заменяет ревьюера. expectedVersion — версия из If-Match, 0 — без проверки; now — момент замены;
+// seed — зерно генератора выбора, сохраняется вместе с объяснением выбора
+func (r *pullRequestRepositoryPostgres) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, expectedVersion int64, now time.Time, seed int64) (*domain.Reviewer, error) {
+	const op = "repository.postgres.pullRequest.ReassignReviewer"
+
 	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-	}()
+	defer tx.Rollback(ctx)
 
-	pr, err := r.getPRWithReviewersTx(ctx, tx, prID)
+	pr, err := r.getPRWithReviewers(ctx, tx, prID, true)
 	if err != nil {
 		return nil, err
 	}
 
-	// проверка на MERGED
+	if !versionMatches(pr.Version, expectedVersion) {
+		return nil, repository.ErrVersionMismatch
+	}
+
+	// проверка на MERGED и CLOSED
 	if pr.Status == domain.PRStatusMerged {
 		return nil, repository.ErrPullRequestMerged
 	}
+	if pr.Status == domain.PRStatusClosed {
+		return nil, repository.ErrPullRequestClosed
+	}
 
 	// проверка есть ли вообще такой пользователь
 	_, err = r.userRepo.GetByIDTx(ctx, tx, oldReviewerID)
@@ -252,20 +544,71 @@ func (r *pullRequestRepositoryPostgres) ReassignReviewer(ctx context.Context, pr
 		return nil, err
 	}
 
-	candidates := []string{}
+	// уже назначенные ревьюеры не могут стать заменой: (pull_request_id, user_id) — первичный ключ pr_reviewers
+	assigned := make(map[string]struct{}, len(pr.AssignedReviewers))
+	for _, uid := range pr.AssignedReviewers {
+		assigned[uid] = struct{}{}
+	}
+
+	hours, err := workingHoursInTeam(ctx, tx, oldUser.TeamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	// дни недели периодов отсутствия считаются в часовом поясе расписания пользователя
+	absent, err := absentInTeam(ctx, tx, oldUser.TeamName, hours, now)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	atCapacity, err := atCapacityInTeam(ctx, tx, oldUser.TeamName, r.maxOpenReviews)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rules, err := reviewerRules(ctx, tx, oldUser.TeamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	recent, err := recentPairings(ctx, tx, oldUser.TeamName, pr.AuthorID, now)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	// новый ревьюер должен покрыть теги и правила уровней, которые держались только на заменяемом
+	var remaining []string
+	for _, uid := range pr.AssignedReviewers {
+		if uid != oldReviewerID {
+			remaining = append(remaining, uid)
+		}
+	}
+	skills := domain.SkillsOf(team.Members)
+	levels := domain.LevelsOf(team.Members)
+	pool := domain.ReviewerPool{
+		Hours:    hours,
+		Now:      now,
+		Skills:   skills,
+		Required: domain.UncoveredTags(pr.RequiredTags, skills, remaining),
+		Levels:   levels,
+		Rules:    domain.UnmetRules(rules, levels, remaining),
+		Recent:   recent,
+	}
 	for _, u := range team.Members {
-		if u.ID != oldReviewerID && u.ID != pr.AuthorID && u.IsActive {
-			candidates = append(candidates, u.ID)
+		if _, ok := assigned[u.ID]; ok {
+			continue
+		}
+		if u.ID != pr.AuthorID && u.IsActive && !absent[u.ID] && !atCapacity[u.ID] {
+			pool.Candidates = append(pool.Candidates, u.ID)
 		}
 	}
 
 	// проверка на наличие кандидата
-	if len(candidates) == 0 {
+	if len(pool.Candidates) == 0 {
 		return nil, repository.ErrNoReplacementCandidate
 	}
 
-	randGen := rand.New(rand.NewSource(time.Now().UnixNano()))
-	newReviewer := candidates[randGen.Intn(len(candidates))]
+	picked, explained := pool.Explain(1, domain.NewRand(seed))
+	newReviewer := picked[0]
+	if len(pool.Unmet([]string{newReviewer})) > 0 {
+		return nil, repository.ErrReviewerRulesUnmet
+	}
 
 	queryUpdate := `
         UPDATE pr_reviewers
@@ -274,14 +617,224 @@ func (r *pullRequestRepositoryPostgres) ReassignReviewer(ctx context.Context, pr
     `
 	_, err = tx.Exec(ctx, queryUpdate, newReviewer, prID, oldReviewerID)
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
+	}
+
+	var version int64
+	queryBumpVersion := `
+        UPDATE pull_requests
+        SET version = version + 1
+        WHERE pull_request_id = $1
+        RETURNING version
+    `
+	if err = tx.QueryRow(ctx, queryBumpVersion, prID).Scan(&version); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	assignment := domain.Assignment{
+		PullRequestID:  prID,
+		Kind:           domain.AssignmentReassign,
+		ReplacedUserID: oldReviewerID,
+		Seed:           seed,
+		Strategy:       pool.Strategy(),
+		At:             now,
+		Candidates:     explained,
+	}
+	if err = insertAssignment(ctx, tx, assignment); err != nil {
+		return nil, repository.Internal(op, err)
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
 
 	return &domain.Reviewer{
-		ID: newReviewer,
+		ID:                 newReviewer,
+		PullRequestVersion: version,
 	}, nil
 }
+
+// versionMatches expected == 0 означает, что клиент не прислал If-Match
+func versionMatches(current, expected int64) bool {
+	return expected == 0 || current == expected
+}
+
+// exportFetchSize сколько строк курсора выгрузки читается за один FETCH
+const exportFetchSize = 500
+
+// Export читает PR по фильтру серверным курсором и отдает их по одному в fn, в порядке created_at.
+// В памяти держится не больше exportFetchSize строк. Транзакция только на чтение с REPEATABLE READ,
+// поэтому выгрузка — согласованный снимок. Ошибка fn (например, клиент отключился) возвращается как есть
+func (r *pullRequestRepositoryPostgres) Export(ctx context.Context, filter domain.ExportFilter, fn func(domain.PullRequestExport) error) error {
+	const op = "repository.postgres.pullRequest.Export"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return repository.Internal(op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if filter.TeamName != "" {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM teams WHERE team_name = $1)`, filter.TeamName).Scan(&exists); err != nil {
+			return repository.Internal(op, err)
+		}
+		if !exists {
+			return repository.ErrTeamNotFound
+		}
+	}
+
+	queryDeclare := `
+        DECLARE export_cursor NO SCROLL CURSOR FOR
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.version,
+               COALESCE(u.team_name, ''),
+               COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.user_id IS NOT NULL), '{}')
+        FROM pull_requests pr
+        LEFT JOIN users u ON u.user_id = pr.author_id
+        LEFT JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+        WHERE ($1::text = '' OR u.team_name = $1)
+          AND ($2::timestamptz IS NULL OR pr.created_at >= $2)
+          AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+        GROUP BY pr.pull_request_id, u.team_name
+        ORDER BY pr.created_at, pr.pull_request_id
+    `
+	if _, err := tx.Exec(ctx, queryDeclare, filter.TeamName, filter.From, filter.To); err != nil {
+		return repository.Internal(op, err)
+	}
+
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf(`FETCH %d FROM export_cursor`, exportFetchSize))
+		if err != nil {
+			return repository.Internal(op, err)
+		}
+
+		batch := make([]domain.PullRequestExport, 0, exportFetchSize)
+		for rows.Next() {
+			var pr domain.PullRequestExport
+			if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.Version,
+				&pr.TeamName, &pr.AssignedReviewers); err != nil {
+				rows.Close()
+				return repository.Internal(op, err)
+			}
+			batch = append(batch, pr)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return repository.Internal(op, err)
+		}
+
+		// fn вызывается после чтения пачки: пока открыт rows, соединение занято и следующий FETCH не выполнить
+		for _, pr := range batch {
+			if err := fn(pr); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportFetchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+// GetAssignments выборы ревьюеров PR в порядке записи, кандидаты — по rank
+func (r *pullRequestRepositoryPostgres) GetAssignments(ctx context.Context, prID string) ([]domain.Assignment, error) {
+	const op = "repository.postgres.pullRequest.GetAssignments"
+
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`, prID).Scan(&exists); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	if !exists {
+		return nil, repository.ErrPullRequestNotFound
+	}
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT assignment_id, kind, COALESCE(replaced_user_id, ''), seed, strategy, assigned_at
+        FROM reviewer_assignments
+        WHERE pull_request_id = $1
+        ORDER BY assignment_id
+    `, prID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	assignments := []domain.Assignment{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		a := domain.Assignment{PullRequestID: prID}
+		if err := rows.Scan(&a.ID, &a.Kind, &a.ReplacedUserID, &a.Seed, &a.Strategy, &a.At); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		index[a.ID] = len(assignments)
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rows.Close()
+
+	candidateRows, err := r.pool.Query(ctx, `
+        SELECT c.assignment_id, c.user_id, c.rank, c.working, c.pairing_score, c.level, c.tags, c.chosen, c.reason
+        FROM reviewer_assignment_candidates c
+        JOIN reviewer_assignments a ON a.assignment_id = c.assignment_id
+        WHERE a.pull_request_id = $1
+        ORDER BY c.assignment_id, c.rank
+    `, prID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer candidateRows.Close()
+
+	for candidateRows.Next() {
+		var (
+			id int64
+			c  domain.CandidateExplanation
+		)
+		if err := candidateRows.Scan(&id, &c.UserID, &c.Rank, &c.Working, &c.PairingScore, &c.Level, &c.Tags, &c.Chosen, &c.Reason); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		c.Tags = scannedTags(c.Tags)
+		if i, ok := index[id]; ok {
+			assignments[i].Candidates = append(assignments[i].Candidates, c)
+		}
+	}
+	if err := candidateRows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return assignments, nil
+}
+
+// insertAssignment сохраняет выбор ревьюеров с кандидатами в транзакции назначения
+func insertAssignment(ctx context.Context, tx pgx.Tx, a domain.Assignment) error {
+	var replaced *string
+	if a.ReplacedUserID != "" {
+		replaced = &a.ReplacedUserID
+	}
+
+	var id int64
+	err := tx.QueryRow(ctx, `
+        INSERT INTO reviewer_assignments (pull_request_id, kind, replaced_user_id, seed, strategy, assigned_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING assignment_id
+    `, a.PullRequestID, string(a.Kind), replaced, a.Seed, string(a.Strategy), a.At).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range a.Candidates {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO reviewer_assignment_candidates (assignment_id, user_id, rank, working, pairing_score, level, tags, chosen, reason)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        `, id, c.UserID, c.Rank, c.Working, c.PairingScore, string(c.Level), tagsArg(c.Tags), c.Chosen, string(c.Reason))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
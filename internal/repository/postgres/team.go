@@ -8,14 +8,22 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/errors/repository"
+	"time"
 )
 
+// teamMembersRepository — то, что репозиторию команд нужно от репозитория пользователей
+type teamMembersRepository interface {
+	UpsertManyTx(ctx context.Context, tx pgx.Tx, users []domain.User) error
+	GetByIDsTx(ctx context.Context, tx pgx.Tx, userIDs []string) ([]domain.User, error)
+	GetByTeamName(ctx context.Context, teamName string) ([]domain.User, error)
+}
+
 type teamRepositoryPostgres struct {
-	userRepo *userRepositoryPostgres
+	userRepo teamMembersRepository
 	pool     *pgxpool.Pool
 }
 
-func NewTeamRepositoryPostgres(pool *pgxpool.Pool, userRepo *userRepositoryPostgres) *teamRepositoryPostgres {
+func NewTeamRepositoryPostgres(pool *pgxpool.Pool, userRepo teamMembersRepository) *teamRepositoryPostgres {
 	return &teamRepositoryPostgres{
 		userRepo: userRepo,
 		pool:     pool,
@@ -23,9 +31,11 @@ func NewTeamRepositoryPostgres(pool *pgxpool.Pool, userRepo *userRepositoryPostg
 }
 
 func (r *teamRepositoryPostgres) AddTeamWithMembers(ctx context.Context, team domain.Team, members []domain.User) error {
+	const op = "repository.postgres.team.AddTeamWithMembers"
+
 	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return err
+		return repository.Internal(op, err)
 	}
 
 	if err = r.InsertTx(ctx, tx, team); err != nil {
@@ -38,7 +48,10 @@ func (r *teamRepositoryPostgres) AddTeamWithMembers(ctx context.Context, team do
 		return err
 	}
 
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
 }
 
 func (r *teamRepositoryPostgres) GetTeamWithMembers(ctx context.Context, teamName string) (*domain.TeamWithUsers, error) {
@@ -60,6 +73,8 @@ func (r *teamRepositoryPostgres) GetTeamWithMembers(ctx context.Context, teamNam
 
 // ручка проверяет существование команды
 func (r *teamRepositoryPostgres) GetTeamWithMembersTx(ctx context.Context, tx pgx.Tx, teamName string) (*domain.TeamWithUsers, error) {
+	const op = "repository.postgres.team.GetTeamWithMembersTx"
+
 	var team domain.Team
 	err := tx.QueryRow(ctx, `SELECT team_name FROM teams WHERE team_name=$1`, teamName).Scan(&team.Name)
 	if err != nil {
@@ -67,27 +82,32 @@ func (r *teamRepositoryPostgres) GetTeamWithMembersTx(ctx context.Context, tx pg
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrTeamNotFound
 		default:
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
 
 	rows, err := tx.Query(ctx,
-		`SELECT user_id, username, team_name, is_active FROM users WHERE team_name=$1`,
+		// порядок по user_id, как в памяти: в нем кандидаты перемешиваются, и от него зависит, повторит ли seed выбор
+		`SELECT user_id, username, team_name, is_active, skills, level FROM users WHERE team_name=$1 ORDER BY user_id`,
 		teamName,
 	)
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
 	defer rows.Close()
 
 	var members []domain.User
 	for rows.Next() {
 		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
-			return nil, repository.ErrInternalError
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
 		}
+		u.Skills = scannedTags(u.Skills)
 		members = append(members, u)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
 
 	return &domain.TeamWithUsers{
 		TeamName: team.Name,
@@ -96,6 +116,8 @@ func (r *teamRepositoryPostgres) GetTeamWithMembersTx(ctx context.Context, tx pg
 }
 
 func (r *teamRepositoryPostgres) InsertTx(ctx context.Context, tx pgx.Tx, team domain.Team) error {
+	const op = "repository.postgres.team.InsertTx"
+
 	sql := `
         INSERT INTO teams (team_name)
         VALUES ($1)
@@ -110,13 +132,15 @@ func (r *teamRepositoryPostgres) InsertTx(ctx context.Context, tx pgx.Tx, team d
 			case "23505":
 				return repository.ErrTeamAlreadyExists
 			}
-			return repository.ErrInternalError
 		}
+		return repository.Internal(op, err)
 	}
-	return err
+	return nil
 }
 
 func (r *teamRepositoryPostgres) GetByName(ctx context.Context, name string) (*domain.Team, error) {
+	const op = "repository.postgres.team.GetByName"
+
 	queryGetTeam := `SELECT team_name
 		FROM teams
 		WHERE team_name=$1
@@ -129,7 +153,7 @@ func (r *teamRepositoryPostgres) GetByName(ctx context.Context, name string) (*d
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrTeamNotFound
 		default:
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
 	return &team, nil
@@ -139,6 +163,8 @@ func (r *teamRepositoryPostgres) GetByName(ctx context.Context, name string) (*d
 // количество активных и неактивных пользователей,
 // количество открытых и замерженных PR
 func (r *teamRepositoryPostgres) GetTeamStats(ctx context.Context, teamName string) (activeUsers, inactiveUsers, openPRs, mergedPRs int, err error) {
+	const op = "repository.postgres.team.GetTeamStats"
+
 	sql := `
 	SELECT 
 		COUNT(DISTINCT u.user_id) FILTER (WHERE u.is_active) AS active_users,
@@ -159,9 +185,328 @@ func (r *teamRepositoryPostgres) GetTeamStats(ctx context.Context, teamName stri
 		case errors.Is(err, pgx.ErrNoRows):
 			return 0, 0, 0, 0, repository.ErrTeamNotFound
 		default:
-			return 0, 0, 0, 0, repository.ErrInternalError
+			return 0, 0, 0, 0, repository.Internal(op, err)
 		}
 	}
 
 	return activeUsers, inactiveUsers, openPRs, mergedPRs, nil
 }
+
+// GetTeamsWithMembers команды с участниками одним запросом (для батчевой загрузки в GraphQL).
+// Несуществующие команды пропускаются, участники отсортированы по user_id
+func (r *teamRepositoryPostgres) GetTeamsWithMembers(ctx context.Context, teamNames []string) ([]domain.TeamWithUsers, error) {
+	const op = "repository.postgres.team.GetTeamsWithMembers"
+
+	query := `
+        SELECT t.team_name, u.user_id, u.username, u.is_active, u.skills, u.level
+        FROM teams t
+        LEFT JOIN users u ON u.team_name = t.team_name
+        WHERE t.team_name = ANY($1)
+        ORDER BY t.team_name, u.user_id
+    `
+
+	rows, err := r.pool.Query(ctx, query, teamNames)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	teams := []domain.TeamWithUsers{}
+	for rows.Next() {
+		var (
+			teamName string
+			userID   *string
+			username *string
+			isActive *bool
+			skills   []string
+			level    *string
+		)
+		if err := rows.Scan(&teamName, &userID, &username, &isActive, &skills, &level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+
+		if len(teams) == 0 || teams[len(teams)-1].TeamName != teamName {
+			teams = append(teams, domain.TeamWithUsers{TeamName: teamName, Members: []domain.User{}})
+		}
+		// у команды без участников LEFT JOIN дает одну строку с NULL
+		if userID == nil {
+			continue
+		}
+		last := &teams[len(teams)-1]
+		last.Members = append(last.Members, domain.User{
+			ID:       *userID,
+			Username: *username,
+			TeamName: teamName,
+			IsActive: *isActive,
+			Skills:   scannedTags(skills),
+			Level:    domain.Level(*level),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return teams, nil
+}
+
+// ImportTeams создает недостающие команды и upsert'ит участников одной транзакцией.
+// Пользователи из импорта блокируются до конца транзакции, поэтому отчет совпадает с записанным.
+// При dryRun транзакция откатывается и возвращается только отчет
+func (r *teamRepositoryPostgres) ImportTeams(ctx context.Context, teams []domain.TeamWithUsers, dryRun bool) (*domain.ImportReport, error) {
+	const op = "repository.postgres.team.ImportTeams"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	names := make([]string, len(teams))
+	for i, t := range teams {
+		names[i] = t.TeamName
+	}
+	existingTeams := make(map[string]bool, len(teams))
+	rows, err := tx.Query(ctx, `SELECT team_name FROM teams WHERE team_name = ANY($1)`, names)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, repository.Internal(op, err)
+		}
+		existingTeams[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	users := domain.ImportUsers(teams)
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	existing, err := r.userRepo.GetByIDsTx(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	existingUsers := make(map[string]domain.User, len(existing))
+	for _, u := range existing {
+		existingUsers[u.ID] = u
+	}
+
+	report := domain.PlanImport(teams, existingTeams, existingUsers)
+	if dryRun {
+		return &report, nil
+	}
+
+	// команду могли создать параллельно после чтения выше, это не ошибка импорта
+	for _, name := range report.TeamsCreated {
+		if _, err := tx.Exec(ctx, `INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT DO NOTHING`, name); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	if err := r.userRepo.UpsertManyTx(ctx, tx, users); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return &report, nil
+}
+
+// SetReviewerRules заменяет правила команды к уровням ревьюеров, пустой список их снимает
+func (r *teamRepositoryPostgres) SetReviewerRules(ctx context.Context, teamName string, rules []domain.ReviewerRule) ([]domain.ReviewerRule, error) {
+	const op = "repository.postgres.team.SetReviewerRules"
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокировка строки команды: параллельная замена правил ждет, а не смешивает наборы
+	var name string
+	err = tx.QueryRow(ctx, `SELECT team_name FROM teams WHERE team_name=$1 FOR UPDATE`, teamName).Scan(&name)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrTeamNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM team_reviewer_rules WHERE team_name=$1`, teamName); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rules = domain.NormalizeRules(rules)
+	for _, rule := range rules {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO team_reviewer_rules (team_name, min_level, min_count) VALUES ($1, $2, $3)`,
+			teamName, string(rule.MinLevel), rule.Count,
+		)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return rules, nil
+}
+
+// GetReviewerRules правила команды, от старшего уровня к младшему
+func (r *teamRepositoryPostgres) GetReviewerRules(ctx context.Context, teamName string) ([]domain.ReviewerRule, error) {
+	const op = "repository.postgres.team.GetReviewerRules"
+
+	if _, err := r.GetByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	rules, err := reviewerRules(ctx, r.pool, teamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return rules, nil
+}
+
+func reviewerRules(ctx context.Context, q querier, teamName string) ([]domain.ReviewerRule, error) {
+	rows, err := q.Query(ctx, `SELECT min_level, min_count FROM team_reviewer_rules WHERE team_name=$1`, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.ReviewerRule
+	for rows.Next() {
+		var rule domain.ReviewerRule
+		if err := rows.Scan(&rule.MinLevel, &rule.Count); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.NormalizeRules(rules), nil
+}
+
+// SetPairingSettings задает стратегию выбора ревьюеров команды
+func (r *teamRepositoryPostgres) SetPairingSettings(ctx context.Context, teamName string, settings domain.PairingSettings) (*domain.PairingSettings, error) {
+	const op = "repository.postgres.team.SetPairingSettings"
+
+	query := `
+        INSERT INTO team_pairing_settings (team_name, strategy, decay_days)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (team_name) DO UPDATE
+        SET strategy = EXCLUDED.strategy,
+            decay_days = EXCLUDED.decay_days
+    `
+	_, err := r.pool.Exec(ctx, query, teamName, string(settings.Strategy), settings.DecayDays)
+	if err != nil {
+		// внешний ключ на teams: команды нет
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, repository.ErrTeamNotFound
+		}
+		return nil, repository.Internal(op, err)
+	}
+	return &settings, nil
+}
+
+// GetPairingSettings стратегия команды, без настроек — domain.DefaultPairingSettings
+func (r *teamRepositoryPostgres) GetPairingSettings(ctx context.Context, teamName string) (*domain.PairingSettings, error) {
+	const op = "repository.postgres.team.GetPairingSettings"
+
+	if _, err := r.GetByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	settings, err := pairingSettings(ctx, r.pool, teamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return &settings, nil
+}
+
+// GetPairings назначения ревьюеров к PR авторов команды не раньше since
+func (r *teamRepositoryPostgres) GetPairings(ctx context.Context, teamName string, since time.Time) ([]domain.Pairing, error) {
+	const op = "repository.postgres.team.GetPairings"
+
+	if _, err := r.GetByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	pairings, err := pairingsSince(ctx, r.pool, `u.team_name = $1`, teamName, since)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return pairings, nil
+}
+
+func pairingSettings(ctx context.Context, q querier, teamName string) (domain.PairingSettings, error) {
+	var settings domain.PairingSettings
+	err := q.QueryRow(ctx,
+		`SELECT strategy, decay_days FROM team_pairing_settings WHERE team_name=$1`, teamName,
+	).Scan(&settings.Strategy, &settings.DecayDays)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DefaultPairingSettings(), nil
+	}
+	return settings, err
+}
+
+// pairingsSince пары автор-ревьюер из выборов ревьюеров не раньше since, со временем назначения.
+// Ревьюер, которого потом заменили, остается в истории. У PR без истории выборов (созданных до нее) — ревьюеры
+// из pr_reviewers со временем создания PR. cond — условие на pr или автора u с параметром $1
+func pairingsSince(ctx context.Context, q querier, cond, arg string, since time.Time) ([]domain.Pairing, error) {
+	rows, err := q.Query(ctx, `
+        SELECT pr.author_id, c.user_id, a.assigned_at
+        FROM reviewer_assignments a
+        JOIN reviewer_assignment_candidates c ON c.assignment_id = a.assignment_id AND c.chosen
+        JOIN pull_requests pr ON pr.pull_request_id = a.pull_request_id
+        JOIN users u ON u.user_id = pr.author_id
+        WHERE `+cond+` AND a.assigned_at >= $2
+        UNION ALL
+        SELECT pr.author_id, r.user_id, pr.created_at
+        FROM pull_requests pr
+        JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+        JOIN users u ON u.user_id = pr.author_id
+        WHERE `+cond+` AND pr.created_at >= $2
+          AND NOT EXISTS (SELECT 1 FROM reviewer_assignments a WHERE a.pull_request_id = pr.pull_request_id)
+        ORDER BY 3, 1, 2
+    `, arg, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairings []domain.Pairing
+	for rows.Next() {
+		var p domain.Pairing
+		if err := rows.Scan(&p.AuthorID, &p.ReviewerID, &p.At); err != nil {
+			return nil, err
+		}
+		pairings = append(pairings, p)
+	}
+	return pairings, rows.Err()
+}
+
+// recentPairings вес недавних ревью у автора для выбора ревьюеров в команде teamName.
+// nil, если команда выбирает случайно
+func recentPairings(ctx context.Context, q querier, teamName, authorID string, now time.Time) (map[string]float64, error) {
+	settings, err := pairingSettings(ctx, q, teamName)
+	if err != nil || settings.Strategy != domain.PairingDiversity {
+		return nil, err
+	}
+	pairings, err := pairingsSince(ctx, q, `pr.author_id = $1`, authorID, now.Add(-settings.Window()))
+	if err != nil {
+		return nil, err
+	}
+	return domain.PairingScores(pairings, authorID, now, settings.Window()), nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/errors/repository"
+	"time"
 )
 
 type userRepositoryPostgres struct {
@@ -18,6 +19,8 @@ func NewUserRepositoryPostgres(pool *pgxpool.Pool) *userRepositoryPostgres {
 }
 
 func (r *userRepositoryPostgres) UpsertManyTx(ctx context.Context, tx pgx.Tx, users []domain.User) error {
+	const op = "repository.postgres.user.UpsertManyTx"
+
 	query := `
         INSERT INTO users (user_id, username, team_name, is_active)
         VALUES ($1, $2, $3, $4)
@@ -28,28 +31,31 @@ func (r *userRepositoryPostgres) UpsertManyTx(ctx context.Context, tx pgx.Tx, us
     `
 	for _, u := range users {
 		if _, err := tx.Exec(ctx, query, u.ID, u.Username, u.TeamName, u.IsActive); err != nil {
-			return repository.ErrInternalError
+			return repository.Internal(op, err)
 		}
 	}
 	return nil
 }
 
 func (r *userRepositoryPostgres) GetByTeamName(ctx context.Context, teamName string) ([]domain.User, error) {
+	const op = "repository.postgres.user.GetByTeamName"
+
 	rows, err := r.pool.Query(ctx,
-		`SELECT user_id, username, team_name, is_active FROM users WHERE team_name=$1`,
+		`SELECT user_id, username, team_name, is_active, skills, level FROM users WHERE team_name=$1`,
 		teamName,
 	)
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
 	defer rows.Close()
 
 	var users []domain.User
 	for rows.Next() {
 		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
-			return nil, repository.ErrInternalError
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
 		}
+		u.Skills = scannedTags(u.Skills)
 		users = append(users, u)
 	}
 
@@ -57,103 +63,307 @@ func (r *userRepositoryPostgres) GetByTeamName(ctx context.Context, teamName str
 }
 
 func (r *userRepositoryPostgres) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	const op = "repository.postgres.user.SetIsActive"
+
 	query := `
         UPDATE users
         SET is_active = $1
         WHERE user_id = $2
-        RETURNING user_id, username, team_name, is_active
+        RETURNING user_id, username, team_name, is_active, skills, level
     `
 
 	var u domain.User
 	err := r.pool.QueryRow(ctx, query, isActive, userID).Scan(
-		&u.ID, &u.Username, &u.TeamName, &u.IsActive,
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level,
 	)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrUserNotFound
 		default:
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
 
+	u.Skills = scannedTags(u.Skills)
+	return &u, nil
+}
+
+// SetSkills заменяет теги пользователя
+func (r *userRepositoryPostgres) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	const op = "repository.postgres.user.SetSkills"
+
+	query := `
+        UPDATE users
+        SET skills = $1
+        WHERE user_id = $2
+        RETURNING user_id, username, team_name, is_active, skills, level
+    `
+	return r.updateUser(ctx, op, query, tagsArg(domain.NormalizeTags(skills)), userID)
+}
+
+// UpdateSkills добавляет и убирает теги одним запросом, без гонки с параллельными изменениями
+func (r *userRepositoryPostgres) UpdateSkills(ctx context.Context, userID string, add, remove []string) (*domain.User, error) {
+	const op = "repository.postgres.user.UpdateSkills"
+
+	// COLLATE "C" — порядок байт, как у sort.Strings в domain.NormalizeTags
+	query := `
+        UPDATE users
+        SET skills = ARRAY(
+            SELECT DISTINCT s FROM unnest(skills || $1::text[]) s
+            WHERE s <> ALL($2::text[])
+            ORDER BY s COLLATE "C"
+        )
+        WHERE user_id = $3
+        RETURNING user_id, username, team_name, is_active, skills, level
+    `
+	return r.updateUser(ctx, op, query, tagsArg(add), tagsArg(remove), userID)
+}
+
+// SetLevel задает уровень пользователя
+func (r *userRepositoryPostgres) SetLevel(ctx context.Context, userID string, level domain.Level) (*domain.User, error) {
+	const op = "repository.postgres.user.SetLevel"
+
+	query := `
+        UPDATE users
+        SET level = $1
+        WHERE user_id = $2
+        RETURNING user_id, username, team_name, is_active, skills, level
+    `
+	return r.updateUser(ctx, op, query, string(level), userID)
+}
+
+// updateUser выполняет UPDATE ... RETURNING пользователя
+func (r *userRepositoryPostgres) updateUser(ctx context.Context, op, query string, args ...any) (*domain.User, error) {
+	var u domain.User
+	err := r.pool.QueryRow(ctx, query, args...).Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrUserNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	u.Skills = scannedTags(u.Skills)
 	return &u, nil
 }
 
 func (r *userRepositoryPostgres) GetByIDTx(ctx context.Context, tx pgx.Tx, userID string) (*domain.User, error) {
+	const op = "repository.postgres.user.GetByIDTx"
+
 	query := `
-        SELECT user_id, username, team_name, is_active
+        SELECT user_id, username, team_name, is_active, skills, level
         FROM users
         WHERE user_id = $1
     `
 
 	var u domain.User
 	err := tx.QueryRow(ctx, query, userID).Scan(
-		&u.ID, &u.Username, &u.TeamName, &u.IsActive,
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level,
 	)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrUserNotFound
 		default:
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
 
+	u.Skills = scannedTags(u.Skills)
 	return &u, nil
 }
 
 func (r *userRepositoryPostgres) GetByID(ctx context.Context, userID string) (*domain.User, error) {
+	const op = "repository.postgres.user.GetByID"
+
 	query := `
-        SELECT user_id, username, team_name, is_active
+        SELECT user_id, username, team_name, is_active, skills, level
         FROM users
         WHERE user_id = $1
     `
 
 	var u domain.User
 	err := r.pool.QueryRow(ctx, query, userID).Scan(
-		&u.ID, &u.Username, &u.TeamName, &u.IsActive,
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level,
 	)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrUserNotFound
 		default:
-			return nil, repository.ErrInternalError
+			return nil, repository.Internal(op, err)
 		}
 	}
 
+	u.Skills = scannedTags(u.Skills)
 	return &u, nil
 }
 
-func (r *userRepositoryPostgres) GetReviewPullRequests(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+func (r *userRepositoryPostgres) GetReviewPullRequests(ctx context.Context, userID string) ([]domain.ReviewPullRequest, error) {
+	const op = "repository.postgres.user.GetReviewPullRequests"
+
 	// проверка на существование такого пользователя
 	if _, err := r.GetByID(ctx, userID); err != nil {
 		return nil, err
 	}
 
+	// время назначения — последний выбор, в котором ревьюер выбран. У PR без истории выбора — время создания PR
 	queryGetReviewPR := `
-        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, a.assigned_at
         FROM pull_requests pr
         JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+        LEFT JOIN reviewer_assignments a ON a.assignment_id = (
+            SELECT MAX(c.assignment_id)
+            FROM reviewer_assignment_candidates c
+            JOIN reviewer_assignments ra ON ra.assignment_id = c.assignment_id
+            WHERE ra.pull_request_id = pr.pull_request_id AND c.user_id = r.user_id AND c.chosen
+        )
         WHERE r.user_id = $1
         ORDER BY pr.created_at DESC
     `
 
 	rows, err := r.pool.Query(ctx, queryGetReviewPR, userID)
 	if err != nil {
-		return nil, repository.ErrInternalError
+		return nil, repository.Internal(op, err)
 	}
 	defer rows.Close()
 
-	var prs []domain.PullRequest
+	var prs []domain.ReviewPullRequest
 	for rows.Next() {
-		var pr domain.PullRequest
-		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status); err != nil {
-			return nil, repository.ErrInternalError
+		var (
+			pr         domain.ReviewPullRequest
+			assignedAt *time.Time
+		)
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &assignedAt); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		pr.AssignedAt = pr.CreatedAt
+		if assignedAt != nil {
+			pr.AssignedAt = *assignedAt
 		}
 		prs = append(prs, pr)
 	}
 
 	return prs, nil
 }
+
+// GetByIDs пользователи по списку id одним запросом (для батчевой загрузки в GraphQL).
+// Несуществующие id пропускаются, ошибки ErrUserNotFound нет
+func (r *userRepositoryPostgres) GetByIDs(ctx context.Context, userIDs []string) ([]domain.User, error) {
+	const op = "repository.postgres.user.GetByIDs"
+
+	query := `
+        SELECT user_id, username, team_name, is_active, skills, level
+        FROM users
+        WHERE user_id = ANY($1)
+        ORDER BY user_id
+    `
+
+	rows, err := r.pool.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		u.Skills = scannedTags(u.Skills)
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return users, nil
+}
+
+// GetReviewPullRequestIDs id PR на ревью для каждого пользователя, сначала новые.
+// Пользователи без PR (и несуществующие) в map не попадают
+func (r *userRepositoryPostgres) GetReviewPullRequestIDs(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	const op = "repository.postgres.user.GetReviewPullRequestIDs"
+
+	query := `
+        SELECT r.user_id, pr.pull_request_id
+        FROM pr_reviewers r
+        JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+        WHERE r.user_id = ANY($1)
+        ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+    `
+
+	rows, err := r.pool.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	queues := make(map[string][]string)
+	for rows.Next() {
+		var userID, prID string
+		if err := rows.Scan(&userID, &prID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		queues[userID] = append(queues[userID], prID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return queues, nil
+}
+
+// GetByIDsTx как GetByIDs, но в транзакции и с блокировкой строк до ее конца
+func (r *userRepositoryPostgres) GetByIDsTx(ctx context.Context, tx pgx.Tx, userIDs []string) ([]domain.User, error) {
+	const op = "repository.postgres.user.GetByIDsTx"
+
+	query := `
+        SELECT user_id, username, team_name, is_active, skills, level
+        FROM users
+        WHERE user_id = ANY($1)
+        ORDER BY user_id
+        FOR UPDATE
+    `
+
+	rows, err := tx.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.Skills, &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		u.Skills = scannedTags(u.Skills)
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return users, nil
+}
+
+// tagsArg теги для колонки TEXT[] NOT NULL: nil pgx передал бы как NULL
+func tagsArg(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// scannedTags пустой массив из базы — nil, как у пользователей и PR без тегов в остальных хранилищах
+func scannedTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
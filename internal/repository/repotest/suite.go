@@ -0,0 +1,1722 @@
+// Package repotest общий набор тестов для реализаций репозиториев.
+// Любое хранилище (Postgres, memory, ...) должно проходить Run с одинаковым результатом
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"service-order-avito/internal/service/availability"
+	"service-order-avito/internal/service/pull_request"
+	"service-order-avito/internal/service/team"
+	"service-order-avito/internal/service/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Repositories struct {
+	Team         team.TeamRepository
+	User         user.UserRepository
+	PullRequest  pull_request.PullRequestRepository
+	Idempotency  IdempotencyRepository
+	Availability availability.AvailabilityRepository
+	// LimitedPullRequest репозиторий PR поверх того же хранилища с лимитом открытых ревью на ревьюера
+	LimitedPullRequest func(maxOpenReviews int) pull_request.PullRequestRepository
+}
+
+// IdempotencyRepository middleware.IdempotencyStore и очистка истекших ключей
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, resp domain.IdempotentResponse) error
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Factory возвращает репозитории поверх пустого хранилища
+type Factory func(t *testing.T) Repositories
+
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("team", func(t *testing.T) { testTeam(t, newRepos) })
+	t.Run("user", func(t *testing.T) { testUser(t, newRepos) })
+	t.Run("pull request create", func(t *testing.T) { testCreate(t, newRepos) })
+	t.Run("pull request merge", func(t *testing.T) { testMerge(t, newRepos) })
+	t.Run("pull request close", func(t *testing.T) { testClose(t, newRepos) })
+	t.Run("pull request reassign", func(t *testing.T) { testReassign(t, newRepos) })
+	t.Run("pull request version", func(t *testing.T) { testVersion(t, newRepos) })
+	t.Run("batch reads", func(t *testing.T) { testBatch(t, newRepos) })
+	t.Run("import", func(t *testing.T) { testImport(t, newRepos) })
+	t.Run("export", func(t *testing.T) { testExport(t, newRepos) })
+	t.Run("idempotency", func(t *testing.T) { testIdempotency(t, newRepos) })
+	t.Run("availability", func(t *testing.T) { testAvailability(t, newRepos) })
+	t.Run("working hours", func(t *testing.T) { testWorkingHours(t, newRepos) })
+	t.Run("skills", func(t *testing.T) { testSkills(t, newRepos) })
+	t.Run("reviewer rules", func(t *testing.T) { testReviewerRules(t, newRepos) })
+	t.Run("pairing", func(t *testing.T) { testPairing(t, newRepos) })
+	t.Run("assignments", func(t *testing.T) { testAssignments(t, newRepos) })
+	t.Run("preview", func(t *testing.T) { testPreview(t, newRepos) })
+	t.Run("capacity", func(t *testing.T) { testCapacity(t, newRepos) })
+}
+
+func member(id, teamName string, active bool) domain.User {
+	return domain.User{ID: id, Username: "name-" + id, TeamName: teamName, IsActive: active}
+}
+
+func addTeam(t *testing.T, repos Repositories, name string, members ...domain.User) {
+	t.Helper()
+	require.NoError(t, repos.Team.AddTeamWithMembers(context.Background(), domain.Team{Name: name}, members))
+}
+
+func createPR(t *testing.T, repos Repositories, id, authorID string) *domain.PullRequestWithReviewers {
+	t.Helper()
+	pr, err := repos.PullRequest.CreateWithReviewers(context.Background(), domain.PullRequest{
+		ID:       id,
+		Name:     "name-" + id,
+		AuthorID: authorID,
+		Status:   domain.PRStatusOpen,
+	}, 1)
+	require.NoError(t, err)
+	return pr
+}
+
+func userIDs(users []domain.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func testTeam(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("add and get", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", false))
+
+		got, err := repos.Team.GetTeamWithMembers(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, "backend", got.TeamName)
+		assert.ElementsMatch(t, []domain.User{member("u1", "backend", true), member("u2", "backend", false)}, got.Members)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true))
+
+		err := repos.Team.AddTeamWithMembers(ctx, domain.Team{Name: "backend"}, []domain.User{member("u9", "backend", true)})
+		assert.True(t, errors.Is(err, repository.ErrTeamAlreadyExists), "got %v", err)
+
+		// неудачная операция ничего не меняет
+		got, err := repos.Team.GetTeamWithMembers(ctx, "backend")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u1"}, userIDs(got.Members))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Team.GetTeamWithMembers(ctx, "missing")
+		assert.True(t, errors.Is(err, repository.ErrTeamNotFound), "got %v", err)
+
+		_, _, _, _, err = repos.Team.GetTeamStats(ctx, "missing")
+		assert.True(t, errors.Is(err, repository.ErrTeamNotFound), "got %v", err)
+	})
+
+	t.Run("members move between teams", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		addTeam(t, repos, "frontend", member("u2", "frontend", false))
+
+		backend, err := repos.Team.GetTeamWithMembers(ctx, "backend")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u1"}, userIDs(backend.Members))
+
+		frontend, err := repos.Team.GetTeamWithMembers(ctx, "frontend")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []domain.User{member("u2", "frontend", false)}, frontend.Members)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", false),
+		)
+		addTeam(t, repos, "frontend", member("f1", "frontend", true))
+		createPR(t, repos, "pr1", "u1")
+		createPR(t, repos, "pr2", "u2")
+		createPR(t, repos, "pr3", "f1")
+		_, _, err := repos.PullRequest.Merge(ctx, "pr2", 0)
+		require.NoError(t, err)
+
+		active, inactive, open, merged, err := repos.Team.GetTeamStats(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, 2, active)
+		assert.Equal(t, 1, inactive)
+		assert.Equal(t, 1, open)
+		assert.Equal(t, 1, merged)
+	})
+}
+
+func testUser(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("set is active", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true))
+
+		got, err := repos.User.SetIsActive(ctx, "u1", false)
+		require.NoError(t, err)
+		assert.Equal(t, member("u1", "backend", false), *got)
+
+		_, err = repos.User.SetIsActive(ctx, "missing", false)
+		assert.True(t, errors.Is(err, repository.ErrUserNotFound), "got %v", err)
+	})
+
+	t.Run("review pull requests", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+
+		prs, err := repos.User.GetReviewPullRequests(ctx, "u2")
+		require.NoError(t, err)
+		assert.Empty(t, prs)
+
+		createPR(t, repos, "pr1", "u1")
+
+		prs, err = repos.User.GetReviewPullRequests(ctx, "u2")
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, "pr1", prs[0].ID)
+		assert.Equal(t, "u1", prs[0].AuthorID)
+		assert.Equal(t, domain.PRStatusOpen, prs[0].Status)
+
+		_, err = repos.User.GetReviewPullRequests(ctx, "missing")
+		assert.True(t, errors.Is(err, repository.ErrUserNotFound), "got %v", err)
+	})
+}
+
+func testCreate(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("assigns up to two active teammates", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+			member("u5", "backend", false),
+		)
+		addTeam(t, repos, "frontend", member("f1", "frontend", true))
+
+		pr := createPR(t, repos, "pr1", "u1")
+
+		assert.Equal(t, "pr1", pr.ID)
+		assert.Equal(t, domain.PRStatusOpen, pr.Status)
+		assert.Len(t, pr.AssignedReviewers, 2)
+		for _, uid := range pr.AssignedReviewers {
+			assert.Contains(t, []string{"u2", "u3", "u4"}, uid)
+		}
+		assert.NotEqual(t, pr.AssignedReviewers[0], pr.AssignedReviewers[1])
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "solo", member("u1", "solo", true), member("u2", "solo", false))
+
+		pr := createPR(t, repos, "pr1", "u1")
+		assert.Empty(t, pr.AssignedReviewers)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		createPR(t, repos, "pr1", "u1")
+
+		_, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr1", Name: "again", AuthorID: "u2"}, 1)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestExists), "got %v", err)
+	})
+
+	t.Run("author not found", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr1", Name: "pr", AuthorID: "missing"}, 1)
+		assert.True(t, errors.Is(err, repository.ErrUserNotFound), "got %v", err)
+	})
+}
+
+func testMerge(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("merge is idempotent", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		created := createPR(t, repos, "pr1", "u1")
+
+		first, changed, err := repos.PullRequest.Merge(ctx, "pr1", 0)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, domain.PRStatusMerged, first.Status)
+		require.NotNil(t, first.MergedAt)
+		assert.ElementsMatch(t, created.AssignedReviewers, first.AssignedReviewers)
+
+		second, changed, err := repos.PullRequest.Merge(ctx, "pr1", 0)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, domain.PRStatusMerged, second.Status)
+		require.NotNil(t, second.MergedAt)
+		assert.True(t, first.MergedAt.Equal(*second.MergedAt))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, _, err := repos.PullRequest.Merge(ctx, "missing", 0)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestNotFound), "got %v", err)
+	})
+}
+
+func testClose(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("close is idempotent", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		created := createPR(t, repos, "pr1", "u1")
+
+		first, changed, err := repos.PullRequest.Close(ctx, "pr1", 1)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, domain.PRStatusClosed, first.Status)
+		assert.Nil(t, first.MergedAt)
+		assert.Equal(t, int64(2), first.Version)
+		assert.ElementsMatch(t, created.AssignedReviewers, first.AssignedReviewers)
+
+		second, changed, err := repos.PullRequest.Close(ctx, "pr1", 0)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, domain.PRStatusClosed, second.Status)
+		assert.Equal(t, int64(2), second.Version)
+
+		got, err := repos.PullRequest.GetByID(ctx, "pr1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.PRStatusClosed, got.Status)
+	})
+
+	t.Run("closed is not merged or reassigned", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true), member("u4", "backend", true))
+		pr := createPR(t, repos, "pr1", "u1")
+		_, _, err := repos.PullRequest.Close(ctx, "pr1", 0)
+		require.NoError(t, err)
+
+		_, _, err = repos.PullRequest.Merge(ctx, "pr1", 0)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestClosed), "got %v", err)
+
+		_, err = repos.PullRequest.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], 0, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestClosed), "got %v", err)
+	})
+
+	t.Run("merged is not closed", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		createPR(t, repos, "pr1", "u1")
+		_, _, err := repos.PullRequest.Merge(ctx, "pr1", 0)
+		require.NoError(t, err)
+
+		_, _, err = repos.PullRequest.Close(ctx, "pr1", 0)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestMerged), "got %v", err)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		createPR(t, repos, "pr1", "u1")
+
+		_, _, err := repos.PullRequest.Close(ctx, "pr1", 5)
+		assert.True(t, errors.Is(err, repository.ErrVersionMismatch), "got %v", err)
+
+		got, err := repos.PullRequest.GetByID(ctx, "pr1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.PRStatusOpen, got.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, _, err := repos.PullRequest.Close(ctx, "missing", 0)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestNotFound), "got %v", err)
+	})
+}
+
+func testReassign(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("replaces with another active teammate", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+			member("u5", "backend", false),
+		)
+		created := time.Date(2025, 12, 15, 10, 0, 0, 0, time.UTC)
+		pr := createPRAt(t, repos, "pr1", "u1", created)
+		require.Len(t, pr.AssignedReviewers, 2)
+		old, other := pr.AssignedReviewers[0], pr.AssignedReviewers[1]
+
+		got, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", old, 0, created.Add(time.Hour), 1)
+		require.NoError(t, err)
+		assert.NotContains(t, []string{"u1", "u5", old, other}, got.ID)
+
+		// SLA нового ревьюера идет с замены, у оставшегося — с создания PR
+		prs, err := repos.User.GetReviewPullRequests(ctx, got.ID)
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, "pr1", prs[0].ID)
+		assert.True(t, prs[0].AssignedAt.Equal(created.Add(time.Hour)), "got %v", prs[0].AssignedAt)
+
+		prs, err = repos.User.GetReviewPullRequests(ctx, other)
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.True(t, prs[0].AssignedAt.Equal(created), "got %v", prs[0].AssignedAt)
+
+		prs, err = repos.User.GetReviewPullRequests(ctx, old)
+		require.NoError(t, err)
+		assert.Empty(t, prs)
+	})
+
+	t.Run("no candidate", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+		)
+		pr := createPR(t, repos, "pr1", "u1")
+		require.Len(t, pr.AssignedReviewers, 2)
+
+		// второй ревьюер уже назначен, автор и сам заменяемый не подходят
+		_, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], 0, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrNoReplacementCandidate), "got %v", err)
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true), member("u4", "backend", true))
+		pr := createPR(t, repos, "pr1", "u1")
+		_, _, err := repos.PullRequest.Merge(ctx, "pr1", 0)
+		require.NoError(t, err)
+
+		_, err = repos.PullRequest.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], 0, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestMerged), "got %v", err)
+	})
+
+	t.Run("not assigned", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		createPR(t, repos, "pr1", "u1")
+
+		_, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", "u1", 0, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrReviewerNotAssigned), "got %v", err)
+	})
+
+	t.Run("unknown pull request and user", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		createPR(t, repos, "pr1", "u1")
+
+		_, err := repos.PullRequest.ReassignReviewer(ctx, "missing", "u2", 0, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrPullRequestNotFound), "got %v", err)
+
+		_, err = repos.PullRequest.ReassignReviewer(ctx, "pr1", "missing", 0, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrUserNotFound), "got %v", err)
+	})
+}
+
+func testVersion(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("grows on reassign and merge", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+		)
+		pr := createPR(t, repos, "pr1", "u1")
+		assert.Equal(t, int64(1), pr.Version)
+
+		reviewer, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], 1, time.Now(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), reviewer.PullRequestVersion)
+
+		got, err := repos.PullRequest.GetByID(ctx, "pr1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), got.Version)
+		assert.Contains(t, got.AssignedReviewers, reviewer.ID)
+
+		merged, _, err := repos.PullRequest.Merge(ctx, "pr1", 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), merged.Version)
+
+		// повторный merge ничего не меняет, версия остается прежней
+		again, _, err := repos.PullRequest.Merge(ctx, "pr1", 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), again.Version)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+		)
+		pr := createPR(t, repos, "pr1", "u1")
+		_, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], 0, time.Now(), 1)
+		require.NoError(t, err)
+
+		_, err = repos.PullRequest.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[1], 1, time.Now(), 1)
+		assert.True(t, errors.Is(err, repository.ErrVersionMismatch), "got %v", err)
+
+		_, _, err = repos.PullRequest.Merge(ctx, "pr1", 1)
+		assert.True(t, errors.Is(err, repository.ErrVersionMismatch), "got %v", err)
+
+		got, err := repos.PullRequest.GetByID(ctx, "pr1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.PRStatusOpen, got.Status)
+		assert.Equal(t, int64(2), got.Version)
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.PullRequest.GetByID(ctx, "missing")
+		assert.True(t, errors.Is(err, repository.ErrPullRequestNotFound), "got %v", err)
+	})
+}
+
+func testBatch(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	repos := newRepos(t)
+	addTeam(t, repos, "backend", member("u2", "backend", true), member("u1", "backend", true), member("u3", "backend", false))
+	addTeam(t, repos, "empty")
+	first := createPR(t, repos, "pr1", "u1")
+	second := createPR(t, repos, "pr2", "u1")
+
+	t.Run("users", func(t *testing.T) {
+		users, err := repos.User.GetByIDs(ctx, []string{"u3", "missing", "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u1", "u3"}, userIDs(users))
+
+		users, err = repos.User.GetByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		teams, err := repos.Team.GetTeamsWithMembers(ctx, []string{"empty", "missing", "backend"})
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		assert.Equal(t, "backend", teams[0].TeamName)
+		assert.Equal(t, []string{"u1", "u2", "u3"}, userIDs(teams[0].Members))
+		assert.Equal(t, "empty", teams[1].TeamName)
+		assert.Empty(t, teams[1].Members)
+	})
+
+	t.Run("pull requests", func(t *testing.T) {
+		prs, err := repos.PullRequest.GetByIDs(ctx, []string{"pr2", "missing", "pr1"})
+		require.NoError(t, err)
+		require.Len(t, prs, 2)
+		assert.Equal(t, "pr1", prs[0].ID)
+		assert.ElementsMatch(t, first.AssignedReviewers, prs[0].AssignedReviewers)
+		assert.Equal(t, "pr2", prs[1].ID)
+		assert.ElementsMatch(t, second.AssignedReviewers, prs[1].AssignedReviewers)
+		assert.Equal(t, int64(1), prs[1].Version)
+	})
+
+	t.Run("review queues", func(t *testing.T) {
+		queues, err := repos.User.GetReviewPullRequestIDs(ctx, []string{"u1", "u2", "u3"})
+		require.NoError(t, err)
+		// u1 автор, u3 неактивен: оба PR достались u2
+		assert.Equal(t, map[string][]string{"u2": {"pr2", "pr1"}}, queues)
+	})
+}
+
+func exportAll(t *testing.T, repos Repositories, filter domain.ExportFilter) []domain.PullRequestExport {
+	t.Helper()
+	prs := []domain.PullRequestExport{}
+	err := repos.PullRequest.Export(context.Background(), filter, func(pr domain.PullRequestExport) error {
+		prs = append(prs, pr)
+		return nil
+	})
+	require.NoError(t, err)
+	return prs
+}
+
+func testExport(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	repos := newRepos(t)
+	addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true))
+	addTeam(t, repos, "frontend", member("f1", "frontend", true), member("f2", "frontend", true))
+	addTeam(t, repos, "empty")
+	createPR(t, repos, "pr1", "u1")
+	createPR(t, repos, "pr2", "u1")
+	createPR(t, repos, "pr3", "f1")
+	_, _, err := repos.PullRequest.Merge(ctx, "pr2", 0)
+	require.NoError(t, err)
+
+	all := exportAll(t, repos, domain.ExportFilter{})
+
+	t.Run("all in creation order", func(t *testing.T) {
+		require.Len(t, all, 3)
+		assert.Equal(t, "pr1", all[0].ID)
+		assert.Equal(t, "backend", all[0].TeamName)
+		assert.Equal(t, []string{"u2", "u3"}, all[0].AssignedReviewers)
+		assert.Equal(t, domain.PRStatusOpen, all[0].Status)
+		assert.Nil(t, all[0].MergedAt)
+		assert.False(t, all[0].CreatedAt.IsZero())
+
+		assert.Equal(t, "pr2", all[1].ID)
+		assert.Equal(t, domain.PRStatusMerged, all[1].Status)
+		assert.NotNil(t, all[1].MergedAt)
+		assert.Equal(t, int64(2), all[1].Version)
+	})
+
+	t.Run("team", func(t *testing.T) {
+		prs := exportAll(t, repos, domain.ExportFilter{TeamName: "frontend"})
+		require.Len(t, prs, 1)
+		assert.Equal(t, "pr3", prs[0].ID)
+		assert.Equal(t, "frontend", prs[0].TeamName)
+		assert.Equal(t, []string{"f2"}, prs[0].AssignedReviewers)
+
+		assert.Empty(t, exportAll(t, repos, domain.ExportFilter{TeamName: "empty"}))
+
+		err := repos.PullRequest.Export(ctx, domain.ExportFilter{TeamName: "missing"}, func(domain.PullRequestExport) error { return nil })
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		first := all[0].CreatedAt
+		future := time.Now().Add(time.Hour)
+
+		assert.Len(t, exportAll(t, repos, domain.ExportFilter{From: &first}), 3)
+		// to не включается
+		assert.Empty(t, exportAll(t, repos, domain.ExportFilter{To: &first}))
+		assert.Empty(t, exportAll(t, repos, domain.ExportFilter{From: &future}))
+		assert.Len(t, exportAll(t, repos, domain.ExportFilter{TeamName: "backend", From: &first, To: &future}), 2)
+	})
+
+	t.Run("callback error stops export", func(t *testing.T) {
+		stop := errors.New("client gone")
+		calls := 0
+		err := repos.PullRequest.Export(ctx, domain.ExportFilter{}, func(domain.PullRequestExport) error {
+			calls++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func testImport(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	repos := newRepos(t)
+
+	addTeam(t, repos, "old",
+		member("u1", "old", true),
+		member("u2", "old", true),
+		member("u3", "old", true),
+	)
+
+	teams := []domain.TeamWithUsers{
+		{TeamName: "old", Members: []domain.User{
+			member("u1", "", true),
+			{ID: "u2", Username: "renamed", IsActive: false},
+		}},
+		{TeamName: "new", Members: []domain.User{
+			member("u3", "", true),
+			member("u4", "", true),
+		}},
+	}
+
+	want := &domain.ImportReport{
+		TeamsCreated:   []string{"new"},
+		UsersCreated:   []string{"u4"},
+		UsersUpdated:   []domain.UserUpdate{{UserID: "u2", Fields: []string{"username", "is_active"}}},
+		UsersMoved:     []domain.UserMove{{UserID: "u3", FromTeam: "old", ToTeam: "new"}},
+		UsersUnchanged: 1,
+	}
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		report, err := repos.Team.ImportTeams(ctx, teams, true)
+		require.NoError(t, err)
+		assert.Equal(t, want, report)
+
+		_, err = repos.Team.GetTeamWithMembers(ctx, "new")
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+		users, err := repos.User.GetByIDs(ctx, []string{"u2", "u3", "u4"})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.User{member("u2", "old", true), member("u3", "old", true)}, users)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		report, err := repos.Team.ImportTeams(ctx, teams, false)
+		require.NoError(t, err)
+		assert.Equal(t, want, report)
+
+		got, err := repos.Team.GetTeamsWithMembers(ctx, []string{"new", "old"})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, []string{"u3", "u4"}, userIDs(got[0].Members))
+		assert.Equal(t, []domain.User{member("u1", "old", true), {ID: "u2", Username: "renamed", TeamName: "old", IsActive: false}}, got[1].Members)
+	})
+
+	t.Run("repeat is a no-op", func(t *testing.T) {
+		report, err := repos.Team.ImportTeams(ctx, teams, false)
+		require.NoError(t, err)
+		assert.Empty(t, report.TeamsCreated)
+		assert.Empty(t, report.UsersCreated)
+		assert.Empty(t, report.UsersUpdated)
+		assert.Empty(t, report.UsersMoved)
+		assert.Equal(t, 4, report.UsersUnchanged)
+	})
+}
+
+func testIdempotency(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	// время без монотонной части и с точностью до микросекунд, как его вернет бд
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	key := func(k, fingerprint string, createdAt time.Time) domain.IdempotencyKey {
+		return domain.IdempotencyKey{Key: k, Fingerprint: fingerprint, CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)}
+	}
+
+	t.Run("reserve complete replay", func(t *testing.T) {
+		repos := newRepos(t)
+
+		existing, err := repos.Idempotency.Reserve(ctx, key("k1", "fp1", now))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		// пока ответа нет, ключ виден как "в обработке"
+		existing, err = repos.Idempotency.Reserve(ctx, key("k1", "fp1", now.Add(time.Second)))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, "fp1", existing.Fingerprint)
+		assert.Nil(t, existing.Response)
+
+		resp := domain.IdempotentResponse{StatusCode: 201, ContentType: "application/json", ETag: `"3"`, Body: []byte(`{"ok":true}`)}
+		require.NoError(t, repos.Idempotency.Complete(ctx, "k1", resp))
+
+		existing, err = repos.Idempotency.Reserve(ctx, key("k1", "fp2", now.Add(time.Minute)))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, "fp1", existing.Fingerprint)
+		require.NotNil(t, existing.Response)
+		assert.Equal(t, resp, *existing.Response)
+		assert.True(t, existing.ExpiresAt.Equal(now.Add(time.Hour)))
+	})
+
+	t.Run("release", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Idempotency.Reserve(ctx, key("k1", "fp1", now))
+		require.NoError(t, err)
+		require.NoError(t, repos.Idempotency.Release(ctx, "k1"))
+
+		existing, err := repos.Idempotency.Reserve(ctx, key("k1", "fp2", now))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	t.Run("expired key is reserved again", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Idempotency.Reserve(ctx, key("k1", "fp1", now))
+		require.NoError(t, err)
+		require.NoError(t, repos.Idempotency.Complete(ctx, "k1", domain.IdempotentResponse{StatusCode: 200}))
+
+		existing, err := repos.Idempotency.Reserve(ctx, key("k1", "fp2", now.Add(time.Hour)))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+
+		existing, err = repos.Idempotency.Reserve(ctx, key("k1", "fp3", now.Add(time.Hour+time.Second)))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, "fp2", existing.Fingerprint)
+		assert.Nil(t, existing.Response)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Idempotency.Reserve(ctx, key("old", "fp", now))
+		require.NoError(t, err)
+		_, err = repos.Idempotency.Reserve(ctx, key("new", "fp", now.Add(30*time.Minute)))
+		require.NoError(t, err)
+
+		deleted, err := repos.Idempotency.DeleteExpired(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		existing, err := repos.Idempotency.Reserve(ctx, key("new", "fp", now.Add(time.Hour)))
+		require.NoError(t, err)
+		assert.NotNil(t, existing)
+	})
+}
+
+func addAbsence(t *testing.T, repos Repositories, absence domain.Absence) *domain.Absence {
+	t.Helper()
+	added, err := repos.Availability.AddAbsence(context.Background(), absence)
+	require.NoError(t, err)
+	return added
+}
+
+func testAvailability(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	vacation := func(userID string, starts, ends time.Time) domain.Absence {
+		return domain.Absence{UserID: userID, Kind: domain.AbsenceVacation, Starts: starts, Ends: ends}
+	}
+
+	t.Run("add list delete", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+
+		later := addAbsence(t, repos, vacation("u1", now.Add(48*time.Hour), now.Add(72*time.Hour)))
+		partTime := addAbsence(t, repos, domain.Absence{
+			UserID: "u1", Kind: domain.AbsencePartTime, Starts: now, Ends: now.Add(30 * 24 * time.Hour),
+			Weekdays: domain.NewWeekdays(time.Monday, time.Friday),
+		})
+		assert.NotEqual(t, later.ID, partTime.ID)
+
+		absences, err := repos.Availability.ListAbsences(ctx, "u1")
+		require.NoError(t, err)
+		require.Len(t, absences, 2)
+		// по времени начала
+		assert.Equal(t, partTime.ID, absences[0].ID)
+		assert.True(t, absences[0].Starts.Equal(now))
+		assert.Equal(t, domain.NewWeekdays(time.Monday, time.Friday), absences[0].Weekdays)
+		assert.Equal(t, later.ID, absences[1].ID)
+		assert.True(t, absences[1].Ends.Equal(now.Add(72*time.Hour)))
+
+		absences, err = repos.Availability.ListAbsences(ctx, "u2")
+		require.NoError(t, err)
+		assert.Empty(t, absences)
+
+		// чужой период не удаляется
+		assert.ErrorIs(t, repos.Availability.DeleteAbsence(ctx, "u2", later.ID), repository.ErrAbsenceNotFound)
+		require.NoError(t, repos.Availability.DeleteAbsence(ctx, "u1", later.ID))
+		assert.ErrorIs(t, repos.Availability.DeleteAbsence(ctx, "u1", later.ID), repository.ErrAbsenceNotFound)
+
+		absences, err = repos.Availability.ListAbsences(ctx, "u1")
+		require.NoError(t, err)
+		assert.Len(t, absences, 1)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Availability.AddAbsence(ctx, vacation("ghost", now, now.Add(time.Hour)))
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+		_, err = repos.Availability.ListAbsences(ctx, "ghost")
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+		assert.ErrorIs(t, repos.Availability.DeleteAbsence(ctx, "ghost", 1), repository.ErrUserNotFound)
+	})
+
+	t.Run("create skips absent teammates", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true),
+			member("u3", "backend", true), member("u4", "backend", true), member("u5", "backend", true),
+		)
+		addAbsence(t, repos, vacation("u2", now.Add(-time.Hour), now.Add(24*time.Hour)))
+		addAbsence(t, repos, domain.Absence{
+			UserID: "u3", Kind: domain.AbsencePartTime, Starts: now.Add(-time.Hour), Ends: now.Add(24 * time.Hour),
+			Weekdays: domain.NewWeekdays(now.Weekday()),
+		})
+		// закончившийся, будущий и по другому дню недели не мешают
+		addAbsence(t, repos, vacation("u4", now.Add(-48*time.Hour), now.Add(-24*time.Hour)))
+		addAbsence(t, repos, vacation("u5", now.Add(24*time.Hour), now.Add(48*time.Hour)))
+		addAbsence(t, repos, domain.Absence{
+			UserID: "u5", Kind: domain.AbsencePartTime, Starts: now.Add(-time.Hour), Ends: now.Add(24 * time.Hour),
+			Weekdays: domain.NewWeekdays((now.Weekday() + 1) % 7),
+		})
+
+		for i := range 5 {
+			pr := createPR(t, repos, fmt.Sprintf("pr%d", i), "u1")
+			assert.ElementsMatch(t, []string{"u4", "u5"}, pr.AssignedReviewers)
+		}
+	})
+
+	t.Run("weekdays are in user's timezone", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true))
+		// четверг 20:00 UTC — в Новосибирске уже пятница 03:00
+		at := time.Date(2025, 12, 18, 20, 0, 0, 0, time.UTC)
+		_, err := repos.Availability.SetWorkingHours(ctx, officeHours("u2", "Asia/Novosibirsk"))
+		require.NoError(t, err)
+		for _, id := range []string{"u2", "u3"} {
+			addAbsence(t, repos, domain.Absence{
+				UserID: id, Kind: domain.AbsencePartTime, Starts: at.Add(-72 * time.Hour), Ends: at.Add(72 * time.Hour),
+				Weekdays: domain.NewWeekdays(time.Friday),
+			})
+		}
+
+		preview, err := repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1", CreatedAt: at}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, preview.Reviewers)
+		assert.Contains(t, preview.Excluded, domain.CandidateExclusion{UserID: "u2", Reason: domain.ExcludedOutOfOffice})
+	})
+
+	t.Run("reassign skips absent teammates", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true),
+			member("u3", "backend", true), member("u4", "backend", true),
+		)
+		addAbsence(t, repos, vacation("u4", now.Add(-time.Hour), now.Add(24*time.Hour)))
+		pr := createPR(t, repos, "pr1", "u1")
+		require.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+		_, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", "u2", 0, time.Now(), 1)
+		assert.ErrorIs(t, err, repository.ErrNoReplacementCandidate)
+	})
+
+	t.Run("absent reviews", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true))
+		createPR(t, repos, "pr1", "u1")
+		createPR(t, repos, "pr2", "u1")
+		createPR(t, repos, "pr3", "u1")
+		_, _, err := repos.PullRequest.Merge(ctx, "pr3", 0)
+		require.NoError(t, err)
+
+		addAbsence(t, repos, vacation("u2", now.Add(time.Hour), now.Add(48*time.Hour)))
+		addAbsence(t, repos, domain.Absence{
+			UserID: "u3", Kind: domain.AbsencePartTime, Starts: now.Add(-time.Hour), Ends: now.Add(48 * time.Hour),
+			Weekdays: domain.NewWeekdays(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday),
+		})
+
+		// отпуск еще не начался, неполная занятость не в счет
+		reviews, err := repos.Availability.ListAbsentReviews(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, reviews)
+
+		// в MERGED pr3 замен не нужно
+		reviews, err = repos.Availability.ListAbsentReviews(ctx, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []domain.AbsentReview{
+			{PullRequestID: "pr1", ReviewerID: "u2"},
+			{PullRequestID: "pr2", ReviewerID: "u2"},
+		}, reviews)
+	})
+
+	t.Run("import is idempotent on uid", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		manual := addAbsence(t, repos, vacation("u1", now, now.Add(time.Hour)))
+
+		trip := vacation("u1", now.Add(24*time.Hour), now.Add(72*time.Hour))
+		trip.UID = "trip@calendar"
+		// тот же UID у другого пользователя — другой период
+		sameUID := vacation("u2", now, now.Add(24*time.Hour))
+		sameUID.UID = "trip@calendar"
+		imp := domain.AbsenceImport{Users: []string{"u1", "u2"}, Upsert: []domain.Absence{trip, sameUID}}
+
+		report, err := repos.Availability.ImportAbsences(ctx, imp)
+		require.NoError(t, err)
+		assert.Equal(t, domain.AbsenceImportReport{Created: 2}, *report)
+
+		report, err = repos.Availability.ImportAbsences(ctx, imp)
+		require.NoError(t, err)
+		assert.Equal(t, domain.AbsenceImportReport{Unchanged: 2}, *report)
+
+		trip.Ends = now.Add(96 * time.Hour)
+		report, err = repos.Availability.ImportAbsences(ctx, domain.AbsenceImport{Users: []string{"u1"}, Upsert: []domain.Absence{trip}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.AbsenceImportReport{Updated: 1}, *report)
+
+		absences, err := repos.Availability.ListAbsences(ctx, "u1")
+		require.NoError(t, err)
+		require.Len(t, absences, 2)
+		assert.Equal(t, manual.ID, absences[0].ID)
+		assert.Empty(t, absences[0].UID)
+		assert.Equal(t, "trip@calendar", absences[1].UID)
+		assert.True(t, absences[1].Ends.Equal(now.Add(96*time.Hour)))
+
+		// отмена удаляет только импортированный период
+		report, err = repos.Availability.ImportAbsences(ctx, domain.AbsenceImport{
+			Users:  []string{"u1"},
+			Cancel: []domain.Absence{{UserID: "u1", UID: "trip@calendar"}, {UserID: "u1", UID: "unknown"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.AbsenceImportReport{Deleted: 1}, *report)
+
+		absences, err = repos.Availability.ListAbsences(ctx, "u1")
+		require.NoError(t, err)
+		require.Len(t, absences, 1)
+		assert.Equal(t, manual.ID, absences[0].ID)
+		absences, err = repos.Availability.ListAbsences(ctx, "u2")
+		require.NoError(t, err)
+		assert.Len(t, absences, 1)
+	})
+
+	t.Run("import unknown user", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true))
+
+		trip := vacation("u1", now, now.Add(time.Hour))
+		trip.UID = "trip"
+		_, err := repos.Availability.ImportAbsences(ctx, domain.AbsenceImport{Users: []string{"u1", "ghost"}, Upsert: []domain.Absence{trip}})
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+
+		// импорт целиком откатился
+		absences, err := repos.Availability.ListAbsences(ctx, "u1")
+		require.NoError(t, err)
+		assert.Empty(t, absences)
+	})
+}
+
+func officeHours(userID, timezone string) domain.WorkingHours {
+	return domain.WorkingHours{UserID: userID, Timezone: timezone, Start: 9 * 60, End: 18 * 60, Days: domain.DefaultWorkingHours.Days}
+}
+
+func testWorkingHours(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	// среда 12:00 UTC: рабочее время в Москве и по умолчанию (UTC), ночь во Владивостоке и Лос-Анджелесе
+	wednesday := time.Date(2025, 12, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("set get list", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+
+		hours, err := repos.Availability.GetWorkingHours(ctx, "u1")
+		require.NoError(t, err)
+		assert.Nil(t, hours)
+
+		night := domain.WorkingHours{UserID: "u1", Timezone: "Asia/Tokyo", Start: 22 * 60, End: 6 * 60, Days: domain.NewWeekdays(time.Saturday)}
+		_, err = repos.Availability.SetWorkingHours(ctx, officeHours("u1", "Europe/Moscow"))
+		require.NoError(t, err)
+		got, err := repos.Availability.SetWorkingHours(ctx, night)
+		require.NoError(t, err)
+		assert.Equal(t, night, *got)
+
+		hours, err = repos.Availability.GetWorkingHours(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, &night, hours)
+
+		// без расписания и неизвестные пропускаются
+		list, err := repos.Availability.ListWorkingHours(ctx, []string{"u1", "u2", "ghost"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]domain.WorkingHours{"u1": night}, list)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Availability.SetWorkingHours(ctx, officeHours("ghost", "UTC"))
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+		_, err = repos.Availability.GetWorkingHours(ctx, "ghost")
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+	})
+
+	t.Run("create prefers teammates in working hours", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true),
+			member("u4", "backend", true), member("u5", "backend", true),
+		)
+		for _, h := range []domain.WorkingHours{
+			officeHours("u3", "Asia/Vladivostok"), officeHours("u4", "America/Los_Angeles"), officeHours("u5", "Europe/Moscow"),
+		} {
+			_, err := repos.Availability.SetWorkingHours(ctx, h)
+			require.NoError(t, err)
+		}
+
+		for i := range 5 {
+			pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+				ID: fmt.Sprintf("pr%d", i), Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen, CreatedAt: wednesday,
+			}, int64(i))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"u2", "u5"}, pr.AssignedReviewers)
+		}
+
+		// время создания сохраняется: от него считается SLA ревью
+		prs, err := repos.User.GetReviewPullRequests(ctx, "u2")
+		require.NoError(t, err)
+		require.Len(t, prs, 5)
+		assert.True(t, prs[0].CreatedAt.Equal(wednesday), "got %v", prs[0].CreatedAt)
+
+		// в четверг в 03:00 UTC рабочий день идет только во Владивостоке
+		thursday := wednesday.Add(15 * time.Hour)
+		for i := range 5 {
+			got, err := repos.PullRequest.ReassignReviewer(ctx, fmt.Sprintf("pr%d", i), "u2", 0, thursday, int64(i))
+			require.NoError(t, err)
+			assert.Equal(t, "u3", got.ID)
+		}
+	})
+}
+
+func skilled(id, teamName string, skills ...string) domain.User {
+	u := member(id, teamName, true)
+	u.Skills = skills
+	return u
+}
+
+func testSkills(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("set and update", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true))
+
+		u, err := repos.User.SetSkills(ctx, "u1", []string{"sql", "go", "sql"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "sql"}, u.Skills)
+
+		// тег и в add, и в remove убирается
+		u, err = repos.User.UpdateSkills(ctx, "u1", []string{"k8s", "go", "rust"}, []string{"sql", "rust"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "k8s"}, u.Skills)
+
+		got, err := repos.User.GetByIDs(ctx, []string{"u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.User{skilled("u1", "backend", "go", "k8s")}, got)
+
+		u, err = repos.User.SetSkills(ctx, "u1", nil)
+		require.NoError(t, err)
+		assert.Nil(t, u.Skills)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.User.SetSkills(ctx, "ghost", []string{"go"})
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+		_, err = repos.User.UpdateSkills(ctx, "ghost", []string{"go"}, nil)
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+	})
+
+	t.Run("team keeps skills on re-add and import", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		_, err := repos.User.SetSkills(ctx, "u2", []string{"go"})
+		require.NoError(t, err)
+
+		addTeam(t, repos, "platform", member("u2", "platform", true))
+		_, err = repos.Team.ImportTeams(ctx, []domain.TeamWithUsers{
+			{TeamName: "platform", Members: []domain.User{member("u2", "", true), member("u3", "", true)}},
+		}, false)
+		require.NoError(t, err)
+
+		got, err := repos.Team.GetTeamWithMembers(ctx, "platform")
+		require.NoError(t, err)
+		assert.Equal(t, []domain.User{skilled("u2", "platform", "go"), member("u3", "platform", true)}, got.Members)
+	})
+
+	t.Run("create covers required tags", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+			member("u5", "backend", true),
+		)
+		for id, skills := range map[string][]string{"u2": {"go"}, "u3": {"go", "sql"}, "u4": {"k8s"}} {
+			_, err := repos.User.SetSkills(ctx, id, skills)
+			require.NoError(t, err)
+		}
+
+		for i := range 5 {
+			pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+				ID: fmt.Sprintf("pr%d", i), Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen,
+				RequiredTags: []string{"sql", "k8s", "go", "sql"},
+			}, int64(i))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"u3", "u4"}, pr.AssignedReviewers)
+			assert.Equal(t, []string{"go", "k8s", "sql"}, pr.RequiredTags)
+		}
+
+		got, err := repos.PullRequest.GetByID(ctx, "pr0")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "k8s", "sql"}, got.RequiredTags)
+		batch, err := repos.PullRequest.GetByIDs(ctx, []string{"pr1"})
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		assert.Equal(t, []string{"go", "k8s", "sql"}, batch[0].RequiredTags)
+
+		// тега нет ни у кого в команде: второе место занимает кто угодно
+		pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+			ID: "rust", Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen, RequiredTags: []string{"k8s", "rust"},
+		}, 1)
+		require.NoError(t, err)
+		assert.Len(t, pr.AssignedReviewers, 2)
+		assert.Contains(t, pr.AssignedReviewers, "u4")
+	})
+
+	t.Run("reassign keeps coverage", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+			member("u5", "backend", true),
+		)
+		for id, skills := range map[string][]string{"u2": {"go"}, "u3": {"sql"}, "u4": {"sql"}} {
+			_, err := repos.User.SetSkills(ctx, id, skills)
+			require.NoError(t, err)
+		}
+
+		for i := range 5 {
+			id := fmt.Sprintf("pr%d", i)
+			pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+				ID: id, Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen, RequiredTags: []string{"go", "sql"},
+			}, int64(i))
+			require.NoError(t, err)
+			require.Contains(t, pr.AssignedReviewers, "u2")
+
+			var sqlReviewer, other string
+			for _, r := range pr.AssignedReviewers {
+				if r != "u2" {
+					sqlReviewer = r
+				}
+			}
+			if sqlReviewer == "u3" {
+				other = "u4"
+			} else {
+				other = "u3"
+			}
+			got, err := repos.PullRequest.ReassignReviewer(ctx, id, sqlReviewer, 0, time.Now(), int64(i))
+			require.NoError(t, err)
+			assert.Equal(t, other, got.ID)
+		}
+	})
+}
+
+func setLevels(t *testing.T, repos Repositories, levels map[string]domain.Level) {
+	t.Helper()
+	for id, level := range levels {
+		_, err := repos.User.SetLevel(context.Background(), id, level)
+		require.NoError(t, err)
+	}
+}
+
+func testReviewerRules(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	seniorRule := []domain.ReviewerRule{{MinLevel: domain.LevelSenior, Count: 1}}
+
+	t.Run("levels", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+
+		u, err := repos.User.SetLevel(ctx, "u1", domain.LevelSenior)
+		require.NoError(t, err)
+		assert.Equal(t, domain.LevelSenior, u.Level)
+		_, err = repos.User.SetLevel(ctx, "ghost", domain.LevelSenior)
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+
+		// повторное добавление в команду уровень не сбрасывает
+		addTeam(t, repos, "platform", member("u1", "platform", true))
+		got, err := repos.Team.GetTeamsWithMembers(ctx, []string{"platform"})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		want := member("u1", "platform", true)
+		want.Level = domain.LevelSenior
+		assert.Equal(t, []domain.User{want}, got[0].Members)
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true))
+
+		rules, err := repos.Team.GetReviewerRules(ctx, "backend")
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+
+		want := []domain.ReviewerRule{{MinLevel: domain.LevelLead, Count: 1}, {MinLevel: domain.LevelMiddle, Count: 2}}
+		rules, err = repos.Team.SetReviewerRules(ctx, "backend", []domain.ReviewerRule{
+			{MinLevel: domain.LevelMiddle, Count: 2}, {MinLevel: domain.LevelLead, Count: 1},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, want, rules)
+		rules, err = repos.Team.GetReviewerRules(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, want, rules)
+
+		// набор заменяется целиком, пустой снимает правила
+		_, err = repos.Team.SetReviewerRules(ctx, "backend", nil)
+		require.NoError(t, err)
+		rules, err = repos.Team.GetReviewerRules(ctx, "backend")
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+
+		_, err = repos.Team.SetReviewerRules(ctx, "ghost", seniorRule)
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+		_, err = repos.Team.GetReviewerRules(ctx, "ghost")
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+	})
+
+	t.Run("create assigns a senior", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+			member("u5", "backend", true),
+		)
+		setLevels(t, repos, map[string]domain.Level{"u2": domain.LevelJunior, "u3": domain.LevelMiddle, "u5": domain.LevelLead})
+		_, err := repos.Team.SetReviewerRules(ctx, "backend", seniorRule)
+		require.NoError(t, err)
+
+		for i := range 5 {
+			pr := createPR(t, repos, fmt.Sprintf("pr%d", i), "u1")
+			assert.Len(t, pr.AssignedReviewers, 2)
+			assert.Contains(t, pr.AssignedReviewers, "u5")
+		}
+
+		// единственный lead — автор: правило не выполнить, PR не создается
+		_, err = repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+			ID: "by-lead", Name: "name", AuthorID: "u5", Status: domain.PRStatusOpen,
+		}, 1)
+		assert.ErrorIs(t, err, repository.ErrReviewerRulesUnmet)
+		_, err = repos.PullRequest.GetByID(ctx, "by-lead")
+		assert.ErrorIs(t, err, repository.ErrPullRequestNotFound)
+
+		// существующий PR важнее правил
+		_, err = repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+			ID: "pr0", Name: "name", AuthorID: "u5", Status: domain.PRStatusOpen,
+		}, 1)
+		assert.ErrorIs(t, err, repository.ErrPullRequestExists)
+	})
+
+	t.Run("reassign keeps a senior", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+		)
+		setLevels(t, repos, map[string]domain.Level{"u2": domain.LevelSenior, "u3": domain.LevelSenior, "u4": domain.LevelJunior})
+		_, err := repos.Team.SetReviewerRules(ctx, "backend", []domain.ReviewerRule{{MinLevel: domain.LevelSenior, Count: 2}})
+		require.NoError(t, err)
+
+		pr := createPR(t, repos, "pr1", "u1")
+		assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+		// заменить senior можно только на senior, а другого нет
+		_, err = repos.PullRequest.ReassignReviewer(ctx, "pr1", "u2", 0, time.Now(), 1)
+		assert.ErrorIs(t, err, repository.ErrReviewerRulesUnmet)
+
+		setLevels(t, repos, map[string]domain.Level{"u4": domain.LevelLead})
+		got, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", "u2", 0, time.Now(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "u4", got.ID)
+		// неудачная попытка версию не меняла
+		assert.Equal(t, int64(2), got.PullRequestVersion)
+	})
+}
+
+func createPRAt(t *testing.T, repos Repositories, id, authorID string, at time.Time) *domain.PullRequestWithReviewers {
+	t.Helper()
+	pr, err := repos.PullRequest.CreateWithReviewers(context.Background(), domain.PullRequest{
+		ID:        id,
+		Name:      "name-" + id,
+		AuthorID:  authorID,
+		Status:    domain.PRStatusOpen,
+		CreatedAt: at,
+	}, 1)
+	require.NoError(t, err)
+	return pr
+}
+
+func testPairing(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	t.Run("settings", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true))
+
+		settings, err := repos.Team.GetPairingSettings(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DefaultPairingSettings(), *settings)
+
+		want := domain.PairingSettings{Strategy: domain.PairingDiversity, DecayDays: 7}
+		settings, err = repos.Team.SetPairingSettings(ctx, "backend", want)
+		require.NoError(t, err)
+		assert.Equal(t, want, *settings)
+		settings, err = repos.Team.GetPairingSettings(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, want, *settings)
+
+		_, err = repos.Team.SetPairingSettings(ctx, "ghost", want)
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+		_, err = repos.Team.GetPairingSettings(ctx, "ghost")
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+		_, err = repos.Team.GetPairings(ctx, "ghost", now)
+		assert.ErrorIs(t, err, repository.ErrTeamNotFound)
+	})
+
+	t.Run("diversity prefers new pairs", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true),
+			member("u2", "backend", true),
+			member("u3", "backend", true),
+			member("u4", "backend", true),
+		)
+		_, err := repos.Team.SetPairingSettings(ctx, "backend", domain.PairingSettings{Strategy: domain.PairingDiversity, DecayDays: 30})
+		require.NoError(t, err)
+
+		// из трех кандидатов двое только что ревьюили u1, третий обязательно попадает в следующий PR
+		for i := range 3 {
+			prev := createPRAt(t, repos, fmt.Sprintf("prev%d", i), "u1", now.Add(time.Duration(i)*day))
+			next := createPRAt(t, repos, fmt.Sprintf("next%d", i), "u1", now.Add(time.Duration(i)*day+time.Hour))
+			for _, id := range []string{"u2", "u3", "u4"} {
+				if !slices.Contains(prev.AssignedReviewers, id) {
+					assert.Contains(t, next.AssignedReviewers, id)
+				}
+			}
+		}
+	})
+
+	t.Run("pairings", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		addTeam(t, repos, "mobile", member("m1", "mobile", true), member("m2", "mobile", true))
+
+		createPRAt(t, repos, "old", "u1", now.Add(-40*day))
+		createPRAt(t, repos, "pr1", "u1", now.Add(-day))
+		createPRAt(t, repos, "pr2", "u2", now)
+		createPRAt(t, repos, "mobile", "m1", now)
+
+		pairings, err := repos.Team.GetPairings(ctx, "backend", now.Add(-30*day))
+		require.NoError(t, err)
+		require.Len(t, pairings, 2)
+		assert.Equal(t, "u1", pairings[0].AuthorID)
+		assert.Equal(t, "u2", pairings[0].ReviewerID)
+		assert.True(t, pairings[0].At.Equal(now.Add(-day)))
+		assert.Equal(t, "u2", pairings[1].AuthorID)
+		assert.Equal(t, "u1", pairings[1].ReviewerID)
+	})
+
+	t.Run("pairings after reassign", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true),
+			member("u3", "backend", true), member("u4", "backend", true),
+		)
+
+		pr := createPRAt(t, repos, "pr1", "u1", now.Add(-2*day))
+		old := pr.AssignedReviewers[0]
+		reviewer, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", old, 0, now.Add(-day), 1)
+		require.NoError(t, err)
+
+		// замененный ревьюер остается в истории со временем создания, новый — со временем замены
+		pairings, err := repos.Team.GetPairings(ctx, "backend", now.Add(-30*day))
+		require.NoError(t, err)
+		require.Len(t, pairings, 3)
+		at := make(map[string]time.Time)
+		for _, p := range pairings {
+			assert.Equal(t, "u1", p.AuthorID)
+			at[p.ReviewerID] = p.At
+		}
+		assert.True(t, at[old].Equal(now.Add(-2*day)))
+		assert.True(t, at[reviewer.ID].Equal(now.Add(-day)))
+		assert.True(t, pairings[2].At.Equal(now.Add(-day)))
+
+		pairings, err = repos.Team.GetPairings(ctx, "backend", now.Add(-36*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, pairings, 1)
+		assert.Equal(t, reviewer.ID, pairings[0].ReviewerID)
+	})
+}
+
+func testAssignments(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("same seed same reviewers", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true),
+			member("u4", "backend", true), member("u5", "backend", true), member("u6", "backend", true),
+		)
+
+		var first []string
+		for i := range 5 {
+			pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+				ID: fmt.Sprintf("pr%d", i), Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen,
+			}, 99)
+			require.NoError(t, err)
+			if i == 0 {
+				first = pr.AssignedReviewers
+			}
+			assert.Equal(t, first, pr.AssignedReviewers)
+		}
+	})
+
+	t.Run("recorded with seed and candidates", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true),
+			member("u4", "backend", true), member("u5", "backend", false),
+		)
+
+		pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+			ID: "pr1", Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen,
+		}, -7)
+		require.NoError(t, err)
+
+		assignments, err := repos.PullRequest.GetAssignments(ctx, "pr1")
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		created := assignments[0]
+		assert.Equal(t, domain.AssignmentCreate, created.Kind)
+		assert.Equal(t, int64(-7), created.Seed)
+		assert.Equal(t, domain.PairingRandom, created.Strategy)
+		assert.Empty(t, created.ReplacedUserID)
+		assert.Equal(t, pr.AssignedReviewers, created.Reviewers())
+		// автор и неактивный в кандидаты не попадают
+		require.Len(t, created.Candidates, 3)
+		for i, c := range created.Candidates {
+			assert.Equal(t, i+1, c.Rank)
+			assert.NotContains(t, []string{"u1", "u5"}, c.UserID)
+			if c.Chosen {
+				assert.Equal(t, domain.ReasonRank, c.Reason)
+			} else {
+				assert.Equal(t, domain.ReasonOutranked, c.Reason)
+			}
+		}
+
+		old := pr.AssignedReviewers[0]
+		reviewer, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", old, 0, time.Now(), 5)
+		require.NoError(t, err)
+
+		assignments, err = repos.PullRequest.GetAssignments(ctx, "pr1")
+		require.NoError(t, err)
+		require.Len(t, assignments, 2)
+		reassigned := assignments[1]
+		assert.Greater(t, reassigned.ID, created.ID)
+		assert.Equal(t, domain.AssignmentReassign, reassigned.Kind)
+		assert.Equal(t, old, reassigned.ReplacedUserID)
+		assert.Equal(t, int64(5), reassigned.Seed)
+		assert.Equal(t, []string{reviewer.ID}, reassigned.Reviewers())
+		require.Len(t, reassigned.Candidates, 1)
+
+		// неудачная замена ничего не записывает
+		_, err = repos.PullRequest.ReassignReviewer(ctx, "pr1", "u1", 0, time.Now(), 5)
+		assert.ErrorIs(t, err, repository.ErrReviewerNotAssigned)
+		assignments, err = repos.PullRequest.GetAssignments(ctx, "pr1")
+		require.NoError(t, err)
+		assert.Len(t, assignments, 2)
+	})
+
+	t.Run("seed survives member updates", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true),
+			member("u4", "backend", true), member("u5", "backend", true), member("u6", "backend", true),
+		)
+		before := createPRWithSeed(t, repos, "before", 42)
+
+		// изменение строки пользователя может поменять порядок строк в таблице, но не порядок кандидатов
+		for _, id := range []string{"u2", "u3", "u4"} {
+			_, err := repos.User.SetSkills(ctx, id, []string{"go"})
+			require.NoError(t, err)
+		}
+		after := createPRWithSeed(t, repos, "after", 42)
+
+		assert.Equal(t, before.AssignedReviewers, after.AssignedReviewers)
+		explainedBefore, err := repos.PullRequest.GetAssignments(ctx, "before")
+		require.NoError(t, err)
+		explainedAfter, err := repos.PullRequest.GetAssignments(ctx, "after")
+		require.NoError(t, err)
+		assert.Equal(t, candidateIDs(explainedBefore[0]), candidateIDs(explainedAfter[0]))
+	})
+
+	t.Run("missing pull request", func(t *testing.T) {
+		repos := newRepos(t)
+		_, err := repos.PullRequest.GetAssignments(ctx, "missing")
+		assert.ErrorIs(t, err, repository.ErrPullRequestNotFound)
+	})
+}
+
+func testPreview(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("same choice as create", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", false),
+			member("u4", "backend", true), member("u5", "backend", true), member("u6", "backend", true),
+		)
+		addAbsence(t, repos, domain.Absence{UserID: "u4", Kind: domain.AbsenceVacation, Starts: now.Add(-time.Hour), Ends: now.Add(time.Hour)})
+
+		for seed := range int64(5) {
+			preview, err := repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1", CreatedAt: now}, seed)
+			require.NoError(t, err)
+			assert.Equal(t, []domain.CandidateExclusion{
+				{UserID: "u1", Reason: domain.ExcludedAuthor},
+				{UserID: "u3", Reason: domain.ExcludedInactive},
+				{UserID: "u4", Reason: domain.ExcludedOutOfOffice},
+			}, preview.Excluded)
+			require.Len(t, preview.Candidates, 3)
+			assert.Equal(t, seed, preview.Seed)
+
+			pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{
+				ID: fmt.Sprintf("pr%d", seed), Name: "name", AuthorID: "u1", Status: domain.PRStatusOpen, CreatedAt: now,
+			}, seed)
+			require.NoError(t, err)
+			assert.Equal(t, pr.AssignedReviewers, preview.Reviewers)
+
+			// созданный PR ничего не меняет: стратегия RANDOM, история пар не учитывается
+			again, err := repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1", CreatedAt: now}, seed)
+			require.NoError(t, err)
+			assert.Equal(t, preview.Reviewers, again.Reviewers)
+		}
+	})
+
+	t.Run("writes nothing", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true), member("u3", "backend", true))
+
+		preview, err := repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1", RequiredTags: []string{"go", "go"}}, 1)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, preview.Reviewers)
+		assert.Equal(t, []string{"go"}, preview.RequiredTags)
+		assert.False(t, preview.At.IsZero())
+
+		prs, err := repos.User.GetReviewPullRequests(ctx, "u2")
+		require.NoError(t, err)
+		assert.Empty(t, prs)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+
+		_, err := repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "missing"}, 1)
+		assert.ErrorIs(t, err, repository.ErrUserNotFound)
+	})
+
+	t.Run("unmet rules are listed", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+
+		preview, err := repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Empty(t, preview.UnmetRules)
+
+		rule := domain.ReviewerRule{MinLevel: domain.LevelSenior, Count: 1}
+		_, err = repos.Team.SetReviewerRules(ctx, "backend", []domain.ReviewerRule{rule})
+		require.NoError(t, err)
+
+		// создание вернуло бы ошибку, а предпросмотр показывает выбор и что в нем не так
+		_, err = repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr1", Name: "pr1", AuthorID: "u1"}, 1)
+		assert.ErrorIs(t, err, repository.ErrReviewerRulesUnmet)
+		preview, err = repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u2"}, preview.Reviewers)
+		assert.Equal(t, []domain.ReviewerRule{rule}, preview.UnmetRules)
+	})
+}
+
+func testCapacity(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("full reviewers are excluded", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend",
+			member("u1", "backend", true), member("u2", "backend", true),
+			member("u3", "backend", true), member("u4", "backend", true),
+		)
+		limited := repos.LimitedPullRequest(1)
+
+		first, err := limited.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr1", Name: "pr1", AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		require.Len(t, first.AssignedReviewers, 2)
+		var free string
+		for _, id := range []string{"u2", "u3", "u4"} {
+			if !slices.Contains(first.AssignedReviewers, id) {
+				free = id
+			}
+		}
+
+		preview, err := limited.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{free}, preview.Reviewers)
+		for _, id := range first.AssignedReviewers {
+			assert.Contains(t, preview.Excluded, domain.CandidateExclusion{UserID: id, Reason: domain.ExcludedAtCapacity})
+		}
+
+		// без лимита загрузка не учитывается
+		preview, err = repos.PullRequest.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Len(t, preview.Reviewers, 2)
+		assert.Equal(t, []domain.CandidateExclusion{{UserID: "u1", Reason: domain.ExcludedAuthor}}, preview.Excluded)
+
+		second, err := limited.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr2", Name: "pr2", AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{free}, second.AssignedReviewers)
+
+		// замены не найти: остальные заняты, а назначенный на PR не может стать заменой
+		_, err = limited.ReassignReviewer(ctx, "pr2", free, 0, time.Now(), 1)
+		assert.ErrorIs(t, err, repository.ErrNoReplacementCandidate)
+	})
+
+	t.Run("merged reviews free capacity", func(t *testing.T) {
+		repos := newRepos(t)
+		addTeam(t, repos, "backend", member("u1", "backend", true), member("u2", "backend", true))
+		limited := repos.LimitedPullRequest(1)
+
+		_, err := limited.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr1", Name: "pr1", AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		preview, err := limited.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Empty(t, preview.Reviewers)
+
+		_, _, err = limited.Merge(ctx, "pr1", 0)
+		require.NoError(t, err)
+		preview, err = limited.PreviewReviewers(ctx, domain.PullRequest{AuthorID: "u1"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u2"}, preview.Reviewers)
+	})
+}
+
+func createPRWithSeed(t *testing.T, repos Repositories, id string, seed int64) *domain.PullRequestWithReviewers {
+	t.Helper()
+	pr, err := repos.PullRequest.CreateWithReviewers(context.Background(), domain.PullRequest{
+		ID: id, Name: "name-" + id, AuthorID: "u1", Status: domain.PRStatusOpen,
+	}, seed)
+	require.NoError(t, err)
+	return pr
+}
+
+func candidateIDs(a domain.Assignment) []string {
+	ids := make([]string, len(a.Candidates))
+	for i, c := range a.Candidates {
+		ids[i] = c.UserID
+	}
+	return ids
+}
@@ -0,0 +1,348 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"time"
+)
+
+type availabilityRepositorySQLite struct {
+	db *sql.DB
+}
+
+func NewAvailabilityRepositorySQLite(db *sql.DB) *availabilityRepositorySQLite {
+	return &availabilityRepositorySQLite{db: db}
+}
+
+func (r *availabilityRepositorySQLite) AddAbsence(ctx context.Context, absence domain.Absence) (*domain.Absence, error) {
+	const op = "repository.sqlite.availability.AddAbsence"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	// проверка до вставки: по ошибке внешнего ключа SQLite не скажет, какой ключ нарушен
+	if err := userExists(ctx, tx, absence.UserID); err != nil {
+		return nil, err
+	}
+
+	query := `
+        INSERT INTO user_absences (user_id, kind, starts_at, ends_at, weekdays)
+        VALUES (?, ?, ?, ?, ?)
+        RETURNING absence_id
+    `
+	err = tx.QueryRowContext(ctx, query, absence.UserID, absence.Kind, absence.Starts.UTC(), absence.Ends.UTC(), int(absence.Weekdays)).
+		Scan(&absence.ID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	absence.Starts = absence.Starts.UTC()
+	absence.Ends = absence.Ends.UTC()
+	return &absence, nil
+}
+
+// ListAbsences периоды пользователя по времени начала
+func (r *availabilityRepositorySQLite) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	const op = "repository.sqlite.availability.ListAbsences"
+
+	if err := userExists(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT absence_id, user_id, kind, starts_at, ends_at, weekdays, COALESCE(source_uid, '')
+        FROM user_absences
+        WHERE user_id = ?
+        ORDER BY starts_at, absence_id
+    `
+	absences, err := queryAbsences(ctx, r.db, query, userID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return absences, nil
+}
+
+func (r *availabilityRepositorySQLite) DeleteAbsence(ctx context.Context, userID string, absenceID int64) error {
+	const op = "repository.sqlite.availability.DeleteAbsence"
+
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM user_absences WHERE absence_id = ? AND user_id = ?`,
+		absenceID, userID,
+	)
+	if err != nil {
+		return repository.Internal(op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return repository.Internal(op, err)
+	}
+	if n == 0 {
+		// различаем "нет пользователя" и "нет такого периода у пользователя"
+		if err := userExists(ctx, r.db, userID); err != nil {
+			return err
+		}
+		return repository.ErrAbsenceNotFound
+	}
+	return nil
+}
+
+// ImportAbsences применяет импорт календаря одной транзакцией: период ищется по (user_id, source_uid),
+// поэтому повторная загрузка того же календаря обновляет периоды, а не дублирует их
+func (r *availabilityRepositorySQLite) ImportAbsences(ctx context.Context, imp domain.AbsenceImport) (*domain.AbsenceImportReport, error) {
+	const op = "repository.sqlite.availability.ImportAbsences"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	for _, userID := range imp.Users {
+		if err := userExists(ctx, tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &domain.AbsenceImportReport{}
+	for _, absence := range imp.Upsert {
+		stored, err := queryAbsences(ctx, tx, `
+            SELECT absence_id, user_id, kind, starts_at, ends_at, weekdays, COALESCE(source_uid, '')
+            FROM user_absences
+            WHERE user_id = ? AND source_uid = ?
+        `, absence.UserID, absence.UID)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+
+		switch {
+		case len(stored) == 0:
+			_, err = tx.ExecContext(ctx, `
+                INSERT INTO user_absences (user_id, kind, starts_at, ends_at, weekdays, source_uid)
+                VALUES (?, ?, ?, ?, ?, ?)
+            `, absence.UserID, absence.Kind, absence.Starts.UTC(), absence.Ends.UTC(), int(absence.Weekdays), absence.UID)
+			report.Created++
+		case stored[0].SamePeriod(absence):
+			report.Unchanged++
+		default:
+			_, err = tx.ExecContext(ctx, `
+                UPDATE user_absences SET kind = ?, starts_at = ?, ends_at = ?, weekdays = ?
+                WHERE absence_id = ?
+            `, absence.Kind, absence.Starts.UTC(), absence.Ends.UTC(), int(absence.Weekdays), stored[0].ID)
+			report.Updated++
+		}
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	for _, absence := range imp.Cancel {
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM user_absences WHERE user_id = ? AND source_uid = ?`,
+			absence.UserID, absence.UID,
+		)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		report.Deleted += int(n)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return report, nil
+}
+
+// ListAbsentReviews ревьюеры открытых PR, у которых в момент now идет отсутствие без дней недели
+func (r *availabilityRepositorySQLite) ListAbsentReviews(ctx context.Context, now time.Time) ([]domain.AbsentReview, error) {
+	const op = "repository.sqlite.availability.ListAbsentReviews"
+
+	query := `
+        SELECT DISTINCT r.pull_request_id, r.user_id
+        FROM pr_reviewers r
+        JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+        JOIN user_absences a ON a.user_id = r.user_id
+        WHERE p.status = 'OPEN'
+          AND a.weekdays = 0
+          AND a.starts_at <= ? AND a.ends_at > ?
+        ORDER BY r.pull_request_id, r.user_id
+    `
+	rows, err := r.db.QueryContext(ctx, query, now.UTC(), now.UTC())
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	reviews := []domain.AbsentReview{}
+	for rows.Next() {
+		var review domain.AbsentReview
+		if err := rows.Scan(&review.PullRequestID, &review.ReviewerID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return reviews, nil
+}
+
+// SetWorkingHours задает расписание пользователя, заменяя прежнее
+func (r *availabilityRepositorySQLite) SetWorkingHours(ctx context.Context, hours domain.WorkingHours) (*domain.WorkingHours, error) {
+	const op = "repository.sqlite.availability.SetWorkingHours"
+
+	// проверка до вставки: по ошибке внешнего ключа SQLite не скажет, какой ключ нарушен
+	if err := userExists(ctx, r.db, hours.UserID); err != nil {
+		return nil, err
+	}
+
+	query := `
+        INSERT INTO user_working_hours (user_id, timezone, start_minute, end_minute, weekdays)
+        VALUES (?, ?, ?, ?, ?)
+        ON CONFLICT (user_id) DO UPDATE
+        SET timezone = excluded.timezone, start_minute = excluded.start_minute,
+            end_minute = excluded.end_minute, weekdays = excluded.weekdays
+    `
+	if _, err := r.db.ExecContext(ctx, query, hours.UserID, hours.Timezone, hours.Start, hours.End, int(hours.Days)); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return &hours, nil
+}
+
+// GetWorkingHours расписание пользователя, nil — не задано
+func (r *availabilityRepositorySQLite) GetWorkingHours(ctx context.Context, userID string) (*domain.WorkingHours, error) {
+	const op = "repository.sqlite.availability.GetWorkingHours"
+
+	if err := userExists(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+
+	hours, err := queryWorkingHours(ctx, r.db, `
+        SELECT user_id, timezone, start_minute, end_minute, weekdays
+        FROM user_working_hours
+        WHERE user_id = ?
+    `, userID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	w, ok := hours[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &w, nil
+}
+
+// ListWorkingHours заданные расписания пользователей, неизвестные и без расписания пропускаются
+func (r *availabilityRepositorySQLite) ListWorkingHours(ctx context.Context, userIDs []string) (map[string]domain.WorkingHours, error) {
+	const op = "repository.sqlite.availability.ListWorkingHours"
+
+	if len(userIDs) == 0 {
+		return map[string]domain.WorkingHours{}, nil
+	}
+
+	in, args := inList(userIDs)
+	hours, err := queryWorkingHours(ctx, r.db, `
+        SELECT user_id, timezone, start_minute, end_minute, weekdays
+        FROM user_working_hours
+        WHERE user_id IN (`+in+`)
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return hours, nil
+}
+
+func userExists(ctx context.Context, q querier, userID string) error {
+	const op = "repository.sqlite.availability.userExists"
+
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE user_id = ?)`, userID).Scan(&exists); err != nil {
+		return repository.Internal(op, err)
+	}
+	if !exists {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+// absentInTeam участники команды, отсутствующие в момент now: их не назначают ревьюерами.
+// Дни недели проверяются в Go в часовом поясе из hours, как и в остальных хранилищах (domain.AbsentAt)
+func absentInTeam(ctx context.Context, q querier, teamName string, hours map[string]domain.WorkingHours, now time.Time) (map[string]bool, error) {
+	query := `
+        SELECT a.absence_id, a.user_id, a.kind, a.starts_at, a.ends_at, a.weekdays, COALESCE(a.source_uid, '')
+        FROM user_absences a
+        JOIN users u ON u.user_id = a.user_id
+        WHERE u.team_name = ? AND a.starts_at <= ? AND a.ends_at > ?
+    `
+	absences, err := queryAbsences(ctx, q, query, teamName, now.UTC(), now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return domain.AbsentAt(absences, hours, now), nil
+}
+
+func queryAbsences(ctx context.Context, q querier, query string, args ...any) ([]domain.Absence, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absences := []domain.Absence{}
+	for rows.Next() {
+		var (
+			a        domain.Absence
+			weekdays int
+		)
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Kind, &a.Starts, &a.Ends, &weekdays, &a.UID); err != nil {
+			return nil, err
+		}
+		a.Weekdays = domain.Weekdays(weekdays)
+		absences = append(absences, a)
+	}
+	return absences, rows.Err()
+}
+
+// workingHoursInTeam заданные расписания участников команды: по ним выбор ревьюеров предпочитает тех, кто сейчас работает
+func workingHoursInTeam(ctx context.Context, q querier, teamName string) (map[string]domain.WorkingHours, error) {
+	return queryWorkingHours(ctx, q, `
+        SELECT w.user_id, w.timezone, w.start_minute, w.end_minute, w.weekdays
+        FROM user_working_hours w
+        JOIN users u ON u.user_id = w.user_id
+        WHERE u.team_name = ?
+    `, teamName)
+}
+
+func queryWorkingHours(ctx context.Context, q querier, query string, args ...any) (map[string]domain.WorkingHours, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hours := make(map[string]domain.WorkingHours)
+	for rows.Next() {
+		var (
+			w    domain.WorkingHours
+			days int
+		)
+		if err := rows.Scan(&w.UserID, &w.Timezone, &w.Start, &w.End, &days); err != nil {
+			return nil, err
+		}
+		w.Days = domain.Weekdays(days)
+		hours[w.UserID] = w
+	}
+	return hours, rows.Err()
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"service-order-avito/internal/config"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ConnectSQLite открывает файл бд. Транзакции начинаются с BEGIN IMMEDIATE: блокировка на запись
+// берется сразу, поэтому create/reassign/merge сериализуются так же, как в Postgres,
+// а конкурирующая транзакция ждет до BusyTimeout
+func ConnectSQLite(ctx context.Context, cfg config.SQLiteStorage) (*sql.DB, error) {
+	const op = "repository.sqlite.ConnectSQLite"
+
+	db, err := sql.Open("sqlite", dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return db, nil
+}
+
+func dsn(cfg config.SQLiteStorage) string {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	q.Set("_txlock", "immediate")
+	q.Set("_time_format", "sqlite")
+
+	return "file:" + cfg.Path + "?" + q.Encode()
+}
+
+// isUniqueViolation аналог проверки кода 23505 в Postgres
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		return true
+	}
+	return false
+}
+
+// inList плейсхолдеры и аргументы для IN (...): в SQLite нет массивов, как в = ANY($1) у Postgres.
+// Вызывающий проверяет, что values не пустой
+func inList(values []string) (string, []any) {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "), args
+}
+
+// tagList теги в колонке TEXT через запятую (skills, required_tags): массивов в SQLite нет.
+// Пустая строка и NULL (LEFT JOIN) читаются как nil
+type tagList []string
+
+func (t *tagList) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("tagList: unsupported type %T", src)
+	}
+	*t = nil
+	if s != "" {
+		*t = strings.Split(s, ",")
+	}
+	return nil
+}
+
+func (t tagList) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
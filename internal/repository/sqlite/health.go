@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type pingChecker struct {
+	db *sql.DB
+}
+
+func NewPingChecker(db *sql.DB) *pingChecker {
+	return &pingChecker{db: db}
+}
+
+func (c *pingChecker) Name() string {
+	return "sqlite"
+}
+
+func (c *pingChecker) Check(ctx context.Context) (map[string]any, error) {
+	stat := c.db.Stats()
+	details := map[string]any{
+		"open_conns":   stat.OpenConnections,
+		"idle_conns":   stat.Idle,
+		"in_use_conns": stat.InUse,
+	}
+
+	if err := c.db.PingContext(ctx); err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+type schemaChecker struct {
+	db       *sql.DB
+	expected int64
+}
+
+func NewSchemaChecker(db *sql.DB, expected int64) *schemaChecker {
+	return &schemaChecker{db: db, expected: expected}
+}
+
+func (c *schemaChecker) Name() string {
+	return "schema"
+}
+
+func (c *schemaChecker) Check(ctx context.Context) (map[string]any, error) {
+	details := map[string]any{"expected": c.expected}
+
+	current, err := CurrentSchemaVersion(ctx, c.db)
+	if err != nil {
+		return details, err
+	}
+	details["current"] = current
+
+	if current < c.expected {
+		return details, fmt.Errorf("%w: current %d, expected %d", ErrSchemaOutdated, current, c.expected)
+	}
+	return details, nil
+}
+
+// CurrentSchemaVersion возвращает последнюю примененную миграцию из таблицы goose.
+// Для каждой версии берется последняя запись: откат миграции пишет строку с is_applied = false
+func CurrentSchemaVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	const op = "repository.sqlite.CurrentSchemaVersion"
+
+	query := `
+        SELECT COALESCE(MAX(g.version_id), 0)
+        FROM goose_db_version g
+        WHERE g.is_applied
+          AND g.id = (SELECT MAX(id) FROM goose_db_version WHERE version_id = g.version_id)
+    `
+
+	var version int64
+	if err := db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, nil
+}
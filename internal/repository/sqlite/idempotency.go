@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"time"
+)
+
+type idempotencyRepositorySQLite struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepositorySQLite(db *sql.DB) *idempotencyRepositorySQLite {
+	return &idempotencyRepositorySQLite{db: db}
+}
+
+// Reserve сохраняет ключ как "в обработке" и возвращает nil.
+// Если живой (не истекший на key.CreatedAt) ключ уже есть, возвращает его запись, ничего не меняя
+func (r *idempotencyRepositorySQLite) Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	const op = "repository.sqlite.idempotency.Reserve"
+
+	// BEGIN IMMEDIATE: проверка и вставка атомарны относительно других запросов с тем же ключом
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	// время хранится строкой, поэтому всегда в UTC, иначе сравнение по строкам неверно
+	createdAt, expiresAt := key.CreatedAt.UTC(), key.ExpiresAt.UTC()
+
+	queryGet := `
+        SELECT idempotency_key, fingerprint, status_code, content_type, etag, response_body, created_at, expires_at
+        FROM idempotency_keys
+        WHERE idempotency_key = ? AND expires_at > ?
+    `
+	var (
+		existing    domain.IdempotencyKey
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+		etag        sql.NullString
+		body        []byte
+	)
+	err = tx.QueryRowContext(ctx, queryGet, key.Key, createdAt).Scan(
+		&existing.Key, &existing.Fingerprint, &statusCode, &contentType, &etag, &body, &existing.CreatedAt, &existing.ExpiresAt,
+	)
+	switch {
+	case err == nil:
+		if statusCode.Valid {
+			existing.Response = &domain.IdempotentResponse{
+				StatusCode:  int(statusCode.Int64),
+				ContentType: contentType.String,
+				ETag:        etag.String,
+				Body:        body,
+			}
+		}
+		return &existing, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, repository.Internal(op, err)
+	}
+
+	// истекший ключ перезаписывается, как будто его не было
+	queryReserve := `
+        INSERT OR REPLACE INTO idempotency_keys (idempotency_key, fingerprint, created_at, expires_at)
+        VALUES (?, ?, ?, ?)
+    `
+	if _, err = tx.ExecContext(ctx, queryReserve, key.Key, key.Fingerprint, createdAt, expiresAt); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return nil, nil
+}
+
+func (r *idempotencyRepositorySQLite) Complete(ctx context.Context, key string, resp domain.IdempotentResponse) error {
+	const op = "repository.sqlite.idempotency.Complete"
+
+	query := `
+        UPDATE idempotency_keys
+        SET status_code = ?, content_type = ?, etag = ?, response_body = ?
+        WHERE idempotency_key = ?
+    `
+	if _, err := r.db.ExecContext(ctx, query, resp.StatusCode, resp.ContentType, resp.ETag, resp.Body, key); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+// Release удаляет ключ, по которому не удалось получить ответ: повтор выполнится заново
+func (r *idempotencyRepositorySQLite) Release(ctx context.Context, key string) error {
+	const op = "repository.sqlite.idempotency.Release"
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE idempotency_key = ?`, key); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepositorySQLite) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "repository.sqlite.idempotency.DeleteExpired"
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, repository.Internal(op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, repository.Internal(op, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pressly/goose/v3"
+	"io/fs"
+	"log/slog"
+)
+
+var ErrSchemaOutdated = errors.New("database schema is older than expected")
+
+// Migrator применяет встроенные миграции SQLite (migrations.SQLite) к файлу бд
+type Migrator struct {
+	db       *sql.DB
+	provider *goose.Provider
+}
+
+func NewMigrator(db *sql.DB, migrations fs.FS, log *slog.Logger) (*Migrator, error) {
+	const op = "repository.sqlite.NewMigrator"
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations,
+		goose.WithSlog(log.With(slog.String("component", "migrator"))),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{
+		db:       db,
+		provider: provider,
+	}, nil
+}
+
+// Latest версия последней встроенной миграции — та схема, с которой работает этот бинарник
+func (m *Migrator) Latest() int64 {
+	sources := m.provider.ListSources()
+	if len(sources) == 0 {
+		return 0
+	}
+	return sources[len(sources)-1].Version
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	const op = "repository.sqlite.Migrator.Up"
+
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("%s: %w", op, err)
+	}
+	return results, nil
+}
+
+// Down откатывает одну последнюю миграцию
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	const op = "repository.sqlite.Migrator.Down"
+
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+	return result, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	const op = "repository.sqlite.Migrator.Status"
+
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return statuses, nil
+}
+
+// Version возвращает текущую версию схемы в бд
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	const op = "repository.sqlite.Migrator.Version"
+
+	version, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, nil
+}
+
+// CheckVersion возвращает ErrSchemaOutdated, если в бд применены не все встроенные миграции
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	const op = "repository.sqlite.Migrator.CheckVersion"
+
+	current, err := CurrentSchemaVersion(ctx, m.db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if current < m.Latest() {
+		return fmt.Errorf("%s: %w: current %d, expected %d", op, ErrSchemaOutdated, current, m.Latest())
+	}
+	return nil
+}
@@ -0,0 +1,815 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"sort"
+	"strings"
+	"time"
+)
+
+const MAX_REVIEWERS = 2
+
+// userTxReader и teamTxReader — то, что репозиторию PR нужно от соседних репозиториев внутри своей транзакции
+type userTxReader interface {
+	GetByIDTx(ctx context.Context, tx *sql.Tx, userID string) (*domain.User, error)
+}
+
+type teamTxReader interface {
+	GetTeamWithMembersTx(ctx context.Context, tx *sql.Tx, teamName string) (*domain.TeamWithUsers, error)
+}
+
+// querier общее у *sql.Tx и *sql.DB: чтение PR и внутри транзакции, и без нее
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type pullRequestRepositorySQLite struct {
+	db       *sql.DB
+	teamRepo teamTxReader
+	userRepo userTxReader
+	// maxOpenReviews сколько открытых PR может ревьюить один пользователь, 0 — без ограничения
+	maxOpenReviews int
+}
+
+func NewPullRequestRepositorySQLite(db *sql.DB, teamRepo teamTxReader, userRepo userTxReader, maxOpenReviews int) *pullRequestRepositorySQLite {
+	return &pullRequestRepositorySQLite{
+		db:             db,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		maxOpenReviews: maxOpenReviews,
+	}
+}
+
+// CreateWithReviewers seed — зерно генератора выбора ревьюеров, сохраняется вместе с объяснением выбора
+func (r *pullRequestRepositorySQLite) CreateWithReviewers(ctx context.Context, pr domain.PullRequest, seed int64) (*domain.PullRequestWithReviewers, error) {
+	const op = "repository.sqlite.pullRequest.CreateWithReviewers"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	// после Commit откат ничего не делает, на ранних return откатываем транзакцию
+	defer tx.Rollback()
+
+	pool, _, err := r.createPool(ctx, tx, &pr)
+	if err != nil {
+		return nil, err
+	}
+
+	activeMembers, explained := pool.Explain(MAX_REVIEWERS, domain.NewRand(seed))
+
+	// время пишется из Go: в SQLite нет NOW() с точностью, достаточной для сортировки
+	queryCreatePR := `
+        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, required_tags)
+        VALUES (?, ?, ?, 'OPEN', ?, ?)
+    `
+
+	pr.CreatedAt = pr.CreatedAt.UTC()
+	_, err = tx.ExecContext(ctx, queryCreatePR, pr.ID, pr.Name, pr.AuthorID, pr.CreatedAt, tagList(pr.RequiredTags))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, repository.ErrPullRequestExists
+		}
+		return nil, repository.Internal(op, err)
+	}
+
+	// после вставки: для существующего PR ответ PR_EXISTS, а не ошибка правил. Транзакция откатится
+	if len(pool.Unmet(activeMembers)) > 0 {
+		return nil, repository.ErrReviewerRulesUnmet
+	}
+
+	querySetReviewers := `
+            INSERT INTO pr_reviewers (pull_request_id, user_id)
+            VALUES (?, ?)
+        `
+
+	for _, reviewerID := range activeMembers {
+		if _, err = tx.ExecContext(ctx, querySetReviewers, pr.ID, reviewerID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	assignment := domain.Assignment{
+		PullRequestID: pr.ID,
+		Kind:          domain.AssignmentCreate,
+		Seed:          seed,
+		Strategy:      pool.Strategy(),
+		At:            pr.CreatedAt,
+		Candidates:    explained,
+	}
+	if err = insertAssignment(ctx, tx, assignment); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	pr.Status = domain.PRStatusOpen
+	pr.Version = 1
+
+	return &domain.PullRequestWithReviewers{
+		PullRequest:       pr,
+		AssignedReviewers: activeMembers,
+	}, nil
+}
+
+// PreviewReviewers выбор, который сделал бы CreateWithReviewers с тем же seed, без записи.
+// Невыполнимые правила команды не ошибка, как при создании, а список UnmetRules
+func (r *pullRequestRepositorySQLite) PreviewReviewers(ctx context.Context, pr domain.PullRequest, seed int64) (*domain.ReviewerPreview, error) {
+	const op = "repository.sqlite.pullRequest.PreviewReviewers"
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	// только чтение: транзакция нужна, чтобы участники, отсутствия и история пар были согласованы
+	defer tx.Rollback()
+
+	pool, excluded, err := r.createPool(ctx, tx, &pr)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, explained := pool.Explain(MAX_REVIEWERS, domain.NewRand(seed))
+
+	return &domain.ReviewerPreview{
+		AuthorID:     pr.AuthorID,
+		RequiredTags: pr.RequiredTags,
+		Reviewers:    reviewers,
+		Seed:         seed,
+		Strategy:     pool.Strategy(),
+		At:           pr.CreatedAt,
+		Candidates:   explained,
+		Excluded:     excluded,
+		UnmetRules:   pool.Unmet(reviewers),
+	}, nil
+}
+
+// createPool кандидаты в ревьюеры нового PR и исключенные участники команды автора, в транзакции tx.
+// Заполняет у pr момент создания и нормализует теги
+func (r *pullRequestRepositorySQLite) createPool(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) (domain.ReviewerPool, []domain.CandidateExclusion, error) {
+	const op = "repository.sqlite.pullRequest.createPool"
+
+	author, err := r.userRepo.GetByIDTx(ctx, tx, pr.AuthorID)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+
+	teamWithMembers, err := r.teamRepo.GetTeamWithMembersTx(ctx, tx, author.TeamName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+
+	// момент создания задает сервис: по нему считаются отсутствия и рабочее время
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now()
+	}
+
+	hours, err := workingHoursInTeam(ctx, tx, author.TeamName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	// дни недели периодов отсутствия считаются в часовом поясе расписания пользователя
+	absent, err := absentInTeam(ctx, tx, author.TeamName, hours, pr.CreatedAt)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	atCapacity, err := atCapacityInTeam(ctx, tx, author.TeamName, r.maxOpenReviews)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	rules, err := reviewerRules(ctx, tx, author.TeamName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+	recent, err := recentPairings(ctx, tx, author.TeamName, pr.AuthorID, pr.CreatedAt)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, repository.Internal(op, err)
+	}
+
+	// Случайный выбор ревьюеров среди активных и не отсутствующих сейчас, в первую очередь — покрывающих теги PR
+	// и у кого рабочее время
+	pr.RequiredTags = domain.NormalizeTags(pr.RequiredTags)
+	pool := domain.ReviewerPool{
+		Hours:    hours,
+		Now:      pr.CreatedAt,
+		Skills:   domain.SkillsOf(teamWithMembers.Members),
+		Required: pr.RequiredTags,
+		Levels:   domain.LevelsOf(teamWithMembers.Members),
+		Rules:    rules,
+		Recent:   recent,
+	}
+	var excluded []domain.CandidateExclusion
+	pool.Candidates, excluded = domain.SplitCandidates(teamWithMembers.Members, pr.AuthorID, atCapacity, absent)
+	return pool, excluded, nil
+}
+
+// atCapacityInTeam участники команды, у которых открытых ревью не меньше limit. limit <= 0 — nil, без запроса
+func atCapacityInTeam(ctx context.Context, q querier, teamName string, limit int) (map[string]bool, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	query := `
+        SELECT r.user_id, COUNT(*)
+        FROM pr_reviewers r
+        JOIN pull_requests p ON p.pull_request_id = r.pull_request_id
+        JOIN users u ON u.user_id = r.user_id
+        WHERE u.team_name = ? AND p.status = 'OPEN'
+        GROUP BY r.user_id
+    `
+	rows, err := q.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	open := make(map[string]int)
+	for rows.Next() {
+		var (
+			userID string
+			n      int
+		)
+		if err := rows.Scan(&userID, &n); err != nil {
+			return nil, err
+		}
+		open[userID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.AtCapacity(open, limit), nil
+}
+
+func (r *pullRequestRepositorySQLite) GetByID(ctx context.Context, prID string) (*domain.PullRequestWithReviewers, error) {
+	return r.getPRWithReviewers(ctx, r.db, prID)
+}
+
+// GetByIDs PR с ревьюерами по списку id двумя запросами (для батчевой загрузки в GraphQL).
+// Несуществующие id пропускаются
+func (r *pullRequestRepositorySQLite) GetByIDs(ctx context.Context, prIDs []string) ([]domain.PullRequestWithReviewers, error) {
+	const op = "repository.sqlite.pullRequest.GetByIDs"
+
+	prs := []domain.PullRequestWithReviewers{}
+	if len(prIDs) == 0 {
+		return prs, nil
+	}
+
+	in, args := inList(prIDs)
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags
+        FROM pull_requests
+        WHERE pull_request_id IN (`+in+`)
+        ORDER BY pull_request_id
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	index := make(map[string]int)
+	for rows.Next() {
+		var pr domain.PullRequest
+		var mergedAt sql.NullTime
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &mergedAt, &pr.Version, (*tagList)(&pr.RequiredTags)); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		index[pr.ID] = len(prs)
+		prs = append(prs, domain.PullRequestWithReviewers{PullRequest: pr, AssignedReviewers: []string{}})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rows.Close()
+
+	reviewerRows, err := r.db.QueryContext(ctx, `
+        SELECT pull_request_id, user_id
+        FROM pr_reviewers
+        WHERE pull_request_id IN (`+in+`)
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer reviewerRows.Close()
+
+	for reviewerRows.Next() {
+		var prID, userID string
+		if err := reviewerRows.Scan(&prID, &userID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		if i, ok := index[prID]; ok {
+			prs[i].AssignedReviewers = append(prs[i].AssignedReviewers, userID)
+		}
+	}
+	if err := reviewerRows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return prs, nil
+}
+
+// Merge помечает PR как MERGED. expectedVersion — версия из If-Match, 0 — без проверки.
+// merged false, если PR уже был MERGED и ничего не изменилось (повторный merge)
+func (r *pullRequestRepositorySQLite) Merge(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequestWithReviewers, bool, error) {
+	const op = "repository.sqlite.pullRequest.Merge"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	existing, err := r.getPRWithReviewers(ctx, tx, prID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !versionMatches(existing.Version, expectedVersion) {
+		return nil, false, repository.ErrVersionMismatch
+	}
+
+	if existing.Status == domain.PRStatusClosed {
+		return nil, false, repository.ErrPullRequestClosed
+	}
+
+	// проверка для идемпотентности
+	if existing.Status == domain.PRStatusMerged {
+		if err = tx.Commit(); err != nil {
+			return nil, false, repository.Internal(op, err)
+		}
+		return existing, false, nil
+	}
+
+	queryMerge := `
+        UPDATE pull_requests
+        SET status = 'MERGED', merged_at = ?, version = version + 1
+        WHERE pull_request_id = ?
+    `
+	if _, err = tx.ExecContext(ctx, queryMerge, time.Now().UTC(), prID); err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+
+	updated, err := r.getPRWithReviewers(ctx, tx, prID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+
+	return updated, true, nil
+}
+
+// Close помечает PR как CLOSED. expectedVersion — версия из If-Match, 0 — без проверки.
+// closed false, если PR уже был CLOSED и ничего не изменилось (повторный close)
+func (r *pullRequestRepositorySQLite) Close(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequestWithReviewers, bool, error) {
+	const op = "repository.sqlite.pullRequest.Close"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	existing, err := r.getPRWithReviewers(ctx, tx, prID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !versionMatches(existing.Version, expectedVersion) {
+		return nil, false, repository.ErrVersionMismatch
+	}
+
+	if existing.Status == domain.PRStatusMerged {
+		return nil, false, repository.ErrPullRequestMerged
+	}
+
+	// проверка для идемпотентности
+	if existing.Status == domain.PRStatusClosed {
+		if err = tx.Commit(); err != nil {
+			return nil, false, repository.Internal(op, err)
+		}
+		return existing, false, nil
+	}
+
+	queryClose := `
+        UPDATE pull_requests
+        SET status = 'CLOSED', version = version + 1
+        WHERE pull_request_id = ?
+    `
+	if _, err = tx.ExecContext(ctx, queryClose, prID); err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+
+	updated, err := r.getPRWithReviewers(ctx, tx, prID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, repository.Internal(op, err)
+	}
+
+	return updated, true, nil
+}
+
+// getPRWithReviewers читает PR с ревьюерами. Аналога FOR UPDATE нет и он не нужен:
+// транзакции на запись начинаются с BEGIN IMMEDIATE и уже сериализованы
+func (r *pullRequestRepositorySQLite) getPRWithReviewers(ctx context.Context, q querier, prID string) (*domain.PullRequestWithReviewers, error) {
+	const op = "repository.sqlite.pullRequest.getPRWithReviewers"
+
+	queryGetPR := `
+        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags
+        FROM pull_requests
+        WHERE pull_request_id = ?
+    `
+	var pr domain.PullRequest
+	var mergedAt sql.NullTime
+
+	err := q.QueryRowContext(ctx, queryGetPR, prID).Scan(
+		&pr.ID,
+		&pr.Name,
+		&pr.AuthorID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&mergedAt,
+		&pr.Version,
+		(*tagList)(&pr.RequiredTags),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrPullRequestNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+	if mergedAt.Valid {
+		pr.MergedAt = &mergedAt.Time
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT user_id FROM pr_reviewers WHERE pull_request_id = ?`, prID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	reviewers := []string{}
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		reviewers = append(reviewers, uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return &domain.PullRequestWithReviewers{
+		PullRequest:       pr,
+		AssignedReviewers: reviewers,
+	}, nil
+}
+
+// ReassignReviewer заменяет ревьюера. expectedVersion — версия из If-Match, 0 — без проверки; now — момент замены;
+// seed — зерно генератора выбора, сохраняется вместе с объяснением выбора
+func (r *pullRequestRepositorySQLite) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, expectedVersion int64, now time.Time, seed int64) (*domain.Reviewer, error) {
+	const op = "repository.sqlite.pullRequest.ReassignReviewer"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	pr, err := r.getPRWithReviewers(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !versionMatches(pr.Version, expectedVersion) {
+		return nil, repository.ErrVersionMismatch
+	}
+
+	// проверка на MERGED и CLOSED
+	if pr.Status == domain.PRStatusMerged {
+		return nil, repository.ErrPullRequestMerged
+	}
+	if pr.Status == domain.PRStatusClosed {
+		return nil, repository.ErrPullRequestClosed
+	}
+
+	// проверка есть ли вообще такой пользователь
+	oldUser, err := r.userRepo.GetByIDTx(ctx, tx, oldReviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// проверка, что oldReviewer действительно был назначен
+	assigned := make(map[string]struct{}, len(pr.AssignedReviewers))
+	for _, uid := range pr.AssignedReviewers {
+		assigned[uid] = struct{}{}
+	}
+	if _, ok := assigned[oldReviewerID]; !ok {
+		return nil, repository.ErrReviewerNotAssigned
+	}
+
+	team, err := r.teamRepo.GetTeamWithMembersTx(ctx, tx, oldUser.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	// уже назначенные ревьюеры не могут стать заменой: (pull_request_id, user_id) — первичный ключ pr_reviewers
+	hours, err := workingHoursInTeam(ctx, tx, oldUser.TeamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	// дни недели периодов отсутствия считаются в часовом поясе расписания пользователя
+	absent, err := absentInTeam(ctx, tx, oldUser.TeamName, hours, now)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	atCapacity, err := atCapacityInTeam(ctx, tx, oldUser.TeamName, r.maxOpenReviews)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rules, err := reviewerRules(ctx, tx, oldUser.TeamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	recent, err := recentPairings(ctx, tx, oldUser.TeamName, pr.AuthorID, now)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	// новый ревьюер должен покрыть теги и правила уровней, которые держались только на заменяемом
+	var remaining []string
+	for _, uid := range pr.AssignedReviewers {
+		if uid != oldReviewerID {
+			remaining = append(remaining, uid)
+		}
+	}
+	skills := domain.SkillsOf(team.Members)
+	levels := domain.LevelsOf(team.Members)
+	pool := domain.ReviewerPool{
+		Hours:    hours,
+		Now:      now,
+		Skills:   skills,
+		Required: domain.UncoveredTags(pr.RequiredTags, skills, remaining),
+		Levels:   levels,
+		Rules:    domain.UnmetRules(rules, levels, remaining),
+		Recent:   recent,
+	}
+	for _, u := range team.Members {
+		if _, ok := assigned[u.ID]; ok {
+			continue
+		}
+		if u.ID != pr.AuthorID && u.IsActive && !absent[u.ID] && !atCapacity[u.ID] {
+			pool.Candidates = append(pool.Candidates, u.ID)
+		}
+	}
+
+	// проверка на наличие кандидата
+	if len(pool.Candidates) == 0 {
+		return nil, repository.ErrNoReplacementCandidate
+	}
+
+	picked, explained := pool.Explain(1, domain.NewRand(seed))
+	newReviewer := picked[0]
+	if len(pool.Unmet([]string{newReviewer})) > 0 {
+		return nil, repository.ErrReviewerRulesUnmet
+	}
+
+	queryUpdate := `
+        UPDATE pr_reviewers
+        SET user_id = ?
+        WHERE pull_request_id = ? AND user_id = ?
+    `
+	if _, err = tx.ExecContext(ctx, queryUpdate, newReviewer, prID, oldReviewerID); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	var version int64
+	queryBumpVersion := `
+        UPDATE pull_requests
+        SET version = version + 1
+        WHERE pull_request_id = ?
+        RETURNING version
+    `
+	if err = tx.QueryRowContext(ctx, queryBumpVersion, prID).Scan(&version); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	assignment := domain.Assignment{
+		PullRequestID:  prID,
+		Kind:           domain.AssignmentReassign,
+		ReplacedUserID: oldReviewerID,
+		Seed:           seed,
+		Strategy:       pool.Strategy(),
+		At:             now,
+		Candidates:     explained,
+	}
+	if err = insertAssignment(ctx, tx, assignment); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return &domain.Reviewer{
+		ID:                 newReviewer,
+		PullRequestVersion: version,
+	}, nil
+}
+
+// versionMatches expected == 0 означает, что клиент не прислал If-Match
+func versionMatches(current, expected int64) bool {
+	return expected == 0 || current == expected
+}
+
+// Export отдает PR по фильтру по одному в fn, в порядке created_at. Строки читаются курсором sql.Rows
+// одним SELECT (в WAL это согласованный снимок без блокировки записи), все PR в памяти не держатся.
+// Ошибка fn (например, клиент отключился) возвращается как есть
+func (r *pullRequestRepositorySQLite) Export(ctx context.Context, filter domain.ExportFilter, fn func(domain.PullRequestExport) error) error {
+	const op = "repository.sqlite.pullRequest.Export"
+
+	if filter.TeamName != "" {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM teams WHERE team_name = ?)`, filter.TeamName).Scan(&exists); err != nil {
+			return repository.Internal(op, err)
+		}
+		if !exists {
+			return repository.ErrTeamNotFound
+		}
+	}
+
+	query := `
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at, pr.version,
+               COALESCE(u.team_name, ''), COALESCE(group_concat(r.user_id), '')
+        FROM pull_requests pr
+        LEFT JOIN users u ON u.user_id = pr.author_id
+        LEFT JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+        WHERE 1 = 1`
+	var args []any
+	if filter.TeamName != "" {
+		query += ` AND u.team_name = ?`
+		args = append(args, filter.TeamName)
+	}
+	// время хранится строкой в UTC, поэтому границы тоже переводятся в UTC
+	if filter.From != nil {
+		query += ` AND pr.created_at >= ?`
+		args = append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		query += ` AND pr.created_at < ?`
+		args = append(args, filter.To.UTC())
+	}
+	query += `
+        GROUP BY pr.pull_request_id
+        ORDER BY pr.created_at, pr.pull_request_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pr        domain.PullRequestExport
+			mergedAt  sql.NullTime
+			reviewers string
+		)
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &mergedAt, &pr.Version,
+			&pr.TeamName, &reviewers); err != nil {
+			return repository.Internal(op, err)
+		}
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		// запятая не встречается в id (правило id в dto), порядок group_concat не задан
+		pr.AssignedReviewers = []string{}
+		if reviewers != "" {
+			pr.AssignedReviewers = strings.Split(reviewers, ",")
+			sort.Strings(pr.AssignedReviewers)
+		}
+
+		if err := fn(pr); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+// GetAssignments выборы ревьюеров PR в порядке записи, кандидаты — по rank
+func (r *pullRequestRepositorySQLite) GetAssignments(ctx context.Context, prID string) ([]domain.Assignment, error) {
+	const op = "repository.sqlite.pullRequest.GetAssignments"
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = ?)`, prID).Scan(&exists); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	if !exists {
+		return nil, repository.ErrPullRequestNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT assignment_id, kind, COALESCE(replaced_user_id, ''), seed, strategy, assigned_at
+        FROM reviewer_assignments
+        WHERE pull_request_id = ?
+        ORDER BY assignment_id
+    `, prID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	assignments := []domain.Assignment{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		a := domain.Assignment{PullRequestID: prID}
+		if err := rows.Scan(&a.ID, &a.Kind, &a.ReplacedUserID, &a.Seed, &a.Strategy, &a.At); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		index[a.ID] = len(assignments)
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rows.Close()
+
+	candidateRows, err := r.db.QueryContext(ctx, `
+        SELECT c.assignment_id, c.user_id, c.rank, c.working, c.pairing_score, c.level, c.tags, c.chosen, c.reason
+        FROM reviewer_assignment_candidates c
+        JOIN reviewer_assignments a ON a.assignment_id = c.assignment_id
+        WHERE a.pull_request_id = ?
+        ORDER BY c.assignment_id, c.rank
+    `, prID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer candidateRows.Close()
+
+	for candidateRows.Next() {
+		var (
+			id int64
+			c  domain.CandidateExplanation
+		)
+		if err := candidateRows.Scan(&id, &c.UserID, &c.Rank, &c.Working, &c.PairingScore, &c.Level, (*tagList)(&c.Tags), &c.Chosen, &c.Reason); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		if i, ok := index[id]; ok {
+			assignments[i].Candidates = append(assignments[i].Candidates, c)
+		}
+	}
+	if err := candidateRows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return assignments, nil
+}
+
+// insertAssignment сохраняет выбор ревьюеров с кандидатами в транзакции назначения
+func insertAssignment(ctx context.Context, tx *sql.Tx, a domain.Assignment) error {
+	var replaced sql.NullString
+	if a.ReplacedUserID != "" {
+		replaced = sql.NullString{String: a.ReplacedUserID, Valid: true}
+	}
+
+	var id int64
+	err := tx.QueryRowContext(ctx, `
+        INSERT INTO reviewer_assignments (pull_request_id, kind, replaced_user_id, seed, strategy, assigned_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        RETURNING assignment_id
+    `, a.PullRequestID, string(a.Kind), replaced, a.Seed, string(a.Strategy), a.At.UTC()).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range a.Candidates {
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO reviewer_assignment_candidates (assignment_id, user_id, rank, working, pairing_score, level, tags, chosen, reason)
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+        `, id, c.UserID, c.Rank, c.Working, c.PairingScore, string(c.Level), tagList(c.Tags), c.Chosen, string(c.Reason))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
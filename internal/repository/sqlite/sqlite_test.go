@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/config"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/repository/repotest"
+	"service-order-avito/internal/service/pull_request"
+	"service-order-avito/migrations"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRepositories(t *testing.T) repotest.Repositories {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := ConnectSQLite(ctx, config.SQLiteStorage{
+		Path:        filepath.Join(t.TempDir(), "test.db"),
+		BusyTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := NewMigrator(db, migrations.SQLite, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.CheckVersion(ctx))
+
+	userRepo := NewUserRepositorySQLite(db)
+	teamRepo := NewTeamRepositorySQLite(db, userRepo)
+	return repotest.Repositories{
+		Team:        teamRepo,
+		User:        userRepo,
+		PullRequest: NewPullRequestRepositorySQLite(db, teamRepo, userRepo, 0),
+		LimitedPullRequest: func(maxOpenReviews int) pull_request.PullRequestRepository {
+			return NewPullRequestRepositorySQLite(db, teamRepo, userRepo, maxOpenReviews)
+		},
+		Idempotency:  NewIdempotencyRepositorySQLite(db),
+		Availability: NewAvailabilityRepositorySQLite(db),
+	}
+}
+
+func TestRepositoriesSQLite(t *testing.T) {
+	repotest.Run(t, newRepositories)
+}
+
+// BEGIN IMMEDIATE сериализует транзакции: параллельные замены одного ревьюера не назначают двух
+func TestConcurrentReassignSQLite(t *testing.T) {
+	ctx := context.Background()
+	repos := newRepositories(t)
+
+	members := []string{"u1", "u2", "u3", "u4", "u5", "u6"}
+	users := make([]domain.User, 0, len(members))
+	for _, id := range members {
+		users = append(users, domain.User{ID: id, Username: id, TeamName: "backend", IsActive: true})
+	}
+	require.NoError(t, repos.Team.AddTeamWithMembers(ctx, domain.Team{Name: "backend"}, users))
+	pr, err := repos.PullRequest.CreateWithReviewers(ctx, domain.PullRequest{ID: "pr1", Name: "pr", AuthorID: "u1"}, 1)
+	require.NoError(t, err)
+	require.Len(t, pr.AssignedReviewers, 2)
+	old := pr.AssignedReviewers[0]
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repos.PullRequest.ReassignReviewer(ctx, "pr1", old, 0, time.Now(), 1); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	merged, _, err := repos.PullRequest.Merge(ctx, "pr1", 0)
+	require.NoError(t, err)
+	assert.Len(t, merged.AssignedReviewers, 2)
+	assert.NotContains(t, merged.AssignedReviewers, old)
+}
+
+// миграция CLOSED пересоздает pull_requests: ревьюеры и история выбора не должны уйти каскадом ни вверх, ни вниз
+func TestPullRequestClosedMigrationKeepsReviewersSQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := ConnectSQLite(ctx, config.SQLiteStorage{
+		Path:        filepath.Join(t.TempDir(), "test.db"),
+		BusyTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations.SQLite)
+	require.NoError(t, err)
+	_, err = provider.UpTo(ctx, 20251217090000)
+	require.NoError(t, err)
+
+	for _, q := range []string{
+		`INSERT INTO teams (team_name) VALUES ('backend')`,
+		`INSERT INTO users (user_id, username, team_name) VALUES ('u1', 'a', 'backend'), ('u2', 'b', 'backend')`,
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, created_at, version, required_tags)
+		 VALUES ('pr1', 'name', 'u1', '2025-12-01 10:00:00', 3, 'go')`,
+		`INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ('pr1', 'u2')`,
+		`INSERT INTO reviewer_assignments (pull_request_id, kind, seed, strategy, assigned_at)
+		 VALUES ('pr1', 'CREATE', 1, 'RANDOM', '2025-12-01 10:00:00')`,
+	} {
+		_, err = db.ExecContext(ctx, q)
+		require.NoError(t, err)
+	}
+
+	count := func(query string) int {
+		var n int
+		require.NoError(t, db.QueryRowContext(ctx, query).Scan(&n))
+		return n
+	}
+
+	_, err = provider.UpByOne(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM pr_reviewers`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM reviewer_assignments`))
+	assert.Equal(t, 3, count(`SELECT version FROM pull_requests WHERE pull_request_id = 'pr1'`))
+	assert.Equal(t, 1, count(`SELECT foreign_keys FROM pragma_foreign_keys`))
+
+	_, err = db.ExecContext(ctx, `UPDATE pull_requests SET status = 'CLOSED' WHERE pull_request_id = 'pr1'`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE pull_requests SET status = 'OPEN' WHERE pull_request_id = 'pr1'`)
+	require.NoError(t, err)
+
+	_, err = provider.Down(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM pr_reviewers`))
+	_, err = db.ExecContext(ctx, `UPDATE pull_requests SET status = 'CLOSED' WHERE pull_request_id = 'pr1'`)
+	assert.Error(t, err)
+}
+
+// у PR, созданных до истории выбора ревьюеров, пары и начало SLA берутся из pr_reviewers со временем создания PR
+func TestPullRequestsWithoutAssignmentsSQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := ConnectSQLite(ctx, config.SQLiteStorage{
+		Path:        filepath.Join(t.TempDir(), "test.db"),
+		BusyTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := NewMigrator(db, migrations.SQLite, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	for _, q := range []string{
+		`INSERT INTO teams (team_name) VALUES ('backend')`,
+		`INSERT INTO users (user_id, username, team_name) VALUES ('u1', 'a', 'backend'), ('u2', 'b', 'backend')`,
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, created_at)
+		 VALUES ('pr1', 'name', 'u1', '2025-12-01 10:00:00')`,
+		`INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ('pr1', 'u2')`,
+	} {
+		_, err = db.ExecContext(ctx, q)
+		require.NoError(t, err)
+	}
+
+	userRepo := NewUserRepositorySQLite(db)
+	pairings, err := NewTeamRepositorySQLite(db, userRepo).GetPairings(ctx, "backend", time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, pairings, 1)
+	assert.Equal(t, "u1", pairings[0].AuthorID)
+	assert.Equal(t, "u2", pairings[0].ReviewerID)
+	assert.True(t, pairings[0].At.Equal(time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)))
+
+	prs, err := userRepo.GetReviewPullRequests(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.True(t, prs[0].AssignedAt.Equal(time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)))
+}
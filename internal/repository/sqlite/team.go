@@ -0,0 +1,514 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"time"
+)
+
+// teamMembersRepository — то, что репозиторию команд нужно от репозитория пользователей
+type teamMembersRepository interface {
+	UpsertManyTx(ctx context.Context, tx *sql.Tx, users []domain.User) error
+	GetByIDsTx(ctx context.Context, tx *sql.Tx, userIDs []string) ([]domain.User, error)
+	GetByTeamName(ctx context.Context, teamName string) ([]domain.User, error)
+}
+
+type teamRepositorySQLite struct {
+	userRepo teamMembersRepository
+	db       *sql.DB
+}
+
+func NewTeamRepositorySQLite(db *sql.DB, userRepo teamMembersRepository) *teamRepositorySQLite {
+	return &teamRepositorySQLite{
+		userRepo: userRepo,
+		db:       db,
+	}
+}
+
+func (r *teamRepositorySQLite) AddTeamWithMembers(ctx context.Context, team domain.Team, members []domain.User) error {
+	const op = "repository.sqlite.team.AddTeamWithMembers"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	if err = r.InsertTx(ctx, tx, team); err != nil {
+		return err
+	}
+
+	if err = r.userRepo.UpsertManyTx(ctx, tx, members); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+func (r *teamRepositorySQLite) GetTeamWithMembers(ctx context.Context, teamName string) (*domain.TeamWithUsers, error) {
+	team, err := r.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := r.userRepo.GetByTeamName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TeamWithUsers{
+		TeamName: team.Name,
+		Members:  members,
+	}, nil
+}
+
+// ручка проверяет существование команды
+func (r *teamRepositorySQLite) GetTeamWithMembersTx(ctx context.Context, tx *sql.Tx, teamName string) (*domain.TeamWithUsers, error) {
+	const op = "repository.sqlite.team.GetTeamWithMembersTx"
+
+	var team domain.Team
+	err := tx.QueryRowContext(ctx, `SELECT team_name FROM teams WHERE team_name=?`, teamName).Scan(&team.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrTeamNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		// порядок по user_id, как в памяти: в нем кандидаты перемешиваются, и от него зависит, повторит ли seed выбор
+		`SELECT user_id, username, team_name, is_active, skills, level FROM users WHERE team_name=? ORDER BY user_id`,
+		teamName,
+	)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	var members []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, (*tagList)(&u.Skills), &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		members = append(members, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return &domain.TeamWithUsers{
+		TeamName: team.Name,
+		Members:  members,
+	}, nil
+}
+
+func (r *teamRepositorySQLite) InsertTx(ctx context.Context, tx *sql.Tx, team domain.Team) error {
+	const op = "repository.sqlite.team.InsertTx"
+
+	query := `
+        INSERT INTO teams (team_name)
+        VALUES (?)
+    `
+
+	if _, err := tx.ExecContext(ctx, query, team.Name); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrTeamAlreadyExists
+		}
+		return repository.Internal(op, err)
+	}
+	return nil
+}
+
+func (r *teamRepositorySQLite) GetByName(ctx context.Context, name string) (*domain.Team, error) {
+	const op = "repository.sqlite.team.GetByName"
+
+	var team domain.Team
+	err := r.db.QueryRowContext(ctx, `SELECT team_name FROM teams WHERE team_name=?`, name).Scan(&team.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrTeamNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+	return &team, nil
+}
+
+// GetTeamStats возвращает статистику по команде:
+// количество активных и неактивных пользователей,
+// количество открытых и замерженных PR
+func (r *teamRepositorySQLite) GetTeamStats(ctx context.Context, teamName string) (activeUsers, inactiveUsers, openPRs, mergedPRs int, err error) {
+	const op = "repository.sqlite.team.GetTeamStats"
+
+	query := `
+	SELECT
+		COUNT(DISTINCT u.user_id) FILTER (WHERE u.is_active) AS active_users,
+		COUNT(DISTINCT u.user_id) FILTER (WHERE NOT u.is_active) AS inactive_users,
+		COUNT(pr.pull_request_id) FILTER (WHERE pr.status='OPEN') AS open_prs,
+		COUNT(pr.pull_request_id) FILTER (WHERE pr.status='MERGED') AS merged_prs
+	FROM teams t
+	LEFT JOIN users u ON u.team_name = t.team_name
+	LEFT JOIN pull_requests pr ON pr.author_id = u.user_id
+	WHERE t.team_name = ?
+	GROUP BY t.team_name
+	`
+
+	err = r.db.QueryRowContext(ctx, query, teamName).Scan(&activeUsers, &inactiveUsers, &openPRs, &mergedPRs)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, 0, 0, 0, repository.ErrTeamNotFound
+		default:
+			return 0, 0, 0, 0, repository.Internal(op, err)
+		}
+	}
+
+	return activeUsers, inactiveUsers, openPRs, mergedPRs, nil
+}
+
+// GetTeamsWithMembers команды с участниками одним запросом (для батчевой загрузки в GraphQL).
+// Несуществующие команды пропускаются, участники отсортированы по user_id
+func (r *teamRepositorySQLite) GetTeamsWithMembers(ctx context.Context, teamNames []string) ([]domain.TeamWithUsers, error) {
+	const op = "repository.sqlite.team.GetTeamsWithMembers"
+
+	teams := []domain.TeamWithUsers{}
+	if len(teamNames) == 0 {
+		return teams, nil
+	}
+
+	in, args := inList(teamNames)
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT t.team_name, u.user_id, u.username, u.is_active, u.skills, u.level
+        FROM teams t
+        LEFT JOIN users u ON u.team_name = t.team_name
+        WHERE t.team_name IN (`+in+`)
+        ORDER BY t.team_name, u.user_id
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			teamName string
+			userID   sql.NullString
+			username sql.NullString
+			isActive sql.NullBool
+			skills   tagList
+			level    sql.NullString
+		)
+		if err := rows.Scan(&teamName, &userID, &username, &isActive, &skills, &level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+
+		if len(teams) == 0 || teams[len(teams)-1].TeamName != teamName {
+			teams = append(teams, domain.TeamWithUsers{TeamName: teamName, Members: []domain.User{}})
+		}
+		// у команды без участников LEFT JOIN дает одну строку с NULL
+		if !userID.Valid {
+			continue
+		}
+		last := &teams[len(teams)-1]
+		last.Members = append(last.Members, domain.User{
+			ID:       userID.String,
+			Username: username.String,
+			TeamName: teamName,
+			IsActive: isActive.Bool,
+			Skills:   skills,
+			Level:    domain.Level(level.String),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return teams, nil
+}
+
+// ImportTeams создает недостающие команды и upsert'ит участников одной транзакцией.
+// При dryRun транзакция откатывается и возвращается только отчет
+func (r *teamRepositorySQLite) ImportTeams(ctx context.Context, teams []domain.TeamWithUsers, dryRun bool) (*domain.ImportReport, error) {
+	const op = "repository.sqlite.team.ImportTeams"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	names := make([]string, len(teams))
+	for i, t := range teams {
+		names[i] = t.TeamName
+	}
+	existingTeams := make(map[string]bool, len(teams))
+	if len(names) > 0 {
+		in, args := inList(names)
+		rows, err := tx.QueryContext(ctx, `SELECT team_name FROM teams WHERE team_name IN (`+in+`)`, args...)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return nil, repository.Internal(op, err)
+			}
+			existingTeams[name] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	users := domain.ImportUsers(teams)
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	existing, err := r.userRepo.GetByIDsTx(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	existingUsers := make(map[string]domain.User, len(existing))
+	for _, u := range existing {
+		existingUsers[u.ID] = u
+	}
+
+	report := domain.PlanImport(teams, existingTeams, existingUsers)
+	if dryRun {
+		return &report, nil
+	}
+
+	for _, name := range report.TeamsCreated {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO teams (team_name) VALUES (?) ON CONFLICT DO NOTHING`, name); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	if err := r.userRepo.UpsertManyTx(ctx, tx, users); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return &report, nil
+}
+
+// SetReviewerRules заменяет правила команды к уровням ревьюеров, пустой список их снимает
+func (r *teamRepositorySQLite) SetReviewerRules(ctx context.Context, teamName string, rules []domain.ReviewerRule) ([]domain.ReviewerRule, error) {
+	const op = "repository.sqlite.team.SetReviewerRules"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRowContext(ctx, `SELECT team_name FROM teams WHERE team_name=?`, teamName).Scan(&name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrTeamNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_reviewer_rules WHERE team_name=?`, teamName); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	rules = domain.NormalizeRules(rules)
+	for _, rule := range rules {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO team_reviewer_rules (team_name, min_level, min_count) VALUES (?, ?, ?)`,
+			teamName, string(rule.MinLevel), rule.Count,
+		)
+		if err != nil {
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return rules, nil
+}
+
+// GetReviewerRules правила команды, от старшего уровня к младшему
+func (r *teamRepositorySQLite) GetReviewerRules(ctx context.Context, teamName string) ([]domain.ReviewerRule, error) {
+	const op = "repository.sqlite.team.GetReviewerRules"
+
+	if _, err := r.GetByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	rules, err := reviewerRules(ctx, r.db, teamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return rules, nil
+}
+
+func reviewerRules(ctx context.Context, q querier, teamName string) ([]domain.ReviewerRule, error) {
+	rows, err := q.QueryContext(ctx, `SELECT min_level, min_count FROM team_reviewer_rules WHERE team_name=?`, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.ReviewerRule
+	for rows.Next() {
+		var rule domain.ReviewerRule
+		if err := rows.Scan(&rule.MinLevel, &rule.Count); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.NormalizeRules(rules), nil
+}
+
+// SetPairingSettings задает стратегию выбора ревьюеров команды
+func (r *teamRepositorySQLite) SetPairingSettings(ctx context.Context, teamName string, settings domain.PairingSettings) (*domain.PairingSettings, error) {
+	const op = "repository.sqlite.team.SetPairingSettings"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRowContext(ctx, `SELECT team_name FROM teams WHERE team_name=?`, teamName).Scan(&name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrTeamNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	query := `
+        INSERT INTO team_pairing_settings (team_name, strategy, decay_days)
+        VALUES (?, ?, ?)
+        ON CONFLICT (team_name) DO UPDATE
+        SET strategy = excluded.strategy,
+            decay_days = excluded.decay_days
+    `
+	if _, err := tx.ExecContext(ctx, query, teamName, string(settings.Strategy), settings.DecayDays); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return &settings, nil
+}
+
+// GetPairingSettings стратегия команды, без настроек — domain.DefaultPairingSettings
+func (r *teamRepositorySQLite) GetPairingSettings(ctx context.Context, teamName string) (*domain.PairingSettings, error) {
+	const op = "repository.sqlite.team.GetPairingSettings"
+
+	if _, err := r.GetByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	settings, err := pairingSettings(ctx, r.db, teamName)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return &settings, nil
+}
+
+// GetPairings назначения ревьюеров к PR авторов команды не раньше since
+func (r *teamRepositorySQLite) GetPairings(ctx context.Context, teamName string, since time.Time) ([]domain.Pairing, error) {
+	const op = "repository.sqlite.team.GetPairings"
+
+	if _, err := r.GetByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	pairings, err := pairingsSince(ctx, r.db, `u.team_name = ?1`, teamName, since)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return pairings, nil
+}
+
+func pairingSettings(ctx context.Context, q querier, teamName string) (domain.PairingSettings, error) {
+	var settings domain.PairingSettings
+	err := q.QueryRowContext(ctx,
+		`SELECT strategy, decay_days FROM team_pairing_settings WHERE team_name=?`, teamName,
+	).Scan(&settings.Strategy, &settings.DecayDays)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.DefaultPairingSettings(), nil
+	}
+	return settings, err
+}
+
+// pairingsSince пары автор-ревьюер из выборов ревьюеров не раньше since, со временем назначения.
+// Ревьюер, которого потом заменили, остается в истории. У PR без истории выборов (созданных до нее) — ревьюеры
+// из pr_reviewers со временем создания PR. cond — условие на pr или автора u с параметром ?1
+func pairingsSince(ctx context.Context, q querier, cond, arg string, since time.Time) ([]domain.Pairing, error) {
+	rows, err := q.QueryContext(ctx, `
+        SELECT pr.author_id, c.user_id, a.assigned_at
+        FROM reviewer_assignments a
+        JOIN reviewer_assignment_candidates c ON c.assignment_id = a.assignment_id AND c.chosen
+        JOIN pull_requests pr ON pr.pull_request_id = a.pull_request_id
+        JOIN users u ON u.user_id = pr.author_id
+        WHERE `+cond+` AND a.assigned_at >= ?2
+        UNION ALL
+        SELECT pr.author_id, r.user_id, pr.created_at
+        FROM pull_requests pr
+        JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+        JOIN users u ON u.user_id = pr.author_id
+        WHERE `+cond+` AND pr.created_at >= ?2
+          AND NOT EXISTS (SELECT 1 FROM reviewer_assignments a WHERE a.pull_request_id = pr.pull_request_id)
+        ORDER BY 3, 1, 2
+    `, arg, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairings []domain.Pairing
+	for rows.Next() {
+		var p domain.Pairing
+		if err := rows.Scan(&p.AuthorID, &p.ReviewerID, &p.At); err != nil {
+			return nil, err
+		}
+		pairings = append(pairings, p)
+	}
+	return pairings, rows.Err()
+}
+
+// recentPairings вес недавних ревью у автора для выбора ревьюеров в команде teamName.
+// nil, если команда выбирает случайно
+func recentPairings(ctx context.Context, q querier, teamName, authorID string, now time.Time) (map[string]float64, error) {
+	settings, err := pairingSettings(ctx, q, teamName)
+	if err != nil || settings.Strategy != domain.PairingDiversity {
+		return nil, err
+	}
+	pairings, err := pairingsSince(ctx, q, `pr.author_id = ?1`, authorID, now.Add(-settings.Window()))
+	if err != nil {
+		return nil, err
+	}
+	return domain.PairingScores(pairings, authorID, now, settings.Window()), nil
+}
@@ -0,0 +1,340 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/errors/repository"
+	"slices"
+)
+
+type userRepositorySQLite struct {
+	db *sql.DB
+}
+
+func NewUserRepositorySQLite(db *sql.DB) *userRepositorySQLite {
+	return &userRepositorySQLite{db: db}
+}
+
+func (r *userRepositorySQLite) UpsertManyTx(ctx context.Context, tx *sql.Tx, users []domain.User) error {
+	const op = "repository.sqlite.user.UpsertManyTx"
+
+	query := `
+        INSERT INTO users (user_id, username, team_name, is_active)
+        VALUES (?, ?, ?, ?)
+        ON CONFLICT (user_id) DO UPDATE
+        SET username = excluded.username,
+            team_name = excluded.team_name,
+            is_active = excluded.is_active
+    `
+	for _, u := range users {
+		if _, err := tx.ExecContext(ctx, query, u.ID, u.Username, u.TeamName, u.IsActive); err != nil {
+			return repository.Internal(op, err)
+		}
+	}
+	return nil
+}
+
+func (r *userRepositorySQLite) GetByTeamName(ctx context.Context, teamName string) ([]domain.User, error) {
+	const op = "repository.sqlite.user.GetByTeamName"
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, username, team_name, is_active, skills, level FROM users WHERE team_name=?`,
+		teamName,
+	)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, (*tagList)(&u.Skills), &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return users, nil
+}
+
+func (r *userRepositorySQLite) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	const op = "repository.sqlite.user.SetIsActive"
+
+	query := `
+        UPDATE users
+        SET is_active = ?
+        WHERE user_id = ?
+        RETURNING user_id, username, team_name, is_active, skills, level
+    `
+
+	var u domain.User
+	err := r.db.QueryRowContext(ctx, query, isActive, userID).Scan(
+		&u.ID, &u.Username, &u.TeamName, &u.IsActive, (*tagList)(&u.Skills), &u.Level,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrUserNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	return &u, nil
+}
+
+// SetSkills заменяет теги пользователя
+func (r *userRepositorySQLite) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	const op = "repository.sqlite.user.SetSkills"
+
+	query := `
+        UPDATE users
+        SET skills = ?
+        WHERE user_id = ?
+        RETURNING user_id, username, team_name, is_active, skills, level
+    `
+	return scanUser(op, r.db.QueryRowContext(ctx, query, tagList(domain.NormalizeTags(skills)), userID))
+}
+
+// SetLevel задает уровень пользователя
+func (r *userRepositorySQLite) SetLevel(ctx context.Context, userID string, level domain.Level) (*domain.User, error) {
+	const op = "repository.sqlite.user.SetLevel"
+
+	query := `
+        UPDATE users
+        SET level = ?
+        WHERE user_id = ?
+        RETURNING user_id, username, team_name, is_active, skills, level
+    `
+	return scanUser(op, r.db.QueryRowContext(ctx, query, string(level), userID))
+}
+
+// UpdateSkills добавляет и убирает теги. Список меняется в Go, поэтому чтение и запись в одной транзакции
+func (r *userRepositorySQLite) UpdateSkills(ctx context.Context, userID string, add, remove []string) (*domain.User, error) {
+	const op = "repository.sqlite.user.UpdateSkills"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer tx.Rollback()
+
+	u, err := scanUser(op, tx.QueryRowContext(ctx, queryGetUser, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	skills := make([]string, 0, len(u.Skills)+len(add))
+	for _, s := range append(u.Skills, add...) {
+		if !slices.Contains(remove, s) {
+			skills = append(skills, s)
+		}
+	}
+	u.Skills = domain.NormalizeTags(skills)
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET skills = ? WHERE user_id = ?`, tagList(u.Skills), userID); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	return u, nil
+}
+
+func (r *userRepositorySQLite) GetByIDTx(ctx context.Context, tx *sql.Tx, userID string) (*domain.User, error) {
+	const op = "repository.sqlite.user.GetByIDTx"
+
+	return scanUser(op, tx.QueryRowContext(ctx, queryGetUser, userID))
+}
+
+func (r *userRepositorySQLite) GetByID(ctx context.Context, userID string) (*domain.User, error) {
+	const op = "repository.sqlite.user.GetByID"
+
+	return scanUser(op, r.db.QueryRowContext(ctx, queryGetUser, userID))
+}
+
+const queryGetUser = `
+        SELECT user_id, username, team_name, is_active, skills, level
+        FROM users
+        WHERE user_id = ?
+    `
+
+func scanUser(op string, row *sql.Row) (*domain.User, error) {
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, (*tagList)(&u.Skills), &u.Level)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, repository.ErrUserNotFound
+		default:
+			return nil, repository.Internal(op, err)
+		}
+	}
+
+	return &u, nil
+}
+
+func (r *userRepositorySQLite) GetReviewPullRequests(ctx context.Context, userID string) ([]domain.ReviewPullRequest, error) {
+	const op = "repository.sqlite.user.GetReviewPullRequests"
+
+	// проверка на существование такого пользователя
+	if _, err := r.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// время назначения — последний выбор, в котором ревьюер выбран. У PR без истории выбора — время создания PR
+	queryGetReviewPR := `
+        SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, a.assigned_at
+        FROM pull_requests pr
+        JOIN pr_reviewers r ON r.pull_request_id = pr.pull_request_id
+        LEFT JOIN reviewer_assignments a ON a.assignment_id = (
+            SELECT MAX(c.assignment_id)
+            FROM reviewer_assignment_candidates c
+            JOIN reviewer_assignments ra ON ra.assignment_id = c.assignment_id
+            WHERE ra.pull_request_id = pr.pull_request_id AND c.user_id = r.user_id AND c.chosen
+        )
+        WHERE r.user_id = ?
+        ORDER BY pr.created_at DESC
+    `
+
+	rows, err := r.db.QueryContext(ctx, queryGetReviewPR, userID)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	var prs []domain.ReviewPullRequest
+	for rows.Next() {
+		var (
+			pr         domain.ReviewPullRequest
+			assignedAt sql.NullTime
+		)
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &assignedAt); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		pr.AssignedAt = pr.CreatedAt
+		if assignedAt.Valid {
+			pr.AssignedAt = assignedAt.Time
+		}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return prs, nil
+}
+
+// GetByIDs пользователи по списку id одним запросом (для батчевой загрузки в GraphQL).
+// Несуществующие id пропускаются, ошибки ErrUserNotFound нет
+func (r *userRepositorySQLite) GetByIDs(ctx context.Context, userIDs []string) ([]domain.User, error) {
+	const op = "repository.sqlite.user.GetByIDs"
+
+	users := []domain.User{}
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	in, args := inList(userIDs)
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT user_id, username, team_name, is_active, skills, level
+        FROM users
+        WHERE user_id IN (`+in+`)
+        ORDER BY user_id
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, (*tagList)(&u.Skills), &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return users, nil
+}
+
+// GetReviewPullRequestIDs id PR на ревью для каждого пользователя, сначала новые.
+// Пользователи без PR (и несуществующие) в map не попадают
+func (r *userRepositorySQLite) GetReviewPullRequestIDs(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	const op = "repository.sqlite.user.GetReviewPullRequestIDs"
+
+	queues := make(map[string][]string)
+	if len(userIDs) == 0 {
+		return queues, nil
+	}
+
+	in, args := inList(userIDs)
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT r.user_id, pr.pull_request_id
+        FROM pr_reviewers r
+        JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+        WHERE r.user_id IN (`+in+`)
+        ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID, prID string
+		if err := rows.Scan(&userID, &prID); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		queues[userID] = append(queues[userID], prID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return queues, nil
+}
+
+// GetByIDsTx как GetByIDs, но в транзакции. Отдельная блокировка строк не нужна: BEGIN IMMEDIATE уже держит запись
+func (r *userRepositorySQLite) GetByIDsTx(ctx context.Context, tx *sql.Tx, userIDs []string) ([]domain.User, error) {
+	const op = "repository.sqlite.user.GetByIDsTx"
+
+	users := []domain.User{}
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	in, args := inList(userIDs)
+	rows, err := tx.QueryContext(ctx, `
+        SELECT user_id, username, team_name, is_active, skills, level
+        FROM users
+        WHERE user_id IN (`+in+`)
+        ORDER BY user_id
+    `, args...)
+	if err != nil {
+		return nil, repository.Internal(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, (*tagList)(&u.Skills), &u.Level); err != nil {
+			return nil, repository.Internal(op, err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.Internal(op, err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,219 @@
+package availability
+
+import (
+	"context"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/service/error_wrapper"
+	"strings"
+	"time"
+)
+
+// mockgen -source="internal/service/availability/availability.go" -destination="internal/service/availability/mocks/mock_availability_repository.go" -package=mocks AvailabilityRepository,TeamRepository,Reassigner
+type AvailabilityRepository interface {
+	AddAbsence(context.Context, domain.Absence) (*domain.Absence, error)
+	ListAbsences(context.Context, string) ([]domain.Absence, error)
+	DeleteAbsence(context.Context, string, int64) error
+	// ListAbsentReviews назначения в открытых PR, ревьюер которых отсутствует в момент времени (без периодов по дням недели)
+	ListAbsentReviews(context.Context, time.Time) ([]domain.AbsentReview, error)
+	// ImportAbsences применяет импорт календаря одной транзакцией, периоды ищутся по (UserID, UID)
+	ImportAbsences(context.Context, domain.AbsenceImport) (*domain.AbsenceImportReport, error)
+	SetWorkingHours(context.Context, domain.WorkingHours) (*domain.WorkingHours, error)
+	// GetWorkingHours nil — пользователь расписание не задавал
+	GetWorkingHours(context.Context, string) (*domain.WorkingHours, error)
+	ListWorkingHours(context.Context, []string) (map[string]domain.WorkingHours, error)
+}
+
+// TeamRepository участники команды для импорта общего календаря
+type TeamRepository interface {
+	GetTeamWithMembers(context.Context, string) (*domain.TeamWithUsers, error)
+}
+
+// Reassigner замена отсутствующего ревьюера через сервис PR: с событиями, но без лимита замен клиентов
+type Reassigner interface {
+	ReassignAbsentReviewer(context.Context, *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error)
+}
+
+type availabilityService struct {
+	repo  AvailabilityRepository
+	teams TeamRepository
+}
+
+func NewAvailabilityService(repo AvailabilityRepository, teams TeamRepository) *availabilityService {
+	return &availabilityService{repo: repo, teams: teams}
+}
+
+func (s *availabilityService) AddAbsence(ctx context.Context, req *dto.AddAbsenceRequest) (*dto.AbsenceResponse, error) {
+	starts, ends, err := req.Period()
+	if err != nil {
+		return nil, err
+	}
+
+	absence, err := s.repo.AddAbsence(ctx, domain.Absence{
+		UserID:   req.UserID,
+		Kind:     req.Kind,
+		Starts:   starts,
+		Ends:     ends,
+		Weekdays: dto.ParseWeekdays(req.Weekdays),
+	})
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := absenceResponse(*absence)
+	return &resp, nil
+}
+
+func (s *availabilityService) ListAbsences(ctx context.Context, req *dto.GetAbsencesRequest) (*dto.GetAbsencesResponse, error) {
+	absences, err := s.repo.ListAbsences(ctx, req.UserID)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := &dto.GetAbsencesResponse{
+		UserID:   req.UserID,
+		Absences: make([]dto.AbsenceResponse, len(absences)),
+	}
+	for i, a := range absences {
+		resp.Absences[i] = absenceResponse(a)
+	}
+	return resp, nil
+}
+
+func (s *availabilityService) DeleteAbsence(ctx context.Context, req *dto.DeleteAbsenceRequest) error {
+	if err := s.repo.DeleteAbsence(ctx, req.UserID, req.AbsenceID); err != nil {
+		return error_wrapper.WrapRepositoryError(err)
+	}
+	return nil
+}
+
+// ImportCalendar переносит события OOO календаря в периоды отсутствия. Календарь пользователя целиком относится к нему,
+// событие общего календаря команды — к участникам, чей user_id, адрес до @ или имя совпадает с ORGANIZER/ATTENDEE
+func (s *availabilityService) ImportCalendar(ctx context.Context, req *dto.ImportCalendarRequest) (*dto.ImportCalendarResponse, error) {
+	imp := domain.AbsenceImport{Users: []string{req.UserID}}
+	var members []domain.User
+	if req.TeamName != "" {
+		team, err := s.teams.GetTeamWithMembers(ctx, req.TeamName)
+		if err != nil {
+			return nil, error_wrapper.WrapRepositoryError(err)
+		}
+		members = team.Members
+		imp.Users = make([]string, len(members))
+		for i, m := range members {
+			imp.Users[i] = m.ID
+		}
+	}
+
+	resp := &dto.ImportCalendarResponse{Ignored: req.Ignored, Skipped: []dto.SkippedEvent{}}
+	for _, ev := range req.Events {
+		if ev.Skip != "" {
+			resp.Skipped = append(resp.Skipped, dto.SkippedEvent{UID: ev.UID, Reason: ev.Skip})
+			continue
+		}
+
+		owners := []string{req.UserID}
+		if req.TeamName != "" {
+			owners = matchMembers(members, ev.People)
+			if len(owners) == 0 {
+				resp.Skipped = append(resp.Skipped, dto.SkippedEvent{UID: ev.UID, Reason: "no team member among organizer and attendees"})
+				continue
+			}
+		}
+
+		for _, userID := range owners {
+			absence := domain.Absence{
+				UserID:   userID,
+				Kind:     ev.Kind,
+				Starts:   ev.Starts,
+				Ends:     ev.Ends,
+				Weekdays: ev.Weekdays,
+				UID:      ev.UID,
+			}
+			if ev.Cancelled {
+				imp.Cancel = append(imp.Cancel, absence)
+			} else {
+				imp.Upsert = append(imp.Upsert, absence)
+			}
+		}
+	}
+
+	report, err := s.repo.ImportAbsences(ctx, imp)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp.Created = report.Created
+	resp.Updated = report.Updated
+	resp.Unchanged = report.Unchanged
+	resp.Deleted = report.Deleted
+	return resp, nil
+}
+
+func (s *availabilityService) SetWorkingHours(ctx context.Context, req *dto.SetWorkingHoursRequest) (*dto.WorkingHoursResponse, error) {
+	hours, err := s.repo.SetWorkingHours(ctx, domain.WorkingHours{
+		UserID:   req.UserID,
+		Timezone: req.Timezone,
+		Start:    dto.ParseClock(req.Start),
+		End:      dto.ParseClock(req.End),
+		Days:     dto.ParseWeekdays(req.Weekdays),
+	})
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := workingHoursResponse(*hours, false)
+	return &resp, nil
+}
+
+// GetWorkingHours расписание пользователя, без заданного — общее с is_default
+func (s *availabilityService) GetWorkingHours(ctx context.Context, req *dto.GetWorkingHoursRequest) (*dto.WorkingHoursResponse, error) {
+	hours, err := s.repo.GetWorkingHours(ctx, req.UserID)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	if hours == nil {
+		resp := workingHoursResponse(domain.HoursOf(nil, req.UserID), true)
+		return &resp, nil
+	}
+	resp := workingHoursResponse(*hours, false)
+	return &resp, nil
+}
+
+// matchMembers участники команды среди people (адреса и имена из события), без повторов, в порядке состава команды
+func matchMembers(members []domain.User, people []string) []string {
+	var matched []string
+	for _, m := range members {
+		for _, p := range people {
+			local, _, _ := strings.Cut(p, "@")
+			if strings.EqualFold(p, m.ID) || strings.EqualFold(local, m.ID) || strings.EqualFold(p, m.Username) {
+				matched = append(matched, m.ID)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func absenceResponse(a domain.Absence) dto.AbsenceResponse {
+	return dto.AbsenceResponse{
+		AbsenceID: a.ID,
+		UserID:    a.UserID,
+		Kind:      a.Kind,
+		StartsAt:  a.Starts.UTC(),
+		EndsAt:    a.Ends.UTC(),
+		Weekdays:  dto.FormatWeekdays(a.Weekdays),
+		UID:       a.UID,
+	}
+}
+
+func workingHoursResponse(w domain.WorkingHours, isDefault bool) dto.WorkingHoursResponse {
+	return dto.WorkingHoursResponse{
+		UserID:    w.UserID,
+		Timezone:  w.Timezone,
+		Start:     dto.FormatClock(w.Start),
+		End:       dto.FormatClock(w.End),
+		Weekdays:  dto.FormatWeekdays(w.Days),
+		IsDefault: isDefault,
+	}
+}
@@ -0,0 +1,237 @@
+package availability
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/repository"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/service/availability/mocks"
+	"testing"
+	"time"
+)
+
+func TestAvailabilityService_AddAbsence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAvailabilityRepository(ctrl)
+	s := NewAvailabilityService(repo, mocks.NewMockTeamRepository(ctrl))
+
+	t.Run("part time until end of day", func(t *testing.T) {
+		want := domain.Absence{
+			UserID:   "u1",
+			Kind:     domain.AbsencePartTime,
+			Starts:   time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			Ends:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			Weekdays: domain.NewWeekdays(time.Friday),
+		}
+		repo.EXPECT().AddAbsence(gomock.Any(), want).DoAndReturn(func(_ context.Context, a domain.Absence) (*domain.Absence, error) {
+			a.ID = 7
+			return &a, nil
+		})
+
+		resp, err := s.AddAbsence(context.Background(), &dto.AddAbsenceRequest{
+			UserID:   "u1",
+			Kind:     domain.AbsencePartTime,
+			StartsAt: "2025-12-01",
+			EndsAt:   "2026-02-28",
+			Weekdays: []string{"FRI"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.AbsenceResponse{
+			AbsenceID: 7,
+			UserID:    "u1",
+			Kind:      domain.AbsencePartTime,
+			StartsAt:  want.Starts,
+			EndsAt:    want.Ends,
+			Weekdays:  []string{"FRI"},
+		}, resp)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		repo.EXPECT().AddAbsence(gomock.Any(), gomock.Any()).Return(nil, repository.ErrUserNotFound)
+
+		_, err := s.AddAbsence(context.Background(), &dto.AddAbsenceRequest{
+			UserID: "u9", Kind: domain.AbsenceVacation, StartsAt: "2025-12-22", EndsAt: "2025-12-31",
+		})
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+	})
+}
+
+func TestAvailabilityService_ListAbsences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAvailabilityRepository(ctrl)
+	s := NewAvailabilityService(repo, mocks.NewMockTeamRepository(ctrl))
+
+	starts := time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().ListAbsences(gomock.Any(), "u1").Return([]domain.Absence{
+		{ID: 1, UserID: "u1", Kind: domain.AbsenceVacation, Starts: starts, Ends: starts.AddDate(0, 0, 10)},
+	}, nil)
+	repo.EXPECT().ListAbsences(gomock.Any(), "u2").Return([]domain.Absence{}, nil)
+
+	resp, err := s.ListAbsences(context.Background(), &dto.GetAbsencesRequest{UserID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Absences, 1)
+	assert.Nil(t, resp.Absences[0].Weekdays)
+	assert.Equal(t, starts.AddDate(0, 0, 10), resp.Absences[0].EndsAt)
+
+	// пустой список, а не null
+	resp, err = s.ListAbsences(context.Background(), &dto.GetAbsencesRequest{UserID: "u2"})
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Absences)
+}
+
+func TestAvailabilityService_DeleteAbsence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAvailabilityRepository(ctrl)
+	s := NewAvailabilityService(repo, mocks.NewMockTeamRepository(ctrl))
+
+	repo.EXPECT().DeleteAbsence(gomock.Any(), "u1", int64(1)).Return(nil)
+	repo.EXPECT().DeleteAbsence(gomock.Any(), "u1", int64(2)).Return(repository.ErrAbsenceNotFound)
+	repo.EXPECT().DeleteAbsence(gomock.Any(), "u1", int64(3)).Return(errors.New("db down"))
+
+	assert.NoError(t, s.DeleteAbsence(context.Background(), &dto.DeleteAbsenceRequest{UserID: "u1", AbsenceID: 1}))
+	assert.ErrorIs(t, s.DeleteAbsence(context.Background(), &dto.DeleteAbsenceRequest{UserID: "u1", AbsenceID: 2}), service.ErrAbsenceNotFound)
+	assert.ErrorIs(t, s.DeleteAbsence(context.Background(), &dto.DeleteAbsenceRequest{UserID: "u1", AbsenceID: 3}), service.ErrInternalError)
+}
+
+func TestAvailabilityService_WorkingHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAvailabilityRepository(ctrl)
+	s := NewAvailabilityService(repo, mocks.NewMockTeamRepository(ctrl))
+
+	t.Run("set night shift", func(t *testing.T) {
+		want := domain.WorkingHours{
+			UserID:   "u1",
+			Timezone: "Asia/Novosibirsk",
+			Start:    22 * 60,
+			End:      6*60 + 30,
+			Days:     domain.NewWeekdays(time.Monday, time.Tuesday),
+		}
+		repo.EXPECT().SetWorkingHours(gomock.Any(), want).Return(&want, nil)
+
+		resp, err := s.SetWorkingHours(context.Background(), &dto.SetWorkingHoursRequest{
+			UserID:   "u1",
+			Timezone: "Asia/Novosibirsk",
+			Start:    "22:00",
+			End:      "06:30",
+			Weekdays: []string{"TUE", "MON"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.WorkingHoursResponse{
+			UserID:   "u1",
+			Timezone: "Asia/Novosibirsk",
+			Start:    "22:00",
+			End:      "06:30",
+			Weekdays: []string{"MON", "TUE"},
+		}, resp)
+	})
+
+	t.Run("default when unset", func(t *testing.T) {
+		repo.EXPECT().GetWorkingHours(gomock.Any(), "u2").Return(nil, nil)
+
+		resp, err := s.GetWorkingHours(context.Background(), &dto.GetWorkingHoursRequest{UserID: "u2"})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.WorkingHoursResponse{
+			UserID:    "u2",
+			Timezone:  "UTC",
+			Start:     "09:00",
+			End:       "18:00",
+			Weekdays:  []string{"MON", "TUE", "WED", "THU", "FRI"},
+			IsDefault: true,
+		}, resp)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		repo.EXPECT().GetWorkingHours(gomock.Any(), "u9").Return(nil, repository.ErrUserNotFound)
+
+		_, err := s.GetWorkingHours(context.Background(), &dto.GetWorkingHoursRequest{UserID: "u9"})
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+	})
+}
+
+func TestAvailabilityService_ImportCalendar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAvailabilityRepository(ctrl)
+	teams := mocks.NewMockTeamRepository(ctrl)
+	s := NewAvailabilityService(repo, teams)
+
+	starts := time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC)
+	ends := starts.AddDate(0, 0, 5)
+
+	t.Run("user calendar", func(t *testing.T) {
+		repo.EXPECT().ImportAbsences(gomock.Any(), domain.AbsenceImport{
+			Users:  []string{"u1"},
+			Upsert: []domain.Absence{{UserID: "u1", Kind: domain.AbsenceVacation, Starts: starts, Ends: ends, UID: "a"}},
+			Cancel: []domain.Absence{{UserID: "u1", Kind: domain.AbsenceVacation, UID: "b"}},
+		}).Return(&domain.AbsenceImportReport{Created: 1, Deleted: 1}, nil)
+
+		resp, err := s.ImportCalendar(context.Background(), &dto.ImportCalendarRequest{
+			UserID: "u1",
+			Events: []dto.CalendarEvent{
+				{UID: "a", Kind: domain.AbsenceVacation, Starts: starts, Ends: ends},
+				{UID: "b", Kind: domain.AbsenceVacation, Cancelled: true},
+				{UID: "c", Skip: "recurrence without UNTIL is not supported"},
+			},
+			Ignored: 3,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.ImportCalendarResponse{
+			Created: 1,
+			Deleted: 1,
+			Ignored: 3,
+			Skipped: []dto.SkippedEvent{{UID: "c", Reason: "recurrence without UNTIL is not supported"}},
+		}, resp)
+	})
+
+	t.Run("team calendar matches people", func(t *testing.T) {
+		teams.EXPECT().GetTeamWithMembers(gomock.Any(), "backend").Return(&domain.TeamWithUsers{
+			TeamName: "backend",
+			Members: []domain.User{
+				{ID: "alice", Username: "Alice Smith"},
+				{ID: "bob", Username: "Bob"},
+				{ID: "carol", Username: "Carol"},
+			},
+		}, nil)
+		repo.EXPECT().ImportAbsences(gomock.Any(), domain.AbsenceImport{
+			Users: []string{"alice", "bob", "carol"},
+			Upsert: []domain.Absence{
+				{UserID: "alice", Kind: domain.AbsenceVacation, Starts: starts, Ends: ends, UID: "a"},
+				{UserID: "bob", Kind: domain.AbsenceSickLeave, Starts: starts, Ends: ends, UID: "b"},
+				{UserID: "carol", Kind: domain.AbsenceSickLeave, Starts: starts, Ends: ends, UID: "b"},
+			},
+		}).Return(&domain.AbsenceImportReport{Created: 3}, nil)
+
+		resp, err := s.ImportCalendar(context.Background(), &dto.ImportCalendarRequest{
+			TeamName: "backend",
+			Events: []dto.CalendarEvent{
+				{UID: "a", Kind: domain.AbsenceVacation, Starts: starts, Ends: ends, People: []string{"ALICE@example.com"}},
+				{UID: "b", Kind: domain.AbsenceSickLeave, Starts: starts, Ends: ends, People: []string{"x@example.com", "Carol", "bob"}},
+				{UID: "c", Kind: domain.AbsenceVacation, Starts: starts, Ends: ends, People: []string{"dave@example.com"}},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, resp.Created)
+		assert.Equal(t, []dto.SkippedEvent{{UID: "c", Reason: "no team member among organizer and attendees"}}, resp.Skipped)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		teams.EXPECT().GetTeamWithMembers(gomock.Any(), "ghost").Return(nil, repository.ErrTeamNotFound)
+
+		_, err := s.ImportCalendar(context.Background(), &dto.ImportCalendarRequest{TeamName: "ghost"})
+		assert.ErrorIs(t, err, service.ErrTeamNotFound)
+	})
+}
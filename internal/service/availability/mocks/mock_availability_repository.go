@@ -0,0 +1,233 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/availability/availability.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "service-order-avito/internal/domain"
+	dto "service-order-avito/internal/domain/dto"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAvailabilityRepository is a mock of AvailabilityRepository interface.
+type MockAvailabilityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAvailabilityRepositoryMockRecorder
+}
+
+// MockAvailabilityRepositoryMockRecorder is the mock recorder for MockAvailabilityRepository.
+type MockAvailabilityRepositoryMockRecorder struct {
+	mock *MockAvailabilityRepository
+}
+
+// NewMockAvailabilityRepository creates a new mock instance.
+func NewMockAvailabilityRepository(ctrl *gomock.Controller) *MockAvailabilityRepository {
+	mock := &MockAvailabilityRepository{ctrl: ctrl}
+	mock.recorder = &MockAvailabilityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAvailabilityRepository) EXPECT() *MockAvailabilityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddAbsence mocks base method.
+func (m *MockAvailabilityRepository) AddAbsence(arg0 context.Context, arg1 domain.Absence) (*domain.Absence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAbsence", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Absence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAbsence indicates an expected call of AddAbsence.
+func (mr *MockAvailabilityRepositoryMockRecorder) AddAbsence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAbsence", reflect.TypeOf((*MockAvailabilityRepository)(nil).AddAbsence), arg0, arg1)
+}
+
+// DeleteAbsence mocks base method.
+func (m *MockAvailabilityRepository) DeleteAbsence(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAbsence", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAbsence indicates an expected call of DeleteAbsence.
+func (mr *MockAvailabilityRepositoryMockRecorder) DeleteAbsence(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAbsence", reflect.TypeOf((*MockAvailabilityRepository)(nil).DeleteAbsence), arg0, arg1, arg2)
+}
+
+// GetWorkingHours mocks base method.
+func (m *MockAvailabilityRepository) GetWorkingHours(arg0 context.Context, arg1 string) (*domain.WorkingHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkingHours", arg0, arg1)
+	ret0, _ := ret[0].(*domain.WorkingHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkingHours indicates an expected call of GetWorkingHours.
+func (mr *MockAvailabilityRepositoryMockRecorder) GetWorkingHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkingHours", reflect.TypeOf((*MockAvailabilityRepository)(nil).GetWorkingHours), arg0, arg1)
+}
+
+// ImportAbsences mocks base method.
+func (m *MockAvailabilityRepository) ImportAbsences(arg0 context.Context, arg1 domain.AbsenceImport) (*domain.AbsenceImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportAbsences", arg0, arg1)
+	ret0, _ := ret[0].(*domain.AbsenceImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportAbsences indicates an expected call of ImportAbsences.
+func (mr *MockAvailabilityRepositoryMockRecorder) ImportAbsences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAbsences", reflect.TypeOf((*MockAvailabilityRepository)(nil).ImportAbsences), arg0, arg1)
+}
+
+// ListAbsences mocks base method.
+func (m *MockAvailabilityRepository) ListAbsences(arg0 context.Context, arg1 string) ([]domain.Absence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAbsences", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Absence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAbsences indicates an expected call of ListAbsences.
+func (mr *MockAvailabilityRepositoryMockRecorder) ListAbsences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAbsences", reflect.TypeOf((*MockAvailabilityRepository)(nil).ListAbsences), arg0, arg1)
+}
+
+// ListAbsentReviews mocks base method.
+func (m *MockAvailabilityRepository) ListAbsentReviews(arg0 context.Context, arg1 time.Time) ([]domain.AbsentReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAbsentReviews", arg0, arg1)
+	ret0, _ := ret[0].([]domain.AbsentReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAbsentReviews indicates an expected call of ListAbsentReviews.
+func (mr *MockAvailabilityRepositoryMockRecorder) ListAbsentReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAbsentReviews", reflect.TypeOf((*MockAvailabilityRepository)(nil).ListAbsentReviews), arg0, arg1)
+}
+
+// ListWorkingHours mocks base method.
+func (m *MockAvailabilityRepository) ListWorkingHours(arg0 context.Context, arg1 []string) (map[string]domain.WorkingHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkingHours", arg0, arg1)
+	ret0, _ := ret[0].(map[string]domain.WorkingHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkingHours indicates an expected call of ListWorkingHours.
+func (mr *MockAvailabilityRepositoryMockRecorder) ListWorkingHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkingHours", reflect.TypeOf((*MockAvailabilityRepository)(nil).ListWorkingHours), arg0, arg1)
+}
+
+// SetWorkingHours mocks base method.
+func (m *MockAvailabilityRepository) SetWorkingHours(arg0 context.Context, arg1 domain.WorkingHours) (*domain.WorkingHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWorkingHours", arg0, arg1)
+	ret0, _ := ret[0].(*domain.WorkingHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWorkingHours indicates an expected call of SetWorkingHours.
+func (mr *MockAvailabilityRepositoryMockRecorder) SetWorkingHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkingHours", reflect.TypeOf((*MockAvailabilityRepository)(nil).SetWorkingHours), arg0, arg1)
+}
+
+// MockTeamRepository is a mock of TeamRepository interface.
+type MockTeamRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamRepositoryMockRecorder
+}
+
+// MockTeamRepositoryMockRecorder is the mock recorder for MockTeamRepository.
+type MockTeamRepositoryMockRecorder struct {
+	mock *MockTeamRepository
+}
+
+// NewMockTeamRepository creates a new mock instance.
+func NewMockTeamRepository(ctrl *gomock.Controller) *MockTeamRepository {
+	mock := &MockTeamRepository{ctrl: ctrl}
+	mock.recorder = &MockTeamRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamRepository) EXPECT() *MockTeamRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTeamWithMembers mocks base method.
+func (m *MockTeamRepository) GetTeamWithMembers(arg0 context.Context, arg1 string) (*domain.TeamWithUsers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamWithMembers", arg0, arg1)
+	ret0, _ := ret[0].(*domain.TeamWithUsers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamWithMembers indicates an expected call of GetTeamWithMembers.
+func (mr *MockTeamRepositoryMockRecorder) GetTeamWithMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamWithMembers", reflect.TypeOf((*MockTeamRepository)(nil).GetTeamWithMembers), arg0, arg1)
+}
+
+// MockReassigner is a mock of Reassigner interface.
+type MockReassigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockReassignerMockRecorder
+}
+
+// MockReassignerMockRecorder is the mock recorder for MockReassigner.
+type MockReassignerMockRecorder struct {
+	mock *MockReassigner
+}
+
+// NewMockReassigner creates a new mock instance.
+func NewMockReassigner(ctrl *gomock.Controller) *MockReassigner {
+	mock := &MockReassigner{ctrl: ctrl}
+	mock.recorder = &MockReassignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReassigner) EXPECT() *MockReassignerMockRecorder {
+	return m.recorder
+}
+
+// ReassignAbsentReviewer mocks base method.
+func (m *MockReassigner) ReassignAbsentReviewer(arg0 context.Context, arg1 *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignAbsentReviewer", arg0, arg1)
+	ret0, _ := ret[0].(*dto.PullRequestReassignResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignAbsentReviewer indicates an expected call of ReassignAbsentReviewer.
+func (mr *MockReassignerMockRecorder) ReassignAbsentReviewer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignAbsentReviewer", reflect.TypeOf((*MockReassigner)(nil).ReassignAbsentReviewer), arg0, arg1)
+}
@@ -0,0 +1,116 @@
+package availability
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"sync"
+	"time"
+)
+
+// retryFailedAfter через сколько повторять замену, которая не удалась (нет кандидата, правила команды):
+// до тех пор состав команды вряд ли изменится, а попытка на каждом тике только засоряет лог
+const retryFailedAfter = time.Hour
+
+// Scheduler снимает с открытых PR ревьюеров, у которых началось отсутствие.
+// Периоды по дням недели (PART_TIME) не трогает: пользователь вернется через день-два,
+// а новые назначения его и так обходят
+type Scheduler struct {
+	log        *slog.Logger
+	repo       AvailabilityRepository
+	reassigner Reassigner
+	now        func() time.Time
+
+	mu sync.Mutex
+	// failed неудачные замены и когда они были. Забываются, когда назначение пропадает из выборки
+	// (отсутствие кончилось, PR влили) или через retryFailedAfter
+	failed map[domain.AbsentReview]time.Time
+}
+
+func NewScheduler(log *slog.Logger, repo AvailabilityRepository, reassigner Reassigner) *Scheduler {
+	return &Scheduler{
+		log:        log,
+		repo:       repo,
+		reassigner: reassigner,
+		now:        time.Now,
+		failed:     make(map[domain.AbsentReview]time.Time),
+	}
+}
+
+// Run вызывает ReassignAbsent каждые interval, пока не отменится ctx
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reassigned, err := s.ReassignAbsent(ctx)
+			if err != nil {
+				s.log.Error("reassign absent reviewers", slog.String("error", err.Error()))
+				continue
+			}
+			if reassigned > 0 {
+				s.log.Info("absent reviewers reassigned", slog.Int("reassigned", reassigned))
+			}
+		}
+	}
+}
+
+// ReassignAbsent заменяет отсутствующих ревьюеров и возвращает число замен.
+// Неудачная замена (нет кандидата, правила команды, PR успели влить) не прерывает проход
+// и повторяется не раньше чем через retryFailedAfter
+func (s *Scheduler) ReassignAbsent(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	reviews, err := s.repo.ListAbsentReviews(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	// назначения, которых больше нет в выборке, забываются: если отсутствие начнется снова, замена пойдет сразу
+	current := make(map[domain.AbsentReview]struct{}, len(reviews))
+	for _, review := range reviews {
+		current[review] = struct{}{}
+	}
+	for review := range s.failed {
+		if _, ok := current[review]; !ok {
+			delete(s.failed, review)
+		}
+	}
+
+	reassigned := 0
+	for _, review := range reviews {
+		if failedAt, ok := s.failed[review]; ok && now.Sub(failedAt) < retryFailedAfter {
+			continue
+		}
+
+		_, err := s.reassigner.ReassignAbsentReviewer(ctx, &dto.PullRequestReassignRequest{
+			PullRequestID: review.PullRequestID,
+			OldReviewerID: review.ReviewerID,
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrInternalError) {
+				return reassigned, err
+			}
+			s.failed[review] = now
+			s.log.Warn("absent reviewer not reassigned",
+				slog.String("pull_request_id", review.PullRequestID),
+				slog.String("reviewer_id", review.ReviewerID),
+				slog.String("error", err.Error()),
+				slog.Duration("retry_in", retryFailedAfter),
+			)
+			continue
+		}
+		delete(s.failed, review)
+		reassigned++
+	}
+	return reassigned, nil
+}
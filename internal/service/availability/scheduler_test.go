@@ -0,0 +1,120 @@
+package availability
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"service-order-avito/internal/domain"
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/service/availability/mocks"
+	"testing"
+	"time"
+)
+
+func newTestScheduler(t *testing.T) (*Scheduler, *mocks.MockAvailabilityRepository, *mocks.MockReassigner, time.Time) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAvailabilityRepository(ctrl)
+	reassigner := mocks.NewMockReassigner(ctrl)
+
+	now := time.Date(2025, 12, 22, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, reassigner)
+	s.now = func() time.Time { return now }
+	return s, repo, reassigner, now
+}
+
+func reassignOf(prID, oldID string) *dto.PullRequestReassignRequest {
+	return &dto.PullRequestReassignRequest{PullRequestID: prID, OldReviewerID: oldID}
+}
+
+func TestScheduler_ReassignAbsent(t *testing.T) {
+	t.Run("failed reassign does not stop the pass", func(t *testing.T) {
+		s, repo, reassigner, now := newTestScheduler(t)
+
+		repo.EXPECT().ListAbsentReviews(gomock.Any(), now).Return([]domain.AbsentReview{
+			{PullRequestID: "pr1", ReviewerID: "u2"},
+			{PullRequestID: "pr2", ReviewerID: "u2"},
+			{PullRequestID: "pr3", ReviewerID: "u3"},
+		}, nil)
+		gomock.InOrder(
+			reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr1", "u2")).
+				Return(&dto.PullRequestReassignResponse{ReplacedBy: "u4", Version: 2}, nil),
+			reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr2", "u2")).
+				Return(nil, &service.Error{Kind: service.ErrNoReplacementCandidate}),
+			reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr3", "u3")).
+				Return(&dto.PullRequestReassignResponse{ReplacedBy: "u1", Version: 3}, nil),
+		)
+
+		reassigned, err := s.ReassignAbsent(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, reassigned)
+	})
+
+	t.Run("failed reassign is not retried on every tick", func(t *testing.T) {
+		s, repo, reassigner, now := newTestScheduler(t)
+		reviews := []domain.AbsentReview{{PullRequestID: "pr1", ReviewerID: "u2"}}
+
+		repo.EXPECT().ListAbsentReviews(gomock.Any(), gomock.Any()).Return(reviews, nil).Times(3)
+		reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr1", "u2")).
+			Return(nil, &service.Error{Kind: service.ErrNoReplacementCandidate}).
+			Times(2)
+
+		for _, at := range []time.Time{now, now.Add(time.Minute), now.Add(retryFailedAfter)} {
+			s.now = func() time.Time { return at }
+			reassigned, err := s.ReassignAbsent(context.Background())
+			require.NoError(t, err)
+			assert.Zero(t, reassigned)
+		}
+	})
+
+	t.Run("failure is forgotten when review leaves the list", func(t *testing.T) {
+		s, repo, reassigner, _ := newTestScheduler(t)
+		review := domain.AbsentReview{PullRequestID: "pr1", ReviewerID: "u2"}
+
+		gomock.InOrder(
+			repo.EXPECT().ListAbsentReviews(gomock.Any(), gomock.Any()).Return([]domain.AbsentReview{review}, nil),
+			repo.EXPECT().ListAbsentReviews(gomock.Any(), gomock.Any()).Return([]domain.AbsentReview{}, nil),
+			repo.EXPECT().ListAbsentReviews(gomock.Any(), gomock.Any()).Return([]domain.AbsentReview{review}, nil),
+		)
+		gomock.InOrder(
+			reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr1", "u2")).
+				Return(nil, &service.Error{Kind: service.ErrNoReplacementCandidate}),
+			reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr1", "u2")).
+				Return(&dto.PullRequestReassignResponse{ReplacedBy: "u3", Version: 2}, nil),
+		)
+
+		for _, want := range []int{0, 0, 1} {
+			reassigned, err := s.ReassignAbsent(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, want, reassigned)
+		}
+	})
+
+	t.Run("internal error stops the pass", func(t *testing.T) {
+		s, repo, reassigner, now := newTestScheduler(t)
+
+		repo.EXPECT().ListAbsentReviews(gomock.Any(), now).Return([]domain.AbsentReview{
+			{PullRequestID: "pr1", ReviewerID: "u2"},
+			{PullRequestID: "pr2", ReviewerID: "u2"},
+		}, nil)
+		reassigner.EXPECT().ReassignAbsentReviewer(gomock.Any(), reassignOf("pr1", "u2")).
+			Return(nil, &service.Error{Kind: service.ErrInternalError, Cause: errors.New("db down")})
+
+		reassigned, err := s.ReassignAbsent(context.Background())
+		assert.ErrorIs(t, err, service.ErrInternalError)
+		assert.Zero(t, reassigned)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		s, repo, _, now := newTestScheduler(t)
+
+		repo.EXPECT().ListAbsentReviews(gomock.Any(), now).Return(nil, errors.New("db down"))
+
+		_, err := s.ReassignAbsent(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -1,33 +1,45 @@
 package error_wrapper
 
 import (
+	"errors"
 	"service-order-avito/internal/domain/errors/repository"
 	"service-order-avito/internal/domain/errors/service"
 )
 
-// сделал эту мапу, чтобы не передавать ошибки с уровня репозитория наверх к уровню контроллеров
-// в принципе, мне кажется, можно было бы и передавать, но я захотел реализовать более чистую архитектуру, полностью изолировав контроллер от репозитория
-var repoToServiceMap = map[string]error{
-	repository.ErrTeamAlreadyExists.Error():      service.ErrTeamAlreadyExists,
-	repository.ErrTeamNotFound.Error():           service.ErrTeamNotFound,
-	repository.ErrUserNotFound.Error():           service.ErrUserNotFound,
-	repository.ErrInternalError.Error():          service.ErrInternalError,
-	repository.ErrPullRequestExists.Error():      service.ErrPullRequestExists,
-	repository.ErrPullRequestNotFound.Error():    service.ErrPullRequestNotFound,
-	repository.ErrPullRequestMerged.Error():      service.ErrPullRequestMerged,
-	repository.ErrReviewerNotAssigned.Error():    service.ErrReviewerNotAssigned,
-	repository.ErrNoReplacementCandidate.Error(): service.ErrNoReplacementCandidate,
+// сделал это соответствие, чтобы не передавать ошибки с уровня репозитория наверх к уровню контроллеров
+// в принципе, мне кажется, можно было бы и передавать, но я захотел реализовать более чистую архитектуру, полностью изолировав контроллер от репозитория.
+// Ошибка репозитория при этом не теряется, а лежит в service.Error.Cause
+var repoToService = []struct {
+	repo    error
+	service error
+}{
+	{repository.ErrTeamAlreadyExists, service.ErrTeamAlreadyExists},
+	{repository.ErrTeamNotFound, service.ErrTeamNotFound},
+	{repository.ErrUserNotFound, service.ErrUserNotFound},
+	{repository.ErrPullRequestExists, service.ErrPullRequestExists},
+	{repository.ErrPullRequestNotFound, service.ErrPullRequestNotFound},
+	{repository.ErrPullRequestMerged, service.ErrPullRequestMerged},
+	{repository.ErrPullRequestClosed, service.ErrPullRequestClosed},
+	{repository.ErrReviewerNotAssigned, service.ErrReviewerNotAssigned},
+	{repository.ErrNoReplacementCandidate, service.ErrNoReplacementCandidate},
+	{repository.ErrReviewerRulesUnmet, service.ErrReviewerRulesUnmet},
+	{repository.ErrVersionMismatch, service.ErrVersionMismatch},
+	{repository.ErrAbsenceNotFound, service.ErrAbsenceNotFound},
+	{repository.ErrInternalError, service.ErrInternalError},
 }
 
-// WrapRepositoryError возвращает ошибку сервиса по ошибке репозитория
+// WrapRepositoryError возвращает ошибку сервиса по ошибке репозитория.
+// Сравнение идет через errors.Is, поэтому ошибки репозитория можно оборачивать
 func WrapRepositoryError(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	if mapped, ok := repoToServiceMap[err.Error()]; ok {
-		return mapped
+	for _, m := range repoToService {
+		if errors.Is(err, m.repo) {
+			return &service.Error{Kind: m.service, Cause: err}
+		}
 	}
 
-	return service.ErrInternalError
+	return &service.Error{Kind: service.ErrInternalError, Cause: err}
 }
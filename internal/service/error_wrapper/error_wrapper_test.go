@@ -0,0 +1,68 @@
+package error_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"service-order-avito/internal/domain/errors/repository"
+	"service-order-avito/internal/domain/errors/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapRepositoryError(t *testing.T) {
+	cause := errors.New("pgx: conn closed")
+
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantKind error
+	}{
+		{"team exists", repository.ErrTeamAlreadyExists, service.ErrTeamAlreadyExists},
+		{"team not found", repository.ErrTeamNotFound, service.ErrTeamNotFound},
+		{"user not found", repository.ErrUserNotFound, service.ErrUserNotFound},
+		{"pr exists", repository.ErrPullRequestExists, service.ErrPullRequestExists},
+		{"pr not found", repository.ErrPullRequestNotFound, service.ErrPullRequestNotFound},
+		{"pr merged", repository.ErrPullRequestMerged, service.ErrPullRequestMerged},
+		{"pr closed", repository.ErrPullRequestClosed, service.ErrPullRequestClosed},
+		{"not assigned", repository.ErrReviewerNotAssigned, service.ErrReviewerNotAssigned},
+		{"no candidate", repository.ErrNoReplacementCandidate, service.ErrNoReplacementCandidate},
+		{"reviewer rules unmet", repository.ErrReviewerRulesUnmet, service.ErrReviewerRulesUnmet},
+		{"version mismatch", repository.ErrVersionMismatch, service.ErrVersionMismatch},
+		{"internal", repository.ErrInternalError, service.ErrInternalError},
+		{"internal with cause", repository.Internal("op", cause), service.ErrInternalError},
+		{"wrapped not found", fmt.Errorf("op: %w", repository.ErrTeamNotFound), service.ErrTeamNotFound},
+		{"unknown", cause, service.ErrInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WrapRepositoryError(tt.repoErr)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.wantKind)
+			// исходная ошибка остается в цепочке
+			assert.ErrorIs(t, err, tt.repoErr)
+
+			var svcErr *service.Error
+			require.ErrorAs(t, err, &svcErr)
+			assert.Equal(t, tt.wantKind, svcErr.Kind)
+		})
+	}
+}
+
+func TestWrapRepositoryError_KeepsCause(t *testing.T) {
+	cause := errors.New("pgx: conn closed")
+
+	err := WrapRepositoryError(repository.Internal("repository.postgres.user.GetByID", cause))
+
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), cause.Error())
+	assert.Contains(t, err.Error(), "repository.postgres.user.GetByID")
+}
+
+func TestWrapRepositoryError_Nil(t *testing.T) {
+	assert.NoError(t, WrapRepositoryError(nil))
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	domain "service-order-avito/internal/domain"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,47 +36,223 @@ func (m *MockPullRequestRepository) EXPECT() *MockPullRequestRepositoryMockRecor
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockPullRequestRepository) Close(arg0 context.Context, arg1 string, arg2 int64) (*domain.PullRequestWithReviewers, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.PullRequestWithReviewers)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockPullRequestRepositoryMockRecorder) Close(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPullRequestRepository)(nil).Close), arg0, arg1, arg2)
+}
+
 // CreateWithReviewers mocks base method.
-func (m *MockPullRequestRepository) CreateWithReviewers(arg0 context.Context, arg1 domain.PullRequest) (*domain.PullRequestWithReviewers, error) {
+func (m *MockPullRequestRepository) CreateWithReviewers(arg0 context.Context, arg1 domain.PullRequest, arg2 int64) (*domain.PullRequestWithReviewers, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWithReviewers", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateWithReviewers", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*domain.PullRequestWithReviewers)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateWithReviewers indicates an expected call of CreateWithReviewers.
-func (mr *MockPullRequestRepositoryMockRecorder) CreateWithReviewers(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryMockRecorder) CreateWithReviewers(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithReviewers", reflect.TypeOf((*MockPullRequestRepository)(nil).CreateWithReviewers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithReviewers", reflect.TypeOf((*MockPullRequestRepository)(nil).CreateWithReviewers), arg0, arg1, arg2)
 }
 
-// Merge mocks base method.
-func (m *MockPullRequestRepository) Merge(arg0 context.Context, arg1 string) (*domain.PullRequestWithReviewers, error) {
+// Export mocks base method.
+func (m *MockPullRequestRepository) Export(arg0 context.Context, arg1 domain.ExportFilter, arg2 func(domain.PullRequestExport) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Merge", arg0, arg1)
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockPullRequestRepositoryMockRecorder) Export(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockPullRequestRepository)(nil).Export), arg0, arg1, arg2)
+}
+
+// GetAssignments mocks base method.
+func (m *MockPullRequestRepository) GetAssignments(arg0 context.Context, arg1 string) ([]domain.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignments", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignments indicates an expected call of GetAssignments.
+func (mr *MockPullRequestRepositoryMockRecorder) GetAssignments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignments", reflect.TypeOf((*MockPullRequestRepository)(nil).GetAssignments), arg0, arg1)
+}
+
+// GetByID mocks base method.
+func (m *MockPullRequestRepository) GetByID(arg0 context.Context, arg1 string) (*domain.PullRequestWithReviewers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(*domain.PullRequestWithReviewers)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPullRequestRepositoryMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPullRequestRepository)(nil).GetByID), arg0, arg1)
+}
+
+// GetByIDs mocks base method.
+func (m *MockPullRequestRepository) GetByIDs(arg0 context.Context, arg1 []string) ([]domain.PullRequestWithReviewers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]domain.PullRequestWithReviewers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockPullRequestRepositoryMockRecorder) GetByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockPullRequestRepository)(nil).GetByIDs), arg0, arg1)
+}
+
+// Merge mocks base method.
+func (m *MockPullRequestRepository) Merge(arg0 context.Context, arg1 string, arg2 int64) (*domain.PullRequestWithReviewers, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.PullRequestWithReviewers)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // Merge indicates an expected call of Merge.
-func (mr *MockPullRequestRepositoryMockRecorder) Merge(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryMockRecorder) Merge(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequestRepository)(nil).Merge), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockPullRequestRepository)(nil).Merge), arg0, arg1, arg2)
+}
+
+// PreviewReviewers mocks base method.
+func (m *MockPullRequestRepository) PreviewReviewers(arg0 context.Context, arg1 domain.PullRequest, arg2 int64) (*domain.ReviewerPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewReviewers", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.ReviewerPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewReviewers indicates an expected call of PreviewReviewers.
+func (mr *MockPullRequestRepositoryMockRecorder) PreviewReviewers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewReviewers", reflect.TypeOf((*MockPullRequestRepository)(nil).PreviewReviewers), arg0, arg1, arg2)
 }
 
 // ReassignReviewer mocks base method.
-func (m *MockPullRequestRepository) ReassignReviewer(arg0 context.Context, arg1, arg2 string) (*domain.Reviewer, error) {
+func (m *MockPullRequestRepository) ReassignReviewer(arg0 context.Context, arg1, arg2 string, arg3 int64, arg4 time.Time, arg5 int64) (*domain.Reviewer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReassignReviewer", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ReassignReviewer", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(*domain.Reviewer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ReassignReviewer indicates an expected call of ReassignReviewer.
-func (mr *MockPullRequestRepositoryMockRecorder) ReassignReviewer(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockPullRequestRepositoryMockRecorder) ReassignReviewer(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).ReassignReviewer), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(arg0 domain.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", arg0)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), arg0)
+}
+
+// MockReassignLimiter is a mock of ReassignLimiter interface.
+type MockReassignLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockReassignLimiterMockRecorder
+}
+
+// MockReassignLimiterMockRecorder is the mock recorder for MockReassignLimiter.
+type MockReassignLimiterMockRecorder struct {
+	mock *MockReassignLimiter
+}
+
+// NewMockReassignLimiter creates a new mock instance.
+func NewMockReassignLimiter(ctrl *gomock.Controller) *MockReassignLimiter {
+	mock := &MockReassignLimiter{ctrl: ctrl}
+	mock.recorder = &MockReassignLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReassignLimiter) EXPECT() *MockReassignLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockReassignLimiter) Allow(key string) (bool, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(time.Duration)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockReassignLimiterMockRecorder) Allow(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockReassignLimiter)(nil).Allow), key)
+}
+
+// Refund mocks base method.
+func (m *MockReassignLimiter) Refund(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Refund", key)
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockReassignLimiterMockRecorder) Refund(key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignReviewer", reflect.TypeOf((*MockPullRequestRepository)(nil).ReassignReviewer), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockReassignLimiter)(nil).Refund), key)
 }
@@ -2,39 +2,82 @@ package pull_request
 
 import (
 	"context"
+	"math"
+	"math/rand"
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/service"
 	"service-order-avito/internal/service/error_wrapper"
+	"slices"
+	"time"
 )
 
-// mockgen -source="internal/service/pull_request/pull_request.go" -destination="internal/service/pull_request/mocks/mock_pull_request_repository.go" -package=mocks PullRequestRepository
+// mockgen -source="internal/service/pull_request/pull_request.go" -destination="internal/service/pull_request/mocks/mock_pull_request_repository.go" -package=mocks PullRequestRepository,EventPublisher
+// Merge, Close и ReassignReviewer принимают ожидаемую версию PR (0 — без проверки)
+// и возвращают ErrVersionMismatch, если PR успели изменить
 type PullRequestRepository interface {
-	CreateWithReviewers(context.Context, domain.PullRequest) (*domain.PullRequestWithReviewers, error)
-	Merge(context.Context, string) (*domain.PullRequestWithReviewers, error)
-	ReassignReviewer(context.Context, string, string) (*domain.Reviewer, error)
+	// CreateWithReviewers и ReassignReviewer последний аргумент — seed генератора выбора, записывается вместе с выбором
+	CreateWithReviewers(context.Context, domain.PullRequest, int64) (*domain.PullRequestWithReviewers, error)
+	GetByID(context.Context, string) (*domain.PullRequestWithReviewers, error)
+	GetByIDs(context.Context, []string) ([]domain.PullRequestWithReviewers, error)
+	// Merge второе значение — изменился ли статус: повторный merge уже смерженного PR ничего не меняет
+	Merge(context.Context, string, int64) (*domain.PullRequestWithReviewers, bool, error)
+	// Close второе значение — изменился ли статус, как у Merge. Смерженный PR не закрыть (ErrPullRequestMerged)
+	Close(context.Context, string, int64) (*domain.PullRequestWithReviewers, bool, error)
+	// ReassignReviewer последний аргумент — момент замены, по нему считаются отсутствия и рабочее время
+	ReassignReviewer(context.Context, string, string, int64, time.Time, int64) (*domain.Reviewer, error)
+	// PreviewReviewers выбор, который сделал бы CreateWithReviewers, без записи
+	PreviewReviewers(context.Context, domain.PullRequest, int64) (*domain.ReviewerPreview, error)
+	// GetAssignments записанные выборы ревьюеров PR в порядке записи
+	GetAssignments(context.Context, string) ([]domain.Assignment, error)
+	// Export отдает PR по фильтру по одному, не загружая выборку целиком
+	Export(context.Context, domain.ExportFilter, func(domain.PullRequestExport) error) error
+}
+
+// EventPublisher получает события после успешной записи в репозиторий. Publish не должен блокироваться
+type EventPublisher interface {
+	Publish(domain.Event)
+}
+
+// ReassignLimiter ограничивает число замен ревьюеров одного PR (реализация в internal/ratelimit).
+// Refund возвращает попытку, если замена не состоялась
+type ReassignLimiter interface {
+	Allow(key string) (bool, time.Duration)
+	Refund(key string)
 }
 
 type pullRequestService struct {
-	repo PullRequestRepository
+	repo            PullRequestRepository
+	publisher       EventPublisher
+	reassignLimiter ReassignLimiter
+	// now источник времени: момент назначения решает, кто в отпуске и у кого рабочее время. Тесты подменяют его
+	now func() time.Time
+	// seed зерно для каждого выбора ревьюеров. Тесты фиксируют его, чтобы выбор повторялся
+	seed func() int64
 }
 
-func NewPullRequestService(repo PullRequestRepository) *pullRequestService {
-	return &pullRequestService{repo: repo}
+// NewPullRequestService reassignLimiter может быть nil — тогда замены не ограничиваются
+func NewPullRequestService(repo PullRequestRepository, publisher EventPublisher, reassignLimiter ReassignLimiter) *pullRequestService {
+	return &pullRequestService{repo: repo, publisher: publisher, reassignLimiter: reassignLimiter, now: time.Now, seed: rand.Int63}
 }
 
 func (s *pullRequestService) Create(ctx context.Context, req *dto.PullRequestCreateRequest) (*dto.PullRequestCreateResponse, error) {
 	prDomain := domain.PullRequest{
-		ID:       req.PullRequestID,
-		Name:     req.PullRequestName,
-		AuthorID: req.AuthorID,
-		Status:   "OPEN",
+		ID:           req.PullRequestID,
+		Name:         req.PullRequestName,
+		AuthorID:     req.AuthorID,
+		Status:       "OPEN",
+		CreatedAt:    s.now(),
+		RequiredTags: req.RequiredTags,
 	}
 
-	prWithReviewers, err := s.repo.CreateWithReviewers(ctx, prDomain)
+	prWithReviewers, err := s.repo.CreateWithReviewers(ctx, prDomain, s.seed())
 	if err != nil {
 		return nil, error_wrapper.WrapRepositoryError(err)
 	}
 
+	s.publisher.Publish(newEvent(domain.EventPullRequestCreated, prWithReviewers, s.now()))
+
 	resp := &dto.PullRequestCreateResponse{
 		PullRequest: dto.PullRequestResponse{
 			PullRequestID:     prWithReviewers.ID,
@@ -42,41 +85,264 @@ func (s *pullRequestService) Create(ctx context.Context, req *dto.PullRequestCre
 			AuthorID:          prWithReviewers.AuthorID,
 			Status:            prWithReviewers.Status,
 			AssignedReviewers: prWithReviewers.AssignedReviewers,
+			RequiredTags:      prWithReviewers.RequiredTags,
+			Version:           prWithReviewers.Version,
 		},
 	}
 
 	return resp, nil
 }
 
+func (s *pullRequestService) Get(ctx context.Context, req *dto.GetPullRequestRequest) (*dto.GetPullRequestResponse, error) {
+	prWithReviewers, err := s.repo.GetByID(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return &dto.GetPullRequestResponse{
+		PullRequest: toPullRequestMergedResponse(prWithReviewers),
+	}, nil
+}
+
+// GetMany PR пачкой, несуществующие id пропускаются
+func (s *pullRequestService) GetMany(ctx context.Context, req *dto.GetPullRequestsRequest) (*dto.GetPullRequestsResponse, error) {
+	prs, err := s.repo.GetByIDs(ctx, req.PullRequestIDs)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := make([]dto.PullRequestMergedResponse, len(prs))
+	for i := range prs {
+		resp[i] = toPullRequestMergedResponse(&prs[i])
+	}
+
+	return &dto.GetPullRequestsResponse{PullRequests: resp}, nil
+}
+
 func (s *pullRequestService) Merge(ctx context.Context, req *dto.PullRequestMergeRequest) (*dto.PullRequestMergeResponse, error) {
-	prWithReviewers, err := s.repo.Merge(ctx, req.PullRequestID)
+	prWithReviewers, merged, err := s.repo.Merge(ctx, req.PullRequestID, req.ExpectedVersion)
 	if err != nil {
 		return nil, error_wrapper.WrapRepositoryError(err)
 	}
 
+	// повторный merge идемпотентен: ответ тот же, но события нет, подписчики уже получили его при первом
+	if merged {
+		s.publisher.Publish(newEvent(domain.EventPullRequestMerged, prWithReviewers, s.now()))
+	}
+
 	resp := &dto.PullRequestMergeResponse{
-		PullRequest: dto.PullRequestMergedResponse{
-			PullRequestID:     prWithReviewers.ID,
-			PullRequestName:   prWithReviewers.Name,
-			AuthorID:          prWithReviewers.AuthorID,
-			Status:            prWithReviewers.Status,
-			AssignedReviewers: prWithReviewers.AssignedReviewers,
-			MergedAt:          prWithReviewers.MergedAt,
-		},
+		PullRequest: toPullRequestMergedResponse(prWithReviewers),
+	}
+
+	return resp, nil
+}
+
+func (s *pullRequestService) Close(ctx context.Context, req *dto.PullRequestCloseRequest) (*dto.PullRequestCloseResponse, error) {
+	prWithReviewers, closed, err := s.repo.Close(ctx, req.PullRequestID, req.ExpectedVersion)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	// повторный close идемпотентен так же, как merge: событие только на первый
+	if closed {
+		s.publisher.Publish(newEvent(domain.EventPullRequestClosed, prWithReviewers, s.now()))
+	}
+
+	resp := &dto.PullRequestCloseResponse{
+		PullRequest: toPullRequestMergedResponse(prWithReviewers),
 	}
 
 	return resp, nil
 }
 
+func toPullRequestMergedResponse(pr *domain.PullRequestWithReviewers) dto.PullRequestMergedResponse {
+	return dto.PullRequestMergedResponse{
+		PullRequestID:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            pr.Status,
+		AssignedReviewers: pr.AssignedReviewers,
+		MergedAt:          pr.MergedAt,
+		RequiredTags:      pr.RequiredTags,
+		Version:           pr.Version,
+	}
+}
+
+// ReassignReviewer замена считается в лимит PR только если состоялась: ошибки (NOT_ASSIGNED, NO_CANDIDATE, ...) попытку возвращают
 func (s *pullRequestService) ReassignReviewer(ctx context.Context, req *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error) {
-	reviewer, err := s.repo.ReassignReviewer(ctx, req.PullRequestID, req.OldReviewerID)
+	if s.reassignLimiter != nil {
+		if ok, wait := s.reassignLimiter.Allow(req.PullRequestID); !ok {
+			return nil, &service.Error{Kind: service.ErrReassignLimitExceeded, RetryAfter: wait}
+		}
+	}
+
+	resp, err := s.reassign(ctx, req)
+	if err != nil && s.reassignLimiter != nil {
+		s.reassignLimiter.Refund(req.PullRequestID)
+	}
+	return resp, err
+}
+
+// ReassignAbsentReviewer замена ревьюера, у которого началось отсутствие (планировщик availability).
+// Та же замена с событием, что и ReassignReviewer, но мимо лимита замен: лимит защищает PR от частых ручных
+// замен, а снятие отсутствующих не должно ни упираться в него, ни расходовать попытки клиентов
+func (s *pullRequestService) ReassignAbsentReviewer(ctx context.Context, req *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error) {
+	return s.reassign(ctx, req)
+}
+
+func (s *pullRequestService) reassign(ctx context.Context, req *dto.PullRequestReassignRequest) (*dto.PullRequestReassignResponse, error) {
+	reviewer, err := s.repo.ReassignReviewer(ctx, req.PullRequestID, req.OldReviewerID, req.ExpectedVersion, s.now(), s.seed())
 	if err != nil {
 		return nil, error_wrapper.WrapRepositoryError(err)
 	}
 
+	s.publisher.Publish(domain.Event{
+		Type:          domain.EventPullRequestReassigned,
+		PullRequestID: req.PullRequestID,
+		OldReviewerID: req.OldReviewerID,
+		NewReviewerID: reviewer.ID,
+		Version:       reviewer.PullRequestVersion,
+		OccurredAt:    s.now(),
+	})
+
 	resp := &dto.PullRequestReassignResponse{
 		ReplacedBy: reviewer.ID,
+		Version:    reviewer.PullRequestVersion,
+	}
+
+	return resp, nil
+}
+
+// Explain почему ревьюеры PR выбраны именно так: записанные выборы с seed, стратегией и всеми кандидатами
+func (s *pullRequestService) Explain(ctx context.Context, req *dto.ExplainAssignmentsRequest) (*dto.PullRequestAssignmentsResponse, error) {
+	assignments, err := s.repo.GetAssignments(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := &dto.PullRequestAssignmentsResponse{
+		PullRequestID: req.PullRequestID,
+		Assignments:   []dto.AssignmentResponse{},
+	}
+	for _, a := range assignments {
+		reviewers := a.Reviewers()
+		if req.ReviewerID != "" && !slices.Contains(reviewers, req.ReviewerID) {
+			continue
+		}
+		resp.Assignments = append(resp.Assignments, dto.AssignmentResponse{
+			AssignmentID:   a.ID,
+			Kind:           string(a.Kind),
+			ReplacedUserID: a.ReplacedUserID,
+			Reviewers:      reviewers,
+			Seed:           a.Seed,
+			Strategy:       string(a.Strategy),
+			AssignedAt:     a.At,
+			Candidates:     toCandidateResponses(a.Candidates),
+		})
 	}
 
 	return resp, nil
 }
+
+// PreviewReviewers кого назначило бы создание PR автора сейчас: тот же выбор, что и в Create, но ничего не пишется
+// и событий нет. seed свой на каждый вызов, поэтому среди равных кандидатов создание может выбрать других
+func (s *pullRequestService) PreviewReviewers(ctx context.Context, req *dto.PreviewReviewersRequest) (*dto.ReviewersPreviewResponse, error) {
+	preview, err := s.repo.PreviewReviewers(ctx, domain.PullRequest{
+		AuthorID:     req.AuthorID,
+		CreatedAt:    s.now(),
+		RequiredTags: req.RequiredTags,
+	}, s.seed())
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	excluded := make([]dto.ExcludedMemberResponse, len(preview.Excluded))
+	for i, e := range preview.Excluded {
+		excluded[i] = dto.ExcludedMemberResponse{UserID: e.UserID, Reason: string(e.Reason)}
+	}
+	unmet := make([]dto.ReviewerRuleResponse, len(preview.UnmetRules))
+	for i, r := range preview.UnmetRules {
+		unmet[i] = dto.ReviewerRuleResponse{MinLevel: string(r.MinLevel), Count: r.Count}
+	}
+
+	return &dto.ReviewersPreviewResponse{
+		AuthorID:     preview.AuthorID,
+		RequiredTags: preview.RequiredTags,
+		Reviewers:    preview.Reviewers,
+		Seed:         preview.Seed,
+		Strategy:     string(preview.Strategy),
+		Candidates:   toCandidateResponses(preview.Candidates),
+		Excluded:     excluded,
+		UnmetRules:   unmet,
+	}, nil
+}
+
+func toCandidateResponses(candidates []domain.CandidateExplanation) []dto.AssignmentCandidateResponse {
+	resp := make([]dto.AssignmentCandidateResponse, len(candidates))
+	for i, c := range candidates {
+		resp[i] = dto.AssignmentCandidateResponse{
+			UserID:       c.UserID,
+			Rank:         c.Rank,
+			Chosen:       c.Chosen,
+			Reason:       string(c.Reason),
+			WorkingHours: c.Working,
+			PairingScore: math.Round(c.PairingScore*100) / 100,
+			Level:        string(c.Level),
+			Tags:         c.Tags,
+		}
+	}
+	return resp
+}
+
+func newEvent(eventType string, pr *domain.PullRequestWithReviewers, occurredAt time.Time) domain.Event {
+	return domain.Event{
+		Type:              eventType,
+		PullRequestID:     pr.ID,
+		AuthorID:          pr.AuthorID,
+		Name:              pr.Name,
+		Status:            pr.Status,
+		MergedAt:          pr.MergedAt,
+		AssignedReviewers: pr.AssignedReviewers,
+		Version:           pr.Version,
+		OccurredAt:        occurredAt,
+	}
+}
+
+// Export выгрузка PR по фильтрам. fn вызывается на каждую строку по мере чтения из репозитория;
+// ошибка fn прерывает выгрузку и возвращается обернутой в service.Error
+func (s *pullRequestService) Export(ctx context.Context, req *dto.ExportRequest, fn func(dto.PullRequestExportRow) error) error {
+	filter := domain.ExportFilter{TeamName: req.TeamName}
+	if req.From != "" {
+		from, _, err := dto.ParseTimestamp(req.From)
+		if err != nil {
+			return err
+		}
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, dateOnly, err := dto.ParseTimestamp(req.To)
+		if err != nil {
+			return err
+		}
+		// to=2025-11-30 — включая весь день 30 ноября
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	err := s.repo.Export(ctx, filter, func(pr domain.PullRequestExport) error {
+		return fn(dto.PullRequestExportRow{
+			PullRequestID:     pr.ID,
+			PullRequestName:   pr.Name,
+			AuthorID:          pr.AuthorID,
+			TeamName:          pr.TeamName,
+			Status:            pr.Status,
+			AssignedReviewers: pr.AssignedReviewers,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+			Version:           pr.Version,
+		})
+	})
+	return error_wrapper.WrapRepositoryError(err)
+}
@@ -3,6 +3,8 @@ package pull_request
 import (
 	"context"
 	"service-order-avito/internal/domain/errors/repository"
+	serviceErrors "service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/ratelimit"
 	"testing"
 	"time"
 
@@ -19,39 +21,58 @@ func TestPullRequestService_Create_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
-	service := NewPullRequestService(mockRepo)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+	// момент создания берется из часов сервиса: по нему репозиторий выбирает ревьюеров
+	now := time.Date(2025, 12, 10, 14, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	// seed передается в репозиторий и записывается вместе с выбором
+	service.seed = func() int64 { return 42 }
 
 	req := &dto.PullRequestCreateRequest{
 		PullRequestID:   "pr1",
 		PullRequestName: "Fix bug",
 		AuthorID:        "user1",
+		RequiredTags:    []string{"go"},
 	}
 
 	expectedDomain := domain.PullRequest{
-		ID:       "pr1",
-		Name:     "Fix bug",
-		AuthorID: "user1",
-		Status:   "OPEN",
+		ID:           "pr1",
+		Name:         "Fix bug",
+		AuthorID:     "user1",
+		Status:       "OPEN",
+		CreatedAt:    now,
+		RequiredTags: []string{"go"},
 	}
 
-	now := time.Now()
 	prWithReviewers := &domain.PullRequestWithReviewers{
 		PullRequest: domain.PullRequest{
-			ID:        "pr1",
-			Name:      "Fix bug",
-			AuthorID:  "user1",
-			Status:    "OPEN",
-			CreatedAt: now,
-			MergedAt:  nil,
+			ID:           "pr1",
+			Name:         "Fix bug",
+			AuthorID:     "user1",
+			Status:       "OPEN",
+			CreatedAt:    now,
+			MergedAt:     nil,
+			RequiredTags: []string{"go"},
 		},
 		AssignedReviewers: []string{"rev1", "rev2"},
 	}
 
 	mockRepo.
 		EXPECT().
-		CreateWithReviewers(gomock.Any(), expectedDomain).
+		CreateWithReviewers(gomock.Any(), expectedDomain, int64(42)).
 		Return(prWithReviewers, nil)
 
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Do(func(e domain.Event) {
+			require.Equal(t, domain.EventPullRequestCreated, e.Type)
+			require.Equal(t, "pr1", e.PullRequestID)
+			require.Equal(t, "user1", e.AuthorID)
+			require.Equal(t, []string{"rev1", "rev2"}, e.AssignedReviewers)
+		})
+
 	resp, err := service.Create(context.Background(), req)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -61,6 +82,7 @@ func TestPullRequestService_Create_Success(t *testing.T) {
 	require.Equal(t, "user1", resp.PullRequest.AuthorID)
 	require.Equal(t, "OPEN", resp.PullRequest.Status)
 	require.Equal(t, []string{"rev1", "rev2"}, resp.PullRequest.AssignedReviewers)
+	require.Equal(t, []string{"go"}, resp.PullRequest.RequiredTags)
 }
 
 func TestPullRequestService_Create_Error(t *testing.T) {
@@ -68,13 +90,14 @@ func TestPullRequestService_Create_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
-	service := NewPullRequestService(mockRepo)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
 
 	repoErr := repository.ErrPullRequestExists
 
 	mockRepo.
 		EXPECT().
-		CreateWithReviewers(gomock.Any(), gomock.Any()).
+		CreateWithReviewers(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, repoErr)
 
 	_, err := service.Create(context.Background(), &dto.PullRequestCreateRequest{})
@@ -87,10 +110,12 @@ func TestPullRequestService_Merge_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
-	service := NewPullRequestService(mockRepo)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
 
 	req := &dto.PullRequestMergeRequest{
-		PullRequestID: "pr1",
+		PullRequestID:   "pr1",
+		ExpectedVersion: 3,
 	}
 
 	merged := &domain.PullRequestWithReviewers{
@@ -99,14 +124,24 @@ func TestPullRequestService_Merge_Success(t *testing.T) {
 			Name:     "Feature X",
 			AuthorID: "user1",
 			Status:   "MERGED",
+			Version:  4,
 		},
 		AssignedReviewers: []string{"rev1"},
 	}
 
 	mockRepo.
 		EXPECT().
-		Merge(gomock.Any(), "pr1").
-		Return(merged, nil)
+		Merge(gomock.Any(), "pr1", int64(3)).
+		Return(merged, true, nil)
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Do(func(e domain.Event) {
+			require.Equal(t, domain.EventPullRequestMerged, e.Type)
+			require.Equal(t, "MERGED", e.Status)
+			require.Equal(t, int64(4), e.Version)
+		})
 
 	resp, err := service.Merge(context.Background(), req)
 	require.NoError(t, err)
@@ -116,6 +151,32 @@ func TestPullRequestService_Merge_Success(t *testing.T) {
 	require.Equal(t, "user1", resp.PullRequest.AuthorID)
 	require.Equal(t, "MERGED", resp.PullRequest.Status)
 	require.Equal(t, []string{"rev1"}, resp.PullRequest.AssignedReviewers)
+	require.Equal(t, int64(4), resp.PullRequest.Version)
+}
+
+// Повторный merge отвечает как первый, но события нет: оно уже ушло при первом
+func TestPullRequestService_Merge_AlreadyMerged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	merged := &domain.PullRequestWithReviewers{
+		PullRequest: domain.PullRequest{ID: "pr1", Name: "Feature X", AuthorID: "user1", Status: "MERGED", Version: 4},
+	}
+
+	mockRepo.
+		EXPECT().
+		Merge(gomock.Any(), "pr1", int64(0)).
+		Return(merged, false, nil)
+	mockPublisher.EXPECT().Publish(gomock.Any()).Times(0)
+
+	resp, err := service.Merge(context.Background(), &dto.PullRequestMergeRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	require.Equal(t, "MERGED", resp.PullRequest.Status)
+	require.Equal(t, int64(4), resp.PullRequest.Version)
 }
 
 func TestPullRequestService_Merge_Error(t *testing.T) {
@@ -123,14 +184,15 @@ func TestPullRequestService_Merge_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
-	service := NewPullRequestService(mockRepo)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
 
 	repoErr := repository.ErrPullRequestNotFound
 
 	mockRepo.
 		EXPECT().
-		Merge(gomock.Any(), "pr1").
-		Return(nil, repoErr)
+		Merge(gomock.Any(), "pr1", int64(0)).
+		Return(nil, false, repoErr)
 
 	_, err := service.Merge(context.Background(),
 		&dto.PullRequestMergeRequest{PullRequestID: "pr1"},
@@ -140,28 +202,119 @@ func TestPullRequestService_Merge_Error(t *testing.T) {
 	require.Equal(t, error_wrapper.WrapRepositoryError(repoErr), err)
 }
 
+func TestPullRequestService_Close_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	closed := &domain.PullRequestWithReviewers{
+		PullRequest:       domain.PullRequest{ID: "pr1", Name: "Feature X", AuthorID: "user1", Status: "CLOSED", Version: 4},
+		AssignedReviewers: []string{"rev1"},
+	}
+
+	mockRepo.
+		EXPECT().
+		Close(gomock.Any(), "pr1", int64(3)).
+		Return(closed, true, nil)
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Do(func(e domain.Event) {
+			require.Equal(t, domain.EventPullRequestClosed, e.Type)
+			require.Equal(t, "CLOSED", e.Status)
+			require.Equal(t, int64(4), e.Version)
+		})
+
+	resp, err := service.Close(context.Background(), &dto.PullRequestCloseRequest{PullRequestID: "pr1", ExpectedVersion: 3})
+	require.NoError(t, err)
+	require.Equal(t, "CLOSED", resp.PullRequest.Status)
+	require.Equal(t, []string{"rev1"}, resp.PullRequest.AssignedReviewers)
+	require.Equal(t, int64(4), resp.PullRequest.Version)
+}
+
+func TestPullRequestService_Close_AlreadyClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	closed := &domain.PullRequestWithReviewers{
+		PullRequest: domain.PullRequest{ID: "pr1", Name: "Feature X", AuthorID: "user1", Status: "CLOSED", Version: 4},
+	}
+
+	mockRepo.
+		EXPECT().
+		Close(gomock.Any(), "pr1", int64(0)).
+		Return(closed, false, nil)
+	mockPublisher.EXPECT().Publish(gomock.Any()).Times(0)
+
+	resp, err := service.Close(context.Background(), &dto.PullRequestCloseRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	require.Equal(t, "CLOSED", resp.PullRequest.Status)
+}
+
+func TestPullRequestService_Close_Merged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	mockRepo.
+		EXPECT().
+		Close(gomock.Any(), "pr1", int64(0)).
+		Return(nil, false, repository.ErrPullRequestMerged)
+
+	_, err := service.Close(context.Background(), &dto.PullRequestCloseRequest{PullRequestID: "pr1"})
+	require.ErrorIs(t, err, serviceErrors.ErrPullRequestMerged)
+}
+
 func TestPullRequestService_ReassignReviewer_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
-	service := NewPullRequestService(mockRepo)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
 
 	req := &dto.PullRequestReassignRequest{
-		PullRequestID: "pr1",
-		OldReviewerID: "rev_old",
+		PullRequestID:   "pr1",
+		OldReviewerID:   "rev_old",
+		ExpectedVersion: 2,
 	}
 
-	expectedReviewer := &domain.Reviewer{ID: "rev_new"}
+	expectedReviewer := &domain.Reviewer{ID: "rev_new", PullRequestVersion: 3}
+	now := time.Date(2025, 12, 10, 14, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	service.seed = func() int64 { return 7 }
 
 	mockRepo.
 		EXPECT().
-		ReassignReviewer(gomock.Any(), "pr1", "rev_old").
+		ReassignReviewer(gomock.Any(), "pr1", "rev_old", int64(2), now, int64(7)).
 		Return(expectedReviewer, nil)
 
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Do(func(e domain.Event) {
+			require.Equal(t, domain.EventPullRequestReassigned, e.Type)
+			require.Equal(t, "rev_old", e.OldReviewerID)
+			require.Equal(t, "rev_new", e.NewReviewerID)
+			require.Equal(t, int64(3), e.Version)
+			require.Equal(t, now, e.OccurredAt)
+		})
+
 	resp, err := service.ReassignReviewer(context.Background(), req)
 	require.NoError(t, err)
 	require.Equal(t, "rev_new", resp.ReplacedBy)
+	require.Equal(t, int64(3), resp.Version)
 }
 
 func TestPullRequestService_ReassignReviewer_Error(t *testing.T) {
@@ -169,13 +322,14 @@ func TestPullRequestService_ReassignReviewer_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
-	service := NewPullRequestService(mockRepo)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
 
 	repoErr := repository.ErrNoReplacementCandidate
 
 	mockRepo.
 		EXPECT().
-		ReassignReviewer(gomock.Any(), "pr1", "rev1").
+		ReassignReviewer(gomock.Any(), "pr1", "rev1", int64(0), gomock.Any(), gomock.Any()).
 		Return(nil, repoErr)
 
 	_, err := service.ReassignReviewer(context.Background(),
@@ -188,3 +342,328 @@ func TestPullRequestService_ReassignReviewer_Error(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, error_wrapper.WrapRepositoryError(repoErr), err)
 }
+
+func TestPullRequestService_ReassignReviewer_LimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, ratelimit.New(ratelimit.Limit{N: 1, Per: time.Hour}))
+
+	mockRepo.
+		EXPECT().
+		ReassignReviewer(gomock.Any(), "pr1", "rev1", int64(0), gomock.Any(), gomock.Any()).
+		Return(&domain.Reviewer{ID: "rev2", PullRequestVersion: 2}, nil)
+	mockPublisher.EXPECT().Publish(gomock.Any())
+
+	req := &dto.PullRequestReassignRequest{PullRequestID: "pr1", OldReviewerID: "rev1"}
+	_, err := service.ReassignReviewer(context.Background(), req)
+	require.NoError(t, err)
+
+	// в репозиторий второй вызов уже не идет
+	_, err = service.ReassignReviewer(context.Background(), req)
+	require.ErrorIs(t, err, serviceErrors.ErrReassignLimitExceeded)
+	var svcErr *serviceErrors.Error
+	require.ErrorAs(t, err, &svcErr)
+	require.Greater(t, svcErr.RetryAfter, 59*time.Minute)
+}
+
+func TestPullRequestService_ReassignReviewer_FailedAttemptRefunded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, ratelimit.New(ratelimit.Limit{N: 1, Per: time.Hour}))
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			ReassignReviewer(gomock.Any(), "pr1", "rev1", int64(0), gomock.Any(), gomock.Any()).
+			Return(nil, repository.ErrNoReplacementCandidate),
+		mockRepo.
+			EXPECT().
+			ReassignReviewer(gomock.Any(), "pr1", "rev1", int64(0), gomock.Any(), gomock.Any()).
+			Return(&domain.Reviewer{ID: "rev2", PullRequestVersion: 2}, nil),
+	)
+	mockPublisher.EXPECT().Publish(gomock.Any())
+
+	req := &dto.PullRequestReassignRequest{PullRequestID: "pr1", OldReviewerID: "rev1"}
+	_, err := service.ReassignReviewer(context.Background(), req)
+	require.ErrorIs(t, err, serviceErrors.ErrNoReplacementCandidate)
+
+	_, err = service.ReassignReviewer(context.Background(), req)
+	require.NoError(t, err)
+}
+
+// Замены планировщика отсутствий не упираются в лимит и не расходуют его
+func TestPullRequestService_ReassignAbsentReviewer_BypassesLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, ratelimit.New(ratelimit.Limit{N: 1, Per: time.Hour}))
+
+	mockRepo.
+		EXPECT().
+		ReassignReviewer(gomock.Any(), "pr1", gomock.Any(), int64(0), gomock.Any(), gomock.Any()).
+		Return(&domain.Reviewer{ID: "rev3", PullRequestVersion: 2}, nil).
+		Times(3)
+	mockPublisher.EXPECT().Publish(gomock.Any()).Times(3)
+
+	for _, old := range []string{"rev1", "rev2"} {
+		_, err := service.ReassignAbsentReviewer(context.Background(), &dto.PullRequestReassignRequest{PullRequestID: "pr1", OldReviewerID: old})
+		require.NoError(t, err)
+	}
+
+	// попытка клиента осталась целой
+	_, err := service.ReassignReviewer(context.Background(), &dto.PullRequestReassignRequest{PullRequestID: "pr1", OldReviewerID: "rev3"})
+	require.NoError(t, err)
+}
+
+func TestPullRequestService_Get_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	pr := &domain.PullRequestWithReviewers{
+		PullRequest: domain.PullRequest{
+			ID:       "pr1",
+			Name:     "Feature X",
+			AuthorID: "user1",
+			Status:   "OPEN",
+			Version:  2,
+		},
+		AssignedReviewers: []string{"rev1", "rev2"},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(gomock.Any(), "pr1").
+		Return(pr, nil)
+
+	resp, err := service.Get(context.Background(), &dto.GetPullRequestRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	require.Equal(t, "pr1", resp.PullRequest.PullRequestID)
+	require.Equal(t, []string{"rev1", "rev2"}, resp.PullRequest.AssignedReviewers)
+	require.Equal(t, int64(2), resp.PullRequest.Version)
+}
+
+func TestPullRequestService_Get_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	repoErr := repository.ErrPullRequestNotFound
+
+	mockRepo.
+		EXPECT().
+		GetByID(gomock.Any(), "pr1").
+		Return(nil, repoErr)
+
+	_, err := service.Get(context.Background(), &dto.GetPullRequestRequest{PullRequestID: "pr1"})
+
+	require.Error(t, err)
+	require.Equal(t, error_wrapper.WrapRepositoryError(repoErr), err)
+}
+
+func TestPullRequestService_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	createdAt := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	// дата в to включает весь день
+	to := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.
+		EXPECT().
+		Export(gomock.Any(), domain.ExportFilter{TeamName: "backend", From: &from, To: &to}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ domain.ExportFilter, fn func(domain.PullRequestExport) error) error {
+			return fn(domain.PullRequestExport{
+				PullRequestWithReviewers: domain.PullRequestWithReviewers{
+					PullRequest:       domain.PullRequest{ID: "pr1", Name: "Fix bug", AuthorID: "u1", Status: "OPEN", CreatedAt: createdAt, Version: 1},
+					AssignedReviewers: []string{"u2"},
+				},
+				TeamName: "backend",
+			})
+		})
+
+	var rows []dto.PullRequestExportRow
+	err := service.Export(context.Background(), &dto.ExportRequest{TeamName: "backend", From: "2025-11-01", To: "2025-11-30"}, func(row dto.PullRequestExportRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []dto.PullRequestExportRow{{
+		PullRequestID:     "pr1",
+		PullRequestName:   "Fix bug",
+		AuthorID:          "u1",
+		TeamName:          "backend",
+		Status:            "OPEN",
+		AssignedReviewers: []string{"u2"},
+		CreatedAt:         createdAt,
+		Version:           1,
+	}}, rows)
+}
+
+func TestPullRequestService_Export_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	mockPublisher := mocks.NewMockEventPublisher(ctrl)
+	service := NewPullRequestService(mockRepo, mockPublisher, nil)
+
+	repoErr := repository.ErrTeamNotFound
+
+	mockRepo.
+		EXPECT().
+		Export(gomock.Any(), domain.ExportFilter{TeamName: "missing"}, gomock.Any()).
+		Return(repoErr)
+
+	err := service.Export(context.Background(), &dto.ExportRequest{TeamName: "missing"}, func(dto.PullRequestExportRow) error { return nil })
+
+	require.Error(t, err)
+	require.Equal(t, error_wrapper.WrapRepositoryError(repoErr), err)
+}
+
+func TestPullRequestService_Explain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	service := NewPullRequestService(mockRepo, mocks.NewMockEventPublisher(ctrl), nil)
+
+	at := time.Date(2025, 12, 16, 9, 0, 0, 0, time.UTC)
+	assignments := []domain.Assignment{
+		{
+			ID: 1, PullRequestID: "pr1", Kind: domain.AssignmentCreate, Seed: -42, Strategy: domain.PairingDiversity, At: at,
+			Candidates: []domain.CandidateExplanation{
+				{UserID: "u2", Rank: 1, Working: true, Level: domain.LevelSenior, Tags: []string{"go"}, Chosen: true, Reason: domain.ReasonRequiredTags},
+				{UserID: "u3", Rank: 2, Working: true, PairingScore: 1.0 / 3, Chosen: true, Reason: domain.ReasonRank},
+				{UserID: "u4", Rank: 3, PairingScore: 2, Reason: domain.ReasonOutranked},
+			},
+		},
+		{
+			ID: 2, PullRequestID: "pr1", Kind: domain.AssignmentReassign, ReplacedUserID: "u3", Seed: 7, Strategy: domain.PairingDiversity, At: at.Add(time.Hour),
+			Candidates: []domain.CandidateExplanation{
+				{UserID: "u4", Rank: 1, PairingScore: 2, Chosen: true, Reason: domain.ReasonRank},
+			},
+		},
+	}
+
+	mockRepo.EXPECT().GetAssignments(gomock.Any(), "pr1").Return(assignments, nil).Times(2)
+
+	resp, err := service.Explain(context.Background(), &dto.ExplainAssignmentsRequest{PullRequestID: "pr1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Assignments, 2)
+	require.Equal(t, dto.AssignmentResponse{
+		AssignmentID: 1,
+		Kind:         "CREATE",
+		Reviewers:    []string{"u2", "u3"},
+		Seed:         -42,
+		Strategy:     "PAIRING_DIVERSITY",
+		AssignedAt:   at,
+		Candidates: []dto.AssignmentCandidateResponse{
+			{UserID: "u2", Rank: 1, Chosen: true, Reason: "REQUIRED_TAGS", WorkingHours: true, Level: "SENIOR", Tags: []string{"go"}},
+			{UserID: "u3", Rank: 2, Chosen: true, Reason: "RANK", WorkingHours: true, PairingScore: 0.33},
+			{UserID: "u4", Rank: 3, Reason: "OUTRANKED", PairingScore: 2},
+		},
+	}, resp.Assignments[0])
+	require.Equal(t, "u3", resp.Assignments[1].ReplacedUserID)
+
+	// reviewer_id оставляет только выборы, в которых он назначен
+	resp, err = service.Explain(context.Background(), &dto.ExplainAssignmentsRequest{PullRequestID: "pr1", ReviewerID: "u2"})
+	require.NoError(t, err)
+	require.Len(t, resp.Assignments, 1)
+	require.Equal(t, int64(1), resp.Assignments[0].AssignmentID)
+}
+
+func TestPullRequestService_Explain_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	service := NewPullRequestService(mockRepo, mocks.NewMockEventPublisher(ctrl), nil)
+
+	mockRepo.EXPECT().GetAssignments(gomock.Any(), "missing").Return(nil, repository.ErrPullRequestNotFound)
+
+	_, err := service.Explain(context.Background(), &dto.ExplainAssignmentsRequest{PullRequestID: "missing"})
+	require.Equal(t, error_wrapper.WrapRepositoryError(repository.ErrPullRequestNotFound), err)
+}
+
+func TestPullRequestService_PreviewReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	// превью не публикует событий: ожиданий на publisher нет
+	service := NewPullRequestService(mockRepo, mocks.NewMockEventPublisher(ctrl), nil)
+	now := time.Date(2025, 12, 17, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	service.seed = func() int64 { return 11 }
+
+	mockRepo.EXPECT().
+		PreviewReviewers(gomock.Any(), domain.PullRequest{AuthorID: "u1", CreatedAt: now, RequiredTags: []string{"go"}}, int64(11)).
+		Return(&domain.ReviewerPreview{
+			AuthorID:     "u1",
+			RequiredTags: []string{"go"},
+			Reviewers:    []string{"u2"},
+			Seed:         11,
+			Strategy:     domain.PairingRandom,
+			At:           now,
+			Candidates: []domain.CandidateExplanation{
+				{UserID: "u2", Rank: 1, Working: true, Tags: []string{"go"}, Chosen: true, Reason: domain.ReasonRequiredTags},
+			},
+			Excluded: []domain.CandidateExclusion{
+				{UserID: "u1", Reason: domain.ExcludedAuthor},
+				{UserID: "u3", Reason: domain.ExcludedOutOfOffice},
+			},
+			UnmetRules: []domain.ReviewerRule{{MinLevel: domain.LevelSenior, Count: 1}},
+		}, nil)
+
+	resp, err := service.PreviewReviewers(context.Background(), &dto.PreviewReviewersRequest{AuthorID: "u1", RequiredTags: []string{"go"}})
+	require.NoError(t, err)
+	require.Equal(t, &dto.ReviewersPreviewResponse{
+		AuthorID:     "u1",
+		RequiredTags: []string{"go"},
+		Reviewers:    []string{"u2"},
+		Seed:         11,
+		Strategy:     "RANDOM",
+		Candidates: []dto.AssignmentCandidateResponse{
+			{UserID: "u2", Rank: 1, Chosen: true, Reason: "REQUIRED_TAGS", WorkingHours: true, Tags: []string{"go"}},
+		},
+		Excluded: []dto.ExcludedMemberResponse{
+			{UserID: "u1", Reason: "AUTHOR"},
+			{UserID: "u3", Reason: "OUT_OF_OFFICE"},
+		},
+		UnmetRules: []dto.ReviewerRuleResponse{{MinLevel: "SENIOR", Count: 1}},
+	}, resp)
+}
+
+func TestPullRequestService_PreviewReviewers_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPullRequestRepository(ctrl)
+	service := NewPullRequestService(mockRepo, mocks.NewMockEventPublisher(ctrl), nil)
+
+	mockRepo.EXPECT().PreviewReviewers(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, repository.ErrUserNotFound)
+
+	_, err := service.PreviewReviewers(context.Background(), &dto.PreviewReviewersRequest{AuthorID: "u1"})
+	require.Equal(t, error_wrapper.WrapRepositoryError(repository.ErrUserNotFound), err)
+}
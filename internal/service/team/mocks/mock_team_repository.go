@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	domain "service-order-avito/internal/domain"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -49,6 +50,51 @@ func (mr *MockTeamRepositoryMockRecorder) AddTeamWithMembers(arg0, arg1, arg2 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeamWithMembers", reflect.TypeOf((*MockTeamRepository)(nil).AddTeamWithMembers), arg0, arg1, arg2)
 }
 
+// GetPairingSettings mocks base method.
+func (m *MockTeamRepository) GetPairingSettings(ctx context.Context, teamName string) (*domain.PairingSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPairingSettings", ctx, teamName)
+	ret0, _ := ret[0].(*domain.PairingSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPairingSettings indicates an expected call of GetPairingSettings.
+func (mr *MockTeamRepositoryMockRecorder) GetPairingSettings(ctx, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPairingSettings", reflect.TypeOf((*MockTeamRepository)(nil).GetPairingSettings), ctx, teamName)
+}
+
+// GetPairings mocks base method.
+func (m *MockTeamRepository) GetPairings(ctx context.Context, teamName string, since time.Time) ([]domain.Pairing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPairings", ctx, teamName, since)
+	ret0, _ := ret[0].([]domain.Pairing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPairings indicates an expected call of GetPairings.
+func (mr *MockTeamRepositoryMockRecorder) GetPairings(ctx, teamName, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPairings", reflect.TypeOf((*MockTeamRepository)(nil).GetPairings), ctx, teamName, since)
+}
+
+// GetReviewerRules mocks base method.
+func (m *MockTeamRepository) GetReviewerRules(ctx context.Context, teamName string) ([]domain.ReviewerRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewerRules", ctx, teamName)
+	ret0, _ := ret[0].([]domain.ReviewerRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewerRules indicates an expected call of GetReviewerRules.
+func (mr *MockTeamRepositoryMockRecorder) GetReviewerRules(ctx, teamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewerRules", reflect.TypeOf((*MockTeamRepository)(nil).GetReviewerRules), ctx, teamName)
+}
+
 // GetTeamStats mocks base method.
 func (m *MockTeamRepository) GetTeamStats(arg0 context.Context, arg1 string) (int, int, int, int, error) {
 	m.ctrl.T.Helper()
@@ -81,3 +127,63 @@ func (mr *MockTeamRepositoryMockRecorder) GetTeamWithMembers(arg0, arg1 interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamWithMembers", reflect.TypeOf((*MockTeamRepository)(nil).GetTeamWithMembers), arg0, arg1)
 }
+
+// GetTeamsWithMembers mocks base method.
+func (m *MockTeamRepository) GetTeamsWithMembers(arg0 context.Context, arg1 []string) ([]domain.TeamWithUsers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamsWithMembers", arg0, arg1)
+	ret0, _ := ret[0].([]domain.TeamWithUsers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamsWithMembers indicates an expected call of GetTeamsWithMembers.
+func (mr *MockTeamRepositoryMockRecorder) GetTeamsWithMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsWithMembers", reflect.TypeOf((*MockTeamRepository)(nil).GetTeamsWithMembers), arg0, arg1)
+}
+
+// ImportTeams mocks base method.
+func (m *MockTeamRepository) ImportTeams(ctx context.Context, teams []domain.TeamWithUsers, dryRun bool) (*domain.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTeams", ctx, teams, dryRun)
+	ret0, _ := ret[0].(*domain.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTeams indicates an expected call of ImportTeams.
+func (mr *MockTeamRepositoryMockRecorder) ImportTeams(ctx, teams, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTeams", reflect.TypeOf((*MockTeamRepository)(nil).ImportTeams), ctx, teams, dryRun)
+}
+
+// SetPairingSettings mocks base method.
+func (m *MockTeamRepository) SetPairingSettings(ctx context.Context, teamName string, settings domain.PairingSettings) (*domain.PairingSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPairingSettings", ctx, teamName, settings)
+	ret0, _ := ret[0].(*domain.PairingSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPairingSettings indicates an expected call of SetPairingSettings.
+func (mr *MockTeamRepositoryMockRecorder) SetPairingSettings(ctx, teamName, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPairingSettings", reflect.TypeOf((*MockTeamRepository)(nil).SetPairingSettings), ctx, teamName, settings)
+}
+
+// SetReviewerRules mocks base method.
+func (m *MockTeamRepository) SetReviewerRules(ctx context.Context, teamName string, rules []domain.ReviewerRule) ([]domain.ReviewerRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewerRules", ctx, teamName, rules)
+	ret0, _ := ret[0].([]domain.ReviewerRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReviewerRules indicates an expected call of SetReviewerRules.
+func (mr *MockTeamRepositoryMockRecorder) SetReviewerRules(ctx, teamName, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewerRules", reflect.TypeOf((*MockTeamRepository)(nil).SetReviewerRules), ctx, teamName, rules)
+}
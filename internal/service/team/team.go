@@ -2,9 +2,11 @@ package team
 
 import (
 	"context"
+	"math"
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/service/error_wrapper"
+	"time"
 )
 
 // mockgen -source="internal/service/team/team.go" -destination="internal/service/team/mocks/mock_team_repository.go" -package=mocks TeamRepository
@@ -12,14 +14,26 @@ type TeamRepository interface {
 	AddTeamWithMembers(context.Context, domain.Team, []domain.User) error
 	GetTeamWithMembers(context.Context, string) (*domain.TeamWithUsers, error)
 	GetTeamStats(context.Context, string) (activeUsers, inactiveUsers, openPRs, mergedPRs int, err error)
+	GetTeamsWithMembers(context.Context, []string) ([]domain.TeamWithUsers, error)
+	// ImportTeams применяет импорт одной транзакцией, при dryRun только возвращает отчет
+	ImportTeams(ctx context.Context, teams []domain.TeamWithUsers, dryRun bool) (*domain.ImportReport, error)
+	// SetReviewerRules заменяет правила команды к уровням ревьюеров и возвращает сохраненные
+	SetReviewerRules(ctx context.Context, teamName string, rules []domain.ReviewerRule) ([]domain.ReviewerRule, error)
+	GetReviewerRules(ctx context.Context, teamName string) ([]domain.ReviewerRule, error)
+	SetPairingSettings(ctx context.Context, teamName string, settings domain.PairingSettings) (*domain.PairingSettings, error)
+	// GetPairingSettings настройки команды, без них — domain.DefaultPairingSettings
+	GetPairingSettings(ctx context.Context, teamName string) (*domain.PairingSettings, error)
+	// GetPairings назначения ревьюеров к PR авторов команды не раньше since
+	GetPairings(ctx context.Context, teamName string, since time.Time) ([]domain.Pairing, error)
 }
 
 type teamService struct {
 	repo TeamRepository
+	now  func() time.Time
 }
 
 func NewTeamService(repo TeamRepository) *teamService {
-	return &teamService{repo: repo}
+	return &teamService{repo: repo, now: time.Now}
 }
 
 func (s *teamService) AddTeam(ctx context.Context, req *dto.TeamAddRequest) (*dto.AddTeamResponse, error) {
@@ -68,6 +82,8 @@ func (s *teamService) GetTeam(ctx context.Context, req *dto.GetTeamRequest) (*dt
 			UserID:   m.ID,
 			Username: m.Username,
 			IsActive: m.IsActive,
+			Skills:   m.Skills,
+			Level:    string(m.Level),
 		}
 	}
 
@@ -91,3 +107,173 @@ func (s *teamService) GetTeamStats(ctx context.Context, req *dto.GetTeamStatsReq
 		MergedPRs:     mergedPRs,
 	}, nil
 }
+
+// GetTeams команды с участниками пачкой, несуществующие пропускаются
+func (s *teamService) GetTeams(ctx context.Context, req *dto.GetTeamsRequest) (*dto.GetTeamsResponse, error) {
+	teams, err := s.repo.GetTeamsWithMembers(ctx, req.TeamNames)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := make([]dto.GetTeamResponse, len(teams))
+	for i, t := range teams {
+		members := make([]dto.TeamMemberResponse, len(t.Members))
+		for j, m := range t.Members {
+			members[j] = dto.TeamMemberResponse{
+				UserID:   m.ID,
+				Username: m.Username,
+				IsActive: m.IsActive,
+				Skills:   m.Skills,
+				Level:    string(m.Level),
+			}
+		}
+		resp[i] = dto.GetTeamResponse{
+			TeamName: t.TeamName,
+			Members:  members,
+		}
+	}
+
+	return &dto.GetTeamsResponse{Teams: resp}, nil
+}
+
+// Import команды и пользователи пачкой. Отчет считается в той же транзакции, что и запись
+func (s *teamService) Import(ctx context.Context, req *dto.ImportRequest) (*dto.ImportResponse, error) {
+	teams := make([]domain.TeamWithUsers, len(req.Teams))
+	for i, t := range req.Teams {
+		members := make([]domain.User, len(t.Members))
+		for j, m := range t.Members {
+			members[j] = domain.User{
+				ID:       m.UserID,
+				Username: m.Username,
+				TeamName: t.TeamName,
+				IsActive: m.IsActive,
+			}
+		}
+		teams[i] = domain.TeamWithUsers{TeamName: t.TeamName, Members: members}
+	}
+
+	report, err := s.repo.ImportTeams(ctx, teams, req.DryRun)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := &dto.ImportResponse{
+		DryRun:         req.DryRun,
+		TeamsCreated:   report.TeamsCreated,
+		UsersCreated:   report.UsersCreated,
+		UsersUpdated:   make([]dto.ImportUserUpdate, len(report.UsersUpdated)),
+		UsersMoved:     make([]dto.ImportUserMove, len(report.UsersMoved)),
+		UsersUnchanged: report.UsersUnchanged,
+	}
+	for i, u := range report.UsersUpdated {
+		resp.UsersUpdated[i] = dto.ImportUserUpdate{UserID: u.UserID, Fields: u.Fields}
+	}
+	for i, m := range report.UsersMoved {
+		resp.UsersMoved[i] = dto.ImportUserMove{UserID: m.UserID, FromTeam: m.FromTeam, ToTeam: m.ToTeam}
+	}
+
+	return resp, nil
+}
+
+// SetReviewerRules правила команды к уровням ревьюеров: при создании PR и замене они выполняются
+// или запрос завершается ошибкой REVIEWER_RULE_UNMET
+func (s *teamService) SetReviewerRules(ctx context.Context, req *dto.SetReviewerRulesRequest) (*dto.ReviewerRulesResponse, error) {
+	rules := make([]domain.ReviewerRule, len(req.Rules))
+	for i, r := range req.Rules {
+		rules[i] = domain.ReviewerRule{MinLevel: domain.Level(r.MinLevel), Count: r.Count}
+	}
+
+	saved, err := s.repo.SetReviewerRules(ctx, req.TeamName, rules)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return reviewerRulesResponse(req.TeamName, saved), nil
+}
+
+func (s *teamService) GetReviewerRules(ctx context.Context, req *dto.GetReviewerRulesRequest) (*dto.ReviewerRulesResponse, error) {
+	rules, err := s.repo.GetReviewerRules(ctx, req.TeamName)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return reviewerRulesResponse(req.TeamName, rules), nil
+}
+
+func reviewerRulesResponse(teamName string, rules []domain.ReviewerRule) *dto.ReviewerRulesResponse {
+	resp := &dto.ReviewerRulesResponse{
+		TeamName: teamName,
+		Rules:    make([]dto.ReviewerRuleResponse, len(rules)),
+	}
+	for i, r := range rules {
+		resp.Rules[i] = dto.ReviewerRuleResponse{MinLevel: string(r.MinLevel), Count: r.Count}
+	}
+	return resp
+}
+
+// SetPairingSettings стратегия выбора ревьюеров команды. PAIRING_DIVERSITY из равных кандидатов
+// берет тех, кто реже ревьюил автора за окно decay_days
+func (s *teamService) SetPairingSettings(ctx context.Context, req *dto.SetPairingSettingsRequest) (*dto.PairingSettingsResponse, error) {
+	settings := domain.PairingSettings{Strategy: domain.PairingStrategy(req.Strategy), DecayDays: req.DecayDays}
+	if settings.DecayDays == 0 {
+		settings.DecayDays = domain.DefaultPairingDecayDays
+	}
+
+	saved, err := s.repo.SetPairingSettings(ctx, req.TeamName, settings)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return pairingSettingsResponse(req.TeamName, *saved), nil
+}
+
+func (s *teamService) GetPairingSettings(ctx context.Context, req *dto.GetPairingSettingsRequest) (*dto.PairingSettingsResponse, error) {
+	settings, err := s.repo.GetPairingSettings(ctx, req.TeamName)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return pairingSettingsResponse(req.TeamName, *settings), nil
+}
+
+// GetPairingMatrix кто кого ревьюил за окно затухания команды, чтобы видеть перекос в парах
+func (s *teamService) GetPairingMatrix(ctx context.Context, req *dto.GetPairingMatrixRequest) (*dto.PairingMatrixResponse, error) {
+	settings, err := s.repo.GetPairingSettings(ctx, req.TeamName)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	now := s.now()
+	since := now.Add(-settings.Window())
+	pairings, err := s.repo.GetPairings(ctx, req.TeamName, since)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	cells := domain.PairingMatrix(pairings, now, settings.Window())
+	resp := &dto.PairingMatrixResponse{
+		TeamName:  req.TeamName,
+		Strategy:  string(settings.Strategy),
+		DecayDays: settings.DecayDays,
+		Since:     since.UTC(),
+		Pairs:     make([]dto.PairingCellResponse, len(cells)),
+	}
+	for i, c := range cells {
+		resp.Pairs[i] = dto.PairingCellResponse{
+			AuthorID:   c.AuthorID,
+			ReviewerID: c.ReviewerID,
+			Count:      c.Count,
+			// вес для людей, точность выше сотых не нужна
+			Score: math.Round(c.Score*100) / 100,
+		}
+	}
+	return resp, nil
+}
+
+func pairingSettingsResponse(teamName string, settings domain.PairingSettings) *dto.PairingSettingsResponse {
+	return &dto.PairingSettingsResponse{
+		TeamName:  teamName,
+		Strategy:  string(settings.Strategy),
+		DecayDays: settings.DecayDays,
+	}
+}
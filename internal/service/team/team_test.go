@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/dto"
@@ -156,3 +157,172 @@ func TestTeamService_GetTeam(t *testing.T) {
 		})
 	}
 }
+
+func TestTeamService_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockTeamRepository(ctrl)
+	svc := NewTeamService(mockRepo)
+
+	ctx := context.Background()
+	req := &dto.ImportRequest{
+		Teams: []dto.TeamAddRequest{{
+			TeamName: "backend",
+			Members:  []dto.TeamMemberRequest{{UserID: "u1", Username: "Alice", IsActive: true}},
+		}},
+		DryRun: true,
+	}
+
+	mockRepo.EXPECT().
+		ImportTeams(ctx, []domain.TeamWithUsers{{
+			TeamName: "backend",
+			Members:  []domain.User{{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true}},
+		}}, true).
+		Return(&domain.ImportReport{
+			TeamsCreated: []string{},
+			UsersCreated: []string{},
+			UsersUpdated: []domain.UserUpdate{{UserID: "u1", Fields: []string{"username"}}},
+			UsersMoved:   []domain.UserMove{{UserID: "u1", FromTeam: "old", ToTeam: "backend"}},
+		}, nil)
+
+	resp, err := svc.Import(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.DryRun {
+		t.Fatalf("expected dry_run in response")
+	}
+	if len(resp.UsersMoved) != 1 || resp.UsersMoved[0].FromTeam != "old" {
+		t.Fatalf("unexpected moved users: %+v", resp.UsersMoved)
+	}
+	if len(resp.UsersUpdated) != 1 || resp.UsersUpdated[0].Fields[0] != "username" {
+		t.Fatalf("unexpected updated users: %+v", resp.UsersUpdated)
+	}
+
+	mockRepo.EXPECT().
+		ImportTeams(ctx, gomock.Any(), false).
+		Return(nil, errors.New("unknown repo error"))
+
+	req.DryRun = false
+	if _, err := svc.Import(ctx, req); !errors.Is(err, serviceErr.ErrInternalError) {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+}
+
+func TestTeamService_ReviewerRules(t *testing.T) {
+	ctx := context.Background()
+	saved := []domain.ReviewerRule{{MinLevel: domain.LevelSenior, Count: 1}}
+
+	t.Run("set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := NewTeamService(mockRepo)
+
+		mockRepo.EXPECT().
+			SetReviewerRules(ctx, "backend", []domain.ReviewerRule{{MinLevel: domain.LevelSenior, Count: 1}}).
+			Return(saved, nil)
+
+		resp, err := svc.SetReviewerRules(ctx, &dto.SetReviewerRulesRequest{
+			TeamName: "backend",
+			Rules:    []dto.ReviewerRuleRequest{{MinLevel: "SENIOR", Count: 1}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.TeamName != "backend" || len(resp.Rules) != 1 || resp.Rules[0] != (dto.ReviewerRuleResponse{MinLevel: "SENIOR", Count: 1}) {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("get empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := NewTeamService(mockRepo)
+
+		mockRepo.EXPECT().GetReviewerRules(ctx, "backend").Return(nil, nil)
+
+		resp, err := svc.GetReviewerRules(ctx, &dto.GetReviewerRulesRequest{TeamName: "backend"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// пустой список, а не null в JSON
+		if resp.Rules == nil || len(resp.Rules) != 0 {
+			t.Fatalf("expected empty rules, got %+v", resp.Rules)
+		}
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := NewTeamService(mockRepo)
+
+		mockRepo.EXPECT().GetReviewerRules(ctx, "ghost").Return(nil, repoErr.ErrTeamNotFound)
+
+		_, err := svc.GetReviewerRules(ctx, &dto.GetReviewerRulesRequest{TeamName: "ghost"})
+		if !errors.Is(err, serviceErr.ErrTeamNotFound) {
+			t.Fatalf("expected %v, got %v", serviceErr.ErrTeamNotFound, err)
+		}
+	})
+}
+
+func TestTeamService_Pairing(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	t.Run("set default window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := NewTeamService(mockRepo)
+
+		settings := domain.PairingSettings{Strategy: domain.PairingDiversity, DecayDays: domain.DefaultPairingDecayDays}
+		mockRepo.EXPECT().SetPairingSettings(ctx, "backend", settings).Return(&settings, nil)
+
+		resp, err := svc.SetPairingSettings(ctx, &dto.SetPairingSettingsRequest{TeamName: "backend", Strategy: "PAIRING_DIVERSITY"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *resp != (dto.PairingSettingsResponse{TeamName: "backend", Strategy: "PAIRING_DIVERSITY", DecayDays: 30}) {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("matrix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := NewTeamService(mockRepo)
+		svc.now = func() time.Time { return now }
+
+		settings := domain.PairingSettings{Strategy: domain.PairingRandom, DecayDays: 3}
+		mockRepo.EXPECT().GetPairingSettings(ctx, "backend").Return(&settings, nil)
+		mockRepo.EXPECT().GetPairings(ctx, "backend", now.Add(-3*day)).Return([]domain.Pairing{
+			{AuthorID: "u1", ReviewerID: "u2", At: now.Add(-day)},
+			{AuthorID: "u1", ReviewerID: "u2", At: now.Add(-day)},
+			{AuthorID: "u2", ReviewerID: "u1", At: now},
+		}, nil)
+
+		resp, err := svc.GetPairingMatrix(ctx, &dto.GetPairingMatrixRequest{TeamName: "backend"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []dto.PairingCellResponse{
+			{AuthorID: "u1", ReviewerID: "u2", Count: 2, Score: 1.33},
+			{AuthorID: "u2", ReviewerID: "u1", Count: 1, Score: 1},
+		}
+		if resp.DecayDays != 3 || !resp.Since.Equal(now.Add(-3*day)) || len(resp.Pairs) != 2 || resp.Pairs[0] != want[0] || resp.Pairs[1] != want[1] {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockTeamRepository(ctrl)
+		svc := NewTeamService(mockRepo)
+
+		mockRepo.EXPECT().GetPairingSettings(ctx, "ghost").Return(nil, repoErr.ErrTeamNotFound)
+
+		_, err := svc.GetPairingMatrix(ctx, &dto.GetPairingMatrixRequest{TeamName: "ghost"})
+		if !errors.Is(err, serviceErr.ErrTeamNotFound) {
+			t.Fatalf("expected %v, got %v", serviceErr.ErrTeamNotFound, err)
+		}
+	})
+}
@@ -35,11 +35,41 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetByIDs mocks base method.
+func (m *MockUserRepository) GetByIDs(arg0 context.Context, arg1 []string) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockUserRepositoryMockRecorder) GetByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUserRepository)(nil).GetByIDs), arg0, arg1)
+}
+
+// GetReviewPullRequestIDs mocks base method.
+func (m *MockUserRepository) GetReviewPullRequestIDs(arg0 context.Context, arg1 []string) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewPullRequestIDs", arg0, arg1)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewPullRequestIDs indicates an expected call of GetReviewPullRequestIDs.
+func (mr *MockUserRepositoryMockRecorder) GetReviewPullRequestIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewPullRequestIDs", reflect.TypeOf((*MockUserRepository)(nil).GetReviewPullRequestIDs), arg0, arg1)
+}
+
 // GetReviewPullRequests mocks base method.
-func (m *MockUserRepository) GetReviewPullRequests(arg0 context.Context, arg1 string) ([]domain.PullRequest, error) {
+func (m *MockUserRepository) GetReviewPullRequests(arg0 context.Context, arg1 string) ([]domain.ReviewPullRequest, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetReviewPullRequests", arg0, arg1)
-	ret0, _ := ret[0].([]domain.PullRequest)
+	ret0, _ := ret[0].([]domain.ReviewPullRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -64,3 +94,86 @@ func (mr *MockUserRepositoryMockRecorder) SetIsActive(arg0, arg1, arg2 interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsActive", reflect.TypeOf((*MockUserRepository)(nil).SetIsActive), arg0, arg1, arg2)
 }
+
+// SetLevel mocks base method.
+func (m *MockUserRepository) SetLevel(ctx context.Context, userID string, level domain.Level) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLevel", ctx, userID, level)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLevel indicates an expected call of SetLevel.
+func (mr *MockUserRepositoryMockRecorder) SetLevel(ctx, userID, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLevel", reflect.TypeOf((*MockUserRepository)(nil).SetLevel), ctx, userID, level)
+}
+
+// SetSkills mocks base method.
+func (m *MockUserRepository) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSkills", ctx, userID, skills)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSkills indicates an expected call of SetSkills.
+func (mr *MockUserRepositoryMockRecorder) SetSkills(ctx, userID, skills interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSkills", reflect.TypeOf((*MockUserRepository)(nil).SetSkills), ctx, userID, skills)
+}
+
+// UpdateSkills mocks base method.
+func (m *MockUserRepository) UpdateSkills(ctx context.Context, userID string, add, remove []string) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSkills", ctx, userID, add, remove)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSkills indicates an expected call of UpdateSkills.
+func (mr *MockUserRepositoryMockRecorder) UpdateSkills(ctx, userID, add, remove interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSkills", reflect.TypeOf((*MockUserRepository)(nil).UpdateSkills), ctx, userID, add, remove)
+}
+
+// MockWorkingHoursRepository is a mock of WorkingHoursRepository interface.
+type MockWorkingHoursRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkingHoursRepositoryMockRecorder
+}
+
+// MockWorkingHoursRepositoryMockRecorder is the mock recorder for MockWorkingHoursRepository.
+type MockWorkingHoursRepositoryMockRecorder struct {
+	mock *MockWorkingHoursRepository
+}
+
+// NewMockWorkingHoursRepository creates a new mock instance.
+func NewMockWorkingHoursRepository(ctrl *gomock.Controller) *MockWorkingHoursRepository {
+	mock := &MockWorkingHoursRepository{ctrl: ctrl}
+	mock.recorder = &MockWorkingHoursRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkingHoursRepository) EXPECT() *MockWorkingHoursRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListWorkingHours mocks base method.
+func (m *MockWorkingHoursRepository) ListWorkingHours(arg0 context.Context, arg1 []string) (map[string]domain.WorkingHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkingHours", arg0, arg1)
+	ret0, _ := ret[0].(map[string]domain.WorkingHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkingHours indicates an expected call of ListWorkingHours.
+func (mr *MockWorkingHoursRepositoryMockRecorder) ListWorkingHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkingHours", reflect.TypeOf((*MockWorkingHoursRepository)(nil).ListWorkingHours), arg0, arg1)
+}
@@ -5,20 +5,37 @@ import (
 	"service-order-avito/internal/domain"
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/service/error_wrapper"
+	"time"
 )
 
-// mockgen -source="internal/service/user/user.go" -destination="internal/service/user/mocks/mock_user_repository.go" -package=mocks UserRepository
+// mockgen -source="internal/service/user/user.go" -destination="internal/service/user/mocks/mock_user_repository.go" -package=mocks UserRepository,WorkingHoursRepository
 type UserRepository interface {
 	SetIsActive(context.Context, string, bool) (*domain.User, error)
-	GetReviewPullRequests(context.Context, string) ([]domain.PullRequest, error)
+	// GetReviewPullRequests PR на ревью у пользователя со временем его назначения, сначала новые
+	GetReviewPullRequests(context.Context, string) ([]domain.ReviewPullRequest, error)
+	GetByIDs(context.Context, []string) ([]domain.User, error)
+	GetReviewPullRequestIDs(context.Context, []string) (map[string][]string, error)
+	SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error)
+	// UpdateSkills добавляет add и убирает remove атомарно
+	UpdateSkills(ctx context.Context, userID string, add, remove []string) (*domain.User, error)
+	SetLevel(ctx context.Context, userID string, level domain.Level) (*domain.User, error)
+}
+
+// WorkingHoursRepository расписания ревьюеров: SLA ревью считается только в их рабочее время
+type WorkingHoursRepository interface {
+	ListWorkingHours(context.Context, []string) (map[string]domain.WorkingHours, error)
 }
 
 type userService struct {
-	repo UserRepository
+	repo  UserRepository
+	hours WorkingHoursRepository
+	// slaReview сколько рабочего времени ревьюера дается на ревью с момента его назначения
+	slaReview time.Duration
+	now       func() time.Time
 }
 
-func NewUserService(repo UserRepository) *userService {
-	return &userService{repo: repo}
+func NewUserService(repo UserRepository, hours WorkingHoursRepository, slaReview time.Duration) *userService {
+	return &userService{repo: repo, hours: hours, slaReview: slaReview, now: time.Now}
 }
 
 func (s *userService) SetIsActive(ctx context.Context, req *dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error) {
@@ -28,21 +45,51 @@ func (s *userService) SetIsActive(ctx context.Context, req *dto.SetIsActiveReque
 	}
 
 	return &dto.SetIsActiveResponse{
-		User: dto.UserResponse{
-			UserID:   user.ID,
-			Username: user.Username,
-			TeamName: user.TeamName,
-			IsActive: user.IsActive,
-		},
+		User: userResponse(*user),
 	}, nil
 }
 
+// SetSkills заменяет теги пользователя, пустой список их очищает
+func (s *userService) SetSkills(ctx context.Context, req *dto.SetSkillsRequest) (*dto.UserSkillsResponse, error) {
+	user, err := s.repo.SetSkills(ctx, req.UserID, req.Skills)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return &dto.UserSkillsResponse{User: userResponse(*user)}, nil
+}
+
+// UpdateSkills добавляет и убирает теги. Тег и в add, и в remove убирается
+func (s *userService) UpdateSkills(ctx context.Context, req *dto.UpdateSkillsRequest) (*dto.UserSkillsResponse, error) {
+	user, err := s.repo.UpdateSkills(ctx, req.UserID, req.Add, req.Remove)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return &dto.UserSkillsResponse{User: userResponse(*user)}, nil
+}
+
+// SetLevel уровень пользователя, по нему проверяются правила команды к ревьюерам
+func (s *userService) SetLevel(ctx context.Context, req *dto.SetLevelRequest) (*dto.SetLevelResponse, error) {
+	user, err := s.repo.SetLevel(ctx, req.UserID, domain.Level(req.Level))
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return &dto.SetLevelResponse{User: userResponse(*user)}, nil
+}
+
 func (s *userService) GetReviewPullRequests(ctx context.Context, req *dto.GetReviewPRRequest) (*dto.GetReviewPRResponse, error) {
 	prs, err := s.repo.GetReviewPullRequests(ctx, req.UserID)
 	if err != nil {
 		return nil, error_wrapper.WrapRepositoryError(err)
 	}
 
+	sla, err := s.reviewSLA(ctx, req.UserID, prs)
+	if err != nil {
+		return nil, err
+	}
+
 	respPRs := make([]dto.PullRequestShortResponse, len(prs))
 	for i, pr := range prs {
 		respPRs[i] = dto.PullRequestShortResponse{
@@ -50,6 +97,7 @@ func (s *userService) GetReviewPullRequests(ctx context.Context, req *dto.GetRev
 			PullRequestName: pr.Name,
 			AuthorID:        pr.AuthorID,
 			Status:          pr.Status,
+			SLA:             sla(pr),
 		}
 	}
 
@@ -58,3 +106,76 @@ func (s *userService) GetReviewPullRequests(ctx context.Context, req *dto.GetRev
 		PullRequests: respPRs,
 	}, nil
 }
+
+// reviewSLA таймеры ревью открытых PR по рабочему времени ревьюера: ночи, выходные и нерабочие дни не считаются.
+// Таймер идет с назначения ревьюера: после замены новый ревьюер начинает с нуля. Расписание читается, только если открытые PR есть
+func (s *userService) reviewSLA(ctx context.Context, reviewerID string, prs []domain.ReviewPullRequest) (func(domain.ReviewPullRequest) *dto.ReviewSLAResponse, error) {
+	none := func(domain.ReviewPullRequest) *dto.ReviewSLAResponse { return nil }
+	if !hasOpen(prs) {
+		return none, nil
+	}
+
+	hours, err := s.hours.ListWorkingHours(ctx, []string{reviewerID})
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+	schedule := domain.HoursOf(hours, reviewerID)
+	now := s.now()
+
+	return func(pr domain.ReviewPullRequest) *dto.ReviewSLAResponse {
+		if pr.Status != "OPEN" {
+			return nil
+		}
+		elapsed := schedule.BusinessDuration(pr.AssignedAt, now)
+		return &dto.ReviewSLAResponse{
+			ElapsedSeconds: int64(elapsed / time.Second),
+			DueAt:          schedule.AddBusiness(pr.AssignedAt, s.slaReview).UTC(),
+			Breached:       elapsed >= s.slaReview,
+		}
+	}, nil
+}
+
+func hasOpen(prs []domain.ReviewPullRequest) bool {
+	for _, pr := range prs {
+		if pr.Status == "OPEN" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUsers пользователи пачкой, несуществующие id пропускаются
+func (s *userService) GetUsers(ctx context.Context, req *dto.GetUsersRequest) (*dto.GetUsersResponse, error) {
+	users, err := s.repo.GetByIDs(ctx, req.UserIDs)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	resp := make([]dto.UserResponse, len(users))
+	for i, u := range users {
+		resp[i] = userResponse(u)
+	}
+
+	return &dto.GetUsersResponse{Users: resp}, nil
+}
+
+// GetReviewQueues id PR на ревью для нескольких пользователей одним походом в репозиторий
+func (s *userService) GetReviewQueues(ctx context.Context, req *dto.GetReviewQueuesRequest) (*dto.GetReviewQueuesResponse, error) {
+	queues, err := s.repo.GetReviewPullRequestIDs(ctx, req.UserIDs)
+	if err != nil {
+		return nil, error_wrapper.WrapRepositoryError(err)
+	}
+
+	return &dto.GetReviewQueuesResponse{Queues: queues}, nil
+}
+
+func userResponse(u domain.User) dto.UserResponse {
+	return dto.UserResponse{
+		UserID:   u.ID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+		Skills:   u.Skills,
+		Level:    string(u.Level),
+	}
+}
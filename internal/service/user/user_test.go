@@ -11,6 +11,7 @@ import (
 	"service-order-avito/internal/domain/errors/service"
 	"service-order-avito/internal/service/user/mocks"
 	"testing"
+	"time"
 )
 
 func TestUserService_SetIsActive(t *testing.T) {
@@ -79,10 +80,10 @@ func TestUserService_SetIsActive(t *testing.T) {
 				SetIsActive(gomock.Any(), tt.req.UserID, tt.req.IsActive).
 				Return(tt.mockUser, tt.mockErr)
 
-			svc := NewUserService(mockRepo)
+			svc := NewUserService(mockRepo, mocks.NewMockWorkingHoursRepository(ctrl), 16*time.Hour)
 			resp, err := svc.SetIsActive(context.Background(), tt.req)
 
-			assert.Equal(t, tt.expectedError, err)
+			assert.ErrorIs(t, err, tt.expectedError)
 			assert.Equal(t, tt.expectedResp, resp)
 		})
 	}
@@ -92,7 +93,7 @@ func TestUserService_GetReviewPullRequests(t *testing.T) {
 	tests := []struct {
 		name          string
 		req           *dto.GetReviewPRRequest
-		mockPRs       []domain.PullRequest
+		mockPRs       []domain.ReviewPullRequest
 		mockErr       error
 		expectedResp  *dto.GetReviewPRResponse
 		expectedError error
@@ -102,15 +103,23 @@ func TestUserService_GetReviewPullRequests(t *testing.T) {
 			req: &dto.GetReviewPRRequest{
 				UserID: "u1",
 			},
-			mockPRs: []domain.PullRequest{
-				{ID: "pr1", Name: "Add feature", AuthorID: "u2", Status: "OPEN"},
-				{ID: "pr2", Name: "Fix bug", AuthorID: "u3", Status: "MERGED"},
+			// ревьюер без расписания: 09:00–18:00 UTC по будням. С вечера вторника до полудня среды прошло 4 рабочих часа,
+			// оставшиеся 12 истекают в четверг в 15:00
+			mockPRs: []domain.ReviewPullRequest{
+				{
+					PullRequest: domain.PullRequest{ID: "pr1", Name: "Add feature", AuthorID: "u2", Status: "OPEN", CreatedAt: time.Date(2025, 12, 9, 17, 0, 0, 0, time.UTC)},
+					AssignedAt:  time.Date(2025, 12, 9, 17, 0, 0, 0, time.UTC),
+				},
+				{PullRequest: domain.PullRequest{ID: "pr2", Name: "Fix bug", AuthorID: "u3", Status: "MERGED"}},
 			},
 			mockErr: nil,
 			expectedResp: &dto.GetReviewPRResponse{
 				UserID: "u1",
 				PullRequests: []dto.PullRequestShortResponse{
-					{PullRequestID: "pr1", PullRequestName: "Add feature", AuthorID: "u2", Status: "OPEN"},
+					{PullRequestID: "pr1", PullRequestName: "Add feature", AuthorID: "u2", Status: "OPEN", SLA: &dto.ReviewSLAResponse{
+						ElapsedSeconds: 4 * 3600,
+						DueAt:          time.Date(2025, 12, 11, 15, 0, 0, 0, time.UTC),
+					}},
 					{PullRequestID: "pr2", PullRequestName: "Fix bug", AuthorID: "u3", Status: "MERGED"},
 				},
 			},
@@ -148,11 +157,140 @@ func TestUserService_GetReviewPullRequests(t *testing.T) {
 				GetReviewPullRequests(gomock.Any(), tt.req.UserID).
 				Return(tt.mockPRs, tt.mockErr)
 
-			svc := NewUserService(mockRepo)
+			mockHours := mocks.NewMockWorkingHoursRepository(ctrl)
+			if tt.mockErr == nil {
+				mockHours.EXPECT().ListWorkingHours(gomock.Any(), []string{tt.req.UserID}).Return(map[string]domain.WorkingHours{}, nil)
+			}
+
+			svc := NewUserService(mockRepo, mockHours, 16*time.Hour)
+			svc.now = func() time.Time { return time.Date(2025, 12, 10, 12, 0, 0, 0, time.UTC) }
 			resp, err := svc.GetReviewPullRequests(context.Background(), tt.req)
 
-			assert.Equal(t, tt.expectedError, err)
+			assert.ErrorIs(t, err, tt.expectedError)
 			assert.Equal(t, tt.expectedResp, resp)
 		})
 	}
 }
+
+func TestUserService_GetReviewPullRequests_SLA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockHours := mocks.NewMockWorkingHoursRepository(ctrl)
+	svc := NewUserService(mockRepo, mockHours, 8*time.Hour)
+	// понедельник 10:00 по Москве
+	svc.now = func() time.Time { return time.Date(2025, 12, 15, 7, 0, 0, 0, time.UTC) }
+
+	// PR создан в пятницу в 15:00 по Москве: выходные не считаются, 3 часа в пятницу и 1 в понедельник
+	created := time.Date(2025, 12, 12, 12, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetReviewPullRequests(gomock.Any(), "u1").Return([]domain.ReviewPullRequest{
+		{PullRequest: domain.PullRequest{ID: "pr1", Name: "Add feature", AuthorID: "u2", Status: "OPEN", CreatedAt: created}, AssignedAt: created},
+	}, nil)
+	mockHours.EXPECT().ListWorkingHours(gomock.Any(), []string{"u1"}).Return(map[string]domain.WorkingHours{
+		"u1": {UserID: "u1", Timezone: "Europe/Moscow", Start: 9 * 60, End: 18 * 60, Days: domain.DefaultWorkingHours.Days},
+	}, nil)
+
+	resp, err := svc.GetReviewPullRequests(context.Background(), &dto.GetReviewPRRequest{UserID: "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.ReviewSLAResponse{
+		ElapsedSeconds: 4 * 3600,
+		DueAt:          time.Date(2025, 12, 15, 11, 0, 0, 0, time.UTC),
+	}, resp.PullRequests[0].SLA)
+
+	t.Run("breached", func(t *testing.T) {
+		svc.now = func() time.Time { return time.Date(2025, 12, 15, 11, 0, 0, 0, time.UTC) }
+		mockRepo.EXPECT().GetReviewPullRequests(gomock.Any(), "u1").Return([]domain.ReviewPullRequest{
+			{PullRequest: domain.PullRequest{ID: "pr1", Status: "OPEN", CreatedAt: created}, AssignedAt: created},
+		}, nil)
+		mockHours.EXPECT().ListWorkingHours(gomock.Any(), []string{"u1"}).Return(nil, nil)
+
+		// без расписания — UTC: пятница 12:00–18:00 и понедельник 09:00–11:00
+		resp, err := svc.GetReviewPullRequests(context.Background(), &dto.GetReviewPRRequest{UserID: "u1"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8*3600), resp.PullRequests[0].SLA.ElapsedSeconds)
+		assert.True(t, resp.PullRequests[0].SLA.Breached)
+	})
+
+	t.Run("from reassign", func(t *testing.T) {
+		svc.now = func() time.Time { return time.Date(2025, 12, 15, 11, 0, 0, 0, time.UTC) }
+		// ревьюер назначен заменой в понедельник в 09:00 UTC: время до замены ему не считается
+		mockRepo.EXPECT().GetReviewPullRequests(gomock.Any(), "u1").Return([]domain.ReviewPullRequest{
+			{PullRequest: domain.PullRequest{ID: "pr1", Status: "OPEN", CreatedAt: created}, AssignedAt: time.Date(2025, 12, 15, 9, 0, 0, 0, time.UTC)},
+		}, nil)
+		mockHours.EXPECT().ListWorkingHours(gomock.Any(), []string{"u1"}).Return(nil, nil)
+
+		resp, err := svc.GetReviewPullRequests(context.Background(), &dto.GetReviewPRRequest{UserID: "u1"})
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.ReviewSLAResponse{
+			ElapsedSeconds: 2 * 3600,
+			DueAt:          time.Date(2025, 12, 15, 17, 0, 0, 0, time.UTC),
+		}, resp.PullRequests[0].SLA)
+	})
+}
+
+func TestUserService_Skills(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	svc := NewUserService(mockRepo, mocks.NewMockWorkingHoursRepository(ctrl), 16*time.Hour)
+
+	t.Run("set", func(t *testing.T) {
+		mockRepo.EXPECT().
+			SetSkills(gomock.Any(), "u1", []string{"sql", "go"}).
+			Return(&domain.User{ID: "u1", Username: "Alice", TeamName: "team1", IsActive: true, Skills: []string{"go", "sql"}}, nil)
+
+		resp, err := svc.SetSkills(context.Background(), &dto.SetSkillsRequest{UserID: "u1", Skills: []string{"sql", "go"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.UserSkillsResponse{User: dto.UserResponse{
+			UserID: "u1", Username: "Alice", TeamName: "team1", IsActive: true, Skills: []string{"go", "sql"},
+		}}, resp)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockRepo.EXPECT().
+			UpdateSkills(gomock.Any(), "u1", []string{"go"}, []string{"java"}).
+			Return(&domain.User{ID: "u1", Skills: []string{"go"}}, nil)
+
+		resp, err := svc.UpdateSkills(context.Background(), &dto.UpdateSkillsRequest{UserID: "u1", Add: []string{"go"}, Remove: []string{"java"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"go"}, resp.User.Skills)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo.EXPECT().
+			UpdateSkills(gomock.Any(), "u999", nil, []string{"go"}).
+			Return(nil, repository.ErrUserNotFound)
+
+		resp, err := svc.UpdateSkills(context.Background(), &dto.UpdateSkillsRequest{UserID: "u999", Remove: []string{"go"}})
+
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestUserService_SetLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	svc := NewUserService(mockRepo, mocks.NewMockWorkingHoursRepository(ctrl), 16*time.Hour)
+
+	mockRepo.EXPECT().
+		SetLevel(gomock.Any(), "u1", domain.LevelSenior).
+		Return(&domain.User{ID: "u1", Username: "Alice", TeamName: "team1", IsActive: true, Level: domain.LevelSenior}, nil)
+
+	resp, err := svc.SetLevel(context.Background(), &dto.SetLevelRequest{UserID: "u1", Level: "SENIOR"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.SetLevelResponse{User: dto.UserResponse{
+		UserID: "u1", Username: "Alice", TeamName: "team1", IsActive: true, Level: "SENIOR",
+	}}, resp)
+
+	mockRepo.EXPECT().SetLevel(gomock.Any(), "u999", domain.LevelLead).Return(nil, repository.ErrUserNotFound)
+	_, err = svc.SetLevel(context.Background(), &dto.SetLevelRequest{UserID: "u999", Level: "LEAD"})
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+}
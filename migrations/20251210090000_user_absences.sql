@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- weekdays — битовая маска дней недели (1 << time.Weekday), 0 — весь период
+CREATE TABLE user_absences (
+                               absence_id BIGSERIAL PRIMARY KEY,
+                               user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                               kind TEXT NOT NULL CHECK (kind IN ('VACATION', 'SICK_LEAVE', 'PART_TIME')),
+                               starts_at TIMESTAMPTZ NOT NULL,
+                               ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+                               weekdays SMALLINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX user_absences_user_id_idx ON user_absences (user_id, ends_at);
+CREATE INDEX user_absences_period_idx ON user_absences (starts_at, ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_absences;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- source_uid — UID события календаря, из которого импортирован период: повторный импорт обновляет период по нему
+ALTER TABLE user_absences ADD COLUMN source_uid TEXT;
+
+CREATE UNIQUE INDEX user_absences_source_uid_idx ON user_absences (user_id, source_uid) WHERE source_uid IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS user_absences_source_uid_idx;
+ALTER TABLE user_absences DROP COLUMN IF EXISTS source_uid;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- skills — теги экспертизы пользователя, required_tags — теги, которые должны покрыть ревьюеры PR.
+-- Оба массива отсортированы и без повторов
+ALTER TABLE users ADD COLUMN skills TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pull_requests ADD COLUMN required_tags TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN required_tags;
+ALTER TABLE users DROP COLUMN skills;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- level — уровень пользователя, пустая строка — не задан.
+-- team_reviewer_rules — правила команды: среди ревьюеров PR не меньше min_count с уровнем не ниже min_level
+ALTER TABLE users ADD COLUMN level TEXT NOT NULL DEFAULT ''
+    CHECK (level IN ('', 'JUNIOR', 'MIDDLE', 'SENIOR', 'LEAD'));
+
+CREATE TABLE team_reviewer_rules (
+                                     team_name VARCHAR(255) REFERENCES teams(team_name) ON DELETE CASCADE,
+                                     min_level TEXT NOT NULL CHECK (min_level IN ('JUNIOR', 'MIDDLE', 'SENIOR', 'LEAD')),
+                                     min_count SMALLINT NOT NULL CHECK (min_count > 0),
+                                     PRIMARY KEY (team_name, min_level)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_reviewer_rules;
+ALTER TABLE users DROP COLUMN level;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reviewer_assignments — каждый выбор ревьюеров (создание PR и замена) с seed генератора:
+-- по нему и кандидатам выбор повторяется. reviewer_assignment_candidates — кандидаты в момент выбора
+-- в порядке выбора (rank) и чем для каждого он закончился
+CREATE TABLE reviewer_assignments (
+                                      assignment_id BIGSERIAL PRIMARY KEY,
+                                      pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+                                      kind TEXT NOT NULL CHECK (kind IN ('CREATE', 'REASSIGN')),
+                                      replaced_user_id VARCHAR(255),
+                                      seed BIGINT NOT NULL,
+                                      strategy TEXT NOT NULL CHECK (strategy IN ('RANDOM', 'PAIRING_DIVERSITY')),
+                                      assigned_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX reviewer_assignments_pull_request_id_idx ON reviewer_assignments (pull_request_id);
+
+CREATE TABLE reviewer_assignment_candidates (
+                                                assignment_id BIGINT REFERENCES reviewer_assignments(assignment_id) ON DELETE CASCADE,
+                                                user_id VARCHAR(255) NOT NULL,
+                                                rank SMALLINT NOT NULL,
+                                                working BOOLEAN NOT NULL,
+                                                pairing_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+                                                level TEXT NOT NULL DEFAULT '',
+                                                tags TEXT[] NOT NULL DEFAULT '{}',
+                                                chosen BOOLEAN NOT NULL,
+                                                reason TEXT NOT NULL CHECK (reason IN ('REVIEWER_RULES', 'REQUIRED_TAGS', 'RANK', 'OUTRANKED')),
+                                                PRIMARY KEY (assignment_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reviewer_assignment_candidates;
+DROP TABLE IF EXISTS reviewer_assignments;
+-- +goose StatementEnd
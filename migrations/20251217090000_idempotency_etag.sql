@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- etag — заголовок ETag исходного ответа: с повтором по Idempotency-Key клиент получает версию PR для If-Match
+ALTER TABLE idempotency_keys ADD COLUMN etag TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys DROP COLUMN etag;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- CLOSED — PR закрыт без merge. Новое значение в той же транзакции не используется, поэтому ADD VALUE можно здесь же
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'CLOSED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- значение из enum не удалить: тип пересоздается. Закрытые PR в старой схеме не выразить, они удаляются
+DELETE FROM pull_requests WHERE status = 'CLOSED';
+ALTER TYPE pr_status RENAME TO pr_status_old;
+CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
+ALTER TABLE pull_requests ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN status TYPE pr_status USING status::text::pr_status;
+ALTER TABLE pull_requests ALTER COLUMN status SET DEFAULT 'OPEN';
+DROP TYPE pr_status_old;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+// Package migrations встраивает sql-миграции goose в бинарник (см. `migrate` в cmd/main.go).
+// Миграции Postgres лежат в корне, SQLite — в sqlite/ с теми же версиями
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite миграции для internal/repository/sqlite, файлы в корне FS
+var SQLite = mustSub(sqliteFS, "sqlite")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
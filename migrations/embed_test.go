@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// все встроенные миграции должны читаться goose так же, как из папки: версия в имени и обе секции
+func TestEmbeddedMigrations(t *testing.T) {
+	for name, fsys := range map[string]fs.FS{"postgres": FS, "sqlite": SQLite} {
+		t.Run(name, func(t *testing.T) {
+			files, err := fs.Glob(fsys, "*.sql")
+			require.NoError(t, err)
+			require.NotEmpty(t, files)
+
+			for _, name := range files {
+				t.Run(name, func(t *testing.T) {
+					version, err := goose.NumericComponent(name)
+					require.NoError(t, err)
+					assert.Positive(t, version)
+
+					body, err := fs.ReadFile(fsys, name)
+					require.NoError(t, err)
+					assert.True(t, strings.Contains(string(body), "-- +goose Up"))
+					assert.True(t, strings.Contains(string(body), "-- +goose Down"))
+				})
+			}
+		})
+	}
+}
+
+// версии схемы у бэкендов совпадают: проверка версии при старте и /readyz одинаковы для обоих
+func TestSQLiteMigrationsMatchPostgres(t *testing.T) {
+	versions := func(fsys fs.FS) []int64 {
+		files, err := fs.Glob(fsys, "*.sql")
+		require.NoError(t, err)
+		out := make([]int64, 0, len(files))
+		for _, name := range files {
+			v, err := goose.NumericComponent(name)
+			require.NoError(t, err)
+			out = append(out, v)
+		}
+		return out
+	}
+
+	assert.Equal(t, versions(FS), versions(SQLite))
+}
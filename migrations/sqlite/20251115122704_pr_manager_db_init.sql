@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE teams (
+                       team_name VARCHAR(255) PRIMARY KEY
+);
+
+CREATE TABLE users (
+                       user_id VARCHAR(255) PRIMARY KEY,
+                       username VARCHAR(255) NOT NULL,
+                       team_name VARCHAR(255) REFERENCES teams(team_name) ON DELETE SET NULL,
+                       is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- вместо enum pr_status
+CREATE TABLE pull_requests (
+                               pull_request_id VARCHAR(255) PRIMARY KEY,
+                               pull_request_name VARCHAR(255) NOT NULL,
+                               author_id VARCHAR(255) REFERENCES users(user_id) ON DELETE CASCADE,
+                               status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+                               created_at TIMESTAMP NOT NULL,
+                               merged_at TIMESTAMP
+);
+
+CREATE TABLE pr_reviewers (
+                              pull_request_id VARCHAR(255) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+                              user_id VARCHAR(255) REFERENCES users(user_id) ON DELETE CASCADE,
+                              PRIMARY KEY (pull_request_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
+-- +goose StatementEnd
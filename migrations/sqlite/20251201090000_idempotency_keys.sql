@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys (
+                                  idempotency_key VARCHAR(255) PRIMARY KEY,
+                                  fingerprint CHAR(64) NOT NULL,
+                                  status_code INTEGER,
+                                  content_type TEXT,
+                                  response_body BLOB,
+                                  created_at TIMESTAMP NOT NULL,
+                                  expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
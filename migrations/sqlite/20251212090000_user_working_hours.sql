@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Рабочее время пользователя: минуты от полуночи в поясе timezone, end_minute <= start_minute — смена через полночь.
+-- weekdays — битовая маска дней недели (1 << time.Weekday). Нет строки — расписание по умолчанию (пн-пт 09:00-18:00 UTC)
+CREATE TABLE user_working_hours (
+                                    user_id VARCHAR(255) PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+                                    timezone TEXT NOT NULL,
+                                    start_minute INTEGER NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+                                    end_minute INTEGER NOT NULL CHECK (end_minute BETWEEN 0 AND 1439),
+                                    weekdays INTEGER NOT NULL CHECK (weekdays > 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_working_hours;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- team_pairing_settings — стратегия выбора ревьюеров команды, без строки — RANDOM с окном 30 дней.
+-- Историю пар дают pr_reviewers и pull_requests, индекс нужен для выборки PR автора за окно
+CREATE TABLE team_pairing_settings (
+                                       team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+                                       strategy TEXT NOT NULL CHECK (strategy IN ('RANDOM', 'PAIRING_DIVERSITY')),
+                                       decay_days INTEGER NOT NULL CHECK (decay_days BETWEEN 1 AND 365)
+);
+
+CREATE INDEX pull_requests_author_created_at_idx ON pull_requests (author_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS pull_requests_author_created_at_idx;
+DROP TABLE IF EXISTS team_pairing_settings;
+-- +goose StatementEnd
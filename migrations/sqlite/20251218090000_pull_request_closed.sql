@@ -0,0 +1,67 @@
+-- +goose NO TRANSACTION
+
+-- +goose Up
+-- CHECK в SQLite не изменить: таблица пересоздается. Внешние ключи выключаются на время пересоздания,
+-- иначе DROP TABLE каскадом удалит pr_reviewers и reviewer_assignments. PRAGMA внутри транзакции не работает,
+-- поэтому миграция без транзакции goose, а своя транзакция — между PRAGMA
+PRAGMA foreign_keys = OFF;
+
+-- +goose StatementBegin
+BEGIN;
+
+CREATE TABLE pull_requests_new (
+                                   pull_request_id VARCHAR(255) PRIMARY KEY,
+                                   pull_request_name VARCHAR(255) NOT NULL,
+                                   author_id VARCHAR(255) REFERENCES users(user_id) ON DELETE CASCADE,
+                                   status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED', 'CLOSED')),
+                                   created_at TIMESTAMP NOT NULL,
+                                   merged_at TIMESTAMP,
+                                   version INTEGER NOT NULL DEFAULT 1,
+                                   required_tags TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO pull_requests_new (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags)
+SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags
+FROM pull_requests;
+
+DROP TABLE pull_requests;
+ALTER TABLE pull_requests_new RENAME TO pull_requests;
+CREATE INDEX pull_requests_author_created_at_idx ON pull_requests (author_id, created_at);
+
+COMMIT;
+-- +goose StatementEnd
+
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+-- закрытые PR в старой схеме не выразить, они удаляются вместе с ревьюерами
+DELETE FROM pull_requests WHERE status = 'CLOSED';
+
+PRAGMA foreign_keys = OFF;
+
+-- +goose StatementBegin
+BEGIN;
+
+CREATE TABLE pull_requests_old (
+                                   pull_request_id VARCHAR(255) PRIMARY KEY,
+                                   pull_request_name VARCHAR(255) NOT NULL,
+                                   author_id VARCHAR(255) REFERENCES users(user_id) ON DELETE CASCADE,
+                                   status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+                                   created_at TIMESTAMP NOT NULL,
+                                   merged_at TIMESTAMP,
+                                   version INTEGER NOT NULL DEFAULT 1,
+                                   required_tags TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO pull_requests_old (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags)
+SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version, required_tags
+FROM pull_requests;
+
+DROP TABLE pull_requests;
+ALTER TABLE pull_requests_old RENAME TO pull_requests;
+CREATE INDEX pull_requests_author_created_at_idx ON pull_requests (author_id, created_at);
+
+COMMIT;
+-- +goose StatementEnd
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,511 @@
+// Package client Go SDK PR-Manager для других Go-сервисов и prctl.
+// Модели (models.gen.go) генерируются из openapi.yml, методы ходят в ресурсные маршруты /api/v1
+// и остальные не устаревшие операции спецификации. От internal не зависит
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 --config oapi-codegen.yaml ../../openapi.yml
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"service-order-avito/pkg/http/etag"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Second
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+	retry      RetryPolicy
+	// strict неизвестные поля в ответе — ошибка. Включается в тестах, чтобы ловить расхождение с openapi.yml
+	strict bool
+}
+
+type Option func(*Client)
+
+// WithHTTPClient свой http.Client (транспорт, прокси). Его Timeout действует и на потоковые методы,
+// поэтому таймаут вызова лучше задавать WithTimeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout таймаут одной попытки обычного вызова, по умолчанию 10s. 0 — без таймаута.
+// На Events и выгрузки не действует: они ограничиваются только контекстом
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry повторы временных ошибок, см. RetryPolicy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New клиент для сервиса по адресу baseURL, например http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{},
+		timeout:    defaultTimeout,
+		retry:      RetryPolicy{MaxAttempts: 1},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RequestOption заголовки одного изменяющего вызова
+type RequestOption func(http.Header)
+
+// WithIdempotencyKey свой Idempotency-Key: повтор вызова с тем же ключом вернет сохраненный ответ
+func WithIdempotencyKey(key string) RequestOption {
+	return func(h http.Header) {
+		h.Set("Idempotency-Key", key)
+	}
+}
+
+// WithIfMatch изменить PR, только если его версия все еще version, иначе ErrVersionMismatch
+func WithIfMatch(version int64) RequestOption {
+	return func(h http.Header) {
+		h.Set(etag.HeaderIfMatch, etag.Format(version))
+	}
+}
+
+// AddTeam создает команду с участниками (пользователи создаются или обновляются)
+func (c *Client) AddTeam(ctx context.Context, team Team, opts ...RequestOption) (*Team, error) {
+	var resp Team
+	if err := c.do(ctx, http.MethodPost, "/api/v1/teams", team, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*Team, error) {
+	var resp Team
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(teamName), nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetTeamStats(ctx context.Context, teamName string) (*TeamStats, error) {
+	var resp TeamStats
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(teamName)+"/stats", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetReviewerRules заменяет правила команды к уровням ревьюеров, пустой список их снимает
+func (c *Client) SetReviewerRules(ctx context.Context, teamName string, rules []ReviewerRule) (*ReviewerRules, error) {
+	var resp ReviewerRules
+	body := ReviewerRulesRequest{Rules: rules}
+	if err := c.do(ctx, http.MethodPut, "/api/v1/teams/"+url.PathEscape(teamName)+"/reviewer-rules", body, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetReviewerRules(ctx context.Context, teamName string) (*ReviewerRules, error) {
+	var resp ReviewerRules
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(teamName)+"/reviewer-rules", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetPairingSettings задает стратегию выбора ревьюеров команды
+func (c *Client) SetPairingSettings(ctx context.Context, teamName string, req PairingSettingsRequest) (*PairingSettings, error) {
+	var resp PairingSettings
+	if err := c.do(ctx, http.MethodPut, "/api/v1/teams/"+url.PathEscape(teamName)+"/pairing-settings", req, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetPairingSettings(ctx context.Context, teamName string) (*PairingSettings, error) {
+	var resp PairingSettings
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(teamName)+"/pairing-settings", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPairingMatrix кто чьи PR ревьюил за окно команды
+func (c *Client) GetPairingMatrix(ctx context.Context, teamName string) (*PairingMatrix, error) {
+	var resp PairingMatrix
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(teamName)+"/pairings", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) SetIsActive(ctx context.Context, userID string, isActive bool, opts ...RequestOption) (*User, error) {
+	var resp UserResult
+	body := UpdateUserRequest{IsActive: isActive}
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/users/"+url.PathEscape(userID), body, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+// SetSkills заменяет теги экспертизы пользователя, пустой список их очищает
+func (c *Client) SetSkills(ctx context.Context, userID string, skills []string) (*User, error) {
+	var resp UserResult
+	if err := c.do(ctx, http.MethodPut, "/api/v1/users/"+url.PathEscape(userID)+"/skills", SetSkillsRequest{Skills: skills}, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+// UpdateSkills добавляет и убирает теги экспертизы одним изменением
+func (c *Client) UpdateSkills(ctx context.Context, userID string, req UpdateSkillsRequest) (*User, error) {
+	var resp UserResult
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/users/"+url.PathEscape(userID)+"/skills", req, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+// SetLevel задает уровень пользователя: по нему проверяются правила команды к ревьюерам
+func (c *Client) SetLevel(ctx context.Context, userID string, level Level) (*User, error) {
+	var resp UserResult
+	if err := c.do(ctx, http.MethodPut, "/api/v1/users/"+url.PathEscape(userID)+"/level", SetLevelRequest{Level: level}, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+// GetUserReviews PR, где пользователь назначен ревьюером
+func (c *Client) GetUserReviews(ctx context.Context, userID string) ([]PullRequestShort, error) {
+	var resp UserReviews
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/"+url.PathEscape(userID)+"/reviews", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.PullRequests, nil
+}
+
+// AddAbsence добавляет период отсутствия: пока он идет, пользователь не назначается ревьюером
+func (c *Client) AddAbsence(ctx context.Context, userID string, req AddAbsenceRequest, opts ...RequestOption) (*Absence, error) {
+	var resp Absence
+	if err := c.do(ctx, http.MethodPost, "/api/v1/users/"+url.PathEscape(userID)+"/absences", req, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAbsences периоды отсутствия пользователя по времени начала, включая прошедшие
+func (c *Client) ListAbsences(ctx context.Context, userID string) ([]Absence, error) {
+	var resp UserAbsences
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/"+url.PathEscape(userID)+"/absences", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return resp.Absences, nil
+}
+
+func (c *Client) DeleteAbsence(ctx context.Context, userID string, absenceID int64) error {
+	path := fmt.Sprintf("/api/v1/users/%s/absences/%d", url.PathEscape(userID), absenceID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// SetWorkingHours задает рабочее время пользователя: по нему выбираются ревьюеры и считается SLA ревью
+func (c *Client) SetWorkingHours(ctx context.Context, userID string, req WorkingHoursRequest) (*WorkingHours, error) {
+	var resp WorkingHours
+	if err := c.do(ctx, http.MethodPut, "/api/v1/users/"+url.PathEscape(userID)+"/working-hours", req, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetWorkingHours рабочее время пользователя. IsDefault — свое он не задавал
+func (c *Client) GetWorkingHours(ctx context.Context, userID string) (*WorkingHours, error) {
+	var resp WorkingHours
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users/"+url.PathEscape(userID)+"/working-hours", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportUserCalendar загружает календарь .ics пользователя: события OOO становятся периодами отсутствия.
+// Периоды привязаны к UID событий, поэтому тот же файл можно загружать повторно
+func (c *Client) ImportUserCalendar(ctx context.Context, userID string, ics []byte, opts ...RequestOption) (*CalendarImportReport, error) {
+	var resp CalendarImportReport
+	body := rawBody{contentType: "text/calendar", data: ics}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/users/"+url.PathEscape(userID)+"/absences/import", body, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportTeamCalendar загружает общий календарь команды: событие достается участникам из ORGANIZER и ATTENDEE
+func (c *Client) ImportTeamCalendar(ctx context.Context, teamName string, ics []byte, opts ...RequestOption) (*CalendarImportReport, error) {
+	var resp CalendarImportReport
+	body := rawBody{contentType: "text/calendar", data: ics}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/teams/"+url.PathEscape(teamName)+"/absences/import", body, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreatePullRequest создает PR, ревьюеры назначаются сервисом
+func (c *Client) CreatePullRequest(ctx context.Context, req CreatePullRequestRequest, opts ...RequestOption) (*PullRequest, error) {
+	var resp PullRequestResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/pull-requests", req, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp.PullRequest, nil
+}
+
+// PreviewReviewers кого назначило бы создание PR автора сейчас. Ничего не создает
+func (c *Client) PreviewReviewers(ctx context.Context, req PreviewReviewersRequest) (*ReviewersPreview, error) {
+	var resp ReviewersPreview
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/previewReviewers", req, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetPullRequest(ctx context.Context, pullRequestID string) (*PullRequest, error) {
+	var resp PullRequestResult
+	if err := c.do(ctx, http.MethodGet, "/api/v1/pull-requests/"+url.PathEscape(pullRequestID), nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp.PullRequest, nil
+}
+
+// MergePullRequest идемпотентен: повторный merge возвращает тот же PR
+func (c *Client) MergePullRequest(ctx context.Context, pullRequestID string, opts ...RequestOption) (*PullRequest, error) {
+	var resp PullRequestResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/pull-requests/"+url.PathEscape(pullRequestID)+"/merge", nil, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp.PullRequest, nil
+}
+
+// ClosePullRequest закрывает PR без merge, идемпотентен как MergePullRequest. Смерженный PR вернет ErrPRMerged
+func (c *Client) ClosePullRequest(ctx context.Context, pullRequestID string, opts ...RequestOption) (*PullRequest, error) {
+	var resp PullRequestResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/pull-requests/"+url.PathEscape(pullRequestID)+"/close", nil, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp.PullRequest, nil
+}
+
+// ExplainAssignments выборы ревьюеров PR с seed и кандидатами. reviewerID не пуст — только выборы, где он назначен
+func (c *Client) ExplainAssignments(ctx context.Context, pullRequestID, reviewerID string) (*PullRequestAssignments, error) {
+	path := "/api/v1/pull-requests/" + url.PathEscape(pullRequestID) + "/explain"
+	if reviewerID != "" {
+		path += "?" + url.Values{"reviewer_id": {reviewerID}}.Encode()
+	}
+	var resp PullRequestAssignments
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReassignReviewer заменяет ревьюера oldUserID другим активным участником его команды
+func (c *Client) ReassignReviewer(ctx context.Context, pullRequestID, oldUserID string, opts ...RequestOption) (*ReassignResult, error) {
+	var resp ReassignResult
+	body := ReassignRequest{OldUserID: oldUserID}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/pull-requests/"+url.PathEscape(pullRequestID)+"/reassign", body, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Import создает команды и создает или обновляет пользователей. dryRun — только отчет без записи
+func (c *Client) Import(ctx context.Context, teams []Team, dryRun bool, opts ...RequestOption) (*ImportReport, error) {
+	var resp ImportReport
+	path := "/admin/import"
+	if dryRun {
+		path += "?dry_run=true"
+	}
+	if err := c.do(ctx, http.MethodPost, path, ImportRequest{Teams: teams}, &resp, opts); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GraphQL выполняет запрос и разбирает data в out (может быть nil).
+// Ошибки резолверов возвращаются как GraphQLErrors, data при этом все равно разобрана
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any, opts ...RequestOption) error {
+	req := GraphQLRequest{Query: query}
+	if variables != nil {
+		req.Variables = &variables
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/graphql", req, &resp, opts); err != nil {
+		return err
+	}
+	if out != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := c.decode(bytes.NewReader(resp.Data), out); err != nil {
+			return fmt.Errorf("client: decode graphql data: %w", err)
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+	return nil
+}
+
+// Ping GET /ping
+func (c *Client) Ping(ctx context.Context) error {
+	var resp Pong
+	return c.do(ctx, http.MethodGet, "/ping", nil, &resp, nil)
+}
+
+// Healthcheck HEAD /healthcheck
+func (c *Client) Healthcheck(ctx context.Context) error {
+	return c.do(ctx, http.MethodHead, "/healthcheck", nil, nil, nil)
+}
+
+func (c *Client) Livez(ctx context.Context) (*HealthReport, error) {
+	var resp HealthReport
+	if err := c.do(ctx, http.MethodGet, "/livez", nil, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Readyz при 503 возвращает и отчет (какая зависимость упала), и ошибку со StatusCode 503
+func (c *Client) Readyz(ctx context.Context) (*HealthReport, error) {
+	var resp HealthReport
+	err := c.do(ctx, http.MethodGet, "/readyz", nil, &resp, nil)
+	if apiErr, ok := err.(*Error); ok && apiErr.StatusCode == http.StatusServiceUnavailable {
+		if json.Unmarshal(apiErr.body, &resp) == nil && resp.Status != "" {
+			return &resp, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// rawBody тело не в JSON (например, .ics): уходит как есть со своим Content-Type
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// do обычный вызов: JSON (или rawBody) в теле, ответ 2xx разбирается в out, остальные превращаются в *Error
+func (c *Client) do(ctx context.Context, method, path string, body, out any, opts []RequestOption) error {
+	return c.send(ctx, method, path, body, c.timeout, opts, func(resp *http.Response) error {
+		defer resp.Body.Close()
+		if out == nil {
+			return nil
+		}
+		if err := c.decode(resp.Body, out); err != nil {
+			return fmt.Errorf("client: decode %s %s: %w", method, path, err)
+		}
+		return nil
+	})
+}
+
+func (c *Client) decode(r io.Reader, out any) error {
+	dec := json.NewDecoder(r)
+	if c.strict {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(out)
+}
+
+// send выполняет запрос с повторами по RetryPolicy. timeout ограничивает каждую попытку целиком,
+// вместе с handle. handle получает только ответ 2xx и сам закрывает тело
+func (c *Client) send(ctx context.Context, method, path string, body any, timeout time.Duration, opts []RequestOption,
+	handle func(*http.Response) error) error {
+	var (
+		raw         []byte
+		contentType string
+	)
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		raw, contentType = b.data, b.contentType
+	default:
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("client: encode request: %w", err)
+		}
+		contentType = "application/json"
+	}
+
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	header.Set("Accept", "application/problem+json")
+	if method == http.MethodPost && c.retry.enabled() {
+		// без ключа повтор POST после обрыва мог бы выполнить запрос второй раз
+		header.Set("Idempotency-Key", newIdempotencyKey())
+	}
+	for _, opt := range opts {
+		opt(header)
+	}
+
+	for attempt := 1; ; attempt++ {
+		retry, wait, err := c.attempt(ctx, method, path, raw, header, timeout, handle)
+		if err == nil || !retry || attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		if c.retry.MaxRetryAfter > 0 && wait > c.retry.MaxRetryAfter {
+			// ждать дольше не имеет смысла в рамках вызова: когда повторить, видно в Error.RetryAfter
+			return err
+		}
+		if sleep(ctx, max(c.retry.backoff(attempt), wait)) != nil {
+			return err
+		}
+	}
+}
+
+// attempt одна попытка: стоит ли повторять и сколько ждать по Retry-After.
+// Ошибка handle повтору не подлежит: ответ уже получен и, возможно, частично прочитан
+func (c *Client) attempt(ctx context.Context, method, path string, raw []byte, header http.Header, timeout time.Duration,
+	handle func(*http.Response) error) (retry bool, wait time.Duration, err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var reader io.Reader
+	if raw != nil {
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return false, 0, fmt.Errorf("client: %w", err)
+	}
+	req.Header = header.Clone()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return retryable(nil, nil), 0, fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		apiErr := decodeError(resp)
+		apiErr.RetryAfter = retryAfter(resp)
+		return retryable(resp, apiErr), apiErr.RetryAfter, apiErr
+	}
+	return false, 0, handle(resp)
+}
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Requests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /api/v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"is_active": false}`, string(body))
+		json.NewEncoder(w).Encode(map[string]any{"user": User{UserID: r.PathValue("id"), Username: "Bob", TeamName: "backend"}})
+	})
+	mux.HandleFunc("POST /api/v1/pull-requests/{id}/reassign", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"old_user_id": "u2"}`, string(body))
+		w.Write([]byte(`{"replaced_by": "u3"}`))
+	})
+	mux.HandleFunc("GET /api/v1/teams/{name}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "team a/b", r.PathValue("name"))
+		json.NewEncoder(w).Encode(Team{TeamName: r.PathValue("name"), Members: []TeamMember{{UserID: "u1"}}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL + "/")
+	ctx := context.Background()
+
+	user, err := c.SetIsActive(ctx, "u2", false)
+	require.NoError(t, err)
+	assert.Equal(t, User{UserID: "u2", Username: "Bob", TeamName: "backend"}, *user)
+
+	reassigned, err := c.ReassignReviewer(ctx, "pr1", "u2")
+	require.NoError(t, err)
+	assert.Equal(t, "u3", reassigned.ReplacedBy)
+
+	team, err := c.GetTeam(ctx, "team a/b")
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 1)
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		status      int
+		body        string
+		want        Error
+	}{
+		{
+			name:        "problem details",
+			contentType: "application/problem+json",
+			status:      http.StatusBadRequest,
+			body:        `{"title": "request validation failed", "status": 400, "code": "VALIDATION_ERROR", "errors": [{"field": "author_id", "rule": "required", "message": "is required"}]}`,
+			want: Error{StatusCode: 400, Code: "VALIDATION_ERROR", Message: "request validation failed",
+				Violations: []FieldViolation{{Field: "author_id", Rule: "required", Message: "is required"}}},
+		},
+		{
+			name:        "legacy",
+			contentType: "application/json",
+			status:      http.StatusConflict,
+			body:        `{"error": {"code": "PR_MERGED", "message": "cannot reassign on merged PR"}}`,
+			want:        Error{StatusCode: 409, Code: "PR_MERGED", Message: "cannot reassign on merged PR"},
+		},
+		{
+			name:        "not from service",
+			contentType: "text/html",
+			status:      http.StatusBadGateway,
+			body:        "<html>bad gateway</html>",
+			want:        Error{StatusCode: 502, Message: "<html>bad gateway</html>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/problem+json", r.Header.Get("Accept"))
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			_, err := New(srv.URL).MergePullRequest(context.Background(), "pr1")
+
+			var apiErr *Error
+			require.True(t, errors.As(err, &apiErr))
+			apiErr.body = nil
+			assert.Equal(t, tt.want, *apiErr)
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := error(&Error{StatusCode: http.StatusConflict, Code: ErrorCodePRMerged, Message: "cannot reassign on merged PR"})
+
+	assert.ErrorIs(t, err, ErrPRMerged)
+	assert.NotErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, &Error{StatusCode: http.StatusBadGateway}, &Error{}, "error without code matches nothing")
+
+	code := ErrorCodeNotFound
+	gqlErr := error(GraphQLErrors{{Message: "x"}, {Message: "team not found", Extensions: &struct {
+		Code   *ErrorCode        `json:"code,omitempty"`
+		Errors *[]FieldViolation `json:"errors,omitempty"`
+	}{Code: &code}}})
+	assert.ErrorIs(t, gqlErr, ErrNotFound)
+	assert.NotErrorIs(t, gqlErr, ErrPRMerged)
+}
+
+func TestClient_Retry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	t.Run("post retried with the same idempotency key", func(t *testing.T) {
+		var keys []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if len(keys) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"pr": {"pull_request_id": "pr1", "pull_request_name": "x", "author_id": "u1", "status": "MERGED", "assigned_reviewers": [], "version": 2}}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		pr, err := New(srv.URL, WithRetry(policy)).MergePullRequest(context.Background(), "pr1")
+		require.NoError(t, err)
+		assert.Equal(t, PullRequestStatusMerged, pr.Status)
+		require.Len(t, keys, 3)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+		assert.Equal(t, keys[0], keys[2])
+	})
+
+	t.Run("own idempotency key and retry-after", func(t *testing.T) {
+		var calls []time.Time
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "k1", r.Header.Get("Idempotency-Key"))
+			calls = append(calls, time.Now())
+			if len(calls) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{"pr": {"pull_request_id": "pr1", "pull_request_name": "x", "author_id": "u1", "status": "MERGED", "assigned_reviewers": [], "version": 2}}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := New(srv.URL, WithRetry(policy)).MergePullRequest(context.Background(), "pr1", WithIdempotencyKey("k1"))
+		require.NoError(t, err)
+		require.Len(t, calls, 2)
+		assert.GreaterOrEqual(t, calls[1].Sub(calls[0]), time.Second)
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": "NOT_FOUND", "message": "resource not found"}}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := New(srv.URL, WithRetry(policy)).GetPullRequest(context.Background(), "pr1")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := New(srv.URL, WithRetry(policy)).GetTeam(context.Background(), "backend")
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("no idempotency key without retries", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("Idempotency-Key"))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := New(srv.URL).MergePullRequest(context.Background(), "pr1")
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	_, err := New(srv.URL, WithTimeout(50*time.Millisecond)).GetTeam(context.Background(), "backend")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestEventStream_Next(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "e-1", r.Header.Get("Last-Event-ID"))
+		assert.Equal(t, "backend", r.URL.Query().Get("team"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "retry: 3000\n\n: ping\n\n")
+		io.WriteString(w, "id: e-2\nevent: pr.merged\ndata: {\"pull_request_id\":\"pr1\",\"status\":\"MERGED\",\"version\":2,\"occurred_at\":\"2025-01-01T00:00:00Z\"}\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	stream, err := New(srv.URL).Events(context.Background(), EventsFilter{Team: "backend", LastEventID: "e-1"})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	e, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "e-2", e.ID)
+	assert.Equal(t, "pr.merged", e.Type)
+	assert.Equal(t, "pr1", e.Data.PullRequestID)
+	assert.Equal(t, int64(2), e.Data.Version)
+	assert.Equal(t, "e-2", stream.LastEventID)
+
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
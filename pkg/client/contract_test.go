@@ -0,0 +1,753 @@
+package client_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"service-order-avito/internal/events"
+	"service-order-avito/internal/graph"
+	"service-order-avito/internal/health"
+	"service-order-avito/internal/http/codes"
+	"service-order-avito/internal/http/middleware"
+	"service-order-avito/internal/http/server"
+	"service-order-avito/internal/http/server/handlers/admin"
+	availability2 "service-order-avito/internal/http/server/handlers/availability"
+	events2 "service-order-avito/internal/http/server/handlers/events"
+	"service-order-avito/internal/http/server/handlers/export"
+	health2 "service-order-avito/internal/http/server/handlers/health"
+	"service-order-avito/internal/http/server/handlers/pull_request"
+	"service-order-avito/internal/http/server/handlers/team"
+	"service-order-avito/internal/http/server/handlers/user"
+	"service-order-avito/internal/ratelimit"
+	"service-order-avito/internal/repository/memory"
+	"service-order-avito/internal/service/availability"
+	pull_request2 "service-order-avito/internal/service/pull_request"
+	team2 "service-order-avito/internal/service/team"
+	user2 "service-order-avito/internal/service/user"
+	"service-order-avito/pkg/client"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specPath = "../../openapi.yml"
+
+// contract настоящий роутер с сервисами на памяти, обернутый проверкой openapi.yml:
+// каждый запрос и ответ сверяется со спецификацией, вызванные операции запоминаются
+type contract struct {
+	doc    *openapi3.T
+	router routers.Router
+
+	mu         sync.Mutex
+	violations []string
+	called     map[string]bool
+}
+
+func newContract(t *testing.T) (*contract, chi.Router) {
+	t.Helper()
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile(specPath)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	specRouter, err := legacy.NewRouter(doc)
+	require.NoError(t, err)
+	// .ics проверяется как строка, разбор — дело обработчика
+	openapi3filter.RegisterBodyDecoder("text/calendar", openapi3filter.PlainBodyDecoder)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	storage := memory.NewStorage()
+	teamService := team2.NewTeamService(memory.NewTeamRepositoryMemory(storage))
+	availRepo := memory.NewAvailabilityRepositoryMemory(storage)
+	userService := user2.NewUserService(memory.NewUserRepositoryMemory(storage), availRepo, 16*time.Hour)
+	broker := events.NewBroker(100, 16)
+	// две замены на PR в час: в TestContract третья замена того же PR получает RATE_LIMITED
+	reassignLimiter := ratelimit.New(ratelimit.Limit{N: 2, Per: time.Hour})
+	prService := pull_request2.NewPullRequestService(memory.NewPullRequestRepositoryMemory(storage, 0), broker, reassignLimiter)
+
+	r := server.InitRouter(log,
+		team.NewTeamHandler(teamService),
+		user.NewUserHandler(userService),
+		pull_request.NewPullRequestHandler(prService),
+		availability2.NewAvailabilityHandler(availability.NewAvailabilityService(availRepo, memory.NewTeamRepositoryMemory(storage))),
+		health2.NewHealthHandler(health.NewProbe(time.Second)),
+		events2.NewEventsHandler(broker, teamService, time.Minute),
+		graph.NewHandler(log, teamService, userService, prService),
+		admin.NewAdminHandler(teamService),
+		export.NewExportHandler(prService),
+		middleware.WithIdempotency(log, memory.NewIdempotencyRepositoryMemory(storage), time.Hour),
+		middleware.WithRateLimit(log, ratelimit.NewRouteLimiter(ratelimit.Limit{}, nil), ""),
+	)
+	return &contract{doc: doc, router: specRouter, called: make(map[string]bool)}, r
+}
+
+func (c *contract) violate(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.violations = append(c.violations, fmt.Sprintf(format, args...))
+}
+
+func (c *contract) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := c.router.FindRoute(r)
+		if err != nil {
+			c.violate("%s %s: not in openapi.yml: %v", r.Method, r.URL.Path, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		c.mu.Lock()
+		c.called[route.Method+" "+route.Path] = true
+		c.mu.Unlock()
+
+		reqBody, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(reqBody))
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r.Clone(r.Context()),
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{MultiError: true},
+		}
+		input.Request.Body = io.NopCloser(bytes.NewReader(reqBody))
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			c.violate("%s %s request: %v", r.Method, r.URL.Path, err)
+		}
+
+		rec := &teeWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		c.checkResponse(r, route, input, rec)
+	})
+}
+
+// checkResponse JSON-ответы проверяет kin-openapi, NDJSON и SSE — построчно по схеме из спецификации
+func (c *contract) checkResponse(r *http.Request, route *routers.Route, input *openapi3filter.RequestValidationInput, rec *teeWriter) {
+	where := fmt.Sprintf("%s %s -> %d", r.Method, r.URL.Path, rec.status)
+	contentType := strings.TrimSpace(strings.Split(rec.Header().Get("Content-Type"), ";")[0])
+
+	switch contentType {
+	case "application/x-ndjson", "text/event-stream":
+		response := route.Operation.Responses.Status(rec.status)
+		if response == nil || response.Value.Content.Get(contentType) == nil {
+			c.violate("%s: %s not described", where, contentType)
+			return
+		}
+		schema := response.Value.Content.Get(contentType).Schema.Value
+		if contentType == "text/event-stream" {
+			schema = c.doc.Components.Schemas["PullRequestEvent"].Value
+		}
+		for _, line := range jsonLines(contentType, rec.body.Bytes()) {
+			var v any
+			if err := json.Unmarshal(line, &v); err != nil {
+				c.violate("%s: %v", where, err)
+				continue
+			}
+			if err := schema.VisitJSON(v); err != nil {
+				c.violate("%s: %s", where, err)
+			}
+		}
+	default:
+		err := openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+		})
+		if err != nil {
+			c.violate("%s response: %v", where, err)
+		}
+	}
+}
+
+// jsonLines строки NDJSON или data событий SSE
+func jsonLines(contentType string, body []byte) [][]byte {
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if contentType == "text/event-stream" {
+			data, ok := bytes.CutPrefix(line, []byte("data: "))
+			if !ok {
+				continue
+			}
+			line = data
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines = append(lines, bytes.Clone(line))
+		}
+	}
+	return lines
+}
+
+// teeWriter копия ответа для проверки. Flush и Unwrap нужны SSE и выгрузкам
+type teeWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *teeWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *teeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// TestContract каждая не устаревшая операция openapi.yml вызывается через SDK против настоящего роутера.
+// Расхождение SDK, сервера и спецификации в любую сторону роняет тест
+func TestContract(t *testing.T) {
+	spec, router := newContract(t)
+	srv := httptest.NewServer(spec.middleware(router))
+
+	ctx := context.Background()
+	retry := client.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, MaxRetryAfter: time.Second}
+	c := client.New(srv.URL, client.WithStrictDecoding(), client.WithRetry(retry))
+
+	t.Run("health", func(t *testing.T) {
+		require.NoError(t, c.Ping(ctx))
+		require.NoError(t, c.Healthcheck(ctx))
+
+		live, err := c.Livez(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, client.HealthReportStatusOk, live.Status)
+
+		ready, err := c.Readyz(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, client.HealthReportStatusOk, ready.Status)
+	})
+
+	t.Run("teams and users", func(t *testing.T) {
+		created, err := c.AddTeam(ctx, client.Team{TeamName: "backend", Members: []client.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+			{UserID: "u4", Username: "Dave", IsActive: true},
+		}})
+		require.NoError(t, err)
+		assert.Len(t, created.Members, 4)
+
+		_, err = c.AddTeam(ctx, client.Team{TeamName: "backend", Members: []client.TeamMember{}})
+		assert.ErrorIs(t, err, client.ErrTeamExists)
+
+		got, err := c.GetTeam(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, "backend", got.TeamName)
+
+		_, err = c.GetTeam(ctx, "missing")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		u4, err := c.SetIsActive(ctx, "u4", false)
+		require.NoError(t, err)
+		assert.False(t, u4.IsActive)
+
+		_, err = c.SetIsActive(ctx, "missing", false)
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		stats, err := c.GetTeamStats(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.ActiveUsers)
+		assert.Equal(t, 1, stats.InactiveUsers)
+	})
+
+	stream, err := c.Events(ctx, client.EventsFilter{Team: "backend"})
+	require.NoError(t, err)
+
+	var pr *client.PullRequest
+	t.Run("pull requests", func(t *testing.T) {
+		pr, err = c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, client.PullRequestStatusOpen, pr.Status)
+		require.Len(t, pr.AssignedReviewers, 2)
+
+		_, err = c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+		assert.ErrorIs(t, err, client.ErrPRExists)
+
+		_, err = c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestName: "no id"})
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.ErrorIs(t, err, client.ErrValidation)
+		assert.NotEmpty(t, apiErr.Violations)
+
+		got, err := c.GetPullRequest(ctx, "pr1")
+		require.NoError(t, err)
+		assert.Equal(t, pr.Version, got.Version)
+
+		reviews, err := c.GetUserReviews(ctx, pr.AssignedReviewers[0])
+		require.NoError(t, err)
+		require.Len(t, reviews, 1)
+		assert.Equal(t, "pr1", reviews[0].PullRequestID)
+		require.NotNil(t, reviews[0].SLA)
+		assert.False(t, reviews[0].SLA.Breached)
+
+		_, err = c.ReassignReviewer(ctx, "pr1", "u1")
+		assert.ErrorIs(t, err, client.ErrNotAssigned)
+
+		// единственный свободный участник неактивен
+		_, err = c.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0])
+		assert.ErrorIs(t, err, client.ErrNoCandidate)
+
+		_, err = c.SetIsActive(ctx, "u4", true)
+		require.NoError(t, err)
+
+		_, err = c.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], client.WithIfMatch(pr.Version+10))
+		assert.ErrorIs(t, err, client.ErrVersionMismatch)
+
+		reassigned, err := c.ReassignReviewer(ctx, "pr1", pr.AssignedReviewers[0], client.WithIfMatch(pr.Version))
+		require.NoError(t, err)
+		assert.Equal(t, "u4", reassigned.ReplacedBy)
+
+		merged, err := c.MergePullRequest(ctx, "pr1", client.WithIdempotencyKey("merge-pr1"))
+		require.NoError(t, err)
+		assert.Equal(t, client.PullRequestStatusMerged, merged.Status)
+		require.NotNil(t, merged.MergedAt)
+
+		_, err = c.ReassignReviewer(ctx, "pr1", "u4")
+		assert.ErrorIs(t, err, client.ErrPRMerged)
+
+		_, err = c.MergePullRequest(ctx, "missing")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		_, err = c.ClosePullRequest(ctx, "pr1")
+		assert.ErrorIs(t, err, client.ErrPRMerged)
+	})
+
+	t.Run("events", func(t *testing.T) {
+		var types []string
+		for range 3 {
+			e, err := stream.Next()
+			require.NoError(t, err)
+			assert.Equal(t, "pr1", e.Data.PullRequestID)
+			types = append(types, e.Type)
+		}
+		assert.Equal(t, []string{"pr.created", "pr.reassigned", "pr.merged"}, types)
+		require.NoError(t, stream.Close())
+
+		_, err := c.Events(ctx, client.EventsFilter{Team: "missing"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		teams := []client.Team{{TeamName: "frontend", Members: []client.TeamMember{
+			{UserID: "u5", Username: "Eve", IsActive: true},
+			{UserID: "u4", Username: "Dave", IsActive: true},
+		}}}
+
+		report, err := c.Import(ctx, teams, true)
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []string{"frontend"}, report.TeamsCreated)
+
+		report, err = c.Import(ctx, teams, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u5"}, report.UsersCreated)
+		require.Len(t, report.UsersMoved, 1)
+		assert.Equal(t, "backend", report.UsersMoved[0].FromTeam)
+
+		_, err = c.Import(ctx, []client.Team{}, false)
+		assert.ErrorIs(t, err, client.ErrValidation)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		var prs []client.PullRequestExportRow
+		require.NoError(t, c.ExportPullRequests(ctx, client.ExportFilter{Team: "backend"}, func(row client.PullRequestExportRow) error {
+			prs = append(prs, row)
+			return nil
+		}))
+		require.Len(t, prs, 1)
+		assert.Equal(t, client.PullRequestStatusMerged, prs[0].Status)
+
+		var reviews []client.ReviewExportRow
+		require.NoError(t, c.ExportReviews(ctx, client.ExportFilter{From: time.Now().Add(-time.Hour)}, func(row client.ReviewExportRow) error {
+			reviews = append(reviews, row)
+			return nil
+		}))
+		assert.Len(t, reviews, 2)
+
+		err := c.ExportPullRequests(ctx, client.ExportFilter{Team: "missing"}, func(client.PullRequestExportRow) error { return nil })
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("graphql", func(t *testing.T) {
+		var data struct {
+			Team struct {
+				Name    string `json:"name"`
+				Members []struct {
+					ID string `json:"id"`
+				} `json:"members"`
+			} `json:"team"`
+		}
+		err := c.GraphQL(ctx, `query($name: String!) { team(name: $name) { name members { id } } }`,
+			map[string]any{"name": "backend"}, &data)
+		require.NoError(t, err)
+		assert.Equal(t, "backend", data.Team.Name)
+		assert.Len(t, data.Team.Members, 3)
+
+		err = c.GraphQL(ctx, `mutation { mergePullRequest(id: "missing") { id } }`, nil, nil)
+		var gqlErrs client.GraphQLErrors
+		require.ErrorAs(t, err, &gqlErrs)
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("reassign limit", func(t *testing.T) {
+		_, err := c.AddTeam(ctx, client.Team{TeamName: "platform", Members: []client.TeamMember{
+			{UserID: "p1", Username: "Pat", IsActive: true},
+			{UserID: "p2", Username: "Quinn", IsActive: true},
+			{UserID: "p3", Username: "Ray", IsActive: true},
+			{UserID: "p4", Username: "Sam", IsActive: true},
+		}})
+		require.NoError(t, err)
+		pr2, err := c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestID: "pr2", PullRequestName: "Limit", AuthorID: "p1"})
+		require.NoError(t, err)
+
+		// меняем одного и того же ревьюера туда и обратно, пока не кончится лимит PR
+		old := pr2.AssignedReviewers[0]
+		for range 2 {
+			reassigned, err := c.ReassignReviewer(ctx, "pr2", old)
+			require.NoError(t, err)
+			old = reassigned.ReplacedBy
+		}
+
+		_, err = c.ReassignReviewer(ctx, "pr2", old)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.ErrorIs(t, err, client.ErrRateLimited)
+		assert.Greater(t, apiErr.RetryAfter, time.Minute)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		pr2, err := c.GetPullRequest(ctx, "pr2")
+		require.NoError(t, err)
+
+		_, err = c.ClosePullRequest(ctx, "pr2", client.WithIfMatch(pr2.Version-1))
+		assert.ErrorIs(t, err, client.ErrVersionMismatch)
+
+		closed, err := c.ClosePullRequest(ctx, "pr2", client.WithIfMatch(pr2.Version))
+		require.NoError(t, err)
+		assert.Equal(t, client.PullRequestStatusClosed, closed.Status)
+		assert.Equal(t, pr2.Version+1, closed.Version)
+		assert.Nil(t, closed.MergedAt)
+
+		_, err = c.MergePullRequest(ctx, "pr2")
+		assert.ErrorIs(t, err, client.ErrPRClosed)
+
+		_, err = c.ClosePullRequest(ctx, "missing")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("absences", func(t *testing.T) {
+		_, err := c.AddTeam(ctx, client.Team{TeamName: "mobile", Members: []client.TeamMember{
+			{UserID: "m1", Username: "Max", IsActive: true},
+			{UserID: "m2", Username: "Nina", IsActive: true},
+			{UserID: "m3", Username: "Oleg", IsActive: true},
+			{UserID: "m4", Username: "Pavel", IsActive: true},
+		}})
+		require.NoError(t, err)
+
+		vacation, err := c.AddAbsence(ctx, "m2", client.AddAbsenceRequest{Kind: client.AbsenceKindVacation, StartsAt: "2020-01-01", EndsAt: "2100-12-31"})
+		require.NoError(t, err)
+		assert.Equal(t, "m2", vacation.UserID)
+		assert.Nil(t, vacation.Weekdays)
+		weekdays := []client.Weekday{client.WeekdayFriday}
+		partTime, err := c.AddAbsence(ctx, "m2", client.AddAbsenceRequest{
+			Kind: client.AbsenceKindPartTime, StartsAt: "2100-01-01", EndsAt: "2100-12-31", Weekdays: &weekdays,
+		})
+		require.NoError(t, err)
+
+		// m2 в отпуске, поэтому ревьюеры — оба оставшихся
+		pr3, err := c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestID: "pr3", PullRequestName: "Offline", AuthorID: "m1"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"m3", "m4"}, pr3.AssignedReviewers)
+
+		absences, err := c.ListAbsences(ctx, "m2")
+		require.NoError(t, err)
+		require.Len(t, absences, 2)
+		assert.Equal(t, vacation.AbsenceID, absences[0].AbsenceID)
+		require.NotNil(t, absences[1].Weekdays)
+		assert.Equal(t, weekdays, *absences[1].Weekdays)
+
+		_, err = c.AddAbsence(ctx, "m2", client.AddAbsenceRequest{Kind: client.AbsenceKindPartTime, StartsAt: "2100-01-01", EndsAt: "2100-12-31"})
+		assert.ErrorIs(t, err, client.ErrValidation)
+		_, err = c.AddAbsence(ctx, "missing", client.AddAbsenceRequest{Kind: client.AbsenceKindSickLeave, StartsAt: "2100-01-01", EndsAt: "2100-01-02"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.ListAbsences(ctx, "missing")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		require.NoError(t, c.DeleteAbsence(ctx, "m2", partTime.AbsenceID))
+		assert.ErrorIs(t, c.DeleteAbsence(ctx, "m2", partTime.AbsenceID), client.ErrNotFound)
+		assert.ErrorIs(t, c.DeleteAbsence(ctx, "m2", 0), client.ErrValidation)
+	})
+
+	t.Run("working hours", func(t *testing.T) {
+		hours, err := c.GetWorkingHours(ctx, "m1")
+		require.NoError(t, err)
+		assert.True(t, hours.IsDefault)
+		assert.Equal(t, "UTC", hours.Timezone)
+
+		hours, err = c.SetWorkingHours(ctx, "m1", client.WorkingHoursRequest{
+			Timezone: "Europe/Moscow", Start: "22:00", End: "06:00", Weekdays: []client.Weekday{client.WeekdayFriday},
+		})
+		require.NoError(t, err)
+		assert.False(t, hours.IsDefault)
+		assert.Equal(t, []client.Weekday{client.WeekdayFriday}, hours.Weekdays)
+
+		_, err = c.SetWorkingHours(ctx, "m1", client.WorkingHoursRequest{Timezone: "Moscow", Start: "9:00", End: "18:00", Weekdays: []client.Weekday{client.WeekdayFriday}})
+		assert.ErrorIs(t, err, client.ErrValidation)
+		_, err = c.GetWorkingHours(ctx, "missing")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("skills", func(t *testing.T) {
+		user, err := c.SetSkills(ctx, "m2", []string{"sql", "go"})
+		require.NoError(t, err)
+		assert.Equal(t, &client.Skills{"go", "sql"}, user.Skills)
+
+		user, err = c.UpdateSkills(ctx, "m2", client.UpdateSkillsRequest{Add: &[]string{"k8s"}, Remove: &[]string{"sql"}})
+		require.NoError(t, err)
+		assert.Equal(t, &client.Skills{"go", "k8s"}, user.Skills)
+
+		_, err = c.UpdateSkills(ctx, "missing", client.UpdateSkillsRequest{Add: &[]string{"go"}})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("calendar import", func(t *testing.T) {
+		ics := []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+			"BEGIN:VEVENT\r\nUID:trip\r\nSUMMARY:OOO\r\nDTSTART;VALUE=DATE:21000101\r\nDTEND;VALUE=DATE:21000110\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nUID:standup\r\nSUMMARY:Standup\r\nDTSTART:21000101T090000Z\r\nDTEND:21000101T091500Z\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n")
+
+		report, err := c.ImportUserCalendar(ctx, "m3", ics)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Ignored)
+		// повторная загрузка ничего не дублирует
+		report, err = c.ImportUserCalendar(ctx, "m3", ics)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 1, report.Unchanged)
+
+		absences, err := c.ListAbsences(ctx, "m3")
+		require.NoError(t, err)
+		require.Len(t, absences, 1)
+		require.NotNil(t, absences[0].UID)
+		assert.Equal(t, "trip", *absences[0].UID)
+
+		team := []byte("BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\nUID:sick\r\nCATEGORIES:OOO,Sick\r\nORGANIZER;CN=Pavel:mailto:pavel@example.com\r\n" +
+			"DTSTART;VALUE=DATE:21000101\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n")
+		report, err = c.ImportTeamCalendar(ctx, "mobile", team)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		absences, err = c.ListAbsences(ctx, "m4")
+		require.NoError(t, err)
+		require.Len(t, absences, 1)
+		assert.Equal(t, client.AbsenceKindSickLeave, absences[0].Kind)
+
+		_, err = c.ImportUserCalendar(ctx, "m3", []byte("not a calendar"))
+		assert.ErrorIs(t, err, client.ErrInvalidFile)
+		_, err = c.ImportTeamCalendar(ctx, "missing", team)
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("reviewer rules", func(t *testing.T) {
+		_, err := c.AddTeam(ctx, client.Team{TeamName: "security", Members: []client.TeamMember{
+			{UserID: "s1", Username: "Ivan", IsActive: true},
+			{UserID: "s2", Username: "Olga", IsActive: true},
+			{UserID: "s3", Username: "Petr", IsActive: true},
+		}})
+		require.NoError(t, err)
+
+		rules, err := c.GetReviewerRules(ctx, "security")
+		require.NoError(t, err)
+		assert.Empty(t, rules.Rules)
+
+		rules, err = c.SetReviewerRules(ctx, "security", []client.ReviewerRule{{MinLevel: client.LevelSENIOR, Count: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, []client.ReviewerRule{{MinLevel: client.LevelSENIOR, Count: 1}}, rules.Rules)
+		_, err = c.SetReviewerRules(ctx, "missing", []client.ReviewerRule{})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		// сеньоров в команде нет
+		_, err = c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestID: "pr-rules", PullRequestName: "Rules", AuthorID: "s1"})
+		assert.ErrorIs(t, err, client.ErrReviewerRuleUnmet)
+		preview, err := c.PreviewReviewers(ctx, client.PreviewReviewersRequest{AuthorID: "s1"})
+		require.NoError(t, err)
+		assert.Equal(t, rules.Rules, preview.UnmetRules)
+
+		user, err := c.SetLevel(ctx, "s3", client.LevelLEAD)
+		require.NoError(t, err)
+		require.NotNil(t, user.Level)
+		assert.Equal(t, client.LevelLEAD, *user.Level)
+		_, err = c.SetLevel(ctx, "missing", client.LevelJUNIOR)
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		pr, err := c.CreatePullRequest(ctx, client.CreatePullRequestRequest{PullRequestID: "pr-rules", PullRequestName: "Rules", AuthorID: "s1"})
+		require.NoError(t, err)
+		assert.Contains(t, pr.AssignedReviewers, "s3")
+	})
+
+	t.Run("pairing", func(t *testing.T) {
+		settings, err := c.GetPairingSettings(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, client.PairingStrategyRANDOM, settings.Strategy)
+		assert.Equal(t, 30, settings.DecayDays)
+
+		days := 14
+		settings, err = c.SetPairingSettings(ctx, "backend", client.PairingSettingsRequest{Strategy: client.PairingStrategyPAIRINGDIVERSITY, DecayDays: &days})
+		require.NoError(t, err)
+		assert.Equal(t, client.PairingStrategyPAIRINGDIVERSITY, settings.Strategy)
+		assert.Equal(t, 14, settings.DecayDays)
+		_, err = c.SetPairingSettings(ctx, "missing", client.PairingSettingsRequest{Strategy: client.PairingStrategyRANDOM})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+
+		// pr1 автора u1 создан выше: его ревьюеры есть в матрице
+		matrix, err := c.GetPairingMatrix(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, 14, matrix.DecayDays)
+		require.NotEmpty(t, matrix.Pairs)
+		assert.Equal(t, "u1", matrix.Pairs[0].AuthorID)
+		_, err = c.GetPairingMatrix(ctx, "missing")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("explain", func(t *testing.T) {
+		// pr1 создан и переназначен выше: оба выбора записаны с seed
+		explained, err := c.ExplainAssignments(ctx, "pr1", "")
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(explained.Assignments), 2)
+		created := explained.Assignments[0]
+		assert.Equal(t, client.AssignmentKindCREATE, created.Kind)
+		assert.NotEmpty(t, created.Seed)
+		require.NotEmpty(t, created.Candidates)
+		assert.Equal(t, 1, created.Candidates[0].Rank)
+
+		reviewer := created.Reviewers[0]
+		filtered, err := c.ExplainAssignments(ctx, "pr1", reviewer)
+		require.NoError(t, err)
+		for _, a := range filtered.Assignments {
+			assert.Contains(t, a.Reviewers, reviewer)
+		}
+
+		_, err = c.ExplainAssignments(ctx, "missing", "")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("preview reviewers", func(t *testing.T) {
+		preview, err := c.PreviewReviewers(ctx, client.PreviewReviewersRequest{AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, "u1", preview.AuthorID)
+		assert.NotEmpty(t, preview.Reviewers)
+		assert.Empty(t, preview.UnmetRules)
+		assert.Contains(t, preview.Excluded, client.ExcludedMember{UserID: "u1", Reason: client.ExcludedMemberReasonAUTHOR})
+		for _, c := range preview.Candidates {
+			assert.NotEqual(t, "u1", c.UserID)
+		}
+
+		_, err = c.PreviewReviewers(ctx, client.PreviewReviewersRequest{AuthorID: "missing"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	// дожидаемся обработчиков (и проверки их ответов), прежде чем смотреть нарушения
+	srv.Close()
+
+	assert.Empty(t, spec.violations, "requests and responses must match openapi.yml")
+
+	var missed []string
+	for path, item := range spec.doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			if !op.Deprecated && !spec.called[method+" "+path] {
+				missed = append(missed, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(missed)
+	assert.Empty(t, missed, "every operation of openapi.yml needs an SDK method covered here")
+}
+
+// TestContract_Routes роутер и openapi.yml описывают одни и те же маршруты, включая устаревшие
+func TestContract_Routes(t *testing.T) {
+	spec, router := newContract(t)
+
+	var served []string
+	require.NoError(t, chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		served = append(served, method+" "+route)
+		return nil
+	}))
+
+	var described []string
+	for path, item := range spec.doc.Paths.Map() {
+		for method := range item.Operations() {
+			described = append(described, method+" "+path)
+		}
+	}
+
+	slices.Sort(served)
+	slices.Sort(described)
+	assert.Equal(t, described, served)
+}
+
+// TestContract_ErrorCodes коды ошибок SDK, openapi.yml и сервера совпадают
+func TestContract_ErrorCodes(t *testing.T) {
+	spec, _ := newContract(t)
+
+	var described []string
+	for _, v := range spec.doc.Components.Schemas["ErrorCode"].Value.Enum {
+		described = append(described, v.(string))
+	}
+
+	sentinels := []*client.Error{
+		client.ErrTeamExists, client.ErrPRExists, client.ErrPRMerged, client.ErrPRClosed, client.ErrNotAssigned, client.ErrNoCandidate,
+		client.ErrNotFound, client.ErrInternal, client.ErrInvalidJSON, client.ErrInvalidFile, client.ErrValidation,
+		client.ErrVersionMismatch, client.ErrIdempotencyKeyReused, client.ErrIdempotencyKeyInProgress, client.ErrRateLimited,
+		client.ErrReviewerRuleUnmet,
+	}
+	var sdk []string
+	for _, e := range sentinels {
+		sdk = append(sdk, string(e.Code))
+	}
+
+	server := []string{
+		codes.TEAM_EXISTS, codes.PR_EXISTS, codes.PR_MERGED, codes.PR_CLOSED, codes.NOT_ASSIGNED, codes.NO_CANDIDATE,
+		codes.NOT_FOUND, codes.INTERNAL_ERROR, codes.INVALID_JSON, codes.INVALID_FILE, codes.VALIDATION_ERROR,
+		codes.VERSION_MISMATCH, codes.IDEMPOTENCY_KEY_REUSED, codes.IDEMPOTENCY_KEY_IN_PROGRESS, codes.RATE_LIMITED,
+		codes.REVIEWER_RULE_UNMET,
+	}
+
+	slices.Sort(described)
+	slices.Sort(sdk)
+	slices.Sort(server)
+	assert.Equal(t, described, sdk)
+	assert.Equal(t, described, server)
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Error ответ сервиса не 2xx. Code — код из API, для ответов не от сервиса (прокси, 502) пустой.
+// Проверяется через errors.Is с ошибками ниже: errors.Is(err, client.ErrNotFound)
+type Error struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	Violations []FieldViolation
+	// RetryAfter из заголовка Retry-After (429 RATE_LIMITED, 503), 0 если его нет
+	RetryAfter time.Duration
+
+	// body тело ответа, например отчет /readyz при 503
+	body []byte
+}
+
+// Ошибки по кодам API (ErrorCode). Сравниваются только по коду, статус и сообщение не важны
+var (
+	ErrTeamExists               = &Error{Code: ErrorCodeTeamExists}
+	ErrPRExists                 = &Error{Code: ErrorCodePRExists}
+	ErrPRMerged                 = &Error{Code: ErrorCodePRMerged}
+	ErrPRClosed                 = &Error{Code: ErrorCodePRClosed}
+	ErrNotAssigned              = &Error{Code: ErrorCodeNotAssigned}
+	ErrNoCandidate              = &Error{Code: ErrorCodeNoCandidate}
+	ErrNotFound                 = &Error{Code: ErrorCodeNotFound}
+	ErrInternal                 = &Error{Code: ErrorCodeInternalError}
+	ErrInvalidJSON              = &Error{Code: ErrorCodeInvalidJSON}
+	ErrInvalidFile              = &Error{Code: ErrorCodeInvalidFile}
+	ErrValidation               = &Error{Code: ErrorCodeValidationError}
+	ErrVersionMismatch          = &Error{Code: ErrorCodeVersionMismatch}
+	ErrIdempotencyKeyReused     = &Error{Code: ErrorCodeIdempotencyKeyReused}
+	ErrIdempotencyKeyInProgress = &Error{Code: ErrorCodeIdempotencyKeyInProgress}
+	ErrRateLimited              = &Error{Code: ErrorCodeRateLimited}
+	ErrReviewerRuleUnmet        = &Error{Code: ErrorCodeReviewerRuleUnmet}
+)
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	for _, v := range e.Violations {
+		msg += fmt.Sprintf("; %s: %s", v.Field, v.Message)
+	}
+	return msg
+}
+
+// Is совпадение по коду API, чтобы errors.Is(err, ErrNotFound) работал для любого ответа с NOT_FOUND
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// GraphQLErrors ошибки из errors[] ответа /graphql. errors.Is находит код любой из них
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, gqlErr := range e {
+		msgs[i] = gqlErr.Message
+		if code := gqlErr.code(); code != "" {
+			msgs[i] = string(code) + ": " + gqlErr.Message
+		}
+	}
+	return "graphql: " + strings.Join(msgs, "; ")
+}
+
+func (e GraphQLErrors) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t.Code == "" {
+		return false
+	}
+	for _, gqlErr := range e {
+		if gqlErr.code() == t.Code {
+			return true
+		}
+	}
+	return false
+}
+
+func (e GraphQLError) code() ErrorCode {
+	if e.Extensions == nil || e.Extensions.Code == nil {
+		return ""
+	}
+	return *e.Extensions.Code
+}
+
+// maxErrorBody сколько тела ответа читается в сообщение, если это не JSON сервиса
+const maxErrorBody = 1 << 10
+
+// decodeError понимает и RFC 7807, и старый формат {"error": {"code", "message"}}
+func decodeError(resp *http.Response) *Error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	e := &Error{StatusCode: resp.StatusCode, body: raw}
+
+	var body struct {
+		Title  string           `json:"title"`
+		Code   ErrorCode        `json:"code"`
+		Errors []FieldViolation `json:"errors"`
+		Error  *struct {
+			Code    ErrorCode `json:"code"`
+			Message string    `json:"message"`
+		} `json:"error"`
+	}
+	switch {
+	case json.Unmarshal(raw, &body) != nil:
+		e.Message = strings.TrimSpace(string(raw[:min(len(raw), maxErrorBody)]))
+	case body.Error != nil:
+		e.Code, e.Message = body.Error.Code, body.Error.Message
+	default:
+		e.Code, e.Message, e.Violations = body.Code, body.Title, body.Errors
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
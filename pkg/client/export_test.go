@@ -0,0 +1,9 @@
+package client
+
+// WithStrictDecoding неизвестные поля в ответах — ошибка: так контрактный тест ловит поля,
+// которые сервер отдает, а openapi.yml не описывает
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
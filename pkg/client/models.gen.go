@@ -0,0 +1,796 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package client
+
+import (
+	"time"
+)
+
+// Defines values for AbsenceKind.
+const (
+	AbsenceKindPartTime  AbsenceKind = "PART_TIME"
+	AbsenceKindSickLeave AbsenceKind = "SICK_LEAVE"
+	AbsenceKindVacation  AbsenceKind = "VACATION"
+)
+
+// Defines values for AssignmentKind.
+const (
+	AssignmentKindCREATE   AssignmentKind = "CREATE"
+	AssignmentKindREASSIGN AssignmentKind = "REASSIGN"
+)
+
+// Defines values for AssignmentCandidateReason.
+const (
+	AssignmentCandidateReasonOUTRANKED     AssignmentCandidateReason = "OUTRANKED"
+	AssignmentCandidateReasonRANK          AssignmentCandidateReason = "RANK"
+	AssignmentCandidateReasonREQUIREDTAGS  AssignmentCandidateReason = "REQUIRED_TAGS"
+	AssignmentCandidateReasonREVIEWERRULES AssignmentCandidateReason = "REVIEWER_RULES"
+)
+
+// Defines values for DependencyReportStatus.
+const (
+	DependencyReportStatusFail DependencyReportStatus = "fail"
+	DependencyReportStatusOk   DependencyReportStatus = "ok"
+)
+
+// Defines values for ErrorCode.
+const (
+	ErrorCodeIdempotencyKeyInProgress ErrorCode = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	ErrorCodeIdempotencyKeyReused     ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeInternalError            ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeInvalidFile              ErrorCode = "INVALID_FILE"
+	ErrorCodeInvalidJSON              ErrorCode = "INVALID_JSON"
+	ErrorCodeNoCandidate              ErrorCode = "NO_CANDIDATE"
+	ErrorCodeNotAssigned              ErrorCode = "NOT_ASSIGNED"
+	ErrorCodeNotFound                 ErrorCode = "NOT_FOUND"
+	ErrorCodePRClosed                 ErrorCode = "PR_CLOSED"
+	ErrorCodePRExists                 ErrorCode = "PR_EXISTS"
+	ErrorCodePRMerged                 ErrorCode = "PR_MERGED"
+	ErrorCodeRateLimited              ErrorCode = "RATE_LIMITED"
+	ErrorCodeReviewerRuleUnmet        ErrorCode = "REVIEWER_RULE_UNMET"
+	ErrorCodeTeamExists               ErrorCode = "TEAM_EXISTS"
+	ErrorCodeValidationError          ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeVersionMismatch          ErrorCode = "VERSION_MISMATCH"
+)
+
+// Defines values for ExcludedMemberReason.
+const (
+	ExcludedMemberReasonATCAPACITY  ExcludedMemberReason = "AT_CAPACITY"
+	ExcludedMemberReasonAUTHOR      ExcludedMemberReason = "AUTHOR"
+	ExcludedMemberReasonINACTIVE    ExcludedMemberReason = "INACTIVE"
+	ExcludedMemberReasonOUTOFOFFICE ExcludedMemberReason = "OUT_OF_OFFICE"
+)
+
+// Defines values for FieldViolationRule.
+const (
+	FieldViolationRuleAfterStartsAt       FieldViolationRule = "after_starts_at"
+	FieldViolationRuleBoolean             FieldViolationRule = "boolean"
+	FieldViolationRuleClock               FieldViolationRule = "clock"
+	FieldViolationRuleGt                  FieldViolationRule = "gt"
+	FieldViolationRuleID                  FieldViolationRule = "id"
+	FieldViolationRuleMax                 FieldViolationRule = "max"
+	FieldViolationRuleMin                 FieldViolationRule = "min"
+	FieldViolationRuleOneof               FieldViolationRule = "oneof"
+	FieldViolationRulePrintable           FieldViolationRule = "printable"
+	FieldViolationRuleRequired            FieldViolationRule = "required"
+	FieldViolationRuleRequiredForPartTime FieldViolationRule = "required_for_part_time"
+	FieldViolationRuleTag                 FieldViolationRule = "tag"
+	FieldViolationRuleTimestamp           FieldViolationRule = "timestamp"
+	FieldViolationRuleTimezone            FieldViolationRule = "timezone"
+	FieldViolationRuleUnique              FieldViolationRule = "unique"
+	FieldViolationRuleUniqueMember        FieldViolationRule = "unique_member"
+)
+
+// Defines values for HealthReportStatus.
+const (
+	HealthReportStatusFail         HealthReportStatus = "fail"
+	HealthReportStatusOk           HealthReportStatus = "ok"
+	HealthReportStatusShuttingDown HealthReportStatus = "shutting_down"
+)
+
+// Defines values for ImportUserUpdateFields.
+const (
+	ImportUserUpdateFieldsIsActive ImportUserUpdateFields = "is_active"
+	ImportUserUpdateFieldsUsername ImportUserUpdateFields = "username"
+)
+
+// Defines values for Level.
+const (
+	LevelJUNIOR Level = "JUNIOR"
+	LevelLEAD   Level = "LEAD"
+	LevelMIDDLE Level = "MIDDLE"
+	LevelSENIOR Level = "SENIOR"
+)
+
+// Defines values for PairingStrategy.
+const (
+	PairingStrategyPAIRINGDIVERSITY PairingStrategy = "PAIRING_DIVERSITY"
+	PairingStrategyRANDOM           PairingStrategy = "RANDOM"
+)
+
+// Defines values for PullRequestStatus.
+const (
+	PullRequestStatusClosed PullRequestStatus = "CLOSED"
+	PullRequestStatusMerged PullRequestStatus = "MERGED"
+	PullRequestStatusOpen   PullRequestStatus = "OPEN"
+)
+
+// Defines values for Weekday.
+const (
+	WeekdayFriday    Weekday = "FRI"
+	WeekdayMonday    Weekday = "MON"
+	WeekdaySaturday  Weekday = "SAT"
+	WeekdaySunday    Weekday = "SUN"
+	WeekdayThursday  Weekday = "THU"
+	WeekdayTuesday   Weekday = "TUE"
+	WeekdayWednesday Weekday = "WED"
+)
+
+// Absence defines model for Absence.
+type Absence struct {
+	AbsenceID int64 `json:"absence_id"`
+
+	// EndsAt Граница не включается
+	EndsAt time.Time `json:"ends_at"`
+
+	// Kind Вид отсутствия. На назначение ревьюеров все виды влияют одинаково
+	Kind     AbsenceKind `json:"kind"`
+	StartsAt time.Time   `json:"starts_at"`
+
+	// UID UID события календаря, из которого импортирован период
+	UID    *string `json:"uid,omitempty"`
+	UserID string  `json:"user_id"`
+
+	// Weekdays Нет поля — период действует все дни
+	Weekdays *[]Weekday `json:"weekdays,omitempty"`
+}
+
+// AbsenceKind Вид отсутствия. На назначение ревьюеров все виды влияют одинаково
+type AbsenceKind string
+
+// AddAbsenceRequest defines model for AddAbsenceRequest.
+type AddAbsenceRequest struct {
+	// EndsAt RFC 3339 (не включается) или дата YYYY-MM-DD (день включается целиком)
+	EndsAt string `json:"ends_at"`
+
+	// Kind Вид отсутствия. На назначение ревьюеров все виды влияют одинаково
+	Kind AbsenceKind `json:"kind"`
+
+	// StartsAt RFC 3339 или дата YYYY-MM-DD (начало дня в UTC)
+	StartsAt string `json:"starts_at"`
+
+	// Weekdays Дни недели (в часовом поясе рабочего расписания пользователя, без расписания — UTC), в которые действует период. Обязательно для PART_TIME
+	Weekdays *[]Weekday `json:"weekdays,omitempty"`
+}
+
+// Assignment defines model for Assignment.
+type Assignment struct {
+	AssignedAt   time.Time `json:"assigned_at"`
+	AssignmentID int64     `json:"assignment_id"`
+
+	// Candidates Все кандидаты в момент выбора по rank. Автор, неактивные и отсутствующие в кандидаты не попадают
+	Candidates []AssignmentCandidate `json:"candidates"`
+
+	// Kind CREATE — выбор при создании PR, REASSIGN — при замене ревьюера
+	Kind AssignmentKind `json:"kind"`
+
+	// ReplacedUserID Кого заменили, только у REASSIGN
+	ReplacedUserID *string  `json:"replaced_user_id,omitempty"`
+	Reviewers      []string `json:"reviewers"`
+
+	// Seed Seed генератора выбора, int64 строкой. С тем же seed и теми же кандидатами выбор повторяется
+	Seed string `json:"seed"`
+
+	// Strategy RANDOM — случайно среди равных по правилам, тегам и рабочему времени.
+	// PAIRING_DIVERSITY — среди них сначала те, кто реже ревьюил автора PR за окно decay_days
+	Strategy PairingStrategy `json:"strategy"`
+}
+
+// AssignmentKind CREATE — выбор при создании PR, REASSIGN — при замене ревьюера
+type AssignmentKind string
+
+// AssignmentCandidate defines model for AssignmentCandidate.
+type AssignmentCandidate struct {
+	Chosen bool `json:"chosen"`
+
+	// Level Уровень пользователя, без уровня поле не передается: такой ревьюер не засчитывается в правила команды
+	Level *Level `json:"level,omitempty"`
+
+	// PairingScore Вес недавних ревью у автора, 0 при стратегии RANDOM
+	PairingScore float64 `json:"pairing_score"`
+
+	// Rank Место в порядке выбора, с 1. Сначала у кого рабочее время, внутри — по pairing_score, равные — в порядке seed
+	Rank int `json:"rank"`
+
+	// Reason REVIEWER_RULES — выбран, чтобы выполнить правила команды к уровням; REQUIRED_TAGS — чтобы покрыть теги PR;
+	// RANK — по месту в порядке; OUTRANKED — не выбран, места заняли кандидаты выше или нужные правилам и тегам
+	Reason AssignmentCandidateReason `json:"reason"`
+
+	// Tags Теги PR, которые кандидат покрывает
+	Tags   *[]string `json:"tags,omitempty"`
+	UserID string    `json:"user_id"`
+
+	// WorkingHours У кандидата было рабочее время в момент выбора
+	WorkingHours bool `json:"working_hours"`
+}
+
+// AssignmentCandidateReason REVIEWER_RULES — выбран, чтобы выполнить правила команды к уровням; REQUIRED_TAGS — чтобы покрыть теги PR;
+// RANK — по месту в порядке; OUTRANKED — не выбран, места заняли кандидаты выше или нужные правилам и тегам
+type AssignmentCandidateReason string
+
+// CalendarImportReport Отчет импорта календаря. Повторная загрузка того же файла дает только unchanged
+type CalendarImportReport struct {
+	Created int `json:"created"`
+
+	// Deleted Отмененные события (STATUS:CANCELLED)
+	Deleted int `json:"deleted"`
+
+	// Ignored События без признака OOO
+	Ignored int `json:"ignored"`
+	Skipped []struct {
+		Reason string `json:"reason"`
+		UID    string `json:"uid"`
+	} `json:"skipped"`
+	Unchanged int `json:"unchanged"`
+	Updated   int `json:"updated"`
+}
+
+// CreatePullRequestRequest defines model for CreatePullRequestRequest.
+type CreatePullRequestRequest struct {
+	AuthorID        string `json:"author_id"`
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+
+	// RequiredTags Каждый тег получает хотя бы одного ревьюера с ним, если такой есть в команде
+	RequiredTags *[]string `json:"required_tags,omitempty"`
+}
+
+// DependencyReport defines model for DependencyReport.
+type DependencyReport struct {
+	Details    *map[string]interface{} `json:"details,omitempty"`
+	DurationMs int                     `json:"duration_ms"`
+	Error      *string                 `json:"error,omitempty"`
+	Status     DependencyReportStatus  `json:"status"`
+}
+
+// DependencyReportStatus defines model for DependencyReport.Status.
+type DependencyReportStatus string
+
+// ErrorCode Машиночитаемый код ошибки, в ErrorResponse.error.code, Problem.code и extensions.code GraphQL
+type ErrorCode string
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error struct {
+		// Code Машиночитаемый код ошибки, в ErrorResponse.error.code, Problem.code и extensions.code GraphQL
+		Code    ErrorCode `json:"code"`
+		Message string    `json:"message"`
+	} `json:"error"`
+}
+
+// ExcludedMember defines model for ExcludedMember.
+type ExcludedMember struct {
+	// Reason AUTHOR — автор PR, INACTIVE — is_active=false, AT_CAPACITY — открытых ревью не меньше ASSIGNMENT_MAX_OPEN_REVIEWS, OUT_OF_OFFICE — сейчас период отсутствия
+	Reason ExcludedMemberReason `json:"reason"`
+	UserID string               `json:"user_id"`
+}
+
+// ExcludedMemberReason AUTHOR — автор PR, INACTIVE — is_active=false, AT_CAPACITY — открытых ревью не меньше ASSIGNMENT_MAX_OPEN_REVIEWS, OUT_OF_OFFICE — сейчас период отсутствия
+type ExcludedMemberReason string
+
+// FieldViolation defines model for FieldViolation.
+type FieldViolation struct {
+	// Field Путь до поля в теле запроса (например, members[1].user_id)
+	Field   string             `json:"field"`
+	Message string             `json:"message"`
+	Rule    FieldViolationRule `json:"rule"`
+}
+
+// FieldViolationRule defines model for FieldViolation.Rule.
+type FieldViolationRule string
+
+// GraphQLError defines model for GraphQLError.
+type GraphQLError struct {
+	Extensions *struct {
+		// Code Машиночитаемый код ошибки, в ErrorResponse.error.code, Problem.code и extensions.code GraphQL
+		Code   *ErrorCode        `json:"code,omitempty"`
+		Errors *[]FieldViolation `json:"errors,omitempty"`
+	} `json:"extensions,omitempty"`
+	Locations *[]struct {
+		Column *int `json:"column,omitempty"`
+		Line   *int `json:"line,omitempty"`
+	} `json:"locations,omitempty"`
+	Message string         `json:"message"`
+	Path    *[]interface{} `json:"path,omitempty"`
+}
+
+// GraphQLRequest defines model for GraphQLRequest.
+type GraphQLRequest struct {
+	OperationName *string                 `json:"operationName,omitempty"`
+	Query         string                  `json:"query"`
+	Variables     *map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse defines model for GraphQLResponse.
+type GraphQLResponse struct {
+	Data   *map[string]interface{} `json:"data"`
+	Errors *[]GraphQLError         `json:"errors,omitempty"`
+}
+
+// HealthReport defines model for HealthReport.
+type HealthReport struct {
+	Checks *map[string]DependencyReport `json:"checks,omitempty"`
+	Status HealthReportStatus           `json:"status"`
+}
+
+// HealthReportStatus defines model for HealthReport.Status.
+type HealthReportStatus string
+
+// ImportReport Отчет импорта (при dry_run — о том, что изменилось бы)
+type ImportReport struct {
+	DryRun         bool               `json:"dry_run"`
+	TeamsCreated   []string           `json:"teams_created"`
+	UsersCreated   []string           `json:"users_created"`
+	UsersMoved     []ImportUserMove   `json:"users_moved"`
+	UsersUnchanged int                `json:"users_unchanged"`
+	UsersUpdated   []ImportUserUpdate `json:"users_updated"`
+}
+
+// ImportRequest defines model for ImportRequest.
+type ImportRequest struct {
+	Teams []Team `json:"teams"`
+}
+
+// ImportUserMove defines model for ImportUserMove.
+type ImportUserMove struct {
+	FromTeam string `json:"from_team"`
+	ToTeam   string `json:"to_team"`
+	UserID   string `json:"user_id"`
+}
+
+// ImportUserUpdate defines model for ImportUserUpdate.
+type ImportUserUpdate struct {
+	Fields []ImportUserUpdateFields `json:"fields"`
+	UserID string                   `json:"user_id"`
+}
+
+// ImportUserUpdateFields defines model for ImportUserUpdate.Fields.
+type ImportUserUpdateFields string
+
+// Level Уровень пользователя, без уровня поле не передается: такой ревьюер не засчитывается в правила команды
+type Level string
+
+// PairingCell defines model for PairingCell.
+type PairingCell struct {
+	AuthorID string `json:"author_id"`
+
+	// Count Сколько PR автора ревьюер ревьюил за окно
+	Count      int    `json:"count"`
+	ReviewerID string `json:"reviewer_id"`
+
+	// Score Те же ревью с затуханием, свежее — 1, к концу окна — 0. По нему штрафует PAIRING_DIVERSITY
+	Score float64 `json:"score"`
+}
+
+// PairingMatrix defines model for PairingMatrix.
+type PairingMatrix struct {
+	DecayDays int `json:"decay_days"`
+
+	// Pairs Непустые ячейки по авторам команды, сначала самые частые пары
+	Pairs []PairingCell `json:"pairs"`
+
+	// Since Начало окна, учитываются PR, созданные с этого момента
+	Since time.Time `json:"since"`
+
+	// Strategy RANDOM — случайно среди равных по правилам, тегам и рабочему времени.
+	// PAIRING_DIVERSITY — среди них сначала те, кто реже ревьюил автора PR за окно decay_days
+	Strategy PairingStrategy `json:"strategy"`
+	TeamName string          `json:"team_name"`
+}
+
+// PairingSettings defines model for PairingSettings.
+type PairingSettings struct {
+	DecayDays int `json:"decay_days"`
+
+	// Strategy RANDOM — случайно среди равных по правилам, тегам и рабочему времени.
+	// PAIRING_DIVERSITY — среди них сначала те, кто реже ревьюил автора PR за окно decay_days
+	Strategy PairingStrategy `json:"strategy"`
+	TeamName string          `json:"team_name"`
+}
+
+// PairingSettingsRequest defines model for PairingSettingsRequest.
+type PairingSettingsRequest struct {
+	// DecayDays За сколько дней ревью автора перестает учитываться, по умолчанию 30. Вес ревью убывает линейно
+	DecayDays *int `json:"decay_days,omitempty"`
+
+	// Strategy RANDOM — случайно среди равных по правилам, тегам и рабочему времени.
+	// PAIRING_DIVERSITY — среди них сначала те, кто реже ревьюил автора PR за окно decay_days
+	Strategy PairingStrategy `json:"strategy"`
+}
+
+// PairingStrategy RANDOM — случайно среди равных по правилам, тегам и рабочему времени.
+// PAIRING_DIVERSITY — среди них сначала те, кто реже ревьюил автора PR за окно decay_days
+type PairingStrategy string
+
+// Pong defines model for Pong.
+type Pong struct {
+	Message string `json:"message"`
+}
+
+// PreviewReviewersRequest defines model for PreviewReviewersRequest.
+type PreviewReviewersRequest struct {
+	AuthorID string `json:"author_id"`
+
+	// RequiredTags Как при создании PR
+	RequiredTags *[]string `json:"required_tags,omitempty"`
+}
+
+// Problem Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type Problem struct {
+	// Code Машиночитаемый код ошибки, в ErrorResponse.error.code, Problem.code и extensions.code GraphQL
+	Code     ErrorCode         `json:"code"`
+	Detail   *string           `json:"detail,omitempty"`
+	Errors   *[]FieldViolation `json:"errors,omitempty"`
+	Instance *string           `json:"instance,omitempty"`
+	Status   int               `json:"status"`
+	Title    string            `json:"title"`
+	Type     string            `json:"type"`
+}
+
+// PullRequest defines model for PullRequest.
+type PullRequest struct {
+	// AssignedReviewers user_id назначенных ревьюверов (0..2)
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	AuthorID          string     `json:"author_id"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	MergedAt          *time.Time `json:"mergedAt"`
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+
+	// RequiredTags Теги, которые ревьюеры покрывают, если в команде они есть у кого-то
+	RequiredTags *[]string `json:"required_tags,omitempty"`
+
+	// Status CLOSED — PR закрыт без merge, ревьюеры остаются, но ревью больше не ждут
+	Status PullRequestStatus `json:"status"`
+
+	// Version Версия PR, растет на каждом merge, close и reassign. Совпадает с ETag
+	Version int64 `json:"version"`
+}
+
+// PullRequestAssignments defines model for PullRequestAssignments.
+type PullRequestAssignments struct {
+	// Assignments Выборы ревьюеров в порядке записи
+	Assignments   []Assignment `json:"assignments"`
+	PullRequestID string       `json:"pull_request_id"`
+}
+
+// PullRequestEvent data события SSE. Для pr.reassigned заполнены только id PR, old_user_id, replaced_by и версия
+type PullRequestEvent struct {
+	AssignedReviewers *[]string  `json:"assigned_reviewers,omitempty"`
+	AuthorID          *string    `json:"author_id,omitempty"`
+	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	OccurredAt        time.Time  `json:"occurred_at"`
+	OldUserID         *string    `json:"old_user_id,omitempty"`
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   *string    `json:"pull_request_name,omitempty"`
+	ReplacedBy        *string    `json:"replaced_by,omitempty"`
+
+	// Status CLOSED — PR закрыт без merge, ревьюеры остаются, но ревью больше не ждут
+	Status  *PullRequestStatus `json:"status,omitempty"`
+	Version int64              `json:"version"`
+}
+
+// PullRequestExportRow Строка NDJSON-выгрузки /export/pullRequests
+type PullRequestExportRow struct {
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	AuthorID          string     `json:"author_id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+
+	// Status CLOSED — PR закрыт без merge, ревьюеры остаются, но ревью больше не ждут
+	Status PullRequestStatus `json:"status"`
+
+	// TeamName Команда автора
+	TeamName string `json:"team_name"`
+	Version  int64  `json:"version"`
+}
+
+// PullRequestResult defines model for PullRequestResult.
+type PullRequestResult struct {
+	PullRequest PullRequest `json:"pr"`
+}
+
+// PullRequestShort defines model for PullRequestShort.
+type PullRequestShort struct {
+	AuthorID        string `json:"author_id"`
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+
+	// SLA Таймер ревью открытого PR в рабочем времени ревьюера (SLA_REVIEW с назначения ревьюера на PR, при замене — с замены).
+	// Ночи, выходные и нерабочие дни по его расписанию не считаются
+	SLA *ReviewSLA `json:"sla,omitempty"`
+
+	// Status CLOSED — PR закрыт без merge, ревьюеры остаются, но ревью больше не ждут
+	Status PullRequestStatus `json:"status"`
+}
+
+// PullRequestStatus CLOSED — PR закрыт без merge, ревьюеры остаются, но ревью больше не ждут
+type PullRequestStatus string
+
+// ReassignRequest defines model for ReassignRequest.
+type ReassignRequest struct {
+	OldUserID string `json:"old_user_id"`
+}
+
+// ReassignResult Новая версия PR приходит в ETag
+type ReassignResult struct {
+	// ReplacedBy user_id нового ревьювера
+	ReplacedBy string `json:"replaced_by"`
+}
+
+// ReviewExportRow Строка NDJSON-выгрузки /export/reviews
+type ReviewExportRow struct {
+	AuthorID        string     `json:"author_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	ReviewerID      string     `json:"reviewer_id"`
+
+	// Status CLOSED — PR закрыт без merge, ревьюеры остаются, но ревью больше не ждут
+	Status   PullRequestStatus `json:"status"`
+	TeamName string            `json:"team_name"`
+}
+
+// ReviewSLA Таймер ревью открытого PR в рабочем времени ревьюера (SLA_REVIEW с назначения ревьюера на PR, при замене — с замены).
+// Ночи, выходные и нерабочие дни по его расписанию не считаются
+type ReviewSLA struct {
+	Breached bool      `json:"breached"`
+	DueAt    time.Time `json:"due_at"`
+
+	// ElapsedSeconds Сколько рабочего времени прошло
+	ElapsedSeconds int64 `json:"elapsed_seconds"`
+}
+
+// ReviewerRule Среди ревьюеров PR не меньше count с уровнем min_level или выше
+type ReviewerRule struct {
+	Count int `json:"count"`
+
+	// MinLevel Уровень пользователя, без уровня поле не передается: такой ревьюер не засчитывается в правила команды
+	MinLevel Level `json:"min_level"`
+}
+
+// ReviewerRules defines model for ReviewerRules.
+type ReviewerRules struct {
+	// Rules От старшего уровня к младшему
+	Rules    []ReviewerRule `json:"rules"`
+	TeamName string         `json:"team_name"`
+}
+
+// ReviewerRulesRequest defines model for ReviewerRulesRequest.
+type ReviewerRulesRequest struct {
+	// Rules Новый набор правил, пустой снимает правила. Уровни не повторяются
+	Rules []ReviewerRule `json:"rules"`
+}
+
+// ReviewersPreview defines model for ReviewersPreview.
+type ReviewersPreview struct {
+	AuthorID string `json:"author_id"`
+
+	// Candidates Все кандидаты по rank, как в объяснении выбора
+	Candidates []AssignmentCandidate `json:"candidates"`
+
+	// Excluded Остальные участники команды автора с причиной, в порядке user_id
+	Excluded []ExcludedMember `json:"excluded"`
+
+	// RequiredTags Теги после нормализации
+	RequiredTags *[]string `json:"required_tags,omitempty"`
+	Reviewers    []string  `json:"reviewers"`
+
+	// Seed Seed генератора выбора, int64 строкой
+	Seed string `json:"seed"`
+
+	// Strategy RANDOM — случайно среди равных по правилам, тегам и рабочему времени.
+	// PAIRING_DIVERSITY — среди них сначала те, кто реже ревьюил автора PR за окно decay_days
+	Strategy PairingStrategy `json:"strategy"`
+
+	// UnmetRules Правила команды, которые выбранные не выполняют. Непустой — создание PR сейчас вернуло бы 409 REVIEWER_RULE_UNMET
+	UnmetRules []ReviewerRule `json:"unmet_rules"`
+}
+
+// SetLevelRequest defines model for SetLevelRequest.
+type SetLevelRequest struct {
+	// Level Уровень пользователя, без уровня поле не передается: такой ревьюер не засчитывается в правила команды
+	Level Level `json:"level"`
+}
+
+// SetSkillsRequest defines model for SetSkillsRequest.
+type SetSkillsRequest struct {
+	// Skills Новый набор тегов, пустой очищает
+	Skills []string `json:"skills"`
+}
+
+// Skills Теги экспертизы по возрастанию, без тегов поле не передается
+type Skills = []string
+
+// Team defines model for Team.
+type Team struct {
+	Members  []TeamMember `json:"members"`
+	TeamName string       `json:"team_name"`
+}
+
+// TeamMember defines model for TeamMember.
+type TeamMember struct {
+	IsActive bool `json:"is_active"`
+
+	// Level Уровень пользователя, без уровня поле не передается: такой ревьюер не засчитывается в правила команды
+	Level *Level `json:"level,omitempty"`
+
+	// Skills Теги экспертизы по возрастанию, без тегов поле не передается
+	Skills   *Skills `json:"skills,omitempty"`
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+}
+
+// TeamStats defines model for TeamStats.
+type TeamStats struct {
+	ActiveUsers   int    `json:"active_users"`
+	InactiveUsers int    `json:"inactive_users"`
+	MergedPRs     int    `json:"merged_prs"`
+	OpenPRs       int    `json:"open_prs"`
+	TeamName      string `json:"team_name"`
+}
+
+// UpdateSkillsRequest defines model for UpdateSkillsRequest.
+type UpdateSkillsRequest struct {
+	Add *[]string `json:"add,omitempty"`
+
+	// Remove Убирается и тег, который есть в add
+	Remove *[]string `json:"remove,omitempty"`
+}
+
+// UpdateUserRequest defines model for UpdateUserRequest.
+type UpdateUserRequest struct {
+	IsActive bool `json:"is_active"`
+}
+
+// User defines model for User.
+type User struct {
+	IsActive bool `json:"is_active"`
+
+	// Level Уровень пользователя, без уровня поле не передается: такой ревьюер не засчитывается в правила команды
+	Level *Level `json:"level,omitempty"`
+
+	// Skills Теги экспертизы по возрастанию, без тегов поле не передается
+	Skills   *Skills `json:"skills,omitempty"`
+	TeamName string  `json:"team_name"`
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+}
+
+// UserAbsences defines model for UserAbsences.
+type UserAbsences struct {
+	Absences []Absence `json:"absences"`
+	UserID   string    `json:"user_id"`
+}
+
+// UserResult defines model for UserResult.
+type UserResult struct {
+	User User `json:"user"`
+}
+
+// UserReviews defines model for UserReviews.
+type UserReviews struct {
+	PullRequests []PullRequestShort `json:"pull_requests"`
+	UserID       string             `json:"user_id"`
+}
+
+// Weekday defines model for Weekday.
+type Weekday string
+
+// WorkingHours defines model for WorkingHours.
+type WorkingHours struct {
+	End string `json:"end"`
+
+	// IsDefault Расписание не задано, действует 09:00–18:00 UTC по будням
+	IsDefault bool      `json:"is_default"`
+	Start     string    `json:"start"`
+	Timezone  string    `json:"timezone"`
+	UserID    string    `json:"user_id"`
+	Weekdays  []Weekday `json:"weekdays"`
+}
+
+// WorkingHoursRequest defines model for WorkingHoursRequest.
+type WorkingHoursRequest struct {
+	// End Конец HH:MM. Не позже start — смена через полночь
+	End string `json:"end"`
+
+	// Start Начало рабочего дня HH:MM
+	Start string `json:"start"`
+
+	// Timezone Пояс IANA, например Europe/Moscow
+	Timezone string `json:"timezone"`
+
+	// Weekdays Дни начала смены
+	Weekdays []Weekday `json:"weekdays"`
+}
+
+// AbsenceIDPath defines model for AbsenceIdPath.
+type AbsenceIDPath = int64
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// PullRequestIDPath defines model for PullRequestIdPath.
+type PullRequestIDPath = string
+
+// PullRequestIDQuery defines model for PullRequestIdQuery.
+type PullRequestIDQuery = string
+
+// TeamNamePath defines model for TeamNamePath.
+type TeamNamePath = string
+
+// TeamNameQuery defines model for TeamNameQuery.
+type TeamNameQuery = string
+
+// UserIDPath defines model for UserIdPath.
+type UserIDPath = string
+
+// UserIDQuery defines model for UserIdQuery.
+type UserIDQuery = string
+
+// BadRequestApplicationJSON defines model for BadRequest.
+type BadRequestApplicationJSON = ErrorResponse
+
+// BadRequestApplicationProblemPlusJSON Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type BadRequestApplicationProblemPlusJSON = Problem
+
+// IdempotencyKeyInProgressApplicationJSON defines model for IdempotencyKeyInProgress.
+type IdempotencyKeyInProgressApplicationJSON = ErrorResponse
+
+// IdempotencyKeyInProgressApplicationProblemPlusJSON Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type IdempotencyKeyInProgressApplicationProblemPlusJSON = Problem
+
+// IdempotencyKeyReusedApplicationJSON defines model for IdempotencyKeyReused.
+type IdempotencyKeyReusedApplicationJSON = ErrorResponse
+
+// IdempotencyKeyReusedApplicationProblemPlusJSON Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type IdempotencyKeyReusedApplicationProblemPlusJSON = Problem
+
+// ReassignLimitedApplicationJSON defines model for ReassignLimited.
+type ReassignLimitedApplicationJSON = ErrorResponse
+
+// ReassignLimitedApplicationProblemPlusJSON Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type ReassignLimitedApplicationProblemPlusJSON = Problem
+
+// TooManyRequestsApplicationJSON defines model for TooManyRequests.
+type TooManyRequestsApplicationJSON = ErrorResponse
+
+// TooManyRequestsApplicationProblemPlusJSON Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type TooManyRequestsApplicationProblemPlusJSON = Problem
+
+// VersionMismatchApplicationJSON defines model for VersionMismatch.
+type VersionMismatchApplicationJSON = ErrorResponse
+
+// VersionMismatchApplicationProblemPlusJSON Ошибка в формате RFC 7807 (application/problem+json). Отдается, только если клиент явно
+// просит Accept: application/problem+json. По умолчанию (без Accept, */*, application/json)
+// отдается прежний формат ErrorResponse.
+type VersionMismatchApplicationProblemPlusJSON = Problem
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy повторы запроса. По умолчанию MaxAttempts = 1, то есть без повторов.
+//
+// Повторяются ошибки сети, 429, 502, 503, 504 и 409 IDEMPOTENCY_KEY_IN_PROGRESS. POST при включенных
+// повторах всегда уходит с Idempotency-Key (сгенерированным, если не задан WithIdempotencyKey),
+// поэтому повтор не создаст PR дважды. Retry-After сервера важнее своей паузы
+type RetryPolicy struct {
+	// MaxAttempts всего попыток, включая первую
+	MaxAttempts int
+	// MinBackoff пауза перед первым повтором, дальше удваивается до MaxBackoff (с разбросом до половины)
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRetryAfter если сервер просит ждать дольше (лимит замен PR — до часа), ошибка возвращается сразу.
+	// 0 — ждать сколько попросят
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy разумные значения для WithRetry
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   3,
+	MinBackoff:    100 * time.Millisecond,
+	MaxBackoff:    2 * time.Second,
+	MaxRetryAfter: 30 * time.Second,
+}
+
+func (p RetryPolicy) enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// backoff пауза перед повтором номер attempt (с 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable стоит ли повторять попытку, закончившуюся resp или ошибкой транспорта (resp == nil).
+// Отмену контекста вызова проверяет сам цикл повторов
+func retryable(resp *http.Response, apiErr *Error) bool {
+	if resp == nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return apiErr != nil && apiErr.Code == ErrorCodeIdempotencyKeyInProgress
+	}
+	return false
+}
+
+// retryAfter значение Retry-After: секунды или HTTP-дата, 0 если заголовка нет
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// sleep пауза, прерываемая контекстом
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newIdempotencyKey случайный ключ для повторов POST
+func newIdempotencyKey() string {
+	var b [16]byte
+	_, _ = cryptorand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ExportFilter фильтры выгрузок. Пустые поля не ограничивают
+type ExportFilter struct {
+	// Team команда автора PR
+	Team string
+	// From created_at не раньше, To — раньше (не включается)
+	From, To time.Time
+}
+
+func (f ExportFilter) query() url.Values {
+	q := url.Values{"format": {"ndjson"}}
+	if f.Team != "" {
+		q.Set("team", f.Team)
+	}
+	if !f.From.IsZero() {
+		q.Set("from", f.From.UTC().Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		q.Set("to", f.To.UTC().Format(time.RFC3339))
+	}
+	return q
+}
+
+// ExportPullRequests читает выгрузку /export/pullRequests построчно и отдает каждую строку в fn.
+// Ошибка fn останавливает чтение и возвращается как есть
+func (c *Client) ExportPullRequests(ctx context.Context, filter ExportFilter, fn func(PullRequestExportRow) error) error {
+	return exportRows(c, ctx, "/export/pullRequests?"+filter.query().Encode(), fn)
+}
+
+// ExportReviews выгрузка /export/reviews: строка на каждую пару PR — ревьюер
+func (c *Client) ExportReviews(ctx context.Context, filter ExportFilter, fn func(ReviewExportRow) error) error {
+	return exportRows(c, ctx, "/export/reviews?"+filter.query().Encode(), fn)
+}
+
+// exportRows NDJSON без таймаута вызова: большая выгрузка идет дольше обычного запроса.
+// Оборванная сервером выгрузка дает ошибку чтения, а не молча неполный результат
+func exportRows[T any](c *Client, ctx context.Context, path string, fn func(T) error) error {
+	return c.send(ctx, http.MethodGet, path, nil, 0, nil, func(resp *http.Response) error {
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		if c.strict {
+			dec.DisallowUnknownFields()
+		}
+		for {
+			var row T
+			if err := dec.Decode(&row); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("client: read export %s: %w", path, err)
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// EventsFilter фильтры /events/stream. LastEventID — продолжить после этого события
+type EventsFilter struct {
+	UserID      string
+	Team        string
+	LastEventID string
+}
+
+// Event одно событие SSE: pr.created, pr.merged или pr.reassigned
+type Event struct {
+	ID   string
+	Type string
+	Data PullRequestEvent
+}
+
+// EventStream открытый поток событий. Закрывается Close или отменой контекста Events
+type EventStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	strict bool
+	// LastEventID id последнего прочитанного события, для переподключения
+	LastEventID string
+}
+
+// Events подписка на события PR. Таймаут вызова не действует, поток живет до Close или отмены ctx
+func (c *Client) Events(ctx context.Context, filter EventsFilter) (*EventStream, error) {
+	q := url.Values{}
+	if filter.UserID != "" {
+		q.Set("user_id", filter.UserID)
+	}
+	if filter.Team != "" {
+		q.Set("team", filter.Team)
+	}
+	path := "/events/stream"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var opts []RequestOption
+	if filter.LastEventID != "" {
+		opts = append(opts, func(h http.Header) { h.Set("Last-Event-ID", filter.LastEventID) })
+	}
+
+	var stream *EventStream
+	err := c.send(ctx, http.MethodGet, path, nil, 0, opts, func(resp *http.Response) error {
+		stream = &EventStream{body: resp.Body, reader: bufio.NewReader(resp.Body), strict: c.strict, LastEventID: filter.LastEventID}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Next блокируется до следующего события. io.EOF — сервер закрыл поток (например, при остановке),
+// переподключаться стоит с LastEventID
+func (s *EventStream) Next() (Event, error) {
+	var e Event
+	var data strings.Builder
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" {
+				return Event{}, io.EOF
+			}
+			if err != io.EOF {
+				return Event{}, fmt.Errorf("client: read events: %w", err)
+			}
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if data.Len() == 0 {
+				// пустое событие или конец комментария ": ping"
+				if err == io.EOF {
+					return Event{}, io.EOF
+				}
+				continue
+			}
+			dec := json.NewDecoder(strings.NewReader(data.String()))
+			if s.strict {
+				dec.DisallowUnknownFields()
+			}
+			if err := dec.Decode(&e.Data); err != nil {
+				return Event{}, fmt.Errorf("client: decode event %s: %w", e.ID, err)
+			}
+			if e.ID != "" {
+				s.LastEventID = e.ID
+			}
+			return e, nil
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			e.ID = value
+		case "event":
+			e.Type = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+		// "" — комментарий, retry и прочие поля клиенту не нужны
+	}
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
@@ -2,49 +2,133 @@ package error_wrapper
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
 	"service-order-avito/internal/domain/dto"
 	"service-order-avito/internal/domain/errors/server"
 	"service-order-avito/internal/domain/errors/service"
 	"service-order-avito/internal/http/codes"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const ContentTypeProblemJSON = "application/problem+json"
+
 type errorMeta struct {
 	Code    string
 	Message string
 	Status  int
 }
 
-var serviceErrorMap = map[error]errorMeta{
-	service.ErrInternalError:          {codes.INTERNAL_ERROR, server.ErrInternalError, http.StatusInternalServerError},
-	service.ErrTeamAlreadyExists:      {codes.TEAM_EXISTS, server.ErrTeamAlreadyExists, http.StatusBadRequest},
-	service.ErrTeamNotFound:           {codes.NOT_FOUND, server.ErrTeamNotFound, http.StatusNotFound},
-	service.ErrUserNotFound:           {codes.NOT_FOUND, server.ErrUserNotFound, http.StatusNotFound},
-	service.ErrPullRequestExists:      {codes.PR_EXISTS, server.ErrPRAlreadyExists, http.StatusConflict},
-	service.ErrPullRequestNotFound:    {codes.NOT_FOUND, server.ErrPRNotFound, http.StatusNotFound},
-	service.ErrPullRequestMerged:      {codes.PR_MERGED, server.ErrPullRequestMerged, http.StatusBadRequest},
-	service.ErrReviewerNotAssigned:    {codes.NOT_ASSIGNED, server.ErrReviewerNotAssigned, http.StatusBadRequest},
-	service.ErrNoReplacementCandidate: {codes.NO_CANDIDATE, server.ErrNoReplacementCandidate, http.StatusBadRequest},
+var internalErrorMeta = errorMeta{codes.INTERNAL_ERROR, server.ErrInternalError, http.StatusInternalServerError}
+
+// порядок важен: ошибка сопоставляется с первым подходящим через errors.Is видом
+var serviceErrors = []struct {
+	kind error
+	meta errorMeta
+}{
+	{service.ErrTeamAlreadyExists, errorMeta{codes.TEAM_EXISTS, server.ErrTeamAlreadyExists, http.StatusBadRequest}},
+	{service.ErrTeamNotFound, errorMeta{codes.NOT_FOUND, server.ErrTeamNotFound, http.StatusNotFound}},
+	{service.ErrUserNotFound, errorMeta{codes.NOT_FOUND, server.ErrUserNotFound, http.StatusNotFound}},
+	{service.ErrAbsenceNotFound, errorMeta{codes.NOT_FOUND, server.ErrAbsenceNotFound, http.StatusNotFound}},
+	{service.ErrPullRequestExists, errorMeta{codes.PR_EXISTS, server.ErrPRAlreadyExists, http.StatusConflict}},
+	{service.ErrPullRequestNotFound, errorMeta{codes.NOT_FOUND, server.ErrPRNotFound, http.StatusNotFound}},
+	{service.ErrPullRequestMerged, errorMeta{codes.PR_MERGED, server.ErrPullRequestMerged, http.StatusBadRequest}},
+	{service.ErrPullRequestClosed, errorMeta{codes.PR_CLOSED, server.ErrPullRequestClosed, http.StatusConflict}},
+	{service.ErrReviewerNotAssigned, errorMeta{codes.NOT_ASSIGNED, server.ErrReviewerNotAssigned, http.StatusBadRequest}},
+	{service.ErrNoReplacementCandidate, errorMeta{codes.NO_CANDIDATE, server.ErrNoReplacementCandidate, http.StatusBadRequest}},
+	{service.ErrReviewerRulesUnmet, errorMeta{codes.REVIEWER_RULE_UNMET, server.ErrReviewerRulesUnmet, http.StatusConflict}},
+	{service.ErrVersionMismatch, errorMeta{codes.VERSION_MISMATCH, server.ErrVersionMismatch, http.StatusPreconditionFailed}},
+	{service.ErrReassignLimitExceeded, errorMeta{codes.RATE_LIMITED, server.ErrReassignLimitExceeded, http.StatusTooManyRequests}},
+	{service.ErrInternalError, internalErrorMeta},
 }
 
-// WriteServiceError принимает ошибку уровня service и пишет ошибку уровня контроллера в ResponseWriter
-func WriteServiceError(w http.ResponseWriter, err error) {
-	meta, ok := serviceErrorMap[err]
-	if !ok {
-		meta = serviceErrorMap[service.ErrInternalError]
+func lookupServiceError(err error) errorMeta {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.kind) {
+			return e.meta
+		}
 	}
+	return internalErrorMeta
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(meta.Status)
-	_ = json.NewEncoder(w).Encode(dto.ErrorResponse{
-		Error: dto.ErrorDetail{
-			Code:    meta.Code,
-			Message: meta.Message,
-		},
+// ServiceErrorCode код и сообщение для ошибки уровня service без записи ответа.
+// Нужен там, где ошибка уходит не статусом HTTP, а в теле (GraphQL). Для внутренних ошибок пишет цепочку в лог
+func ServiceErrorCode(err error) (code string, message string) {
+	meta := lookupServiceError(err)
+
+	if meta.Status >= http.StatusInternalServerError {
+		slog.Error("service error", slog.String("code", meta.Code), slog.String("error", err.Error()))
+	}
+
+	return meta.Code, meta.Message
+}
+
+// WriteServiceError принимает ошибку уровня service и пишет ошибку уровня контроллера в ResponseWriter.
+// Клиенту уходит только код и сообщение, полная цепочка ошибок пишется в лог для 5xx
+func WriteServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	meta := lookupServiceError(err)
+
+	if meta.Status >= http.StatusInternalServerError {
+		slog.Error("service error", slog.String("code", meta.Code), slog.String("error", err.Error()))
+	}
+
+	var svcErr *service.Error
+	if errors.As(err, &svcErr) && svcErr.RetryAfter > 0 {
+		SetRetryAfter(w, svcErr.RetryAfter)
+	}
+
+	WriteError(w, r, meta.Code, meta.Message, meta.Status)
+}
+
+// SetRetryAfter заголовок Retry-After в целых секундах, с округлением вверх и не меньше 1
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+}
+
+// WriteValidationError пишет 400 с нарушениями по полям. Ошибки не из dto.Validate считаются невалидным JSON
+func WriteValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var vErr *dto.ValidationError
+	if !errors.As(err, &vErr) {
+		WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+		return
+	}
+
+	if prefersLegacy(r) {
+		writeLegacy(w, codes.VALIDATION_ERROR, vErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeProblem(w, dto.ProblemDetails{
+		Type:     problemType(codes.VALIDATION_ERROR),
+		Title:    server.ErrValidation,
+		Status:   http.StatusBadRequest,
+		Instance: r.URL.Path,
+		Code:     codes.VALIDATION_ERROR,
+		Errors:   vErr.Violations,
 	})
 }
 
-func WriteError(w http.ResponseWriter, code string, message string, status int) {
+// WriteError пишет ошибку в формате, который выбрал клиент (см. prefersLegacy)
+func WriteError(w http.ResponseWriter, r *http.Request, code string, message string, status int) {
+	if prefersLegacy(r) {
+		writeLegacy(w, code, message, status)
+		return
+	}
+
+	writeProblem(w, dto.ProblemDetails{
+		Type:     problemType(code),
+		Title:    message,
+		Status:   status,
+		Instance: r.URL.Path,
+		Code:     code,
+	})
+}
+
+func writeLegacy(w http.ResponseWriter, code string, message string, status int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(dto.ErrorResponse{
@@ -54,3 +138,55 @@ func WriteError(w http.ResponseWriter, code string, message string, status int)
 		},
 	})
 }
+
+func writeProblem(w http.ResponseWriter, problem dto.ProblemDetails) {
+	w.Header().Set("Content-Type", ContentTypeProblemJSON)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func problemType(code string) string {
+	return "urn:pr-manager:problem:" + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// prefersLegacy решает, отдавать ли старый формат {error:{code,message}}.
+// Он по умолчанию: без Accept, на */* и на application/json. RFC 7807 отдается, только если
+// problem+json указан явно с q больше 0 и не меньше, чем у application/json
+func prefersLegacy(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+
+	problemQ, jsonQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := parseQuality(params)
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case ContentTypeProblemJSON:
+			problemQ = max(problemQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	if problemQ <= 0 {
+		return true
+	}
+	return jsonQ > problemQ
+}
+
+func parseQuality(params string) float64 {
+	for _, p := range strings.Split(params, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if !ok || strings.TrimSpace(k) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
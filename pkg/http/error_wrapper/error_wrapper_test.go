@@ -0,0 +1,320 @@
+package error_wrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-order-avito/internal/domain/dto"
+	"service-order-avito/internal/domain/errors/repository"
+	"service-order-avito/internal/domain/errors/server"
+	"service-order-avito/internal/domain/errors/service"
+	"service-order-avito/internal/http/codes"
+	serviceWrapper "service-order-avito/internal/service/error_wrapper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	pgErr := errors.New("pq: connection refused")
+
+	tests := []struct {
+		name            string
+		err             error
+		expectedCode    string
+		expectedMessage string
+		expectedStatus  int
+	}{
+		{
+			name:            "team exists",
+			err:             service.ErrTeamAlreadyExists,
+			expectedCode:    codes.TEAM_EXISTS,
+			expectedMessage: server.ErrTeamAlreadyExists,
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "pr exists",
+			err:             service.ErrPullRequestExists,
+			expectedCode:    codes.PR_EXISTS,
+			expectedMessage: server.ErrPRAlreadyExists,
+			expectedStatus:  http.StatusConflict,
+		},
+		{
+			name:            "pr merged",
+			err:             service.ErrPullRequestMerged,
+			expectedCode:    codes.PR_MERGED,
+			expectedMessage: server.ErrPullRequestMerged,
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "pr closed",
+			err:             service.ErrPullRequestClosed,
+			expectedCode:    codes.PR_CLOSED,
+			expectedMessage: server.ErrPullRequestClosed,
+			expectedStatus:  http.StatusConflict,
+		},
+		{
+			name:            "not assigned",
+			err:             service.ErrReviewerNotAssigned,
+			expectedCode:    codes.NOT_ASSIGNED,
+			expectedMessage: server.ErrReviewerNotAssigned,
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "no candidate",
+			err:             service.ErrNoReplacementCandidate,
+			expectedCode:    codes.NO_CANDIDATE,
+			expectedMessage: server.ErrNoReplacementCandidate,
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "reviewer rules unmet",
+			err:             service.ErrReviewerRulesUnmet,
+			expectedCode:    codes.REVIEWER_RULE_UNMET,
+			expectedMessage: server.ErrReviewerRulesUnmet,
+			expectedStatus:  http.StatusConflict,
+		},
+		{
+			name:            "version mismatch",
+			err:             service.ErrVersionMismatch,
+			expectedCode:    codes.VERSION_MISMATCH,
+			expectedMessage: server.ErrVersionMismatch,
+			expectedStatus:  http.StatusPreconditionFailed,
+		},
+		{
+			name:            "reassign limit exceeded",
+			err:             &service.Error{Kind: service.ErrReassignLimitExceeded, RetryAfter: time.Minute},
+			expectedCode:    codes.RATE_LIMITED,
+			expectedMessage: server.ErrReassignLimitExceeded,
+			expectedStatus:  http.StatusTooManyRequests,
+		},
+		{
+			name:            "team not found",
+			err:             service.ErrTeamNotFound,
+			expectedCode:    codes.NOT_FOUND,
+			expectedMessage: server.ErrTeamNotFound,
+			expectedStatus:  http.StatusNotFound,
+		},
+		{
+			name:            "user not found",
+			err:             service.ErrUserNotFound,
+			expectedCode:    codes.NOT_FOUND,
+			expectedMessage: server.ErrUserNotFound,
+			expectedStatus:  http.StatusNotFound,
+		},
+		{
+			name:            "absence not found",
+			err:             service.ErrAbsenceNotFound,
+			expectedCode:    codes.NOT_FOUND,
+			expectedMessage: server.ErrAbsenceNotFound,
+			expectedStatus:  http.StatusNotFound,
+		},
+		{
+			name:            "pr not found",
+			err:             service.ErrPullRequestNotFound,
+			expectedCode:    codes.NOT_FOUND,
+			expectedMessage: server.ErrPRNotFound,
+			expectedStatus:  http.StatusNotFound,
+		},
+		{
+			name:            "internal error",
+			err:             service.ErrInternalError,
+			expectedCode:    codes.INTERNAL_ERROR,
+			expectedMessage: server.ErrInternalError,
+			expectedStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:            "unknown error",
+			err:             errors.New("something unexpected"),
+			expectedCode:    codes.INTERNAL_ERROR,
+			expectedMessage: server.ErrInternalError,
+			expectedStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:            "wrapped service error",
+			err:             fmt.Errorf("handler: %w", service.ErrPullRequestMerged),
+			expectedCode:    codes.PR_MERGED,
+			expectedMessage: server.ErrPullRequestMerged,
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "repository error with cause",
+			err:             serviceWrapper.WrapRepositoryError(repository.Internal("repository.postgres.user.GetByID", pgErr)),
+			expectedCode:    codes.INTERNAL_ERROR,
+			expectedMessage: server.ErrInternalError,
+			expectedStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:            "repository not found",
+			err:             serviceWrapper.WrapRepositoryError(repository.ErrUserNotFound),
+			expectedCode:    codes.NOT_FOUND,
+			expectedMessage: server.ErrUserNotFound,
+			expectedStatus:  http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("legacy", func(t *testing.T) {
+				// без Accept — старый формат, существующие клиенты его и ждут
+				r := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+				w := httptest.NewRecorder()
+
+				WriteServiceError(w, r, tt.err)
+
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+				var resp dto.ErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedCode, resp.Error.Code)
+				assert.Equal(t, tt.expectedMessage, resp.Error.Message)
+				// причина не должна утекать клиенту
+				assert.NotContains(t, resp.Error.Message, pgErr.Error())
+			})
+
+			t.Run("problem", func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+				r.Header.Set("Accept", ContentTypeProblemJSON)
+				w := httptest.NewRecorder()
+
+				WriteServiceError(w, r, tt.err)
+
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.Equal(t, ContentTypeProblemJSON, w.Header().Get("Content-Type"))
+
+				var resp dto.ProblemDetails
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedCode, resp.Code)
+				assert.Equal(t, tt.expectedMessage, resp.Title)
+				assert.Equal(t, tt.expectedStatus, resp.Status)
+				assert.Equal(t, "/pullRequest/create", resp.Instance)
+				assert.NotEmpty(t, resp.Type)
+				assert.NotContains(t, resp.Detail, pgErr.Error())
+			})
+		})
+	}
+}
+
+func TestServiceErrorCode(t *testing.T) {
+	code, message := ServiceErrorCode(fmt.Errorf("wrapped: %w", service.ErrPullRequestMerged))
+	assert.Equal(t, codes.PR_MERGED, code)
+	assert.Equal(t, server.ErrPullRequestMerged, message)
+
+	code, message = ServiceErrorCode(&service.Error{Kind: service.ErrInternalError, Cause: errors.New("pq: connection refused")})
+	assert.Equal(t, codes.INTERNAL_ERROR, code)
+	assert.Equal(t, server.ErrInternalError, message)
+}
+
+func TestWriteServiceError_RetryAfter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", nil)
+	w := httptest.NewRecorder()
+
+	WriteServiceError(w, r, &service.Error{Kind: service.ErrReassignLimitExceeded, RetryAfter: 90500 * time.Millisecond})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "91", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	WriteServiceError(w, r, service.ErrPullRequestMerged)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestWriteError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/team/add", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+
+	WriteError(w, r, codes.INVALID_JSON, server.ErrInvalidJSON, http.StatusBadRequest)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp dto.ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, codes.INVALID_JSON, resp.Error.Code)
+	assert.Equal(t, server.ErrInvalidJSON, resp.Error.Message)
+}
+
+func TestWriteValidationError(t *testing.T) {
+	err := dto.Validate(&dto.PullRequestCreateRequest{PullRequestName: "Add search", AuthorID: "u 1"})
+	require.Error(t, err)
+
+	t.Run("problem", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+		r.Header.Set("Accept", ContentTypeProblemJSON)
+		w := httptest.NewRecorder()
+
+		WriteValidationError(w, r, err)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ContentTypeProblemJSON, w.Header().Get("Content-Type"))
+
+		var resp dto.ProblemDetails
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, codes.VALIDATION_ERROR, resp.Code)
+		assert.ElementsMatch(t, []dto.FieldViolation{
+			{Field: "pull_request_id", Rule: "required", Message: "is required"},
+			{Field: "author_id", Rule: "id", Message: "may contain only latin letters, digits and . _ : -"},
+		}, resp.Errors)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+
+		WriteValidationError(w, r, err)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var resp dto.ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, codes.VALIDATION_ERROR, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "pull_request_id")
+	})
+
+	t.Run("not a validation error", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+		w := httptest.NewRecorder()
+
+		WriteValidationError(w, r, errors.New("unexpected EOF"))
+
+		var resp dto.ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, codes.INVALID_JSON, resp.Error.Code)
+	})
+}
+
+func TestPrefersLegacy(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", true},
+		{"*/*", true},
+		{"application/problem+json", false},
+		{"application/json", true},
+		{"application/json, text/plain, */*", true},
+		{"application/problem+json, application/json", false},
+		{"application/json, application/problem+json;q=0.5", true},
+		{"application/json;q=0.5, application/problem+json", false},
+		{"application/json;q=0", true},
+		{"application/problem+json;q=0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			assert.Equal(t, tt.want, prefersLegacy(r))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package etag ETag и If-Match/If-None-Match для ресурсов с целочисленной версией
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// ErrPreconditionFailed If-Match нельзя сопоставить ни с одной версией: ответ 412
+var ErrPreconditionFailed = errors.New("If-Match does not match any version")
+
+// Format возвращает сильный ETag для версии: "3"
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseIfMatch возвращает версию из If-Match. 0 — заголовка нет или "*", проверять не нужно.
+// If-Match сравнивается строго (RFC 9110), поэтому слабые W/"..." ни с чем не совпадают.
+// Поддерживается один ETag: несколько значений или мусор дают ErrPreconditionFailed
+func ParseIfMatch(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	version, ok := parse(header)
+	if !ok || version <= 0 {
+		return 0, ErrPreconditionFailed
+	}
+	return version, nil
+}
+
+// NoneMatch true, если If-None-Match совпадает с версией и можно ответить 304.
+// Для If-None-Match сравнение слабое: W/"3" совпадает с "3"
+func NoneMatch(r *http.Request, version int64) bool {
+	header := strings.TrimSpace(r.Header.Get(HeaderIfNoneMatch))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if v, ok := parse(tag); ok && v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func parse(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,73 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"*", 0, false},
+		{`"3"`, 3, false},
+		{` "12" `, 12, false},
+		{`W/"3"`, 0, true},
+		{`"3", "4"`, 0, true},
+		{`3`, 0, true},
+		{`"abc"`, 0, true},
+		{`"0"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(HeaderIfMatch, tt.header)
+			}
+
+			got, err := ParseIfMatch(r)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrPreconditionFailed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"*", true},
+		{`"3"`, true},
+		{`W/"3"`, true},
+		{`"1", "3"`, true},
+		{`"4"`, false},
+		{`garbage`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(HeaderIfNoneMatch, tt.header)
+			}
+			assert.Equal(t, tt.want, NoneMatch(r, 3))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, `"7"`, Format(7))
+}